@@ -2,18 +2,85 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/infra"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/config"
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	//Stop serving when the process is interrupted or terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//Load the configuration from the environment and the optional config file
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	//Initialize the logger shared by the application and the infrastructure providers
+	logger, err := infra.NewLogger(cfg.Logging, os.Stderr)
+	if err != nil {
+		return err
+	}
+
 	//Initialize the infrastructure providers
-	infraProviders := infra.NewInfraProviders()
+	infraProviders, err := infra.NewInfraProviders(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
 
 	//Initialize the application services using the infrastructure provider implementations
-	appServices := app.NewServices(infraProviders.RunnerRepository, infraProviders.RaceRepository, infraProviders.NotificationService)
+	appServices := app.NewServices(app.Dependencies{
+		RunnerRepository:       infraProviders.RunnerRepository,
+		RaceRepository:         infraProviders.RaceRepository,
+		CourseRepository:       infraProviders.CourseRepository,
+		RegistrationRepository: infraProviders.RegistrationRepository,
+		BibRepository:          infraProviders.BibRepository,
+		TimingRepository:       infraProviders.TimingRepository,
+		NotificationService:    infraProviders.NotificationService,
+		ActivityParser:         infraProviders.ActivityParser,
+		ReadParser:             infraProviders.ReadParser,
+		RouteCodec:             infraProviders.RouteCodec,
+		Authenticator:          infraProviders.Authenticator,
+		Logger:                 logger,
+		LogLevels:              logger,
+		MetricsRecorder:        infraProviders.MetricsRecorder,
+	})
+
+	//Import the read files the timing system drops into the configured folder, on behalf of the system
+	if cfg.Timing.DropDir != "" {
+		go infra.NewTimingDropFolder(appServices, cfg.Timing, logger).Run(auth.WithPrincipal(ctx, auth.System))
+	}
+
+	//Serve HTTP until a shutdown signal arrives, draining the in-flight requests
+	infraHTTPServer := infra.NewHTTPServer(appServices, cfg.HTTP, cfg.Auth, logger, infraProviders.Metrics)
+	serveErr := infraHTTPServer.ListenAndServe(ctx, cfg.HTTP.Address)
 
-	//Initialize the HTTP server that calls the application services
-	infraHTTPServer := infra.NewHTTPServer(appServices)
-	infraHTTPServer.ListenAndServe(":8080")
+	//Flush the pending notifications and release the providers
+	shutdownCtx := context.Background()
+	if timeout := time.Duration(cfg.HTTP.ShutdownTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, timeout)
+		defer cancel()
+	}
+	if err := infraProviders.Shutdown(shutdownCtx); err != nil {
+		logger.Error(shutdownCtx, "failed to shut down the infrastructure providers", logging.Err(err))
+	}
+	return serveErr
 }
@@ -0,0 +1,66 @@
+// Package activity contains the port used to read activity files recorded by GPS devices
+package activity
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format of an activity file
+type Format string
+
+// Supported activity file formats
+const (
+	FormatGPX Format = "gpx"
+	FormatTCX Format = "tcx"
+	FormatFIT Format = "fit"
+)
+
+var (
+	// ErrUnsupportedFormat Error when the activity file format is not supported
+	ErrUnsupportedFormat = errors.New("activity format must be one of gpx, tcx or fit")
+	// ErrInvalidFile Error when the activity file cannot be decoded
+	ErrInvalidFile = errors.New("invalid activity file")
+	// ErrEmptyActivity Error when the activity file does not contain at least two timed samples
+	ErrEmptyActivity = errors.New("activity file contains no recorded samples")
+)
+
+// Split provides the cumulative time of an Activity at a distance marker
+type Split struct {
+	DistanceKm float64
+	Elapsed    time.Duration
+}
+
+// Activity provides the summary of a recorded activity file.
+// HeartRateAvg is 0 when the file has no heart rate samples.
+type Activity struct {
+	Duration     time.Duration
+	DistanceKm   float64
+	HeartRateAvg int
+	// Splits contains the elapsed time at every whole kilometre
+	Splits []Split
+}
+
+// Parser reads Activity files
+type Parser interface {
+	Parse(ctx context.Context, format Format, file io.Reader) (Activity, error)
+}
+
+// ParseFormat Returns the Format of the provided value, ignoring case
+func ParseFormat(value string) (Format, error) {
+	switch f := Format(strings.ToLower(value)); f {
+	case FormatGPX, FormatTCX, FormatFIT:
+		return f, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// FormatFromFilename Returns the Format matching the extension of the provided file name
+func FormatFromFilename(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
@@ -0,0 +1,19 @@
+package activity
+
+import (
+	"context"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockParser reads mock Activity files
+type MockParser struct {
+	mock.Mock
+}
+
+// Parse reads mock Activity files
+func (m *MockParser) Parse(_ context.Context, format Format, _ io.Reader) (Activity, error) {
+	args := m.Called(format)
+	return args.Get(0).(Activity), args.Error(1)
+}
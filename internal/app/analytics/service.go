@@ -0,0 +1,113 @@
+// Package analytics contains the service providing the use cases that analyse the race history of runners
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// ErrEmptyRunnerID Error when the runner id is not provided
+var ErrEmptyRunnerID = errors.New("runner ID cannot be empty")
+
+// Service provides the analytics of the race history of runners
+type Service struct {
+	raceRepo   race.Repository
+	runnerRepo runner.Repository
+	now        func() time.Time
+}
+
+// NewService creates a new analytics Service with the given repositories
+func NewService(raceRepo race.Repository, runnerRepo runner.Repository) Service {
+	return Service{raceRepo: raceRepo, runnerRepo: runnerRepo, now: time.Now}
+}
+
+// PredictionItem represents the predicted finish time of a race and its confidence range
+type PredictionItem struct {
+	DistanceKm    float64
+	ElevationGain float64
+	FinishTime    time.Duration
+	Low           time.Duration
+	High          time.Duration
+	PaceMinPerKm  float64
+	Riegel        time.Duration
+	VDOTTime      time.Duration
+	VDOT          float64
+}
+
+// PredictFinishTimes predicts the finish times of the runner over the provided distances, for races with
+// elevationGain metres of climb. The standard distances are predicted when no distances are provided.
+func (s Service) PredictFinishTimes(ctx context.Context, runnerID uuid.UUID, distancesKm []float64, elevationGain float64) ([]PredictionItem, error) {
+	if runnerID == uuid.Nil {
+		return nil, ErrEmptyRunnerID
+	}
+	if len(distancesKm) == 0 {
+		for _, d := range race.StandardDistances {
+			distancesKm = append(distancesKm, d.DistanceKm)
+		}
+	}
+
+	if _, err := s.runnerRepo.GetByID(ctx, runnerID); err != nil {
+		return nil, err
+	}
+
+	performances, err := s.performances(ctx, runnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	items := make([]PredictionItem, len(distancesKm))
+	for i, distanceKm := range distancesKm {
+		p, err := race.NewPrediction(performances, distanceKm, elevationGain, now)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = PredictionItem{
+			DistanceKm:    p.DistanceKm,
+			ElevationGain: p.ElevationGain,
+			FinishTime:    p.Time,
+			Low:           p.Low,
+			High:          p.High,
+			PaceMinPerKm:  p.Time.Minutes() / p.DistanceKm,
+			Riegel:        p.Riegel,
+			VDOTTime:      p.VDOTTime,
+			VDOT:          p.VDOT,
+		}
+	}
+	return items, nil
+}
+
+// performances returns the performances of the stored results of the runner.
+// Results of races that no longer exist and results of runners that did not finish are ignored.
+func (s Service) performances(ctx context.Context, runnerID uuid.UUID) ([]race.Performance, error) {
+	results, err := s.raceRepo.GetRaceResults(ctx, runnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	races := make(map[uuid.UUID]race.Race)
+	performances := make([]race.Performance, 0, len(results))
+	for _, result := range results {
+		if !result.IsFinished() {
+			continue
+		}
+		r, loaded := races[result.RaceID()]
+		if !loaded {
+			r, err = s.raceRepo.GetRace(ctx, result.RaceID())
+			if errors.Is(err, race.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			races[r.ID()] = r
+		}
+		performances = append(performances, race.PerformanceOf(result, r))
+	}
+	return performances, nil
+}
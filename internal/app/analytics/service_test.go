@@ -0,0 +1,162 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockRaceRepository) SaveRace(_ context.Context, r race.Race) error {
+	return m.Called(r).Error(0)
+}
+
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func TestService_PredictFinishTimes(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	rn, _ := runner.NewRunner("Anna", "anna@example.com")
+	fiveK, _ := race.NewRace("Park Run", "Athens", now.AddDate(0, -1, 0), 5, 0)
+	fiveKResult, _ := race.NewResult(rn.ID(), fiveK.ID(), 20*time.Minute, 4, 160, "")
+	deletedRaceResult, _ := race.NewResult(rn.ID(), uuid.New(), 15*time.Minute, 3, 160, "")
+	dnfResult, _ := race.NewResultWithStatus(rn.ID(), uuid.New(), race.StatusDidNotFinish, "", 0, 0, 0, "")
+
+	tests := []struct {
+		name          string
+		runnerID      uuid.UUID
+		distancesKm   []float64
+		elevationGain float64
+		mockSetup     func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository)
+		wantDistances []float64
+		wantErr       error
+	}{
+		{
+			name:        "predicts requested distances",
+			runnerID:    rn.ID(),
+			distancesKm: []float64{10, 15},
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+				raceRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{fiveKResult, deletedRaceResult, dnfResult}, nil)
+				raceRepo.On("GetRace", fiveK.ID()).Return(fiveK, nil)
+				raceRepo.On("GetRace", deletedRaceResult.RaceID()).Return(race.Race{}, race.ErrNotFound)
+			},
+			wantDistances: []float64{10, 15},
+		},
+		{
+			name:     "predicts standard distances by default",
+			runnerID: rn.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+				raceRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{fiveKResult}, nil)
+				raceRepo.On("GetRace", fiveK.ID()).Return(fiveK, nil)
+			},
+			wantDistances: []float64{5, 10, 21.0975, 42.195},
+		},
+		{
+			name:     "runner without results",
+			runnerID: rn.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+				raceRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{}, nil)
+			},
+			wantErr: race.ErrNoPerformances,
+		},
+		{
+			name:          "invalid elevation gain",
+			runnerID:      rn.ID(),
+			elevationGain: -10,
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+				raceRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{fiveKResult}, nil)
+				raceRepo.On("GetRace", fiveK.ID()).Return(fiveK, nil)
+			},
+			wantErr: race.ErrInvalidElevationGain,
+		},
+		{
+			name:     "runner not found",
+			runnerID: rn.ID(),
+			mockSetup: func(_ *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(nil, runner.ErrNotFound)
+			},
+			wantErr: runner.ErrNotFound,
+		},
+		{
+			name:      "empty runner id",
+			runnerID:  uuid.Nil,
+			mockSetup: func(*mockRaceRepository, *runner.MockRepository) {},
+			wantErr:   ErrEmptyRunnerID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			runnerRepo := new(runner.MockRepository)
+			tt.mockSetup(raceRepo, runnerRepo)
+			service := NewService(raceRepo, runnerRepo)
+			service.now = func() time.Time { return now }
+
+			got, err := service.PredictFinishTimes(context.Background(), tt.runnerID, tt.distancesKm, tt.elevationGain)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.wantDistances))
+			for i, prediction := range got {
+				assert.Equal(t, tt.wantDistances[i], prediction.DistanceKm)
+				want, err := race.NewPrediction([]race.Performance{race.PerformanceOf(fiveKResult, fiveK)}, tt.wantDistances[i], 0, now)
+				require.NoError(t, err)
+				assert.Equal(t, want.Time, prediction.FinishTime)
+				assert.Equal(t, want.Low, prediction.Low)
+				assert.Equal(t, want.High, prediction.High)
+				assert.InDelta(t, want.Time.Minutes()/want.DistanceKm, prediction.PaceMinPerKm, 0.0001)
+			}
+		})
+	}
+}
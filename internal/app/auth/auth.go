@@ -0,0 +1,78 @@
+// Package auth contains the port used to authenticate the callers of the application and the policies that
+// authorize them
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Role of a Principal
+type Role string
+
+// Supported roles. Runners act on their own behalf, organisers manage their own races and admins manage everything.
+const (
+	RoleRunner    Role = "runner"
+	RoleOrganiser Role = "organiser"
+	RoleAdmin     Role = "admin"
+)
+
+var (
+	// ErrUnauthenticated Error when an operation requires an authenticated caller
+	ErrUnauthenticated = errors.New("authentication is required")
+	// ErrInvalidCredentials Error when the credentials of the caller cannot be verified
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrForbidden Error when the caller is not allowed to perform an operation
+	ErrForbidden = errors.New("not allowed to perform this operation")
+	// ErrInvalidRole Error when a role is not one of runner, organiser or admin
+	ErrInvalidRole = errors.New("role must be one of runner, organiser or admin")
+)
+
+// ParseRole Returns the Role of the provided value
+func ParseRole(value string) (Role, error) {
+	switch r := Role(value); r {
+	case RoleRunner, RoleOrganiser, RoleAdmin:
+		return r, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// Principal provides the identity of an authenticated caller.
+// RunnerID is the runner a caller with the runner role acts as.
+type Principal struct {
+	Subject  string
+	Role     Role
+	RunnerID uuid.UUID
+}
+
+// System is the principal of the work the application performs on its own behalf, such as importing the files
+// of the timing drop folder, and of every request when authentication is disabled
+var System = Principal{Subject: "system", Role: RoleAdmin}
+
+// Credentials provides the credentials presented by a caller. Only one of them is expected to be set.
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// Authenticator verifies Credentials
+type Authenticator interface {
+	// Authenticate returns the principal of the credentials, or ErrInvalidCredentials
+	Authenticate(ctx context.Context, credentials Credentials) (Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by the context, if any
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+)
+
+// RequireRole returns ErrUnauthenticated when the context carries no principal and ErrForbidden when the
+// principal has none of the provided roles. Admins are always allowed.
+func RequireRole(ctx context.Context, roles ...Role) (Principal, error) {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return Principal{}, ErrUnauthenticated
+	}
+	if principal.Role == RoleAdmin {
+		return principal, nil
+	}
+	for _, role := range roles {
+		if principal.Role == role {
+			return principal, nil
+		}
+	}
+	return Principal{}, ErrForbidden
+}
+
+// RequireRunner allows admins and the runner with the provided id
+func RequireRunner(ctx context.Context, runnerID uuid.UUID) error {
+	principal, err := RequireRole(ctx, RoleRunner)
+	if err != nil {
+		return err
+	}
+	if principal.Role == RoleRunner && principal.RunnerID != runnerID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RequireOrganiser allows admins and the organiser of the race
+func RequireOrganiser(ctx context.Context, r race.Race) error {
+	principal, err := RequireRole(ctx, RoleOrganiser)
+	if err != nil {
+		return err
+	}
+	if principal.Role == RoleOrganiser && principal.Subject != r.Organiser() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RequireRunnerOrOrganiser allows admins, the runner with the provided id and the organiser of the race
+func RequireRunnerOrOrganiser(ctx context.Context, runnerID uuid.UUID, r race.Race) error {
+	principal, err := RequireRole(ctx, RoleRunner, RoleOrganiser)
+	if err != nil {
+		return err
+	}
+	if principal.Role == RoleOrganiser {
+		return RequireOrganiser(ctx, r)
+	}
+	return RequireRunner(ctx, runnerID)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// Service authenticates the callers of the application
+type Service struct {
+	authenticator Authenticator
+	runnerRepo    runner.Repository
+}
+
+// NewService creates a new Service with the given authenticator and runner repository
+func NewService(authenticator Authenticator, runnerRepo runner.Repository) Service {
+	return Service{authenticator: authenticator, runnerRepo: runnerRepo}
+}
+
+// Authenticate returns the principal of the credentials.
+// ErrUnauthenticated is returned when no credentials are provided and ErrInvalidCredentials when they cannot be
+// verified, carry an unknown role or belong to a runner that does not exist.
+func (s Service) Authenticate(ctx context.Context, credentials Credentials) (Principal, error) {
+	if credentials.APIKey == "" && credentials.BearerToken == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+	principal, err := s.authenticator.Authenticate(ctx, credentials)
+	if err != nil {
+		return Principal{}, err
+	}
+	if _, err := ParseRole(string(principal.Role)); err != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	if principal.Role != RoleRunner {
+		return principal, nil
+	}
+
+	if principal.RunnerID == uuid.Nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	_, err = s.runnerRepo.GetByID(ctx, principal.RunnerID)
+	if errors.Is(err, runner.ErrNotFound) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	return principal, nil
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAuthenticator struct {
+	mock.Mock
+}
+
+func (m *mockAuthenticator) Authenticate(_ context.Context, credentials Credentials) (Principal, error) {
+	args := m.Called(credentials)
+	return args.Get(0).(Principal), args.Error(1)
+}
+
+func TestService_Authenticate(t *testing.T) {
+	anna, _ := runner.NewRunner("Anna", "anna@example.com")
+	deleted := uuid.New()
+	key := Credentials{APIKey: "key"}
+
+	tests := []struct {
+		name        string
+		credentials Credentials
+		principal   Principal
+		authErr     error
+		want        Principal
+		wantErr     error
+	}{
+		{name: "no credentials", wantErr: ErrUnauthenticated},
+		{name: "unknown credentials", credentials: key, authErr: ErrInvalidCredentials, wantErr: ErrInvalidCredentials},
+		{name: "organiser", credentials: key, principal: Principal{Subject: "athens-events", Role: RoleOrganiser}, want: Principal{Subject: "athens-events", Role: RoleOrganiser}},
+		{name: "unknown role", credentials: key, principal: Principal{Subject: "athens-events", Role: "timer"}, wantErr: ErrInvalidCredentials},
+		{name: "runner", credentials: key, principal: Principal{Subject: "anna", Role: RoleRunner, RunnerID: anna.ID()}, want: Principal{Subject: "anna", Role: RoleRunner, RunnerID: anna.ID()}},
+		{name: "runner without runner id", credentials: key, principal: Principal{Subject: "anna", Role: RoleRunner}, wantErr: ErrInvalidCredentials},
+		{name: "deleted runner", credentials: key, principal: Principal{Subject: "bob", Role: RoleRunner, RunnerID: deleted}, wantErr: ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := new(mockAuthenticator)
+			authenticator.On("Authenticate", tt.credentials).Return(tt.principal, tt.authErr)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", anna.ID()).Return(anna, nil)
+			runnerRepo.On("GetByID", deleted).Return(nil, runner.ErrNotFound)
+			service := NewService(authenticator, runnerRepo)
+
+			got, err := service.Authenticate(context.Background(), tt.credentials)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequireOrganiser(t *testing.T) {
+	r, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	r = r.WithOrganiser("athens-events")
+	unorganised, _ := race.NewRace("Night 10K", "Athens", time.Now(), 10, 50)
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		race      race.Race
+		wantErr   error
+	}{
+		{name: "no principal", race: r, wantErr: ErrUnauthenticated},
+		{name: "admin", principal: &System, race: r},
+		{name: "organiser of the race", principal: &Principal{Subject: "athens-events", Role: RoleOrganiser}, race: r},
+		{name: "organiser of another race", principal: &Principal{Subject: "sparta-events", Role: RoleOrganiser}, race: r, wantErr: ErrForbidden},
+		{name: "race without organiser", principal: &Principal{Subject: "athens-events", Role: RoleOrganiser}, race: unorganised, wantErr: ErrForbidden},
+		{name: "runner", principal: &Principal{Subject: "anna", Role: RoleRunner, RunnerID: uuid.New()}, race: r, wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, *tt.principal)
+			}
+			assert.ErrorIs(t, RequireOrganiser(ctx, tt.race), tt.wantErr)
+		})
+	}
+}
+
+func TestRequireRunnerOrOrganiser(t *testing.T) {
+	r, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	r = r.WithOrganiser("athens-events")
+	runnerID := uuid.New()
+
+	tests := []struct {
+		name      string
+		principal Principal
+		wantErr   error
+	}{
+		{name: "the runner", principal: Principal{Subject: "anna", Role: RoleRunner, RunnerID: runnerID}},
+		{name: "another runner", principal: Principal{Subject: "bob", Role: RoleRunner, RunnerID: uuid.New()}, wantErr: ErrForbidden},
+		{name: "organiser of the race", principal: Principal{Subject: "athens-events", Role: RoleOrganiser}},
+		{name: "organiser of another race", principal: Principal{Subject: "sparta-events", Role: RoleOrganiser}, wantErr: ErrForbidden},
+		{name: "admin", principal: System},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RequireRunnerOrOrganiser(WithPrincipal(context.Background(), tt.principal), runnerID, r)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Package bib contains the service providing the use cases for the bib numbers and timing chips of races
+package bib
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/retry"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// Service provides the bib operations
+type Service struct {
+	repo             bib.Repository
+	raceRepo         race.Repository
+	runnerRepo       runner.Repository
+	registrationRepo registration.Repository
+	now              func() time.Time
+}
+
+// NewService creates a new Service with the given repositories
+func NewService(repo bib.Repository, raceRepo race.Repository, runnerRepo runner.Repository, registrationRepo registration.Repository) Service {
+	return Service{repo: repo, raceRepo: raceRepo, runnerRepo: runnerRepo, registrationRepo: registrationRepo, now: time.Now}
+}
+
+// RangeItem represents a block of bib numbers reserved for a category or a wave
+type RangeItem struct {
+	Name  string
+	First int
+	Last  int
+}
+
+// BibItem represents the bib of a runner. RunnerName and Gender are empty when the runner no longer exists.
+type BibItem struct {
+	Number     int
+	Range      string
+	ChipID     string
+	RunnerID   uuid.UUID
+	RunnerName string
+	Gender     string
+	AssignedAt time.Time
+}
+
+// BibChangeItem represents an audited bib reassignment
+type BibChangeItem struct {
+	RunnerID       uuid.UUID
+	PreviousNumber int
+	Number         int
+	PreviousChipID string
+	ChipID         string
+	Reason         string
+	ChangedAt      time.Time
+}
+
+// ConfigureRanges sets the bib ranges of the race. Ranges cannot overlap, and once bibs are assigned the ranges
+// must keep containing them.
+func (s Service) ConfigureRanges(ctx context.Context, raceID uuid.UUID, ranges []RangeItem) error {
+	if raceID == uuid.Nil {
+		return bib.ErrEmptyRaceID
+	}
+	domainRanges := make([]bib.Range, len(ranges))
+	for i, r := range ranges {
+		var err error
+		domainRanges[i], err = bib.NewRange(r.Name, r.First, r.Last)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return err
+	}
+
+	return retry.OnConflict(bib.ErrConcurrentUpdate, func() error {
+		a, err := s.repo.GetAllocation(ctx, raceID)
+		switch {
+		case errors.Is(err, bib.ErrNotConfigured):
+			a, err = bib.NewAllocation(raceID, domainRanges)
+		case err == nil:
+			err = a.Configure(domainRanges)
+		}
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveAllocation(ctx, a)
+	})
+}
+
+// GetRanges returns the bib ranges of the race ordered by first number
+func (s Service) GetRanges(ctx context.Context, raceID uuid.UUID) ([]RangeItem, error) {
+	if raceID == uuid.Nil {
+		return nil, bib.ErrEmptyRaceID
+	}
+	a, err := s.repo.GetAllocation(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := a.Ranges()
+	items := make([]RangeItem, len(ranges))
+	for i, r := range ranges {
+		items[i] = RangeItem{Name: r.Name(), First: r.First(), Last: r.Last()}
+	}
+	return items, nil
+}
+
+// AssignBib gives a runner with a confirmed registration the next free bib of the range and maps the optional chip to it
+func (s Service) AssignBib(ctx context.Context, raceID, runnerID uuid.UUID, rangeName, chipID string) (BibItem, error) {
+	if raceID == uuid.Nil {
+		return BibItem{}, bib.ErrEmptyRaceID
+	}
+	if runnerID == uuid.Nil {
+		return BibItem{}, bib.ErrEmptyRunnerID
+	}
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return BibItem{}, err
+	}
+
+	entryList, err := s.registrationRepo.GetEntryList(ctx, raceID)
+	if err != nil {
+		return BibItem{}, err
+	}
+	if !entryList.IsConfirmed(runnerID) {
+		return BibItem{}, registration.ErrNotRegistered
+	}
+
+	var assignment bib.Assignment
+	err = retry.OnConflict(bib.ErrConcurrentUpdate, func() error {
+		a, err := s.repo.GetAllocation(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		assignment, err = a.Assign(runnerID, rangeName, chipID, s.now())
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveAllocation(ctx, a)
+	})
+	if err != nil {
+		return BibItem{}, err
+	}
+	return s.toBibItem(ctx, assignment)
+}
+
+// ReassignBib changes the bib number, the range or the chip of a runner. The reason is required and recorded
+// with the previous number and chip. A number of 0 keeps the number unless the range changes.
+func (s Service) ReassignBib(ctx context.Context, raceID, runnerID uuid.UUID, number int, rangeName, chipID, reason string) (BibItem, error) {
+	if raceID == uuid.Nil {
+		return BibItem{}, bib.ErrEmptyRaceID
+	}
+	if runnerID == uuid.Nil {
+		return BibItem{}, bib.ErrEmptyRunnerID
+	}
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return BibItem{}, err
+	}
+
+	var assignment bib.Assignment
+	err := retry.OnConflict(bib.ErrConcurrentUpdate, func() error {
+		a, err := s.repo.GetAllocation(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		assignment, err = a.Reassign(runnerID, number, rangeName, chipID, reason, s.now())
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveAllocation(ctx, a)
+	})
+	if err != nil {
+		return BibItem{}, err
+	}
+	return s.toBibItem(ctx, assignment)
+}
+
+// ExportBibs returns the bibs of the race ordered by number with the runner details needed by the timing company
+func (s Service) ExportBibs(ctx context.Context, raceID uuid.UUID) ([]BibItem, error) {
+	if raceID == uuid.Nil {
+		return nil, bib.ErrEmptyRaceID
+	}
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetAllocation(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := a.Assignments()
+	items := make([]BibItem, len(assignments))
+	for i, assignment := range assignments {
+		items[i], err = s.toBibItem(ctx, assignment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// GetBibChanges returns the bib reassignments of the race in the order they were made
+func (s Service) GetBibChanges(ctx context.Context, raceID uuid.UUID) ([]BibChangeItem, error) {
+	if raceID == uuid.Nil {
+		return nil, bib.ErrEmptyRaceID
+	}
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetAllocation(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := a.Changes()
+	items := make([]BibChangeItem, len(changes))
+	for i, c := range changes {
+		items[i] = BibChangeItem{
+			RunnerID:       c.RunnerID(),
+			PreviousNumber: c.PreviousNumber(),
+			Number:         c.Number(),
+			PreviousChipID: c.PreviousChipID(),
+			ChipID:         c.ChipID(),
+			Reason:         c.Reason(),
+			ChangedAt:      c.ChangedAt(),
+		}
+	}
+	return items, nil
+}
+
+// authorizeOrganiser allows admins and the organiser of the race with the provided id, which manage its bibs
+func (s Service) authorizeOrganiser(ctx context.Context, raceID uuid.UUID) error {
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	return auth.RequireOrganiser(ctx, r)
+}
+
+// toBibItem returns the assignment with the name and gender of the runner
+func (s Service) toBibItem(ctx context.Context, a bib.Assignment) (BibItem, error) {
+	item := BibItem{
+		Number:     a.Number(),
+		Range:      a.RangeName(),
+		ChipID:     a.ChipID(),
+		RunnerID:   a.RunnerID(),
+		AssignedAt: a.AssignedAt(),
+	}
+	rn, err := s.runnerRepo.GetByID(ctx, a.RunnerID())
+	if errors.Is(err, runner.ErrNotFound) {
+		return item, nil
+	}
+	if err != nil {
+		return BibItem{}, err
+	}
+	item.RunnerName = rn.Name()
+	item.Gender = string(rn.Gender())
+	return item, nil
+}
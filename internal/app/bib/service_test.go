@@ -0,0 +1,331 @@
+package bib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/retry"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBibRepository stores copies of allocations and rejects the first conflicts saves as concurrent updates
+type fakeBibRepository struct {
+	allocations map[uuid.UUID]*bib.Allocation
+	conflicts   int
+	saves       int
+}
+
+func newFakeBibRepository() *fakeBibRepository {
+	return &fakeBibRepository{allocations: make(map[uuid.UUID]*bib.Allocation)}
+}
+
+func (f *fakeBibRepository) GetAllocation(_ context.Context, raceID uuid.UUID) (*bib.Allocation, error) {
+	a, exists := f.allocations[raceID]
+	if !exists {
+		return nil, bib.ErrNotConfigured
+	}
+	return bib.LoadAllocation(a.RaceID(), a.Ranges(), a.Assignments(), a.Changes(), a.Version())
+}
+
+func (f *fakeBibRepository) SaveAllocation(_ context.Context, a *bib.Allocation) error {
+	f.saves++
+	if f.saves <= f.conflicts {
+		return bib.ErrConcurrentUpdate
+	}
+	saved, err := bib.LoadAllocation(a.RaceID(), a.Ranges(), a.Assignments(), a.Changes(), a.Version()+1)
+	if err != nil {
+		return err
+	}
+	f.allocations[a.RaceID()] = saved
+	return nil
+}
+
+type mockRaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockRaceRepository) SaveRace(_ context.Context, r race.Race) error {
+	return m.Called(r).Error(0)
+}
+
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+type mockRegistrationRepository struct {
+	mock.Mock
+}
+
+func (m *mockRegistrationRepository) GetEntryList(_ context.Context, raceID uuid.UUID) (*registration.EntryList, error) {
+	args := m.Called(raceID)
+	l, _ := args.Get(0).(*registration.EntryList)
+	return l, args.Error(1)
+}
+
+func (m *mockRegistrationRepository) SaveEntryList(_ context.Context, l *registration.EntryList) error {
+	return m.Called(l).Error(0)
+}
+
+var now = time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	service    Service
+	repo       *fakeBibRepository
+	runnerRepo *runner.MockRepository
+	entryList  *registration.EntryList
+	race       race.Race
+}
+
+func newFixture(t *testing.T) fixture {
+	r, err := race.NewRace("Athens Marathon", "Athens", now.AddDate(0, 1, 0), 42.195, 250)
+	require.NoError(t, err)
+	r = r.WithOrganiser("athens-events")
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+
+	entryList, err := registration.NewEntryList(r.ID(), 100, now.AddDate(0, -1, 0), now.AddDate(0, 0, 1), false)
+	require.NoError(t, err)
+	registrationRepo := new(mockRegistrationRepository)
+	registrationRepo.On("GetEntryList", r.ID()).Return(entryList, nil)
+	registrationRepo.On("GetEntryList", mock.Anything).Return(nil, registration.ErrNotConfigured)
+
+	f := fixture{
+		repo:       newFakeBibRepository(),
+		runnerRepo: new(runner.MockRepository),
+		entryList:  entryList,
+		race:       r,
+	}
+	f.service = NewService(f.repo, raceRepo, f.runnerRepo, registrationRepo)
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+// registeredRunner returns a runner with a confirmed registration to the race of the fixture
+func (f fixture) registeredRunner(t *testing.T, name string) *runner.Runner {
+	rn, err := runner.NewRunner(name, name+"@example.com")
+	require.NoError(t, err)
+	require.NoError(t, rn.UpdateProfile(runner.GenderFemale, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)))
+	f.runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+	_, err = f.entryList.Register(rn.ID(), now)
+	require.NoError(t, err)
+	return rn
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
+}
+
+var ranges = []RangeItem{{Name: "elite", First: 1, Last: 99}, {Name: "open", First: 100, Last: 999}}
+
+func TestService_ConfigureRanges(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name    string
+		raceID  uuid.UUID
+		ranges  []RangeItem
+		wantErr error
+	}{
+		{name: "empty race id", raceID: uuid.Nil, ranges: ranges, wantErr: bib.ErrEmptyRaceID},
+		{name: "unknown race", raceID: uuid.New(), ranges: ranges, wantErr: race.ErrNotFound},
+		{name: "invalid range", raceID: f.race.ID(), ranges: []RangeItem{{Name: "elite", First: 10, Last: 1}}, wantErr: bib.ErrInvalidRange},
+		{name: "overlapping ranges", raceID: f.race.ID(), ranges: []RangeItem{{Name: "elite", First: 1, Last: 10}, {Name: "open", First: 5, Last: 20}}, wantErr: bib.ErrOverlappingRanges},
+		{name: "no ranges", raceID: f.race.ID(), wantErr: bib.ErrNoRanges},
+		{name: "valid", raceID: f.race.ID(), ranges: ranges},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.service.ConfigureRanges(adminContext(), tt.raceID, tt.ranges)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got, err := f.service.GetRanges(adminContext(), tt.raceID)
+			require.NoError(t, err)
+			assert.Equal(t, ranges, got)
+		})
+	}
+}
+
+func TestService_AssignBib(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna := f.registeredRunner(t, "anna")
+
+	_, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "elite", "")
+	assert.ErrorIs(t, err, bib.ErrNotConfigured)
+
+	require.NoError(t, f.service.ConfigureRanges(ctx, f.race.ID(), ranges))
+	got, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "elite", "CHIP-1")
+	require.NoError(t, err)
+	assert.Equal(t, BibItem{
+		Number:     1,
+		Range:      "elite",
+		ChipID:     "CHIP-1",
+		RunnerID:   anna.ID(),
+		RunnerName: "anna",
+		Gender:     "female",
+		AssignedAt: now,
+	}, got)
+
+	_, err = f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "open", "")
+	assert.ErrorIs(t, err, bib.ErrAlreadyAssigned)
+	_, err = f.service.AssignBib(ctx, f.race.ID(), uuid.New(), "open", "")
+	assert.ErrorIs(t, err, registration.ErrNotRegistered)
+	_, err = f.service.AssignBib(ctx, uuid.New(), anna.ID(), "open", "")
+	assert.ErrorIs(t, err, race.ErrNotFound)
+}
+
+func TestService_AssignBib_RetriesConcurrentUpdates(t *testing.T) {
+	ctx := adminContext()
+
+	t.Run("succeeds after a conflict", func(t *testing.T) {
+		f := newFixture(t)
+		anna := f.registeredRunner(t, "anna")
+		require.NoError(t, f.service.ConfigureRanges(ctx, f.race.ID(), ranges))
+		f.repo.conflicts = f.repo.saves + 1
+
+		got, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "open", "")
+		require.NoError(t, err)
+		assert.Equal(t, 100, got.Number)
+	})
+
+	t.Run("gives up after repeated conflicts", func(t *testing.T) {
+		f := newFixture(t)
+		anna := f.registeredRunner(t, "anna")
+		require.NoError(t, f.service.ConfigureRanges(ctx, f.race.ID(), ranges))
+		f.repo.conflicts = f.repo.saves + retry.MaxAttempts
+
+		_, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "open", "")
+		assert.ErrorIs(t, err, bib.ErrConcurrentUpdate)
+	})
+}
+
+func TestService_ReassignBib(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna, bob := f.registeredRunner(t, "anna"), f.registeredRunner(t, "bob")
+	require.NoError(t, f.service.ConfigureRanges(ctx, f.race.ID(), ranges))
+	_, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "elite", "CHIP-1")
+	require.NoError(t, err)
+	_, err = f.service.AssignBib(ctx, f.race.ID(), bob.ID(), "elite", "CHIP-2")
+	require.NoError(t, err)
+
+	got, err := f.service.ReassignBib(ctx, f.race.ID(), anna.ID(), 0, "open", "CHIP-1", "moved to the open wave")
+	require.NoError(t, err)
+	assert.Equal(t, 100, got.Number)
+	assert.Equal(t, "open", got.Range)
+
+	_, err = f.service.ReassignBib(ctx, f.race.ID(), anna.ID(), 2, "", "CHIP-1", "lost bib")
+	assert.ErrorIs(t, err, bib.ErrNumberTaken)
+	_, err = f.service.ReassignBib(ctx, f.race.ID(), anna.ID(), 5, "", "CHIP-1", "")
+	assert.ErrorIs(t, err, bib.ErrMissingChangeReason)
+
+	changes, err := f.service.GetBibChanges(ctx, f.race.ID())
+	require.NoError(t, err)
+	assert.Equal(t, []BibChangeItem{{
+		RunnerID:       anna.ID(),
+		PreviousNumber: 1,
+		Number:         100,
+		PreviousChipID: "CHIP-1",
+		ChipID:         "CHIP-1",
+		Reason:         "moved to the open wave",
+		ChangedAt:      now,
+	}}, changes)
+}
+
+func TestService_ExportBibs(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna, bob := f.registeredRunner(t, "anna"), f.registeredRunner(t, "bob")
+	require.NoError(t, f.service.ConfigureRanges(ctx, f.race.ID(), ranges))
+	_, err := f.service.AssignBib(ctx, f.race.ID(), anna.ID(), "open", "CHIP-1")
+	require.NoError(t, err)
+	_, err = f.service.AssignBib(ctx, f.race.ID(), bob.ID(), "elite", "")
+	require.NoError(t, err)
+
+	//deleted runners are exported without their details
+	f.runnerRepo.ExpectedCalls = nil
+	f.runnerRepo.On("GetByID", anna.ID()).Return(anna, nil)
+	f.runnerRepo.On("GetByID", bob.ID()).Return(nil, runner.ErrNotFound)
+
+	got, err := f.service.ExportBibs(ctx, f.race.ID())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[0].Number)
+	assert.Equal(t, bob.ID(), got[0].RunnerID)
+	assert.Empty(t, got[0].RunnerName)
+	assert.Equal(t, 100, got[1].Number)
+	assert.Equal(t, "anna", got[1].RunnerName)
+	assert.Equal(t, "CHIP-1", got[1].ChipID)
+
+	_, err = f.service.ExportBibs(ctx, uuid.New())
+	assert.ErrorIs(t, err, race.ErrNotFound)
+}
+
+func TestService_Authorization(t *testing.T) {
+	f := newFixture(t)
+	anna := f.registeredRunner(t, "anna")
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+	otherOrganiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser})
+	asAnna := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "anna", Role: auth.RoleRunner, RunnerID: anna.ID()})
+
+	assert.ErrorIs(t, f.service.ConfigureRanges(context.Background(), f.race.ID(), ranges), auth.ErrUnauthenticated)
+	assert.ErrorIs(t, f.service.ConfigureRanges(otherOrganiser, f.race.ID(), ranges), auth.ErrForbidden)
+	require.NoError(t, f.service.ConfigureRanges(organiser, f.race.ID(), ranges))
+
+	_, err := f.service.AssignBib(asAnna, f.race.ID(), anna.ID(), "elite", "")
+	assert.ErrorIs(t, err, auth.ErrForbidden, "runners do not assign their own bibs")
+	_, err = f.service.AssignBib(organiser, f.race.ID(), anna.ID(), "elite", "")
+	require.NoError(t, err)
+	_, err = f.service.ReassignBib(otherOrganiser, f.race.ID(), anna.ID(), 2, "", "", "typo")
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = f.service.ExportBibs(asAnna, f.race.ID())
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = f.service.GetBibChanges(otherOrganiser, f.race.ID())
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+}
@@ -2,22 +2,67 @@
 package app
 
 import (
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/analytics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/registration"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	domainBib "github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
 	domainRace "github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	domainRegistration "github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
 	domainRunner "github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	domainTiming "github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
 )
 
 // Services contains the exposed services of the application layer
 type Services struct {
-	RunnerService runner.Service
-	RaceService   race.Service
+	RunnerService       runner.Service
+	RaceService         race.Service
+	AnalyticsService    analytics.Service
+	RegistrationService registration.Service
+	BibService          bib.Service
+	TimingService       timing.Service
+	CourseService       course.Service
+	AuthService         auth.Service
+	LoggingService      logging.Service
+}
+
+// Dependencies contains the ports the application services are created with
+type Dependencies struct {
+	RunnerRepository       domainRunner.Repository
+	RaceRepository         domainRace.Repository
+	CourseRepository       domainRace.CourseRepository
+	RegistrationRepository domainRegistration.Repository
+	BibRepository          domainBib.Repository
+	TimingRepository       domainTiming.Repository
+	NotificationService    notification.Service
+	ActivityParser         activity.Parser
+	ReadParser             timing.ReadParser
+	RouteCodec             course.RouteCodec
+	Authenticator          auth.Authenticator
+	Logger                 logging.Logger
+	LogLevels              logging.Levels
+	MetricsRecorder        metrics.Recorder
 }
 
 // NewServices creates a new application services
-func NewServices(runnerRepo domainRunner.Repository, raceRepo domainRace.Repository, notificationService notification.Service) Services {
-	rs := runner.NewService(runnerRepo, notificationService)
-	rts := race.NewService(raceRepo)
-	return Services{RunnerService: rs, RaceService: rts}
+func NewServices(deps Dependencies) Services {
+	rs := runner.NewService(deps.RunnerRepository, deps.NotificationService, deps.Logger, deps.MetricsRecorder)
+	rts := race.NewService(deps.RaceRepository, deps.CourseRepository, deps.RunnerRepository, deps.RegistrationRepository, deps.TimingRepository, deps.ActivityParser,
+		deps.NotificationService, deps.Logger, deps.MetricsRecorder)
+	as := analytics.NewService(deps.RaceRepository, deps.RunnerRepository)
+	regs := registration.NewService(deps.RegistrationRepository, deps.RaceRepository, deps.RunnerRepository, deps.NotificationService, deps.Logger)
+	bs := bib.NewService(deps.BibRepository, deps.RaceRepository, deps.RunnerRepository, deps.RegistrationRepository)
+	ts := timing.NewService(deps.TimingRepository, deps.RaceRepository, deps.BibRepository, deps.ReadParser, deps.MetricsRecorder)
+	cs := course.NewService(deps.RaceRepository, deps.CourseRepository, deps.RouteCodec)
+	aus := auth.NewService(deps.Authenticator, deps.RunnerRepository)
+	ls := logging.NewService(deps.LogLevels)
+	return Services{RunnerService: rs, RaceService: rts, AnalyticsService: as, RegistrationService: regs, BibService: bs, TimingService: ts, CourseService: cs, AuthService: aus, LoggingService: ls}
 }
@@ -0,0 +1,50 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// RouteFormat of a course route file
+type RouteFormat string
+
+// Supported route file formats
+const (
+	// RouteFormatGPX is a GPX 1.1 file with the course as a track or a route and the checkpoints as waypoints
+	RouteFormatGPX RouteFormat = "gpx"
+	// RouteFormatGeoJSON is a feature collection with the course as a LineString and the checkpoints as Points
+	RouteFormatGeoJSON RouteFormat = "geojson"
+)
+
+var (
+	// ErrUnsupportedRouteFormat Error when the route file format is not supported
+	ErrUnsupportedRouteFormat = errors.New("route format must be one of gpx or geojson")
+	// ErrInvalidRouteFile Error when the route file cannot be decoded
+	ErrInvalidRouteFile = errors.New("invalid route file")
+)
+
+// RouteCodec reads and writes the route files of courses
+type RouteCodec interface {
+	Decode(ctx context.Context, format RouteFormat, file io.Reader) (RouteItem, error)
+	Encode(ctx context.Context, format RouteFormat, route RouteItem, w io.Writer) error
+}
+
+// ParseRouteFormat Returns the RouteFormat of the provided value, ignoring case
+func ParseRouteFormat(value string) (RouteFormat, error) {
+	switch f := RouteFormat(strings.ToLower(value)); f {
+	case RouteFormatGPX, RouteFormatGeoJSON:
+		return f, nil
+	case "json":
+		return RouteFormatGeoJSON, nil
+	default:
+		return "", ErrUnsupportedRouteFormat
+	}
+}
+
+// RouteFormatFromFilename Returns the RouteFormat matching the extension of the provided file name
+func RouteFormatFromFilename(name string) (RouteFormat, error) {
+	return ParseRouteFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
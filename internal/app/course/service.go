@@ -0,0 +1,202 @@
+// Package course contains the service providing the use cases for the courses of races
+package course
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+)
+
+// defaultProfileStepKm is the distance between the points of the elevation profile when none is requested
+const defaultProfileStepKm = 1
+
+// Service provides the course operations
+type Service struct {
+	raceRepo   race.Repository
+	courseRepo race.CourseRepository
+	codec      RouteCodec
+}
+
+// NewService creates a new Service with the given repositories and route file codec
+func NewService(raceRepo race.Repository, courseRepo race.CourseRepository, codec RouteCodec) Service {
+	return Service{raceRepo: raceRepo, courseRepo: courseRepo, codec: codec}
+}
+
+// PointItem represents a track point of a course
+type PointItem struct {
+	Lat        float64
+	Lon        float64
+	ElevationM float64
+}
+
+// CheckpointItem represents a named point of a course. DistanceKm is measured along the course
+// and is ignored when the checkpoint is decoded from a route file.
+type CheckpointItem struct {
+	Name       string
+	Kind       string
+	Lat        float64
+	Lon        float64
+	DistanceKm float64
+}
+
+// RouteItem represents the content of a route file
+type RouteItem struct {
+	Name        string
+	Points      []PointItem
+	Checkpoints []CheckpointItem
+}
+
+// ProfileItem represents the elevation of a course at a distance from the start
+type ProfileItem struct {
+	DistanceKm float64
+	ElevationM float64
+}
+
+// CourseItem represents the course of a race with the figures computed from its track
+type CourseItem struct {
+	RaceID        uuid.UUID
+	DistanceKm    float64
+	ElevationGain float64
+	ElevationLoss float64
+	Checkpoints   []CheckpointItem
+	Profile       []ProfileItem
+}
+
+// SetCourse attaches the route of the file to the race, replacing any existing course. When validateDistance
+// is set, the course is rejected if its length does not match the declared distance of the race.
+// Only the organiser of the race can set its course.
+func (s Service) SetCourse(ctx context.Context, raceID uuid.UUID, format RouteFormat, file io.Reader, validateDistance bool) (CourseItem, error) {
+	if raceID == uuid.Nil {
+		return CourseItem{}, race.ErrEmptyRaceID
+	}
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return CourseItem{}, err
+	}
+	if err := auth.RequireOrganiser(ctx, r); err != nil {
+		return CourseItem{}, err
+	}
+
+	route, err := s.codec.Decode(ctx, format, file)
+	if err != nil {
+		return CourseItem{}, err
+	}
+	c, err := toCourse(route)
+	if err != nil {
+		return CourseItem{}, err
+	}
+	if validateDistance {
+		if err := r.MatchesCourse(c); err != nil {
+			return CourseItem{}, err
+		}
+	}
+
+	if err := s.courseRepo.SaveCourse(ctx, raceID, c); err != nil {
+		return CourseItem{}, err
+	}
+	return toCourseItem(raceID, c, defaultProfileStepKm)
+}
+
+// GetCourse returns the course of the race with its elevation profile every profileStepKm, or every kilometre
+// when profileStepKm is 0
+func (s Service) GetCourse(ctx context.Context, raceID uuid.UUID, profileStepKm float64) (CourseItem, error) {
+	if raceID == uuid.Nil {
+		return CourseItem{}, race.ErrEmptyRaceID
+	}
+	if profileStepKm == 0 {
+		profileStepKm = defaultProfileStepKm
+	}
+	c, err := s.getCourse(ctx, raceID)
+	if err != nil {
+		return CourseItem{}, err
+	}
+	return toCourseItem(raceID, c, profileStepKm)
+}
+
+// ExportCourse writes the course of the race to w as a route file in the provided format
+func (s Service) ExportCourse(ctx context.Context, raceID uuid.UUID, format RouteFormat, w io.Writer) error {
+	if raceID == uuid.Nil {
+		return race.ErrEmptyRaceID
+	}
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	c, err := s.courseRepo.GetCourse(ctx, raceID)
+	if err != nil {
+		return err
+	}
+
+	route := RouteItem{Name: r.Name(), Checkpoints: toCheckpointItems(c.Checkpoints())}
+	for _, p := range c.Points() {
+		route.Points = append(route.Points, PointItem{Lat: p.Lat(), Lon: p.Lon(), ElevationM: p.ElevationM()})
+	}
+	return s.codec.Encode(ctx, format, route, w)
+}
+
+// getCourse returns the course of the race, or race.ErrNotFound when the race does not exist
+func (s Service) getCourse(ctx context.Context, raceID uuid.UUID) (race.Course, error) {
+	if _, err := s.raceRepo.GetRace(ctx, raceID); err != nil {
+		return race.Course{}, err
+	}
+	return s.courseRepo.GetCourse(ctx, raceID)
+}
+
+func toCourse(route RouteItem) (race.Course, error) {
+	points := make([]race.CoursePoint, len(route.Points))
+	for i, p := range route.Points {
+		var err error
+		points[i], err = race.NewCoursePoint(p.Lat, p.Lon, p.ElevationM)
+		if err != nil {
+			return race.Course{}, err
+		}
+	}
+	checkpoints := make([]race.CourseCheckpoint, len(route.Checkpoints))
+	for i, c := range route.Checkpoints {
+		position, err := race.NewCoursePoint(c.Lat, c.Lon, 0)
+		if err != nil {
+			return race.Course{}, err
+		}
+		checkpoints[i], err = race.NewCourseCheckpoint(c.Name, race.CheckpointKind(c.Kind), position)
+		if err != nil {
+			return race.Course{}, err
+		}
+	}
+	return race.NewCourse(points, checkpoints)
+}
+
+func toCourseItem(raceID uuid.UUID, c race.Course, profileStepKm float64) (CourseItem, error) {
+	profile, err := c.ElevationProfile(profileStepKm)
+	if err != nil {
+		return CourseItem{}, err
+	}
+	item := CourseItem{
+		RaceID:        raceID,
+		DistanceKm:    c.DistanceKm(),
+		ElevationGain: c.ElevationGain(),
+		ElevationLoss: c.ElevationLoss(),
+		Checkpoints:   toCheckpointItems(c.Checkpoints()),
+		Profile:       make([]ProfileItem, len(profile)),
+	}
+	for i, p := range profile {
+		item.Profile[i] = ProfileItem{DistanceKm: p.DistanceKm, ElevationM: p.ElevationM}
+	}
+	return item, nil
+}
+
+func toCheckpointItems(checkpoints []race.CourseCheckpoint) []CheckpointItem {
+	items := make([]CheckpointItem, len(checkpoints))
+	for i, c := range checkpoints {
+		items[i] = CheckpointItem{
+			Name:       c.Name(),
+			Kind:       string(c.Kind()),
+			Lat:        c.Position().Lat(),
+			Lon:        c.Position().Lon(),
+			DistanceKm: c.DistanceKm(),
+		}
+	}
+	return items
+}
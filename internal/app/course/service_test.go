@@ -0,0 +1,261 @@
+package course
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCourseRepository struct {
+	courses map[uuid.UUID]race.Course
+}
+
+func (f *fakeCourseRepository) SaveCourse(_ context.Context, raceID uuid.UUID, c race.Course) error {
+	f.courses[raceID] = c
+	return nil
+}
+
+func (f *fakeCourseRepository) GetCourse(_ context.Context, raceID uuid.UUID) (race.Course, error) {
+	c, exists := f.courses[raceID]
+	if !exists {
+		return race.Course{}, race.ErrCourseNotFound
+	}
+	return c, nil
+}
+
+type mockRaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockRaceRepository) SaveRace(_ context.Context, r race.Race) error {
+	return m.Called(r).Error(0)
+}
+
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+type mockRouteCodec struct {
+	mock.Mock
+}
+
+func (m *mockRouteCodec) Decode(_ context.Context, format RouteFormat, _ io.Reader) (RouteItem, error) {
+	args := m.Called(format)
+	return args.Get(0).(RouteItem), args.Error(1)
+}
+
+func (m *mockRouteCodec) Encode(_ context.Context, format RouteFormat, route RouteItem, w io.Writer) error {
+	args := m.Called(format, route)
+	_, _ = io.WriteString(w, string(format))
+	return args.Error(0)
+}
+
+// route is a 2 km course heading north that climbs 30 m and descends 10 m
+var route = RouteItem{
+	Name: "Hill 2K",
+	Points: []PointItem{
+		{Lat: 38.0, Lon: 23.7, ElevationM: 100},
+		{Lat: 38.009, Lon: 23.7, ElevationM: 130},
+		{Lat: 38.018, Lon: 23.7, ElevationM: 120},
+	},
+	Checkpoints: []CheckpointItem{
+		{Name: "Finish", Kind: "finish", Lat: 38.018, Lon: 23.7},
+		{Name: "Start", Kind: "start", Lat: 38.0, Lon: 23.7},
+	},
+}
+
+type fixture struct {
+	service    Service
+	raceRepo   *mockRaceRepository
+	courseRepo *fakeCourseRepository
+	codec      *mockRouteCodec
+	race       race.Race
+}
+
+func newFixture(t *testing.T, distanceKm float64) fixture {
+	r, err := race.NewRace("Hill 2K", "Athens", time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC), distanceKm, 0)
+	require.NoError(t, err)
+	r = r.WithOrganiser("athens-events")
+	f := fixture{
+		raceRepo:   &mockRaceRepository{},
+		courseRepo: &fakeCourseRepository{courses: make(map[uuid.UUID]race.Course)},
+		codec:      &mockRouteCodec{},
+		race:       r,
+	}
+	f.raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	f.raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+	f.service = NewService(f.raceRepo, f.courseRepo, f.codec)
+	return f
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
+}
+
+func TestService_SetCourse(t *testing.T) {
+	tests := []struct {
+		name             string
+		distanceKm       float64
+		validateDistance bool
+		route            RouteItem
+		decodeErr        error
+		raceID           func(f fixture) uuid.UUID
+		wantErr          error
+	}{
+		{name: "should store the course", distanceKm: 2, validateDistance: true, route: route},
+		{name: "should store a course longer than declared without validation", distanceKm: 1.5, route: route},
+		{name: "should reject a course that does not match the distance", distanceKm: 1.5, validateDistance: true, route: route, wantErr: race.ErrCourseDistanceMismatch},
+		{name: "should reject an undecodable file", distanceKm: 2, decodeErr: ErrInvalidRouteFile, wantErr: ErrInvalidRouteFile},
+		{name: "should reject a route without track", distanceKm: 2, route: RouteItem{Points: route.Points[:1]}, wantErr: race.ErrCourseTooShort},
+		{name: "should reject an unknown race", distanceKm: 2, route: route, raceID: func(fixture) uuid.UUID { return uuid.New() }, wantErr: race.ErrNotFound},
+		{name: "should reject an empty race id", distanceKm: 2, route: route, raceID: func(fixture) uuid.UUID { return uuid.Nil }, wantErr: race.ErrEmptyRaceID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, tt.distanceKm)
+			f.codec.On("Decode", RouteFormatGPX).Return(tt.route, tt.decodeErr)
+			raceID := f.race.ID()
+			if tt.raceID != nil {
+				raceID = tt.raceID(f)
+			}
+
+			got, err := f.service.SetCourse(adminContext(), raceID, RouteFormatGPX, strings.NewReader("<gpx/>"), tt.validateDistance)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, f.courseRepo.courses)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, raceID, got.RaceID)
+			assert.InDelta(t, 2.0, got.DistanceKm, 0.01)
+			assert.Equal(t, 30.0, got.ElevationGain)
+			assert.Equal(t, 10.0, got.ElevationLoss)
+			require.Len(t, got.Checkpoints, 2)
+			assert.Equal(t, "Start", got.Checkpoints[0].Name)
+			assert.Len(t, got.Profile, 4, "every kilometre and the finish")
+			assert.Contains(t, f.courseRepo.courses, raceID)
+		})
+	}
+}
+
+func TestService_SetCourse_Authorization(t *testing.T) {
+	f := newFixture(t, 2)
+	f.codec.On("Decode", RouteFormatGPX).Return(route, nil)
+
+	_, err := f.service.SetCourse(context.Background(), f.race.ID(), RouteFormatGPX, strings.NewReader("<gpx/>"), false)
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+	otherOrganiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser})
+	_, err = f.service.SetCourse(otherOrganiser, f.race.ID(), RouteFormatGPX, strings.NewReader("<gpx/>"), false)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	assert.Empty(t, f.courseRepo.courses)
+
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+	_, err = f.service.SetCourse(organiser, f.race.ID(), RouteFormatGPX, strings.NewReader("<gpx/>"), false)
+	assert.NoError(t, err)
+}
+
+func TestService_GetCourse(t *testing.T) {
+	f := newFixture(t, 2)
+	f.codec.On("Decode", RouteFormatGPX).Return(route, nil)
+	_, err := f.service.GetCourse(adminContext(), f.race.ID(), 0)
+	assert.ErrorIs(t, err, race.ErrCourseNotFound)
+
+	_, err = f.service.SetCourse(adminContext(), f.race.ID(), RouteFormatGPX, strings.NewReader("<gpx/>"), false)
+	require.NoError(t, err)
+
+	got, err := f.service.GetCourse(adminContext(), f.race.ID(), 0.5)
+	require.NoError(t, err)
+	assert.Len(t, got.Profile, 6)
+	assert.Equal(t, 100.0, got.Profile[0].ElevationM)
+
+	_, err = f.service.GetCourse(adminContext(), f.race.ID(), -1)
+	assert.ErrorIs(t, err, race.ErrInvalidProfileStep)
+
+	_, err = f.service.GetCourse(adminContext(), uuid.New(), 0)
+	assert.ErrorIs(t, err, race.ErrNotFound)
+}
+
+func TestService_ExportCourse(t *testing.T) {
+	f := newFixture(t, 2)
+	f.codec.On("Decode", RouteFormatGPX).Return(route, nil)
+	var buf bytes.Buffer
+	err := f.service.ExportCourse(adminContext(), f.race.ID(), RouteFormatGeoJSON, &buf)
+	assert.ErrorIs(t, err, race.ErrCourseNotFound)
+
+	_, err = f.service.SetCourse(adminContext(), f.race.ID(), RouteFormatGPX, strings.NewReader("<gpx/>"), false)
+	require.NoError(t, err)
+
+	f.codec.On("Encode", RouteFormatGeoJSON, mock.MatchedBy(func(r RouteItem) bool {
+		return r.Name == "Hill 2K" && len(r.Points) == 3 && len(r.Checkpoints) == 2 && r.Checkpoints[0].Name == "Start"
+	})).Return(nil)
+	require.NoError(t, f.service.ExportCourse(adminContext(), f.race.ID(), RouteFormatGeoJSON, &buf))
+	assert.Equal(t, "geojson", buf.String())
+	f.codec.AssertExpectations(t)
+}
+
+func TestParseRouteFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    RouteFormat
+		wantErr error
+	}{
+		{value: "GPX", want: RouteFormatGPX},
+		{value: "geojson", want: RouteFormatGeoJSON},
+		{value: "json", want: RouteFormatGeoJSON},
+		{value: "kml", wantErr: ErrUnsupportedRouteFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRouteFormat(tt.value)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := RouteFormatFromFilename("marathon.gpx")
+	require.NoError(t, err)
+	assert.Equal(t, RouteFormatGPX, got)
+}
@@ -0,0 +1,96 @@
+// Package logging contains the port the application reports its operational events through, and the service
+// that changes the level of the logged events at runtime
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Level is the severity of a logged event
+type Level string
+
+// Supported levels, from the most to the least verbose
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+// ErrInvalidLevel is returned when a level is not one of the supported levels
+var ErrInvalidLevel = fmt.Errorf("level must be one of %q, %q, %q or %q", LevelDebug, LevelInfo, LevelWarn, LevelError)
+
+// ParseLevel returns the level with the provided case-insensitive name
+func ParseLevel(value string) (Level, error) {
+	switch level := Level(strings.ToLower(value)); level {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return level, nil
+	default:
+		return "", ErrInvalidLevel
+	}
+}
+
+// Field is a key-value pair describing a logged event
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns a field with a string value
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a field with an integer value
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Any returns a field with an arbitrary value
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns the error field of a failed operation
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// RunnerID returns the field identifying the runner an event is about
+func RunnerID(id uuid.UUID) Field {
+	return Field{Key: "runner_id", Value: id.String()}
+}
+
+// RaceID returns the field identifying the race an event is about
+func RaceID(id uuid.UUID) Field {
+	return Field{Key: "race_id", Value: id.String()}
+}
+
+// Logger records operational events. Implementations add the fields carried by the context, such as the request
+// identifier, to every event.
+type Logger interface {
+	Debug(ctx context.Context, msg string, fields ...Field)
+	Info(ctx context.Context, msg string, fields ...Field)
+	Warn(ctx context.Context, msg string, fields ...Field)
+	Error(ctx context.Context, msg string, fields ...Field)
+}
+
+// Levels reads and changes the minimum level of the logged events
+type Levels interface {
+	Level() Level
+	SetLevel(level Level)
+}
+
+// Discard is a Logger that drops every event
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debug(context.Context, string, ...Field) {}
+func (discard) Info(context.Context, string, ...Field)  {}
+func (discard) Warn(context.Context, string, ...Field)  {}
+func (discard) Error(context.Context, string, ...Field) {}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"sync"
+)
+
+// Record is an event captured by the RecordingLogger
+type Record struct {
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Field returns the value of the field with the provided key, if any
+func (r Record) Field(key string) (any, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// RecordingLogger captures the logged events so tests can assert on them
+type RecordingLogger struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// Records returns the captured events in the order they were logged
+func (l *RecordingLogger) Records() []Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Record(nil), l.records...)
+}
+
+// Debug captures a debug event
+func (l *RecordingLogger) Debug(_ context.Context, msg string, fields ...Field) {
+	l.record(LevelDebug, msg, fields)
+}
+
+// Info captures an info event
+func (l *RecordingLogger) Info(_ context.Context, msg string, fields ...Field) {
+	l.record(LevelInfo, msg, fields)
+}
+
+// Warn captures a warning event
+func (l *RecordingLogger) Warn(_ context.Context, msg string, fields ...Field) {
+	l.record(LevelWarn, msg, fields)
+}
+
+// Error captures an error event
+func (l *RecordingLogger) Error(_ context.Context, msg string, fields ...Field) {
+	l.record(LevelError, msg, fields)
+}
+
+func (l *RecordingLogger) record(level Level, msg string, fields []Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, Record{Level: level, Message: msg, Fields: fields})
+}
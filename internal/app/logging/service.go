@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+)
+
+// Service lets admins inspect and change the level of the logged events at runtime
+type Service struct {
+	levels Levels
+}
+
+// NewService creates a new Service changing the provided levels
+func NewService(levels Levels) Service {
+	return Service{levels: levels}
+}
+
+// GetLevel returns the minimum level of the logged events. Only admins can read it.
+func (s Service) GetLevel(ctx context.Context) (Level, error) {
+	if _, err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return "", err
+	}
+	return s.levels.Level(), nil
+}
+
+// SetLevel changes the minimum level of the logged events until the next restart. Only admins can change it.
+func (s Service) SetLevel(ctx context.Context, value string) (Level, error) {
+	if _, err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return "", err
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return "", err
+	}
+	s.levels.SetLevel(level)
+	return level, nil
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLevels struct {
+	level Level
+}
+
+func (f *fakeLevels) Level() Level {
+	return f.level
+}
+
+func (f *fakeLevels) SetLevel(level Level) {
+	f.level = level
+}
+
+func TestService_SetLevel(t *testing.T) {
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		value     string
+		want      Level
+		wantErr   error
+		wantLevel Level
+	}{
+		{
+			name:      "should change the level",
+			ctx:       auth.WithPrincipal(context.Background(), auth.System),
+			value:     "DEBUG",
+			want:      LevelDebug,
+			wantLevel: LevelDebug,
+		},
+		{
+			name:      "should reject unknown levels",
+			ctx:       auth.WithPrincipal(context.Background(), auth.System),
+			value:     "verbose",
+			wantErr:   ErrInvalidLevel,
+			wantLevel: LevelInfo,
+		},
+		{
+			name:      "should reject callers other than admins",
+			ctx:       organiser,
+			value:     "debug",
+			wantErr:   auth.ErrForbidden,
+			wantLevel: LevelInfo,
+		},
+		{
+			name:      "should reject anonymous callers",
+			ctx:       context.Background(),
+			value:     "debug",
+			wantErr:   auth.ErrUnauthenticated,
+			wantLevel: LevelInfo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := &fakeLevels{level: LevelInfo}
+			service := NewService(levels)
+
+			got, err := service.SetLevel(tt.ctx, tt.value)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLevel, levels.level)
+		})
+	}
+}
+
+func TestService_GetLevel(t *testing.T) {
+	service := NewService(&fakeLevels{level: LevelWarn})
+
+	got, err := service.GetLevel(auth.WithPrincipal(context.Background(), auth.System))
+	assert.NoError(t, err)
+	assert.Equal(t, LevelWarn, got)
+
+	_, err = service.GetLevel(context.Background())
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
@@ -0,0 +1,20 @@
+// Package metrics contains the port the application counts its business events through
+package metrics
+
+import "context"
+
+// Recorder counts the business events of the application
+type Recorder interface {
+	// RunnerCreated counts a runner that signed up
+	RunnerCreated(ctx context.Context)
+	// ResultLogged counts a result stored for a race, by its status
+	ResultLogged(ctx context.Context, status string)
+}
+
+// Discard is a Recorder that drops every event
+var Discard Recorder = discard{}
+
+type discard struct{}
+
+func (discard) RunnerCreated(context.Context)        {}
+func (discard) ResultLogged(context.Context, string) {}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRecorder counts mock business events
+type MockRecorder struct {
+	mock.Mock
+}
+
+// RunnerCreated counts a mock runner creation
+func (m *MockRecorder) RunnerCreated(_ context.Context) {
+	m.Called()
+}
+
+// ResultLogged counts a mock result
+func (m *MockRecorder) ResultLogged(_ context.Context, status string) {
+	m.Called(status)
+}
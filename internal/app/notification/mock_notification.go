@@ -1,7 +1,11 @@
 // Package notification contains the mock implementation of the NotificationService interface.
 package notification
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 // MockNotificationService sends mock Notifications
 type MockNotificationService struct {
@@ -9,7 +13,7 @@ type MockNotificationService struct {
 }
 
 // Notify sends mock Notifications
-func (m *MockNotificationService) Notify(notification Notification) error {
+func (m *MockNotificationService) Notify(_ context.Context, notification Notification) error {
 	args := m.Called(notification)
 	return args.Error(0)
 }
@@ -1,5 +1,7 @@
 package notification
 
+import "context"
+
 // Notification provides a struct to send messages via the Service
 type Notification struct {
 	EmailAddress string
@@ -9,5 +11,5 @@ type Notification struct {
 
 // Service sends Notification
 type Service interface {
-	Notify(notification Notification) error
+	Notify(ctx context.Context, notification Notification) error
 }
@@ -0,0 +1,229 @@
+package race
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+)
+
+// RaceChangesItem represents the changes to the details of a race. Nil fields are left unchanged.
+type RaceChangesItem struct {
+	Name          *string
+	Location      *string
+	Date          *time.Time
+	DistanceKm    *float64
+	ElevationGain *float64
+}
+
+// UpdateRace applies the changes to the race with the provided id and returns the updated race.
+// When the distance is corrected the provisional results are recalculated for the new distance and stored
+// together with the race; the distance of races with official results cannot change, and the distance of races
+// with a course cannot move away from the course length by more than the tolerance.
+// The runners with results or active registrations are notified of the changes, unless nothing changed.
+func (s Service) UpdateRace(ctx context.Context, raceID uuid.UUID, changes RaceChangesItem) (RaceItem, error) {
+	if raceID == uuid.Nil {
+		return RaceItem{}, ErrEmptyRaceID
+	}
+
+	original, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return RaceItem{}, err
+	}
+	if err := auth.RequireOrganiser(ctx, original); err != nil {
+		return RaceItem{}, err
+	}
+	results, err := s.repo.GetResultsByRace(ctx, raceID)
+	if err != nil {
+		return RaceItem{}, err
+	}
+
+	updated, err := applyChanges(original, changes, results)
+	if err != nil {
+		return RaceItem{}, err
+	}
+	described := describeChanges(original, updated)
+	if len(described) == 0 {
+		return toRaceItem(updated), nil
+	}
+
+	// The course and the results are checked before anything is saved, so that a course or a result that no
+	// longer fits the race leaves the race unchanged
+	var recalculated []race.Result
+	if updated.DistanceKm() != original.DistanceKm() {
+		if err := s.checkCourse(ctx, updated); err != nil {
+			return RaceItem{}, err
+		}
+		for _, result := range results {
+			result, err = result.ForDistance(updated.DistanceKm())
+			if err != nil {
+				return RaceItem{}, err
+			}
+			recalculated = append(recalculated, result)
+		}
+	}
+
+	if err := s.repo.SaveRaceWithResults(ctx, updated, recalculated); err != nil {
+		return RaceItem{}, err
+	}
+
+	s.notifyParticipants(ctx, updated, results,
+		fmt.Sprintf("%s has changed", updated.Name()),
+		fmt.Sprintf("The details of %s have changed: %s.", original.Name(), strings.Join(described, ", ")))
+	return toRaceItem(updated), nil
+}
+
+// checkCourse returns ErrCourseMismatch when the race has a stored course that no longer matches its distance
+func (s Service) checkCourse(ctx context.Context, r race.Race) error {
+	course, err := s.courseRepo.GetCourse(ctx, r.ID())
+	if errors.Is(err, race.ErrCourseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if r.MatchesCourse(course) != nil {
+		return ErrCourseMismatch
+	}
+	return nil
+}
+
+// applyChanges returns a copy of the race with the changes applied
+func applyChanges(r race.Race, changes RaceChangesItem, results []race.Result) (race.Race, error) {
+	var err error
+	if changes.Name != nil {
+		if r, err = r.Rename(*changes.Name); err != nil {
+			return race.Race{}, err
+		}
+	}
+	if changes.Location != nil {
+		if r, err = r.Relocate(*changes.Location); err != nil {
+			return race.Race{}, err
+		}
+	}
+	if changes.Date != nil {
+		if r, err = r.Reschedule(*changes.Date); err != nil {
+			return race.Race{}, err
+		}
+	}
+	if changes.DistanceKm != nil || changes.ElevationGain != nil {
+		distanceKm, elevationGain := r.DistanceKm(), r.ElevationGain()
+		if changes.DistanceKm != nil {
+			distanceKm = *changes.DistanceKm
+		}
+		if changes.ElevationGain != nil {
+			elevationGain = *changes.ElevationGain
+		}
+		if r, err = r.CorrectDistance(distanceKm, elevationGain, results); err != nil {
+			return race.Race{}, err
+		}
+	}
+	return r, nil
+}
+
+// describeChanges lists the details that differ between the original and the updated race
+func describeChanges(original, updated race.Race) []string {
+	var described []string
+	if updated.Name() != original.Name() {
+		described = append(described, fmt.Sprintf("the race is now called %s", updated.Name()))
+	}
+	if updated.Location() != original.Location() {
+		described = append(described, fmt.Sprintf("the race now takes place in %s", updated.Location()))
+	}
+	if !updated.Date().Equal(original.Date()) {
+		described = append(described, fmt.Sprintf("the race now starts on %s", updated.Date().Format(time.RFC1123)))
+	}
+	if updated.DistanceKm() != original.DistanceKm() {
+		described = append(described, fmt.Sprintf("the distance is now %g km", updated.DistanceKm()))
+	}
+	if updated.ElevationGain() != original.ElevationGain() {
+		described = append(described, fmt.Sprintf("the elevation gain is now %g m", updated.ElevationGain()))
+	}
+	return described
+}
+
+// CancelRace cancels the race with the provided id for the provided reason and returns the cancelled race.
+// The runners with results or active registrations are notified of the cancellation.
+func (s Service) CancelRace(ctx context.Context, raceID uuid.UUID, reason string) (RaceItem, error) {
+	if raceID == uuid.Nil {
+		return RaceItem{}, ErrEmptyRaceID
+	}
+
+	r, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return RaceItem{}, err
+	}
+	if err := auth.RequireOrganiser(ctx, r); err != nil {
+		return RaceItem{}, err
+	}
+	cancelled, err := r.Cancel(reason, s.now())
+	if err != nil {
+		return RaceItem{}, err
+	}
+	results, err := s.repo.GetResultsByRace(ctx, raceID)
+	if err != nil {
+		return RaceItem{}, err
+	}
+
+	if err := s.repo.SaveRace(ctx, cancelled); err != nil {
+		return RaceItem{}, err
+	}
+
+	s.notifyParticipants(ctx, cancelled, results,
+		fmt.Sprintf("%s is cancelled", cancelled.Name()),
+		fmt.Sprintf("%s on %s has been cancelled: %s.", cancelled.Name(), cancelled.Date().Format(time.RFC1123), cancelled.CancellationReason()))
+	return toRaceItem(cancelled), nil
+}
+
+// notifyParticipants notifies every runner with a result or an active registration in the race once.
+// Notification is a best effort operation, so failures are only logged.
+func (s Service) notifyParticipants(ctx context.Context, r race.Race, results []race.Result, subject, message string) {
+	var participants []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	add := func(runnerID uuid.UUID) {
+		if !seen[runnerID] {
+			seen[runnerID] = true
+			participants = append(participants, runnerID)
+		}
+	}
+	for _, result := range results {
+		add(result.RunnerID())
+	}
+	entryList, err := s.registrationRepo.GetEntryList(ctx, r.ID())
+	switch {
+	case err == nil:
+		for _, reg := range entryList.Registrations() {
+			if reg.IsActive() {
+				add(reg.RunnerID())
+			}
+		}
+	case !errors.Is(err, registration.ErrNotConfigured):
+		s.logger.Warn(ctx, "failed to load the registrations of the participants to notify", logging.RaceID(r.ID()), logging.Err(err))
+	}
+
+	for _, runnerID := range participants {
+		rn, err := s.runnerRepo.GetByID(ctx, runnerID)
+		if err != nil {
+			s.logger.Warn(ctx, "failed to load the runner of a race notification",
+				logging.RaceID(r.ID()), logging.RunnerID(runnerID), logging.Err(err))
+			continue
+		}
+		err = s.notificationService.Notify(ctx, notification.Notification{
+			EmailAddress: rn.EmailAddress(),
+			Subject:      subject,
+			Message:      message,
+		})
+		if err != nil {
+			s.logger.Warn(ctx, "failed to send a race notification",
+				logging.RaceID(r.ID()), logging.RunnerID(runnerID), logging.Err(err))
+		}
+	}
+}
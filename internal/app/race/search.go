@@ -0,0 +1,155 @@
+package race
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+)
+
+// Page limits of ListRaces
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Error variables for race search validation
+var (
+	ErrInvalidLimit  = fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+	ErrInvalidOrder  = errors.New("order must be asc or desc")
+	ErrInvalidCursor = errors.New("cursor is not valid for this search")
+)
+
+// RaceFilterItem represents the criteria of a race listing. Zero values do not filter.
+// The date range includes From and excludes To, Text matches any part of the race name, Sort is one of
+// date, name, distance_km or elevation_gain and Order is asc or desc.
+// Cursor is the NextCursor of the previous page, which is only valid with the same sort and order.
+type RaceFilterItem struct {
+	From             time.Time
+	To               time.Time
+	Location         string
+	MinDistanceKm    *float64
+	MaxDistanceKm    *float64
+	MinElevationGain *float64
+	MaxElevationGain *float64
+	Text             string
+	Sort             string
+	Order            string
+	Cursor           string
+	Limit            int
+}
+
+// RacePage represents a page of races. NextCursor is empty on the last page.
+type RacePage struct {
+	Races      []RaceItem
+	NextCursor string
+	Limit      int
+}
+
+// ListRaces returns a page of the races that match the filter, ordered by date unless sorted otherwise.
+// A zero limit falls back to DefaultPageLimit.
+func (s Service) ListRaces(ctx context.Context, filter RaceFilterItem) (RacePage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxPageLimit {
+		return RacePage{}, ErrInvalidLimit
+	}
+
+	search := race.Search{
+		From:             filter.From,
+		To:               filter.To,
+		Location:         filter.Location,
+		MinDistanceKm:    filter.MinDistanceKm,
+		MaxDistanceKm:    filter.MaxDistanceKm,
+		MinElevationGain: filter.MinElevationGain,
+		MaxElevationGain: filter.MaxElevationGain,
+		Text:             filter.Text,
+		SortBy:           race.SortField(filter.Sort),
+		// one more race than the page holds tells whether there is a next page
+		Limit: filter.Limit + 1,
+	}
+	switch filter.Order {
+	case "", "asc":
+	case "desc":
+		search.Descending = true
+	default:
+		return RacePage{}, ErrInvalidOrder
+	}
+	if err := search.Validate(); err != nil {
+		return RacePage{}, err
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor, search)
+		if err != nil {
+			return RacePage{}, err
+		}
+		search.After = &cursor
+	}
+
+	races, err := s.repo.SearchRaces(ctx, search)
+	if err != nil {
+		return RacePage{}, err
+	}
+
+	page := RacePage{Races: make([]RaceItem, 0, filter.Limit), Limit: filter.Limit}
+	if len(races) > filter.Limit {
+		races = races[:filter.Limit]
+		page.NextCursor = encodeCursor(race.CursorOf(races[len(races)-1]), search)
+	}
+	for _, r := range races {
+		page.Races = append(page.Races, toRaceItem(r))
+	}
+	return page, nil
+}
+
+// cursorToken is the content of an encoded cursor. The sort and the order of the search are kept, so that
+// a cursor is not applied to a search in another order.
+type cursorToken struct {
+	Sort          race.SortField `json:"sort"`
+	Descending    bool           `json:"desc"`
+	ID            uuid.UUID      `json:"id"`
+	Name          string         `json:"name"`
+	Date          time.Time      `json:"date"`
+	DistanceKm    float64        `json:"distance_km"`
+	ElevationGain float64        `json:"elevation_gain"`
+}
+
+func encodeCursor(cursor race.Cursor, search race.Search) string {
+	token, _ := json.Marshal(cursorToken{
+		Sort:          search.Sort(),
+		Descending:    search.Descending,
+		ID:            cursor.ID,
+		Name:          cursor.Name,
+		Date:          cursor.Date,
+		DistanceKm:    cursor.DistanceKm,
+		ElevationGain: cursor.ElevationGain,
+	})
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodeCursor(encoded string, search race.Search) (race.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return race.Cursor{}, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID == uuid.Nil {
+		return race.Cursor{}, ErrInvalidCursor
+	}
+	if token.Sort != search.Sort() || token.Descending != search.Descending {
+		return race.Cursor{}, ErrInvalidCursor
+	}
+	return race.Cursor{
+		ID:            token.ID,
+		Name:          token.Name,
+		Date:          token.Date,
+		DistanceKm:    token.DistanceKm,
+		ElevationGain: token.ElevationGain,
+	}, nil
+}
@@ -2,11 +2,23 @@
 package race
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
 )
 
 // Error variables for input validation
@@ -15,108 +27,769 @@ var (
 	ErrEmptyRaceID       = errors.New("race ID cannot be empty")
 	ErrInvalidFinishTime = errors.New("finish time must be greater than zero")
 	ErrInvalidAvgHR      = errors.New("average heart rate must be positive")
+	ErrCourseMismatch    = errors.New("distance differs from the length of the course of the race by more than 2%")
 )
 
 // Service implements the raceTracker interface
 type Service struct {
-	repo race.Repository
+	repo                race.Repository
+	courseRepo          race.CourseRepository
+	runnerRepo          runner.Repository
+	registrationRepo    registration.Repository
+	timingRepo          timing.Repository
+	activityParser      activity.Parser
+	notificationService notification.Service
+	logger              logging.Logger
+	metrics             metrics.Recorder
+	now                 func() time.Time
 }
 
-// NewService creates a new Service with the given repository
-func NewService(repo race.Repository) Service {
-	return Service{repo: repo}
+// NewService creates a new Service with the given repositories, activity file parser, notification service,
+// logger and metrics recorder
+func NewService(repo race.Repository, courseRepo race.CourseRepository, runnerRepo runner.Repository, registrationRepo registration.Repository, timingRepo timing.Repository, activityParser activity.Parser, notificationService notification.Service, logger logging.Logger, recorder metrics.Recorder) Service {
+	return Service{repo: repo, courseRepo: courseRepo, runnerRepo: runnerRepo, registrationRepo: registrationRepo, timingRepo: timingRepo, activityParser: activityParser, notificationService: notificationService, logger: logger, metrics: recorder, now: time.Now}
 }
 
-// AddResult logs race data for a participant
-func (s Service) AddResult(runnerID, raceID uuid.UUID, finishTime time.Duration, avgHR int, notes string) (uuid.UUID, error) {
+// SplitItem represents the cumulative time of a result at a distance marker
+type SplitItem struct {
+	DistanceKm float64
+	Elapsed    time.Duration
+}
+
+// AddedResultItem represents a result logged by AddResult.
+// PersonalRecord is set when the result is the fastest of the runner over the race distance.
+type AddedResultItem struct {
+	ID             uuid.UUID
+	PersonalRecord bool
+}
+
+// AddResult logs race data for a participant, with optional splits ordered by distance.
+// The finish time is the net (chip) time the pace is derived from, and the optional gun time runs from the
+// start signal of the wave of the runner, defaulting to the net time.
+// An empty status logs a finished result; runners that did not finish or did not start have no finish time
+// and heart rate, and disqualified runners need a status reason.
+// Races that require registration only accept results of runners with a confirmed registration, and
+// finished results of races with chip timing are generated from the timing reads instead.
+// A result that sets a personal record is flagged and the runner is notified. Cancelled races accept no results.
+// Runners can only log their own results, and organisers the results of their own races.
+func (s Service) AddResult(ctx context.Context, runnerID, raceID uuid.UUID, status, statusReason string, finishTime, gunTime time.Duration, avgHR int, notes string, splits []SplitItem) (AddedResultItem, error) {
 
 	// Validate inputs
 	if runnerID == uuid.Nil {
-		return uuid.Nil, ErrEmptyRunnerID
+		return AddedResultItem{}, ErrEmptyRunnerID
 	}
 	if raceID == uuid.Nil {
-		return uuid.Nil, ErrEmptyRaceID
+		return AddedResultItem{}, ErrEmptyRaceID
 	}
-	if finishTime <= 0 {
-		return uuid.Nil, ErrInvalidFinishTime
+	resultStatus, err := parseStatus(status)
+	if err != nil {
+		return AddedResultItem{}, err
 	}
-	if avgHR <= 0 {
-		return uuid.Nil, ErrInvalidAvgHR
+	if finishTime < 0 || (resultStatus == race.StatusFinished && finishTime == 0) {
+		return AddedResultItem{}, ErrInvalidFinishTime
+	}
+	if avgHR < 0 || (resultStatus == race.StatusFinished && avgHR == 0) {
+		return AddedResultItem{}, ErrInvalidAvgHR
 	}
 
+	return s.addResult(ctx, runnerID, raceID, resultStatus, statusReason, finishTime, gunTime, avgHR, notes, splits)
+}
+
+// addResult logs the validated result of AddResult and ImportResult, see AddResult
+func (s Service) addResult(ctx context.Context, runnerID, raceID uuid.UUID, resultStatus race.Status, statusReason string, finishTime, gunTime time.Duration, avgHR int, notes string, splits []SplitItem) (AddedResultItem, error) {
 	// GetByID race details to calculate PaceMinPerKm
-	raceDetails, err := s.repo.GetRace(raceID)
+	raceDetails, err := s.repo.GetRace(ctx, raceID)
 	if err != nil {
-		return uuid.Nil, err
+		return AddedResultItem{}, err
+	}
+	if err := auth.RequireRunnerOrOrganiser(ctx, runnerID, raceDetails); err != nil {
+		return AddedResultItem{}, err
+	}
+	if raceDetails.IsCancelled() {
+		return AddedResultItem{}, race.ErrRaceCancelled
 	}
 
-	// Calculate PaceMinPerKm (minutes per km)
-	paceMinPerKm := float64(finishTime.Minutes()) / raceDetails.DistanceKm()
+	if err := s.checkRegistration(ctx, runnerID, raceID); err != nil {
+		return AddedResultItem{}, err
+	}
+	if err := s.checkTiming(ctx, raceID, resultStatus); err != nil {
+		return AddedResultItem{}, err
+	}
 
 	// Create and store the race log
-	raceLog, err := race.NewResult(runnerID, raceID, finishTime, paceMinPerKm, avgHR, notes)
+	raceLog, err := race.NewResultWithStatus(runnerID, raceID, resultStatus, statusReason, finishTime, paceOf(finishTime, raceDetails), avgHR, notes)
 	if err != nil {
-		return uuid.Nil, err
+		return AddedResultItem{}, err
 	}
+	if gunTime != 0 {
+		raceLog, err = raceLog.WithGunTime(gunTime)
+		if err != nil {
+			return AddedResultItem{}, err
+		}
+	}
+
+	// Attach the splits, validated against the race distance
+	if len(splits) > 0 {
+		domainSplits := make([]race.Split, len(splits))
+		for i, split := range splits {
+			domainSplits[i], err = race.NewSplit(split.DistanceKm, split.Elapsed)
+			if err != nil {
+				return AddedResultItem{}, err
+			}
+		}
+		raceLog, err = raceLog.WithSplits(domainSplits, raceDetails.DistanceKm())
+		if err != nil {
+			return AddedResultItem{}, err
+		}
+	}
+
+	// The records are compared before saving, so that the new result is not compared with itself
+	records, err := s.personalRecords(ctx, runnerID)
+	if err != nil {
+		return AddedResultItem{}, err
+	}
+	isRecord, current := race.IsPersonalRecord(records, raceLog, raceDetails)
 
 	// Save the race log using the repository
-	err = s.repo.SaveRaceResult(raceLog)
+	err = s.repo.SaveRaceResult(ctx, raceLog)
 	if err != nil {
-		return uuid.Nil, err
+		return AddedResultItem{}, err
 	}
+	s.metrics.ResultLogged(ctx, string(raceLog.Status()))
 
-	return raceLog.RaceID(), nil
+	if isRecord {
+		s.notifyPersonalRecord(ctx, raceLog, raceDetails, current)
+	}
+
+	return AddedResultItem{ID: raceLog.ID(), PersonalRecord: isRecord}, nil
 }
 
-// ResultItem represents
-type ResultItem struct {
-	ID           uuid.UUID
-	RunnerID     uuid.UUID
+// checkRegistration returns registration.ErrNotRegistered when the race requires registration for results
+// and the runner is not confirmed. Races without registration accept results of any runner.
+func (s Service) checkRegistration(ctx context.Context, runnerID, raceID uuid.UUID) error {
+	entryList, err := s.registrationRepo.GetEntryList(ctx, raceID)
+	if errors.Is(err, registration.ErrNotConfigured) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if entryList.RequiredForResults() && !entryList.IsConfirmed(runnerID) {
+		return registration.ErrNotRegistered
+	}
+	return nil
+}
+
+// checkTiming returns timing.ErrResultFromTiming for finished results of races with chip timing.
+// Runners that did not finish, did not start or were disqualified are still recorded by hand.
+func (s Service) checkTiming(ctx context.Context, raceID uuid.UUID, status race.Status) error {
+	if status != race.StatusFinished {
+		return nil
+	}
+	_, err := s.timingRepo.GetSession(ctx, raceID)
+	if errors.Is(err, timing.ErrNotConfigured) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return timing.ErrResultFromTiming
+}
+
+// parseStatus returns the status of a result, defaulting to finished
+func parseStatus(status string) (race.Status, error) {
+	if status == "" {
+		return race.StatusFinished, nil
+	}
+	return race.ParseStatus(status)
+}
+
+// paceOf returns the pace in minutes per km of a net finishTime in the race, which is 0 without a finish time
+func paceOf(finishTime time.Duration, r race.Race) float64 {
+	if finishTime <= 0 {
+		return 0
+	}
+	return finishTime.Minutes() / r.DistanceKm()
+}
+
+// PublishResults publishes the provisional results of the race with the provided id as official and returns
+// the number of results published. Results that are already published are left unchanged.
+func (s Service) PublishResults(ctx context.Context, raceID uuid.UUID, reason string) (int, error) {
+	if raceID == uuid.Nil {
+		return 0, ErrEmptyRaceID
+	}
+
+	r, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return 0, err
+	}
+	if err := auth.RequireOrganiser(ctx, r); err != nil {
+		return 0, err
+	}
+
+	results, err := s.repo.GetResultsByRace(ctx, raceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var published int
+	for _, result := range results {
+		if result.Publication() != race.PublicationProvisional {
+			continue
+		}
+		official, err := result.Publish(reason)
+		if err != nil {
+			return published, err
+		}
+		if err := s.repo.UpdateRaceResult(ctx, official); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// AmendResult corrects the status and the finish time of a published result of the race.
+// The reason of the amendment is required and recorded with the result.
+func (s Service) AmendResult(ctx context.Context, raceID, resultID uuid.UUID, status, statusReason string, finishTime time.Duration, reason string) error {
+	if raceID == uuid.Nil {
+		return ErrEmptyRaceID
+	}
+	if resultID == uuid.Nil {
+		return race.ErrResultNotFound
+	}
+	resultStatus, err := race.ParseStatus(status)
+	if err != nil {
+		return err
+	}
+	if finishTime < 0 {
+		return ErrInvalidFinishTime
+	}
+
+	raceDetails, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireOrganiser(ctx, raceDetails); err != nil {
+		return err
+	}
+
+	result, err := s.repo.GetResult(ctx, resultID)
+	if err != nil {
+		return err
+	}
+	if result.RaceID() != raceID {
+		return race.ErrResultNotFound
+	}
+
+	amended, err := result.Amend(resultStatus, statusReason, finishTime, paceOf(finishTime, raceDetails), reason)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateRaceResult(ctx, amended)
+}
+
+// notifyPersonalRecord notifies the runner of a new personal record.
+// Notification is a best effort operation, so failures are only logged.
+func (s Service) notifyPersonalRecord(ctx context.Context, result race.Result, r race.Race, previous *race.PersonalRecord) {
+	rn, err := s.runnerRepo.GetByID(ctx, result.RunnerID())
+	if err != nil {
+		s.logger.Warn(ctx, "failed to load the runner of a personal record notification",
+			logging.RaceID(r.ID()), logging.RunnerID(result.RunnerID()), logging.Err(err))
+		return
+	}
+
+	distance := race.DistanceOf(r.DistanceKm())
+	message := fmt.Sprintf("You set a new %s personal record of %s at %s.", distance.Name, result.FinishTime(), r.Name())
+	if previous != nil {
+		message += fmt.Sprintf(" Your previous record was %s.", previous.Result.FinishTime())
+	}
+	err = s.notificationService.Notify(ctx, notification.Notification{
+		EmailAddress: rn.EmailAddress(),
+		Subject:      fmt.Sprintf("New %s personal record", distance.Name),
+		Message:      message,
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to send a personal record notification",
+			logging.RaceID(r.ID()), logging.RunnerID(result.RunnerID()), logging.String("result_id", result.ID().String()), logging.Err(err))
+	}
+}
+
+// PersonalRecordItem represents the fastest result of a runner over a distance
+type PersonalRecordItem struct {
+	Distance     string
+	DistanceKm   float64
+	ResultID     uuid.UUID
 	RaceID       uuid.UUID
+	RaceName     string
+	RaceDate     time.Time
 	FinishTime   time.Duration
 	PaceMinPerKm float64
-	HeartRateAvg int
-	Notes        string
 }
 
-// GetRaceResults retrieves race logs for a participant
-func (s Service) GetResults(runnerID uuid.UUID) ([]ResultItem, error) {
+// GetPersonalRecords returns the personal records of the runner with the provided id, ordered by distance.
+// Records are kept for the standard distances and for every other distance the runner has raced.
+func (s Service) GetPersonalRecords(ctx context.Context, runnerID uuid.UUID) ([]PersonalRecordItem, error) {
+	if runnerID == uuid.Nil {
+		return nil, ErrEmptyRunnerID
+	}
+
+	if _, err := s.runnerRepo.GetByID(ctx, runnerID); err != nil {
+		return nil, err
+	}
+
+	records, err := s.personalRecords(ctx, runnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]PersonalRecordItem, len(records))
+	for i, record := range records {
+		items[i] = PersonalRecordItem{
+			Distance:     record.Distance,
+			DistanceKm:   record.DistanceKm,
+			ResultID:     record.Result.ID(),
+			RaceID:       record.Race.ID(),
+			RaceName:     record.Race.Name(),
+			RaceDate:     record.Race.Date(),
+			FinishTime:   record.Result.FinishTime(),
+			PaceMinPerKm: record.Result.Pace(),
+		}
+	}
+	return items, nil
+}
+
+// personalRecords derives the personal records of a runner from the stored results.
+// Results of races that no longer exist are ignored.
+func (s Service) personalRecords(ctx context.Context, runnerID uuid.UUID) ([]race.PersonalRecord, error) {
+	results, err := s.repo.GetRaceResults(ctx, runnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	races := make(map[uuid.UUID]race.Race)
+	for _, result := range results {
+		if _, loaded := races[result.RaceID()]; loaded {
+			continue
+		}
+		r, err := s.repo.GetRace(ctx, result.RaceID())
+		if errors.Is(err, race.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		races[r.ID()] = r
+	}
+
+	return race.NewPersonalRecords(results, races), nil
+}
+
+// AgeGradeItem represents the performance of a result adjusted for the age and the gender of the runner
+type AgeGradeItem struct {
+	Factor     float64
+	Percent    float64
+	GradedTime time.Duration
+}
+
+// ResultItem represents a race result returned by the service.
+// SplitAnalysis is nil when the result has no splits or no finish time, and AgeGrade is nil when the result
+// cannot be age graded because the profile of the runner is incomplete or the distance has no standard.
+type ResultItem struct {
+	ID                uuid.UUID
+	RunnerID          uuid.UUID
+	RaceID            uuid.UUID
+	Status            string
+	StatusReason      string
+	Publication       string
+	PublicationReason string
+	FinishTime        time.Duration
+	GunTime           time.Duration
+	PaceMinPerKm      float64
+	HeartRateAvg      int
+	Notes             string
+	Splits            []SplitItem
+	SplitAnalysis     *SplitAnalysisItem
+	AgeGrade          *AgeGradeItem
+}
+
+// SegmentItem represents the stretch of a race between two consecutive distance markers
+type SegmentItem struct {
+	StartKm      float64
+	EndKm        float64
+	Duration     time.Duration
+	PaceMinPerKm float64
+}
+
+// SplitAnalysisItem represents the metrics derived from the splits of a result
+type SplitAnalysisItem struct {
+	Segments               []SegmentItem
+	FirstHalf              time.Duration
+	SecondHalf             time.Duration
+	SplitPercent           float64
+	NegativeSplit          bool
+	Fastest                SegmentItem
+	Slowest                SegmentItem
+	PaceVariabilityPercent float64
+}
+
+// GetResults retrieves race logs for a participant, optionally only the ones with the provided status
+func (s Service) GetResults(ctx context.Context, runnerID uuid.UUID, status string) ([]ResultItem, error) {
 	if runnerID == uuid.Nil {
 		return nil, ErrEmptyRunnerID
 	}
+	if status != "" {
+		if _, err := race.ParseStatus(status); err != nil {
+			return nil, err
+		}
+	}
 
-	res, err := s.repo.GetRaceResults(runnerID)
+	all, err := s.repo.GetRaceResults(ctx, runnerID)
 	if err != nil {
 		return nil, err
 	}
+	res := make([]race.Result, 0, len(all))
+	for _, r := range all {
+		if status == "" || string(r.Status()) == status {
+			res = append(res, r)
+		}
+	}
 
+	// Age grading needs the profile of the runner, which is not available for deleted runners
+	rn, err := s.runnerRepo.GetByID(ctx, runnerID)
+	if err != nil && !errors.Is(err, runner.ErrNotFound) {
+		return nil, err
+	}
+
+	// The split analysis and the age grade need the race details, so races are loaded once per race
+	races := make(map[uuid.UUID]race.Race)
 	results := make([]ResultItem, len(res))
 	for i, r := range res {
-		results[i] = ResultItem{
-			ID:           r.ID(),
-			RunnerID:     r.RunnerID(),
-			RaceID:       r.RaceID(),
-			FinishTime:   r.FinishTime(),
-			PaceMinPerKm: r.Pace(),
-			HeartRateAvg: r.HeartRateAvg(),
-			Notes:        r.Notes(),
+		results[i] = toResultItem(r)
+		raceDetails, loaded := races[r.RaceID()]
+		if !loaded {
+			raceDetails, err = s.repo.GetRace(ctx, r.RaceID())
+			if err != nil && !errors.Is(err, race.ErrNotFound) {
+				return nil, err
+			}
+			races[r.RaceID()] = raceDetails
+		}
+		if analysis, err := r.SplitAnalysis(raceDetails.DistanceKm()); err == nil {
+			results[i].SplitAnalysis = toSplitAnalysisItem(analysis)
 		}
+		results[i].AgeGrade = ageGradeOf(r, rn, raceDetails)
 	}
 
 	return results, nil
 }
 
-// CreateRace validates and stores a new race
-func (s Service) CreateRace(name, location string, date time.Time, distanceKm, elevationGain float64) (uuid.UUID, error) {
+// ageGradeOf grades the finished result for the runner on the race date, returning nil when it cannot be graded
+func ageGradeOf(result race.Result, rn *runner.Runner, r race.Race) *AgeGradeItem {
+	if rn == nil || !result.IsFinished() {
+		return nil
+	}
+	age, known := rn.AgeOn(r.Date())
+	if !known {
+		return nil
+	}
+	grade, err := result.AgeGrade(r.DistanceKm(), string(rn.Gender()), age)
+	if err != nil {
+		return nil
+	}
+	item := AgeGradeItem(grade)
+	return &item
+}
+
+func toResultItem(r race.Result) ResultItem {
+	item := ResultItem{
+		ID:                r.ID(),
+		RunnerID:          r.RunnerID(),
+		RaceID:            r.RaceID(),
+		Status:            string(r.Status()),
+		StatusReason:      r.StatusReason(),
+		Publication:       string(r.Publication()),
+		PublicationReason: r.PublicationReason(),
+		FinishTime:        r.FinishTime(),
+		GunTime:           r.GunTime(),
+		PaceMinPerKm:      r.Pace(),
+		HeartRateAvg:      r.HeartRateAvg(),
+		Notes:             r.Notes(),
+	}
+	for _, split := range r.Splits() {
+		item.Splits = append(item.Splits, SplitItem{DistanceKm: split.DistanceKm(), Elapsed: split.Elapsed()})
+	}
+	return item
+}
+
+func toSplitAnalysisItem(analysis race.SplitAnalysis) *SplitAnalysisItem {
+	item := &SplitAnalysisItem{
+		Segments:               make([]SegmentItem, len(analysis.Segments)),
+		FirstHalf:              analysis.FirstHalf,
+		SecondHalf:             analysis.SecondHalf,
+		SplitPercent:           analysis.SplitPercent,
+		NegativeSplit:          analysis.IsNegativeSplit(),
+		Fastest:                SegmentItem(analysis.Fastest),
+		Slowest:                SegmentItem(analysis.Slowest),
+		PaceVariabilityPercent: analysis.PaceVariabilityPercent,
+	}
+	for i, segment := range analysis.Segments {
+		item.Segments[i] = SegmentItem(segment)
+	}
+	return item
+}
+
+// WaveItem represents a group of runners released by the same start signal
+type WaveItem struct {
+	Name      string
+	StartTime time.Time
+}
+
+// CreateRace validates and stores a new race with optional start waves.
+// An empty ranking policy ranks the results by gun time.
+// Races created by an organiser are organised by them; races created by an admin have no organiser.
+func (s Service) CreateRace(ctx context.Context, name, location string, date time.Time, distanceKm, elevationGain float64, waves []WaveItem, rankingPolicy string) (uuid.UUID, error) {
+	principal, err := auth.RequireRole(ctx, auth.RoleOrganiser)
+	if err != nil {
+		return uuid.Nil, err
+	}
 	r, err := race.NewRace(name, location, date, distanceKm, elevationGain)
 	if err != nil {
 		return uuid.Nil, err
 	}
+	if principal.Role == auth.RoleOrganiser {
+		r = r.WithOrganiser(principal.Subject)
+	}
+	domainWaves := make([]race.Wave, len(waves))
+	for i, w := range waves {
+		domainWaves[i], err = race.NewWave(w.Name, w.StartTime)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if r, err = r.WithWaves(domainWaves); err != nil {
+		return uuid.Nil, err
+	}
+	if rankingPolicy != "" {
+		if r, err = r.WithRankingPolicy(race.RankingPolicy(rankingPolicy)); err != nil {
+			return uuid.Nil, err
+		}
+	}
 
-	err = s.repo.SaveRace(r)
+	err = s.repo.SaveRace(ctx, r)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
 	return r.ID(), nil
 }
+
+// RaceItem represents a race returned by the service.
+// CancelledAt is zero for races that are not cancelled.
+type RaceItem struct {
+	ID                 uuid.UUID
+	Name               string
+	Location           string
+	Date               time.Time
+	DistanceKm         float64
+	ElevationGain      float64
+	Waves              []WaveItem
+	RankingPolicy      string
+	Organiser          string
+	CancelledAt        time.Time
+	CancellationReason string
+}
+
+// GetRace retrieves the race with the provided id
+func (s Service) GetRace(ctx context.Context, raceID uuid.UUID) (RaceItem, error) {
+	if raceID == uuid.Nil {
+		return RaceItem{}, ErrEmptyRaceID
+	}
+
+	r, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return RaceItem{}, err
+	}
+	return toRaceItem(r), nil
+}
+
+func toRaceItem(r race.Race) RaceItem {
+	item := RaceItem{
+		ID:                 r.ID(),
+		Name:               r.Name(),
+		Location:           r.Location(),
+		Date:               r.Date(),
+		DistanceKm:         r.DistanceKm(),
+		ElevationGain:      r.ElevationGain(),
+		RankingPolicy:      string(r.RankingPolicy()),
+		Organiser:          r.Organiser(),
+		CancelledAt:        r.CancelledAt(),
+		CancellationReason: r.CancellationReason(),
+	}
+	for _, w := range r.Waves() {
+		item.Waves = append(item.Waves, WaveItem{Name: w.Name(), StartTime: w.StartTime()})
+	}
+	return item
+}
+
+// StandingItem represents a result of a race leaderboard.
+// Runners that did not finish are listed after the finishers with all their positions set to 0.
+type StandingItem struct {
+	Position         int
+	GenderPosition   int
+	AgeGroupPosition int
+	ResultID         uuid.UUID
+	RunnerID         uuid.UUID
+	RunnerName       string
+	Gender           string
+	AgeGroup         string
+	Status           string
+	FinishTime       time.Duration
+	GunTime          time.Duration
+	PaceMinPerKm     float64
+	AgeGrade         *AgeGradeItem
+}
+
+// GetLeaderboard ranks the finished results of the race with the provided id by the gun or the net time,
+// following the ranking policy of the race.
+// Runners that no longer exist are still ranked overall but not in the gender and age-group categories.
+func (s Service) GetLeaderboard(ctx context.Context, raceID uuid.UUID) ([]StandingItem, error) {
+	if raceID == uuid.Nil {
+		return nil, ErrEmptyRaceID
+	}
+
+	r, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.GetResultsByRace(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+
+	//Load the runners of every result in one batch rather than one lookup per finisher
+	runnerIDs := make([]uuid.UUID, 0, len(results))
+	seen := make(map[uuid.UUID]bool, len(results))
+	for _, result := range results {
+		if !seen[result.RunnerID()] {
+			seen[result.RunnerID()] = true
+			runnerIDs = append(runnerIDs, result.RunnerID())
+		}
+	}
+	found, err := s.runnerRepo.GetByIDs(ctx, runnerIDs)
+	if err != nil {
+		return nil, err
+	}
+	runners := make(map[uuid.UUID]*runner.Runner, len(found))
+	divisions := make(map[uuid.UUID]race.Division, len(found))
+	for _, rn := range found {
+		runners[rn.ID()] = rn
+		divisions[rn.ID()] = divisionOf(rn, r.Date())
+	}
+
+	standings := race.NewLeaderboard(results, divisions, r.RankingPolicy())
+	items := make([]StandingItem, len(standings))
+	for i, standing := range standings {
+		rn := runners[standing.Result.RunnerID()]
+		var name string
+		if rn != nil {
+			name = rn.Name()
+		}
+		items[i] = StandingItem{
+			Position:         standing.OverallPosition,
+			GenderPosition:   standing.GenderPosition,
+			AgeGroupPosition: standing.AgeGroupPosition,
+			ResultID:         standing.Result.ID(),
+			RunnerID:         standing.Result.RunnerID(),
+			RunnerName:       name,
+			Gender:           standing.Division.Gender,
+			AgeGroup:         standing.Division.AgeGroup,
+			Status:           string(standing.Result.Status()),
+			FinishTime:       standing.Result.FinishTime(),
+			GunTime:          standing.Result.GunTime(),
+			PaceMinPerKm:     standing.Result.Pace(),
+			AgeGrade:         ageGradeOf(standing.Result, rn, r),
+		}
+	}
+
+	return items, nil
+}
+
+// divisionOf returns the categories of the runner on the race date
+func divisionOf(rn *runner.Runner, raceDate time.Time) race.Division {
+	division := race.Division{Gender: string(rn.Gender())}
+	if age, known := rn.AgeOn(raceDate); known {
+		division.AgeGroup = race.AgeGroup(age)
+	}
+	return division
+}
+
+// DistanceMismatchTolerance is the relative difference between the recorded and the race distance
+// above which an imported result is flagged
+const DistanceMismatchTolerance = 0.05
+
+// ImportedResultItem represents a result created from an activity file
+type ImportedResultItem struct {
+	ResultID         uuid.UUID
+	PersonalRecord   bool
+	FinishTime       time.Duration
+	HeartRateAvg     int
+	Splits           []SplitItem
+	RecordedKm       float64
+	RaceDistanceKm   float64
+	DistanceMismatch bool
+}
+
+// ImportResult creates the result of a runner from an activity file recorded during the race.
+// Splits recorded past the race distance are dropped, and the result is flagged when the recorded
+// distance differs from the race distance by more than DistanceMismatchTolerance.
+// Unlike AddResult, the average heart rate is optional: activities without heart rate samples store none.
+func (s Service) ImportResult(ctx context.Context, runnerID, raceID uuid.UUID, format activity.Format, file io.Reader, notes string) (ImportedResultItem, error) {
+	if runnerID == uuid.Nil {
+		return ImportedResultItem{}, ErrEmptyRunnerID
+	}
+	if raceID == uuid.Nil {
+		return ImportedResultItem{}, ErrEmptyRaceID
+	}
+
+	raceDetails, err := s.repo.GetRace(ctx, raceID)
+	if err != nil {
+		return ImportedResultItem{}, err
+	}
+
+	recorded, err := s.activityParser.Parse(ctx, format, file)
+	if err != nil {
+		return ImportedResultItem{}, err
+	}
+
+	var splits []SplitItem
+	for _, split := range recorded.Splits {
+		if split.DistanceKm < raceDetails.DistanceKm() && split.Elapsed < recorded.Duration {
+			splits = append(splits, SplitItem{DistanceKm: split.DistanceKm, Elapsed: split.Elapsed})
+		}
+	}
+
+	//Activities recorded without a heart rate monitor have no heart rate, which is optional for imported results
+	if recorded.Duration <= 0 {
+		return ImportedResultItem{}, ErrInvalidFinishTime
+	}
+	if recorded.HeartRateAvg < 0 {
+		return ImportedResultItem{}, ErrInvalidAvgHR
+	}
+	added, err := s.addResult(ctx, runnerID, raceID, race.StatusFinished, "", recorded.Duration, 0, recorded.HeartRateAvg, notes, splits)
+	if err != nil {
+		return ImportedResultItem{}, err
+	}
+
+	difference := math.Abs(recorded.DistanceKm-raceDetails.DistanceKm()) / raceDetails.DistanceKm()
+	return ImportedResultItem{
+		ResultID:         added.ID,
+		PersonalRecord:   added.PersonalRecord,
+		FinishTime:       recorded.Duration,
+		HeartRateAvg:     recorded.HeartRateAvg,
+		Splits:           splits,
+		RecordedKm:       recorded.DistanceKm,
+		RaceDistanceKm:   raceDetails.DistanceKm(),
+		DistanceMismatch: difference > DistanceMismatchTolerance,
+	}, nil
+}
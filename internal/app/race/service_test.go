@@ -1,11 +1,21 @@
 package race
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,39 +25,134 @@ type mockRaceRepository struct {
 	mock.Mock
 }
 
-func (m *mockRaceRepository) SaveRace(race race.Race) error {
+func (m *mockRaceRepository) SaveRace(_ context.Context, race race.Race) error {
 	args := m.Called(race)
 	return args.Error(0)
 }
 
-func (m *mockRaceRepository) GetRace(raceID uuid.UUID) (race.Race, error) {
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
 	args := m.Called(raceID)
 	return args.Get(0).(race.Race), args.Error(1)
 }
 
-func (m *mockRaceRepository) SaveRaceResult(raceLog race.Result) error {
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, raceLog race.Result) error {
 	args := m.Called(raceLog)
 	return args.Error(0)
 }
 
-func (m *mockRaceRepository) GetRaceResults(runnerID uuid.UUID) ([]race.Result, error) {
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
 	args := m.Called(runnerID)
 	return args.Get(0).([]race.Result), args.Error(1)
 }
 
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+type mockRegistrationRepository struct {
+	mock.Mock
+}
+
+func (m *mockRegistrationRepository) GetEntryList(_ context.Context, raceID uuid.UUID) (*registration.EntryList, error) {
+	args := m.Called(raceID)
+	l, _ := args.Get(0).(*registration.EntryList)
+	return l, args.Error(1)
+}
+
+func (m *mockRegistrationRepository) SaveEntryList(_ context.Context, l *registration.EntryList) error {
+	return m.Called(l).Error(0)
+}
+
+// noRegistration returns a registration repository of races without registration
+func noRegistration() *mockRegistrationRepository {
+	m := new(mockRegistrationRepository)
+	m.On("GetEntryList", mock.Anything).Return(nil, registration.ErrNotConfigured)
+	return m
+}
+
+type mockTimingRepository struct {
+	mock.Mock
+}
+
+func (m *mockTimingRepository) GetSession(_ context.Context, raceID uuid.UUID) (*timing.Session, error) {
+	args := m.Called(raceID)
+	s, _ := args.Get(0).(*timing.Session)
+	return s, args.Error(1)
+}
+
+func (m *mockTimingRepository) SaveSession(_ context.Context, s *timing.Session) error {
+	return m.Called(s).Error(0)
+}
+
+// noTiming returns a timing repository of races without chip timing
+func noTiming() *mockTimingRepository {
+	m := new(mockTimingRepository)
+	m.On("GetSession", mock.Anything).Return(nil, timing.ErrNotConfigured)
+	return m
+}
+
+type mockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *mockCourseRepository) SaveCourse(_ context.Context, raceID uuid.UUID, course race.Course) error {
+	return m.Called(raceID, course).Error(0)
+}
+
+func (m *mockCourseRepository) GetCourse(_ context.Context, raceID uuid.UUID) (race.Course, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Course), args.Error(1)
+}
+
+func noCourse() *mockCourseRepository {
+	m := new(mockCourseRepository)
+	m.On("GetCourse", mock.Anything).Return(race.Course{}, race.ErrCourseNotFound)
+	return m
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
+}
+
 func TestService_LogRace(t *testing.T) {
 	mockRepo := new(mockRaceRepository)
-	service := NewService(mockRepo)
+	mockRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{}, nil)
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", mock.Anything).Return(nil, runner.ErrNotFound)
+	service := NewService(mockRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
 
 	tests := []struct {
-		name       string
-		runnerID   uuid.UUID
-		raceID     uuid.UUID
-		finishTime time.Duration
-		avgHR      int
-		notes      string
-		mockSetup  func()
-		wantErr    error
+		name         string
+		runnerID     uuid.UUID
+		raceID       uuid.UUID
+		status       string
+		statusReason string
+		finishTime   time.Duration
+		gunTime      time.Duration
+		avgHR        int
+		notes        string
+		splits       []SplitItem
+		mockSetup    func()
+		wantErr      error
 	}{
 		{
 			name:       "valid input",
@@ -73,65 +178,1187 @@ func TestService_LogRace(t *testing.T) {
 			mockSetup:  func() {},
 			wantErr:    ErrEmptyRunnerID,
 		},
-		// Add more test cases as needed
+		{
+			name:       "valid splits",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			finishTime: 30 * time.Minute,
+			avgHR:      150,
+			splits:     []SplitItem{{DistanceKm: 0.5, Elapsed: 14 * time.Minute}, {DistanceKm: 1.0, Elapsed: 30 * time.Minute}},
+			mockSetup:  func() {},
+			wantErr:    nil,
+		},
+		{
+			name:       "gun time after the net time",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			finishTime: 30 * time.Minute,
+			gunTime:    31 * time.Minute,
+			avgHR:      150,
+			mockSetup:  func() {},
+			wantErr:    nil,
+		},
+		{
+			name:       "gun time before the net time",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			finishTime: 30 * time.Minute,
+			gunTime:    29 * time.Minute,
+			avgHR:      150,
+			mockSetup:  func() {},
+			wantErr:    race.ErrInvalidGunTime,
+		},
+		{
+			name:       "split after the finish time",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			finishTime: 30 * time.Minute,
+			avgHR:      150,
+			splits:     []SplitItem{{DistanceKm: 0.5, Elapsed: 31 * time.Minute}},
+			mockSetup:  func() {},
+			wantErr:    race.ErrSplitBeyondFinishTime,
+		},
+		{
+			name:       "invalid split distance",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			finishTime: 30 * time.Minute,
+			avgHR:      150,
+			splits:     []SplitItem{{DistanceKm: 0, Elapsed: 10 * time.Minute}},
+			mockSetup:  func() {},
+			wantErr:    race.ErrInvalidSplitDistance,
+		},
+		{
+			name:      "did not finish without finish time and heart rate",
+			runnerID:  uuid.New(),
+			raceID:    uuid.New(),
+			status:    "dnf",
+			splits:    []SplitItem{{DistanceKm: 0.5, Elapsed: 14 * time.Minute}},
+			mockSetup: func() {},
+			wantErr:   nil,
+		},
+		{
+			name:       "did not finish with finish time",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			status:     "dnf",
+			finishTime: 30 * time.Minute,
+			mockSetup:  func() {},
+			wantErr:    race.ErrUnexpectedFinishTime,
+		},
+		{
+			name:         "disqualified with finish time",
+			runnerID:     uuid.New(),
+			raceID:       uuid.New(),
+			status:       "dsq",
+			statusReason: "course cutting",
+			finishTime:   30 * time.Minute,
+			avgHR:        150,
+			mockSetup:    func() {},
+			wantErr:      nil,
+		},
+		{
+			name:       "disqualified without reason",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			status:     "dsq",
+			finishTime: 30 * time.Minute,
+			mockSetup:  func() {},
+			wantErr:    race.ErrMissingStatusReason,
+		},
+		{
+			name:      "finished without finish time",
+			runnerID:  uuid.New(),
+			raceID:    uuid.New(),
+			status:    "finished",
+			avgHR:     150,
+			mockSetup: func() {},
+			wantErr:   ErrInvalidFinishTime,
+		},
+		{
+			name:       "unknown status",
+			runnerID:   uuid.New(),
+			raceID:     uuid.New(),
+			status:     "retired",
+			finishTime: 30 * time.Minute,
+			avgHR:      150,
+			mockSetup:  func() {},
+			wantErr:    race.ErrInvalidStatus,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			_, err := service.AddResult(tt.runnerID, tt.raceID, tt.finishTime, tt.avgHR, tt.notes)
+			_, err := service.AddResult(adminContext(), tt.runnerID, tt.raceID, tt.status, tt.statusReason, tt.finishTime, tt.gunTime, tt.avgHR, tt.notes, tt.splits)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
-func TestService_GetRaceResults(t *testing.T) {
+func TestService_AddResult_PaceFromNetTime(t *testing.T) {
+	r, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 50)
 	mockRepo := new(mockRaceRepository)
-	service := NewService(mockRepo)
-	result1, _ := race.NewResult(uuid.New(), uuid.New(), 30*time.Minute, 5.0, 150, "First race")
+	mockRepo.On("GetRace", r.ID()).Return(r, nil)
+	mockRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{}, nil)
+	mockRepo.On("SaveRaceResult", mock.MatchedBy(func(result race.Result) bool {
+		return result.FinishTime() == 40*time.Minute && result.GunTime() == 42*time.Minute && result.Pace() == 4
+	})).Return(nil)
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", mock.Anything).Return(nil, runner.ErrNotFound)
+	recorder := new(metrics.MockRecorder)
+	recorder.On("ResultLogged", "finished").Return()
+	service := NewService(mockRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, recorder)
+
+	_, err := service.AddResult(adminContext(), uuid.New(), r.ID(), "", "", 40*time.Minute, 42*time.Minute, 150, "", nil)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	recorder.AssertExpectations(t)
+}
+
+func TestService_AddResult_RaceNotFound(t *testing.T) {
+	mockRepo := new(mockRaceRepository)
+	service := NewService(mockRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+	mockRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+
+	_, err := service.AddResult(adminContext(), uuid.New(), uuid.New(), "", "", 30*time.Minute, 0, 150, "Good race", nil)
+	assert.ErrorIs(t, err, race.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+}
+
+func TestService_AddResult_Registration(t *testing.T) {
+	r, _ := race.NewRace("Athens Marathon", "Athens", time.Now(), 42.195, 250)
+	opensAt := time.Now().Add(-time.Hour)
+	confirmed, waitlisted := uuid.New(), uuid.New()
+	newEntryList := func(requiredForResults bool) *registration.EntryList {
+		l, _ := registration.NewEntryList(r.ID(), 1, opensAt, opensAt.Add(2*time.Hour), requiredForResults)
+		_, _ = l.Register(confirmed, opensAt)
+		_, _ = l.Register(waitlisted, opensAt)
+		return l
+	}
 
 	tests := []struct {
 		name      string
+		entryList *registration.EntryList
+		listErr   error
 		runnerID  uuid.UUID
-		mockSetup func()
 		wantErr   error
-		expected  []ResultItem
+	}{
+		{name: "registration not configured", listErr: registration.ErrNotConfigured, runnerID: uuid.New()},
+		{name: "registration not required", entryList: newEntryList(false), runnerID: uuid.New()},
+		{name: "confirmed runner", entryList: newEntryList(true), runnerID: confirmed},
+		{name: "waitlisted runner", entryList: newEntryList(true), runnerID: waitlisted, wantErr: registration.ErrNotRegistered},
+		{name: "unregistered runner", entryList: newEntryList(true), runnerID: uuid.New(), wantErr: registration.ErrNotRegistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			raceRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{}, nil)
+			raceRepo.On("SaveRaceResult", mock.Anything).Return(nil)
+			registrationRepo := new(mockRegistrationRepository)
+			registrationRepo.On("GetEntryList", r.ID()).Return(tt.entryList, tt.listErr)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", mock.Anything).Return(nil, runner.ErrNotFound)
+			service := NewService(raceRepo, noCourse(), runnerRepo, registrationRepo, noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			_, err := service.AddResult(adminContext(), tt.runnerID, r.ID(), "", "", 3*time.Hour, 0, 150, "", nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_AddResult_Timing(t *testing.T) {
+	r, _ := race.NewRace("Athens 10K", "Athens", time.Now(), 10, 50)
+	finish, _ := timing.NewCheckpoint("finish", 10)
+	session, err := timing.NewSession(r.ID(), time.Now(), 0, []timing.Checkpoint{finish}, 10)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		session    *timing.Session
+		sessionErr error
+		status     string
+		finishTime time.Duration
+		avgHR      int
+		wantErr    error
+	}{
+		{name: "untimed race", sessionErr: timing.ErrNotConfigured, finishTime: 40 * time.Minute, avgHR: 150},
+		{name: "finished result of a timed race", session: session, finishTime: 40 * time.Minute, avgHR: 150, wantErr: timing.ErrResultFromTiming},
+		{name: "did not finish in a timed race", session: session, status: string(race.StatusDidNotFinish)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			raceRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{}, nil)
+			raceRepo.On("SaveRaceResult", mock.Anything).Return(nil)
+			timingRepo := new(mockTimingRepository)
+			timingRepo.On("GetSession", r.ID()).Return(tt.session, tt.sessionErr)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", mock.Anything).Return(nil, runner.ErrNotFound)
+			service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), timingRepo, new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			_, err := service.AddResult(adminContext(), uuid.New(), r.ID(), tt.status, "", tt.finishTime, 0, tt.avgHR, "", nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_CreateRace(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	waves := []WaveItem{{Name: "B", StartTime: date.Add(10 * time.Minute)}, {Name: "A", StartTime: date}}
+
+	tests := []struct {
+		name          string
+		waves         []WaveItem
+		rankingPolicy string
+		wantPolicy    race.RankingPolicy
+		wantErr       error
+	}{
+		{name: "single start ranked by gun time", wantPolicy: race.RankingGunTime},
+		{name: "waves ranked by net time", waves: waves, rankingPolicy: "net", wantPolicy: race.RankingNetTime},
+		{name: "duplicate wave", waves: []WaveItem{waves[0], waves[0]}, wantErr: race.ErrDuplicateWave},
+		{name: "wave without start", waves: []WaveItem{{Name: "A"}}, wantErr: race.ErrMissingWaveStart},
+		{name: "unknown ranking policy", rankingPolicy: "chip", wantErr: race.ErrInvalidRankingPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mockRaceRepository)
+			mockRepo.On("SaveRace", mock.Anything).Return(nil)
+			service := NewService(mockRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			id, err := service.CreateRace(adminContext(), "City 10K", "Athens", date, 10, 50, tt.waves, tt.rankingPolicy)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "SaveRace", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			saved := mockRepo.Calls[0].Arguments.Get(0).(race.Race)
+			assert.Equal(t, id, saved.ID())
+			assert.Equal(t, tt.wantPolicy, saved.RankingPolicy())
+			assert.Len(t, saved.Waves(), len(tt.waves))
+		})
+	}
+}
+
+func TestService_GetRaceResults(t *testing.T) {
+	mockRepo := new(mockRaceRepository)
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", mock.Anything).Return(nil, runner.ErrNotFound)
+	service := NewService(mockRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+	result1, _ := race.NewResult(uuid.New(), uuid.New(), 30*time.Minute, 5.0, 150, "First race")
+	dnf, _ := race.NewResultWithStatus(result1.RunnerID(), result1.RaceID(), race.StatusDidNotFinish, "cramps", 0, 0, 0, "")
+	mockRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{result1, dnf}, nil)
+	mockRepo.On("GetRace", result1.RaceID()).Return(race.Race{}, race.ErrNotFound)
+	finished := ResultItem{
+		ID:           result1.ID(),
+		RunnerID:     result1.RunnerID(),
+		RaceID:       result1.RaceID(),
+		Status:       "finished",
+		Publication:  "provisional",
+		FinishTime:   result1.FinishTime(),
+		GunTime:      result1.GunTime(),
+		PaceMinPerKm: result1.Pace(),
+		HeartRateAvg: result1.HeartRateAvg(),
+		Notes:        result1.Notes(),
+	}
+	didNotFinish := ResultItem{
+		ID:           dnf.ID(),
+		RunnerID:     dnf.RunnerID(),
+		RaceID:       dnf.RaceID(),
+		Status:       "dnf",
+		StatusReason: "cramps",
+		Publication:  "provisional",
+	}
+
+	tests := []struct {
+		name     string
+		runnerID uuid.UUID
+		status   string
+		wantErr  error
+		expected []ResultItem
 	}{
 		{
 			name:     "valid input",
 			runnerID: uuid.New(),
-			mockSetup: func() {
-				mockRepo.On("GetRaceResults", mock.Anything).Return([]race.Result{result1}, nil)
+			wantErr:  nil,
+			expected: []ResultItem{finished, didNotFinish},
+		},
+		{
+			name:     "filter by status",
+			runnerID: uuid.New(),
+			status:   "dnf",
+			expected: []ResultItem{didNotFinish},
+		},
+		{
+			name:     "filter by status without results",
+			runnerID: uuid.New(),
+			status:   "dsq",
+			expected: []ResultItem{},
+		},
+		{
+			name:     "invalid status",
+			runnerID: uuid.New(),
+			status:   "retired",
+			wantErr:  race.ErrInvalidStatus,
+		},
+		{
+			name:     "empty RunnerID",
+			runnerID: uuid.Nil,
+			wantErr:  ErrEmptyRunnerID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := service.GetResults(adminContext(), tt.runnerID, tt.status)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.expected, res)
+			}
+		})
+	}
+}
+
+func TestService_GetRaceResults_SplitAnalysis(t *testing.T) {
+	r, _ := race.NewRace("Park Run", "Athens", time.Now(), 2, 0)
+	runnerID := uuid.New()
+	first, _ := race.NewSplit(1, 6*time.Minute)
+	result, _ := race.NewResult(runnerID, r.ID(), 10*time.Minute, 5.0, 150, "")
+	result, _ = result.WithSplits([]race.Split{first}, r.DistanceKm())
+	withoutSplits, _ := race.NewResult(runnerID, uuid.New(), 10*time.Minute, 5.0, 150, "")
+
+	mockRepo := new(mockRaceRepository)
+	mockRepo.On("GetRaceResults", runnerID).Return([]race.Result{result, withoutSplits}, nil)
+	mockRepo.On("GetRace", r.ID()).Return(r, nil).Once()
+	mockRepo.On("GetRace", withoutSplits.RaceID()).Return(race.Race{}, race.ErrNotFound).Once()
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", runnerID).Return(nil, runner.ErrNotFound)
+	service := NewService(mockRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	res, err := service.GetResults(adminContext(), runnerID, "")
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, []SplitItem{{DistanceKm: 1, Elapsed: 6 * time.Minute}}, res[0].Splits)
+	assert.Equal(t, &SplitAnalysisItem{
+		Segments: []SegmentItem{
+			{StartKm: 0, EndKm: 1, Duration: 6 * time.Minute, PaceMinPerKm: 6},
+			{StartKm: 1, EndKm: 2, Duration: 4 * time.Minute, PaceMinPerKm: 4},
+		},
+		FirstHalf:              6 * time.Minute,
+		SecondHalf:             4 * time.Minute,
+		SplitPercent:           res[0].SplitAnalysis.SplitPercent,
+		NegativeSplit:          true,
+		Fastest:                SegmentItem{StartKm: 1, EndKm: 2, Duration: 4 * time.Minute, PaceMinPerKm: 4},
+		Slowest:                SegmentItem{StartKm: 0, EndKm: 1, Duration: 6 * time.Minute, PaceMinPerKm: 6},
+		PaceVariabilityPercent: 20,
+	}, res[0].SplitAnalysis)
+	assert.InDelta(t, -33.33, res[0].SplitAnalysis.SplitPercent, 0.01)
+	assert.Nil(t, res[1].SplitAnalysis)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_GetRaceResults_AgeGrade(t *testing.T) {
+	raceDate := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
+	tenK, _ := race.NewRace("City 10K", "Athens", raceDate, 10, 0)
+	trail, _ := race.NewRace("Trail", "Parnitha", raceDate, 60, 2000)
+	rn, _ := runner.NewRunner("Anna", "anna@example.com")
+	_ = rn.UpdateProfile(runner.GenderFemale, time.Date(1974, 6, 1, 0, 0, 0, 0, time.UTC))
+	tenKResult, _ := race.NewResult(rn.ID(), tenK.ID(), 45*time.Minute, 4.5, 150, "")
+	trailResult, _ := race.NewResult(rn.ID(), trail.ID(), 8*time.Hour, 8, 140, "")
+
+	mockRepo := new(mockRaceRepository)
+	mockRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{tenKResult, trailResult}, nil)
+	mockRepo.On("GetRace", tenK.ID()).Return(tenK, nil)
+	mockRepo.On("GetRace", trail.ID()).Return(trail, nil)
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+	service := NewService(mockRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	res, err := service.GetResults(adminContext(), rn.ID(), "")
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	grade, _ := race.NewAgeGrade(45*time.Minute, 10, "female", 50)
+	assert.Equal(t, &AgeGradeItem{Factor: grade.Factor, Percent: grade.Percent, GradedTime: grade.GradedTime}, res[0].AgeGrade)
+	assert.Nil(t, res[1].AgeGrade)
+}
+
+func TestService_GetLeaderboard(t *testing.T) {
+	raceDate := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
+	r, _ := race.NewRace("Athens Marathon", "Athens", raceDate, 42.195, 250)
+
+	anna, _ := runner.NewRunner("Anna", "anna@example.com")
+	_ = anna.UpdateProfile(runner.GenderFemale, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
+	bob, _ := runner.NewRunner("Bob", "bob@example.com")
+	_ = bob.UpdateProfile(runner.GenderMale, time.Time{})
+	deletedRunnerID := uuid.New()
+	errRepository := errors.New("repository error")
+
+	annaResult, _ := race.NewResult(anna.ID(), r.ID(), 3*time.Hour, 4.3, 150, "")
+	bobResult, _ := race.NewResult(bob.ID(), r.ID(), 3*time.Hour, 4.3, 150, "")
+	deletedResult, _ := race.NewResult(deletedRunnerID, r.ID(), 4*time.Hour, 5.7, 150, "")
+	carl, _ := runner.NewRunner("Carl", "carl@example.com")
+	carlResult, _ := race.NewResultWithStatus(carl.ID(), r.ID(), race.StatusDisqualified, "course cutting", 2*time.Hour, 2.8, 150, "")
+	grade, _ := race.NewAgeGrade(3*time.Hour, 42.195, "female", 34)
+	annaGrade := &AgeGradeItem{Factor: grade.Factor, Percent: grade.Percent, GradedTime: grade.GradedTime}
+
+	tests := []struct {
+		name      string
+		raceID    uuid.UUID
+		mockSetup func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository)
+		wantErr   error
+		expected  []StandingItem
+	}{
+		{
+			name:   "ranks results with categories",
+			raceID: r.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				raceRepo.On("GetRace", r.ID()).Return(r, nil)
+				raceRepo.On("GetResultsByRace", r.ID()).Return([]race.Result{carlResult, deletedResult, bobResult, annaResult}, nil)
+				runnerRepo.On("GetByIDs", []uuid.UUID{carl.ID(), deletedRunnerID, bob.ID(), anna.ID()}).Return([]*runner.Runner{anna, bob, carl}, nil)
 			},
-			wantErr: nil,
-			expected: []ResultItem{
-				{
-					ID:           result1.ID(),
-					RunnerID:     result1.RunnerID(),
-					RaceID:       result1.RaceID(),
-					FinishTime:   result1.FinishTime(),
-					PaceMinPerKm: result1.Pace(),
-					HeartRateAvg: result1.HeartRateAvg(),
-					Notes:        result1.Notes(),
+			expected: func() []StandingItem {
+				first, second := annaResult, bobResult
+				if bobResult.LoggedAt().Before(annaResult.LoggedAt()) {
+					first, second = bobResult, annaResult
+				}
+				items := map[uuid.UUID]StandingItem{
+					annaResult.ID(): {Position: 1, GenderPosition: 1, AgeGroupPosition: 1, ResultID: annaResult.ID(), RunnerID: anna.ID(), RunnerName: "Anna", Gender: "female", AgeGroup: "30-34", Status: "finished", FinishTime: 3 * time.Hour, GunTime: 3 * time.Hour, PaceMinPerKm: 4.3, AgeGrade: annaGrade},
+					bobResult.ID():  {Position: 1, GenderPosition: 1, ResultID: bobResult.ID(), RunnerID: bob.ID(), RunnerName: "Bob", Gender: "male", Status: "finished", FinishTime: 3 * time.Hour, GunTime: 3 * time.Hour, PaceMinPerKm: 4.3},
+				}
+				return []StandingItem{
+					items[first.ID()],
+					items[second.ID()],
+					{Position: 3, ResultID: deletedResult.ID(), RunnerID: deletedRunnerID, Status: "finished", FinishTime: 4 * time.Hour, GunTime: 4 * time.Hour, PaceMinPerKm: 5.7},
+					{ResultID: carlResult.ID(), RunnerID: carl.ID(), RunnerName: "Carl", Status: "dsq", FinishTime: 2 * time.Hour, GunTime: 2 * time.Hour, PaceMinPerKm: 2.8},
+				}
+			}(),
+		},
+		{
+			name:   "runner lookup fails",
+			raceID: r.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				raceRepo.On("GetRace", r.ID()).Return(r, nil)
+				raceRepo.On("GetResultsByRace", r.ID()).Return([]race.Result{annaResult}, nil)
+				runnerRepo.On("GetByIDs", []uuid.UUID{anna.ID()}).Return(nil, errRepository)
+			},
+			wantErr: errRepository,
+		},
+		{
+			name:   "race not found",
+			raceID: uuid.New(),
+			mockSetup: func(raceRepo *mockRaceRepository, _ *runner.MockRepository) {
+				raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+			},
+			wantErr: race.ErrNotFound,
+		},
+		{
+			name:      "empty RaceID",
+			raceID:    uuid.Nil,
+			mockSetup: func(*mockRaceRepository, *runner.MockRepository) {},
+			wantErr:   ErrEmptyRaceID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			runnerRepo := new(runner.MockRepository)
+			tt.mockSetup(raceRepo, runnerRepo)
+			service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			res, err := service.GetLeaderboard(adminContext(), tt.raceID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.expected, res)
+			}
+		})
+	}
+}
+
+func TestService_ImportResult(t *testing.T) {
+	r, _ := race.NewRace("Park Run", "Athens", time.Now(), 5, 0)
+	runnerID := uuid.New()
+
+	tests := []struct {
+		name         string
+		activity     activity.Activity
+		parseErr     error
+		wantErr      error
+		wantSplits   []SplitItem
+		wantMismatch bool
+	}{
+		{
+			name: "drops splits past the race distance",
+			activity: activity.Activity{
+				Duration:     25 * time.Minute,
+				DistanceKm:   5.04,
+				HeartRateAvg: 160,
+				Splits: []activity.Split{
+					{DistanceKm: 1, Elapsed: 5 * time.Minute},
+					{DistanceKm: 2, Elapsed: 10 * time.Minute},
+					{DistanceKm: 3, Elapsed: 15 * time.Minute},
+					{DistanceKm: 4, Elapsed: 20 * time.Minute},
+					{DistanceKm: 5, Elapsed: 24*time.Minute + 50*time.Second},
 				},
 			},
+			wantSplits: []SplitItem{
+				{DistanceKm: 1, Elapsed: 5 * time.Minute},
+				{DistanceKm: 2, Elapsed: 10 * time.Minute},
+				{DistanceKm: 3, Elapsed: 15 * time.Minute},
+				{DistanceKm: 4, Elapsed: 20 * time.Minute},
+			},
+		},
+		{
+			name: "flags distance mismatch",
+			activity: activity.Activity{
+				Duration:     25 * time.Minute,
+				DistanceKm:   4.5,
+				HeartRateAvg: 160,
+			},
+			wantMismatch: true,
+		},
+		{
+			name:     "parser error",
+			parseErr: activity.ErrInvalidFile,
+			wantErr:  activity.ErrInvalidFile,
+		},
+		{
+			name: "stores activities without heart rate",
+			activity: activity.Activity{
+				Duration:   25 * time.Minute,
+				DistanceKm: 5,
+			},
+		},
+		{
+			name: "activity without duration",
+			activity: activity.Activity{
+				DistanceKm:   5,
+				HeartRateAvg: 160,
+			},
+			wantErr: ErrInvalidFinishTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			var saved race.Result
+			raceRepo.On("SaveRaceResult", mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(0).(race.Result)
+			}).Return(nil)
+			raceRepo.On("GetRaceResults", runnerID).Return([]race.Result{}, nil)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", runnerID).Return(nil, runner.ErrNotFound)
+			parser := new(activity.MockParser)
+			parser.On("Parse", activity.FormatGPX).Return(tt.activity, tt.parseErr)
+			service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), parser, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			imported, err := service.ImportResult(adminContext(), runnerID, r.ID(), activity.FormatGPX, nil, "")
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+				return
+			}
+			assert.NotEqual(t, uuid.Nil, imported.ResultID)
+			assert.Equal(t, tt.activity.Duration, imported.FinishTime)
+			assert.Equal(t, tt.activity.HeartRateAvg, imported.HeartRateAvg)
+			assert.Equal(t, imported.ResultID, saved.ID())
+			assert.Equal(t, tt.activity.HeartRateAvg, saved.HeartRateAvg())
+			assert.Equal(t, tt.activity.DistanceKm, imported.RecordedKm)
+			assert.Equal(t, 5.0, imported.RaceDistanceKm)
+			assert.Equal(t, tt.wantSplits, imported.Splits)
+			assert.Equal(t, tt.wantMismatch, imported.DistanceMismatch)
+			assert.True(t, imported.PersonalRecord)
+		})
+	}
+}
+
+func TestService_AddResult_PersonalRecord(t *testing.T) {
+	rn, _ := runner.NewRunner("Anna", "anna@example.com")
+	tenK, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 0)
+	otherTenK, _ := race.NewRace("Coastal 10K", "Athens", time.Now(), 10.05, 0)
+	half, _ := race.NewRace("Half", "Athens", time.Now(), 21.1, 0)
+	previousTenK, _ := race.NewResult(rn.ID(), otherTenK.ID(), 45*time.Minute, 4.5, 150, "")
+	previousHalf, _ := race.NewResult(rn.ID(), half.ID(), 100*time.Minute, 4.7, 150, "")
+	deletedRace, _ := race.NewResult(rn.ID(), uuid.New(), 30*time.Minute, 3, 150, "")
+
+	tests := []struct {
+		name         string
+		previous     []race.Result
+		finishTime   time.Duration
+		wantRecord   bool
+		wantNotified *notification.Notification
+	}{
+		{
+			name:       "first result of a distance sets a record",
+			previous:   []race.Result{previousHalf, deletedRace},
+			finishTime: 50 * time.Minute,
+			wantRecord: true,
+			wantNotified: &notification.Notification{
+				EmailAddress: "anna@example.com",
+				Subject:      "New 10K personal record",
+				Message:      "You set a new 10K personal record of 50m0s at City 10K.",
+			},
+		},
+		{
+			name:       "faster result sets a record",
+			previous:   []race.Result{previousTenK, previousHalf},
+			finishTime: 44 * time.Minute,
+			wantRecord: true,
+			wantNotified: &notification.Notification{
+				EmailAddress: "anna@example.com",
+				Subject:      "New 10K personal record",
+				Message:      "You set a new 10K personal record of 44m0s at City 10K. Your previous record was 45m0s.",
+			},
+		},
+		{
+			name:       "slower result does not set a record",
+			previous:   []race.Result{previousTenK},
+			finishTime: 46 * time.Minute,
+			wantRecord: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", tenK.ID()).Return(tenK, nil)
+			raceRepo.On("GetRace", otherTenK.ID()).Return(otherTenK, nil)
+			raceRepo.On("GetRace", half.ID()).Return(half, nil)
+			raceRepo.On("GetRace", deletedRace.RaceID()).Return(race.Race{}, race.ErrNotFound)
+			raceRepo.On("GetRaceResults", rn.ID()).Return(tt.previous, nil)
+			raceRepo.On("SaveRaceResult", mock.Anything).Return(nil)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+			notifier := new(notification.MockNotificationService)
+			if tt.wantNotified != nil {
+				notifier.On("Notify", *tt.wantNotified).Return(nil)
+			}
+			service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), notifier, logging.Discard, metrics.Discard)
+
+			added, err := service.AddResult(adminContext(), rn.ID(), tenK.ID(), "", "", tt.finishTime, 0, 150, "", nil)
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, added.ID)
+			assert.Equal(t, tt.wantRecord, added.PersonalRecord)
+			notifier.AssertExpectations(t)
+			if tt.wantNotified == nil {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestService_GetPersonalRecords(t *testing.T) {
+	rn, _ := runner.NewRunner("Anna", "anna@example.com")
+	fiveK, _ := race.NewRace("Park Run", "Athens", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 5, 0)
+	trail, _ := race.NewRace("Trail", "Parnitha", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), 12.5, 600)
+	slow, _ := race.NewResult(rn.ID(), fiveK.ID(), 25*time.Minute, 5, 150, "")
+	fast, _ := race.NewResult(rn.ID(), fiveK.ID(), 22*time.Minute, 4.4, 150, "")
+	trailResult, _ := race.NewResult(rn.ID(), trail.ID(), 80*time.Minute, 6.4, 150, "")
+
+	tests := []struct {
+		name      string
+		runnerID  uuid.UUID
+		mockSetup func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository)
+		want      []PersonalRecordItem
+		wantErr   error
+	}{
+		{
+			name:     "records by distance",
+			runnerID: rn.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+				raceRepo.On("GetRaceResults", rn.ID()).Return([]race.Result{trailResult, slow, fast}, nil)
+				raceRepo.On("GetRace", fiveK.ID()).Return(fiveK, nil).Once()
+				raceRepo.On("GetRace", trail.ID()).Return(trail, nil).Once()
+			},
+			want: []PersonalRecordItem{
+				{Distance: "5K", DistanceKm: 5, ResultID: fast.ID(), RaceID: fiveK.ID(), RaceName: "Park Run", RaceDate: fiveK.Date(), FinishTime: 22 * time.Minute, PaceMinPerKm: 4.4},
+				{Distance: "12.5K", DistanceKm: 12.5, ResultID: trailResult.ID(), RaceID: trail.ID(), RaceName: "Trail", RaceDate: trail.Date(), FinishTime: 80 * time.Minute, PaceMinPerKm: 6.4},
+			},
 		},
 		{
-			name:      "empty RunnerID",
+			name:     "runner not found",
+			runnerID: rn.ID(),
+			mockSetup: func(_ *mockRaceRepository, runnerRepo *runner.MockRepository) {
+				runnerRepo.On("GetByID", rn.ID()).Return(nil, runner.ErrNotFound)
+			},
+			wantErr: runner.ErrNotFound,
+		},
+		{
+			name:      "empty runner id",
 			runnerID:  uuid.Nil,
-			mockSetup: func() {},
+			mockSetup: func(*mockRaceRepository, *runner.MockRepository) {},
 			wantErr:   ErrEmptyRunnerID,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup()
-			res, err := service.GetResults(tt.runnerID)
-			assert.Equal(t, tt.wantErr, err)
-			if err == nil {
-				assert.Equal(t, tt.expected, res)
+			raceRepo := new(mockRaceRepository)
+			runnerRepo := new(runner.MockRepository)
+			tt.mockSetup(raceRepo, runnerRepo)
+			service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			got, err := service.GetPersonalRecords(adminContext(), tt.runnerID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			raceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_PublishResults(t *testing.T) {
+	r, _ := race.NewRace("Park Run", "Athens", time.Now(), 5, 0)
+	provisional, _ := race.NewResult(uuid.New(), r.ID(), 20*time.Minute, 4, 150, "")
+	dns, _ := race.NewResultWithStatus(uuid.New(), r.ID(), race.StatusDidNotStart, "", 0, 0, 0, "")
+	official, _ := race.NewResult(uuid.New(), r.ID(), 22*time.Minute, 4.4, 150, "")
+	official, _ = official.Publish("")
+
+	tests := []struct {
+		name          string
+		raceID        uuid.UUID
+		mockSetup     func(raceRepo *mockRaceRepository)
+		wantPublished int
+		wantErr       error
+	}{
+		{
+			name:   "publishes provisional results",
+			raceID: r.ID(),
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetRace", r.ID()).Return(r, nil)
+				raceRepo.On("GetResultsByRace", r.ID()).Return([]race.Result{provisional, official, dns}, nil)
+				raceRepo.On("UpdateRaceResult", mock.MatchedBy(func(result race.Result) bool {
+					return result.Publication() == race.PublicationOfficial && result.PublicationReason() == "verified"
+				})).Return(nil).Twice()
+			},
+			wantPublished: 2,
+		},
+		{
+			name:   "race not found",
+			raceID: uuid.New(),
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+			},
+			wantErr: race.ErrNotFound,
+		},
+		{
+			name:      "empty RaceID",
+			raceID:    uuid.Nil,
+			mockSetup: func(*mockRaceRepository) {},
+			wantErr:   ErrEmptyRaceID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			tt.mockSetup(raceRepo)
+			service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			published, err := service.PublishResults(adminContext(), tt.raceID, "verified")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantPublished, published)
+			raceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_AmendResult(t *testing.T) {
+	r, _ := race.NewRace("Park Run", "Athens", time.Now(), 5, 0)
+	provisional, _ := race.NewResult(uuid.New(), r.ID(), 20*time.Minute, 4, 150, "")
+	official, _ := provisional.Publish("")
+	otherRace, _ := race.NewResult(uuid.New(), uuid.New(), 20*time.Minute, 4, 150, "")
+
+	tests := []struct {
+		name         string
+		resultID     uuid.UUID
+		status       string
+		statusReason string
+		finishTime   time.Duration
+		reason       string
+		mockSetup    func(raceRepo *mockRaceRepository)
+		wantErr      error
+	}{
+		{
+			name:       "corrects the finish time",
+			resultID:   official.ID(),
+			status:     "finished",
+			finishTime: 21 * time.Minute,
+			reason:     "timing error",
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetResult", official.ID()).Return(official, nil)
+				raceRepo.On("UpdateRaceResult", mock.MatchedBy(func(result race.Result) bool {
+					return result.FinishTime() == 21*time.Minute && result.Pace() == 4.2 && result.Publication() == race.PublicationAmended
+				})).Return(nil)
+			},
+		},
+		{
+			name:         "disqualifies the runner",
+			resultID:     official.ID(),
+			status:       "dsq",
+			statusReason: "course cutting",
+			reason:       "referee report",
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetResult", official.ID()).Return(official, nil)
+				raceRepo.On("UpdateRaceResult", mock.MatchedBy(func(result race.Result) bool {
+					return result.Status() == race.StatusDisqualified && result.FinishTime() == 0
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "provisional result",
+			resultID: provisional.ID(),
+			status:   "dnf",
+			reason:   "timing error",
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetResult", provisional.ID()).Return(provisional, nil)
+			},
+			wantErr: race.ErrInvalidPublicationTransition,
+		},
+		{
+			name:     "result of another race",
+			resultID: otherRace.ID(),
+			status:   "dnf",
+			reason:   "timing error",
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetResult", otherRace.ID()).Return(otherRace, nil)
+			},
+			wantErr: race.ErrResultNotFound,
+		},
+		{
+			name:     "result not found",
+			resultID: uuid.New(),
+			status:   "dnf",
+			reason:   "timing error",
+			mockSetup: func(raceRepo *mockRaceRepository) {
+				raceRepo.On("GetResult", mock.Anything).Return(race.Result{}, race.ErrResultNotFound)
+			},
+			wantErr: race.ErrResultNotFound,
+		},
+		{
+			name:      "invalid status",
+			resultID:  official.ID(),
+			status:    "",
+			reason:    "timing error",
+			mockSetup: func(*mockRaceRepository) {},
+			wantErr:   race.ErrInvalidStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			tt.mockSetup(raceRepo)
+			service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			err := service.AmendResult(adminContext(), r.ID(), tt.resultID, tt.status, tt.statusReason, tt.finishTime, tt.reason)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				raceRepo.AssertNotCalled(t, "UpdateRaceResult", mock.Anything)
 			}
 		})
 	}
 }
+
+func TestService_AddResult_CancelledRace(t *testing.T) {
+	r, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	r, _ = r.Cancel("storm warning", time.Now())
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	_, err := service.AddResult(adminContext(), uuid.New(), r.ID(), "", "", 40*time.Minute, 0, 150, "", nil)
+	assert.ErrorIs(t, err, race.ErrRaceCancelled)
+	raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+}
+
+func TestService_UpdateRace(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	r, _ := race.NewRace("City 10K", "Athens", date, 10, 50)
+	finisher, _ := runner.NewRunner("Anna", "anna@example.com")
+	registered, _ := runner.NewRunner("Nick", "nick@example.com")
+	provisional, _ := race.NewResult(finisher.ID(), r.ID(), 40*time.Minute, 4, 150, "")
+	official, _ := provisional.Publish("")
+	entryList, _ := registration.NewEntryList(r.ID(), 10, date.Add(-48*time.Hour), date.Add(-24*time.Hour), false)
+	_, _ = entryList.Register(finisher.ID(), date.Add(-48*time.Hour))
+	_, _ = entryList.Register(registered.ID(), date.Add(-48*time.Hour))
+	name, location, later := "Night 10K", "Athens", date.Add(24*time.Hour)
+	distance, elevation := 10.5, 50.0
+	start, _ := race.NewCoursePoint(0, 0, 0)
+	finish, _ := race.NewCoursePoint(0, 0.09, 0)
+	course, _ := race.NewCourse([]race.CoursePoint{start, finish}, nil)
+
+	tests := []struct {
+		name         string
+		changes      RaceChangesItem
+		results      []race.Result
+		course       *race.Course
+		wantRace     func(item RaceItem)
+		wantResults  bool
+		wantSubject  string
+		wantMessage  string
+		wantNotified bool
+		wantErr      error
+	}{
+		{
+			name:    "renames and reschedules the race",
+			changes: RaceChangesItem{Name: &name, Date: &later},
+			results: []race.Result{provisional},
+			wantRace: func(item RaceItem) {
+				assert.Equal(t, "Night 10K", item.Name)
+				assert.Equal(t, later, item.Date)
+			},
+			wantSubject:  "Night 10K has changed",
+			wantMessage:  "The details of City 10K have changed: the race is now called Night 10K, the race now starts on Mon, 06 Oct 2025 08:00:00 UTC.",
+			wantNotified: true,
+		},
+		{
+			name:    "recalculates the provisional results for a corrected distance",
+			changes: RaceChangesItem{DistanceKm: &distance},
+			results: []race.Result{provisional},
+			wantRace: func(item RaceItem) {
+				assert.Equal(t, 10.5, item.DistanceKm)
+			},
+			wantResults:  true,
+			wantSubject:  "City 10K has changed",
+			wantMessage:  "The details of City 10K have changed: the distance is now 10.5 km.",
+			wantNotified: true,
+		},
+		{
+			name:    "does not notify when nothing changes",
+			changes: RaceChangesItem{Location: &location, ElevationGain: &elevation},
+			wantRace: func(item RaceItem) {
+				assert.Equal(t, "Athens", item.Location)
+			},
+		},
+		{
+			name:    "rejects a distance correction after official results",
+			changes: RaceChangesItem{DistanceKm: &distance},
+			results: []race.Result{official},
+			wantErr: race.ErrDistanceLocked,
+		},
+		{
+			name:    "rejects a distance correction that no longer matches the course",
+			changes: RaceChangesItem{DistanceKm: &distance},
+			results: []race.Result{provisional},
+			course:  &course,
+			wantErr: ErrCourseMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			raceRepo.On("GetResultsByRace", r.ID()).Return(tt.results, nil)
+			raceRepo.On("SaveRaceWithResults", mock.Anything, mock.Anything).Return(nil)
+			courseRepo := noCourse()
+			if tt.course != nil {
+				courseRepo = new(mockCourseRepository)
+				courseRepo.On("GetCourse", r.ID()).Return(*tt.course, nil)
+			}
+			registrationRepo := new(mockRegistrationRepository)
+			registrationRepo.On("GetEntryList", r.ID()).Return(entryList, nil)
+			runnerRepo := new(runner.MockRepository)
+			runnerRepo.On("GetByID", finisher.ID()).Return(finisher, nil)
+			runnerRepo.On("GetByID", registered.ID()).Return(registered, nil)
+			notifier := new(notification.MockNotificationService)
+			notifier.On("Notify", mock.Anything).Return(nil)
+			service := NewService(raceRepo, courseRepo, runnerRepo, registrationRepo, noTiming(), new(activity.MockParser), notifier, logging.Discard, metrics.Discard)
+
+			item, err := service.UpdateRace(adminContext(), r.ID(), tt.changes)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				raceRepo.AssertNotCalled(t, "SaveRaceWithResults", mock.Anything, mock.Anything)
+				notifier.AssertNotCalled(t, "Notify", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			tt.wantRace(item)
+			if tt.wantResults {
+				raceRepo.AssertCalled(t, "SaveRaceWithResults", mock.Anything, mock.MatchedBy(func(results []race.Result) bool {
+					return len(results) == 1 && results[0].ID() == provisional.ID() && results[0].Pace() == 40.0/10.5
+				}))
+			}
+			if !tt.wantNotified {
+				raceRepo.AssertNotCalled(t, "SaveRaceWithResults", mock.Anything, mock.Anything)
+				notifier.AssertNotCalled(t, "Notify", mock.Anything)
+				return
+			}
+			//the finisher is also registered but notified once
+			notifier.AssertNumberOfCalls(t, "Notify", 2)
+			for _, emailAddress := range []string{"anna@example.com", "nick@example.com"} {
+				notifier.AssertCalled(t, "Notify", notification.Notification{
+					EmailAddress: emailAddress,
+					Subject:      tt.wantSubject,
+					Message:      tt.wantMessage,
+				})
+			}
+		})
+	}
+}
+
+func TestService_CancelRace(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	r, _ := race.NewRace("City 10K", "Athens", date, 10, 50)
+	rn, _ := runner.NewRunner("Anna", "anna@example.com")
+	result, _ := race.NewResult(rn.ID(), r.ID(), 40*time.Minute, 4, 150, "")
+	now := date.Add(-time.Hour)
+
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	raceRepo.On("GetResultsByRace", r.ID()).Return([]race.Result{result}, nil)
+	raceRepo.On("SaveRace", mock.Anything).Return(nil)
+	runnerRepo := new(runner.MockRepository)
+	runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+	notifier := new(notification.MockNotificationService)
+	notifier.On("Notify", notification.Notification{
+		EmailAddress: "anna@example.com",
+		Subject:      "City 10K is cancelled",
+		Message:      "City 10K on Sun, 05 Oct 2025 08:00:00 UTC has been cancelled: storm warning.",
+	}).Return(nil)
+	service := NewService(raceRepo, noCourse(), runnerRepo, noRegistration(), noTiming(), new(activity.MockParser), notifier, logging.Discard, metrics.Discard)
+	service.now = func() time.Time { return now }
+
+	item, err := service.CancelRace(adminContext(), r.ID(), "storm warning")
+	assert.NoError(t, err)
+	assert.Equal(t, now, item.CancelledAt)
+	assert.Equal(t, "storm warning", item.CancellationReason)
+	raceRepo.AssertCalled(t, "SaveRace", mock.MatchedBy(func(saved race.Race) bool { return saved.IsCancelled() }))
+	notifier.AssertExpectations(t)
+
+	_, err = service.CancelRace(adminContext(), r.ID(), "")
+	assert.ErrorIs(t, err, race.ErrMissingCancellationReason)
+	_, err = service.CancelRace(adminContext(), uuid.Nil, "storm warning")
+	assert.ErrorIs(t, err, ErrEmptyRaceID)
+}
+
+func TestService_ListRaces(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	first, _ := race.NewRace("City 10K", "Athens", date, 10, 50)
+	second, _ := race.NewRace("Night 10K", "Athens", date.Add(time.Hour), 10, 20)
+	third, _ := race.NewRace("Classic Marathon", "Athens", date.Add(2*time.Hour), 42.195, 250)
+	minDistance := 5.0
+
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("SearchRaces", mock.MatchedBy(func(search race.Search) bool { return search.After == nil })).
+		Return([]race.Race{first, second, third}, nil)
+	raceRepo.On("SearchRaces", mock.MatchedBy(func(search race.Search) bool { return search.After != nil })).
+		Return([]race.Race{third}, nil)
+	service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+	ctx := adminContext()
+
+	filter := RaceFilterItem{Location: "Athens", MinDistanceKm: &minDistance, Text: "10k", Sort: "distance_km", Order: "desc", Limit: 2}
+	page, err := service.ListRaces(ctx, filter)
+	assert.NoError(t, err)
+	assert.Len(t, page.Races, 2)
+	assert.Equal(t, first.ID(), page.Races[0].ID)
+	assert.Equal(t, 2, page.Limit)
+	assert.NotEmpty(t, page.NextCursor)
+	raceRepo.AssertCalled(t, "SearchRaces", race.Search{
+		Location: "Athens", MinDistanceKm: &minDistance, Text: "10k", SortBy: race.SortByDistance, Descending: true, Limit: 3,
+	})
+
+	filter.Cursor = page.NextCursor
+	page, err = service.ListRaces(ctx, filter)
+	assert.NoError(t, err)
+	assert.Len(t, page.Races, 1)
+	assert.Empty(t, page.NextCursor, "the last page has no next cursor")
+	want := race.CursorOf(second)
+	raceRepo.AssertCalled(t, "SearchRaces", mock.MatchedBy(func(search race.Search) bool {
+		return search.After != nil && *search.After == want
+	}))
+
+	tests := []struct {
+		name    string
+		filter  RaceFilterItem
+		wantErr error
+	}{
+		{name: "limit above the maximum", filter: RaceFilterItem{Limit: MaxPageLimit + 1}, wantErr: ErrInvalidLimit},
+		{name: "unknown order", filter: RaceFilterItem{Order: "up"}, wantErr: ErrInvalidOrder},
+		{name: "unknown sort field", filter: RaceFilterItem{Sort: "location"}, wantErr: race.ErrInvalidSortField},
+		{name: "malformed cursor", filter: RaceFilterItem{Cursor: "not a cursor"}, wantErr: ErrInvalidCursor},
+		{name: "cursor of another order", filter: RaceFilterItem{Sort: "distance_km", Cursor: filter.Cursor}, wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ListRaces(ctx, tt.filter)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_AddResult_Authorization(t *testing.T) {
+	r, _ := race.NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	r = r.WithOrganiser("athens-events")
+	runnerID := uuid.New()
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		wantErr   error
+	}{
+		{name: "no principal", wantErr: auth.ErrUnauthenticated},
+		{name: "another runner", principal: &auth.Principal{Subject: "jane", Role: auth.RoleRunner, RunnerID: uuid.New()}, wantErr: auth.ErrForbidden},
+		{name: "organiser of another race", principal: &auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser}, wantErr: auth.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raceRepo := new(mockRaceRepository)
+			raceRepo.On("GetRace", r.ID()).Return(r, nil)
+			service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, *tt.principal)
+			}
+			_, err := service.AddResult(ctx, runnerID, r.ID(), "", "", 40*time.Minute, 0, 150, "", nil)
+			assert.ErrorIs(t, err, tt.wantErr)
+			raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+		})
+	}
+}
+
+func TestService_RaceAuthorization(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+	otherOrganiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser})
+	runnerCtx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "anna", Role: auth.RoleRunner, RunnerID: uuid.New()})
+
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("SaveRace", mock.Anything).Return(nil)
+	service := NewService(raceRepo, noCourse(), new(runner.MockRepository), noRegistration(), noTiming(), new(activity.MockParser), new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	_, err := service.CreateRace(runnerCtx, "City 10K", "Athens", date, 10, 50, nil, "")
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = service.CreateRace(organiser, "City 10K", "Athens", date, 10, 50, nil, "")
+	assert.NoError(t, err)
+	created := raceRepo.Calls[0].Arguments.Get(0).(race.Race)
+	assert.Equal(t, "athens-events", created.Organiser(), "the organiser creating a race organises it")
+
+	raceRepo.On("GetRace", created.ID()).Return(created, nil)
+	name := "Night 10K"
+	_, err = service.UpdateRace(otherOrganiser, created.ID(), RaceChangesItem{Name: &name})
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = service.CancelRace(runnerCtx, created.ID(), "storm warning")
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	_, err = service.PublishResults(otherOrganiser, created.ID(), "")
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	assert.ErrorIs(t, service.AmendResult(otherOrganiser, created.ID(), uuid.New(), "finished", "", time.Hour, "typo"), auth.ErrForbidden)
+	raceRepo.AssertNumberOfCalls(t, "SaveRace", 1)
+}
@@ -0,0 +1,260 @@
+// Package registration contains the service providing the use cases for registering runners to races
+package registration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/retry"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// Service provides the registration operations
+type Service struct {
+	repo                registration.Repository
+	raceRepo            race.Repository
+	runnerRepo          runner.Repository
+	notificationService notification.Service
+	logger              logging.Logger
+	now                 func() time.Time
+}
+
+// NewService creates a new Service with the given repositories, notification service and logger
+func NewService(repo registration.Repository, raceRepo race.Repository, runnerRepo runner.Repository, notificationService notification.Service, logger logging.Logger) Service {
+	return Service{repo: repo, raceRepo: raceRepo, runnerRepo: runnerRepo, notificationService: notificationService, logger: logger, now: time.Now}
+}
+
+// RegistrationItem represents the registration of a runner to a race.
+// WaitlistPosition is the 1-based position of a waitlisted registration and 0 otherwise.
+type RegistrationItem struct {
+	ID               uuid.UUID
+	RaceID           uuid.UUID
+	RunnerID         uuid.UUID
+	Status           string
+	WaitlistPosition int
+	RegisteredAt     time.Time
+}
+
+// EntryListItem represents the registration settings of a race with its confirmed and waitlisted runners
+type EntryListItem struct {
+	RaceID             uuid.UUID
+	Capacity           int
+	OpensAt            time.Time
+	ClosesAt           time.Time
+	RequiredForResults bool
+	Open               bool
+	Confirmed          []RegistrationItem
+	Waitlist           []RegistrationItem
+}
+
+// ConfigureRegistration sets the capacity and the registration window of the race, enabling registration
+// the first time it is called. When the capacity grows, waitlisted runners are confirmed and notified.
+// Only the organiser of the race can configure its registration.
+func (s Service) ConfigureRegistration(ctx context.Context, raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool) error {
+	if raceID == uuid.Nil {
+		return registration.ErrEmptyRaceID
+	}
+
+	raceDetails, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireOrganiser(ctx, raceDetails); err != nil {
+		return err
+	}
+
+	var promoted []registration.Registration
+	err = retry.OnConflict(registration.ErrConcurrentUpdate, func() error {
+		l, err := s.repo.GetEntryList(ctx, raceID)
+		switch {
+		case errors.Is(err, registration.ErrNotConfigured):
+			l, err = registration.NewEntryList(raceID, capacity, opensAt, closesAt, requiredForResults)
+			promoted = nil
+		case err == nil:
+			promoted, err = l.Configure(capacity, opensAt, closesAt, requiredForResults, s.now())
+		}
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveEntryList(ctx, l)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range promoted {
+		s.notifyPromoted(ctx, raceDetails, r)
+	}
+	return nil
+}
+
+// Register registers the runner to the race. The registration is confirmed while the race has places left,
+// otherwise the runner joins the waitlist. The runner is notified either way.
+// Runners can only register themselves, and organisers register runners to their own races.
+func (s Service) Register(ctx context.Context, raceID, runnerID uuid.UUID) (RegistrationItem, error) {
+	if raceID == uuid.Nil {
+		return RegistrationItem{}, registration.ErrEmptyRaceID
+	}
+	if runnerID == uuid.Nil {
+		return RegistrationItem{}, registration.ErrEmptyRunnerID
+	}
+
+	raceDetails, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return RegistrationItem{}, err
+	}
+	if err := auth.RequireRunnerOrOrganiser(ctx, runnerID, raceDetails); err != nil {
+		return RegistrationItem{}, err
+	}
+	if raceDetails.IsCancelled() {
+		return RegistrationItem{}, race.ErrRaceCancelled
+	}
+	rn, err := s.runnerRepo.GetByID(ctx, runnerID)
+	if err != nil {
+		return RegistrationItem{}, err
+	}
+
+	var item RegistrationItem
+	err = retry.OnConflict(registration.ErrConcurrentUpdate, func() error {
+		l, err := s.repo.GetEntryList(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		r, err := l.Register(runnerID, s.now())
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SaveEntryList(ctx, l); err != nil {
+			return err
+		}
+		item = toRegistrationItem(r, l.WaitlistPosition(runnerID))
+		return nil
+	})
+	if err != nil {
+		return RegistrationItem{}, err
+	}
+
+	message := fmt.Sprintf("Your registration for %s is confirmed.", raceDetails.Name())
+	if item.WaitlistPosition > 0 {
+		message = fmt.Sprintf("%s is full. You are number %d on the waitlist and will be notified if a place becomes available.",
+			raceDetails.Name(), item.WaitlistPosition)
+	}
+	s.notify(ctx, rn.EmailAddress(), fmt.Sprintf("Registration for %s", raceDetails.Name()), message, item.ID, item.RaceID, item.RunnerID)
+
+	return item, nil
+}
+
+// CancelRegistration cancels the registration of the runner to the race.
+// When a confirmed runner cancels, the first runner of the waitlist takes the place and is notified.
+// Runners can only cancel their own registration, and organisers the registrations to their own races.
+func (s Service) CancelRegistration(ctx context.Context, raceID, runnerID uuid.UUID) error {
+	if raceID == uuid.Nil {
+		return registration.ErrEmptyRaceID
+	}
+	if runnerID == uuid.Nil {
+		return registration.ErrEmptyRunnerID
+	}
+
+	raceDetails, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireRunnerOrOrganiser(ctx, runnerID, raceDetails); err != nil {
+		return err
+	}
+
+	var promoted *registration.Registration
+	err = retry.OnConflict(registration.ErrConcurrentUpdate, func() error {
+		l, err := s.repo.GetEntryList(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		_, promoted, err = l.Cancel(runnerID, s.now())
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveEntryList(ctx, l)
+	})
+	if err != nil {
+		return err
+	}
+
+	if promoted != nil {
+		s.notifyPromoted(ctx, raceDetails, *promoted)
+	}
+	return nil
+}
+
+// GetEntryList returns the registration settings of the race with the confirmed runners and the waitlist
+func (s Service) GetEntryList(ctx context.Context, raceID uuid.UUID) (EntryListItem, error) {
+	if raceID == uuid.Nil {
+		return EntryListItem{}, registration.ErrEmptyRaceID
+	}
+
+	l, err := s.repo.GetEntryList(ctx, raceID)
+	if err != nil {
+		return EntryListItem{}, err
+	}
+
+	item := EntryListItem{
+		RaceID:             l.RaceID(),
+		Capacity:           l.Capacity(),
+		OpensAt:            l.OpensAt(),
+		ClosesAt:           l.ClosesAt(),
+		RequiredForResults: l.RequiredForResults(),
+		Open:               l.IsOpen(s.now()),
+		Confirmed:          []RegistrationItem{},
+		Waitlist:           []RegistrationItem{},
+	}
+	for _, r := range l.Confirmed() {
+		item.Confirmed = append(item.Confirmed, toRegistrationItem(r, 0))
+	}
+	for i, r := range l.Waitlist() {
+		item.Waitlist = append(item.Waitlist, toRegistrationItem(r, i+1))
+	}
+	return item, nil
+}
+
+func toRegistrationItem(r registration.Registration, waitlistPosition int) RegistrationItem {
+	return RegistrationItem{
+		ID:               r.ID(),
+		RaceID:           r.RaceID(),
+		RunnerID:         r.RunnerID(),
+		Status:           string(r.Status()),
+		WaitlistPosition: waitlistPosition,
+		RegisteredAt:     r.RegisteredAt(),
+	}
+}
+
+// notifyPromoted notifies a runner that moved from the waitlist to a confirmed place.
+// Notification is a best effort operation, so failures are only logged.
+func (s Service) notifyPromoted(ctx context.Context, r race.Race, promoted registration.Registration) {
+	rn, err := s.runnerRepo.GetByID(ctx, promoted.RunnerID())
+	if err != nil {
+		s.logger.Warn(ctx, "failed to load the runner of a registration notification",
+			logging.RaceID(r.ID()), logging.RunnerID(promoted.RunnerID()), logging.Err(err))
+		return
+	}
+	s.notify(ctx, rn.EmailAddress(), fmt.Sprintf("Registration for %s", r.Name()),
+		fmt.Sprintf("A place became available and your registration for %s is now confirmed.", r.Name()), promoted.ID(), r.ID(), promoted.RunnerID())
+}
+
+func (s Service) notify(ctx context.Context, emailAddress, subject, message string, registrationID, raceID, runnerID uuid.UUID) {
+	err := s.notificationService.Notify(ctx, notification.Notification{
+		EmailAddress: emailAddress,
+		Subject:      subject,
+		Message:      message,
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to send a registration notification",
+			logging.RaceID(raceID), logging.RunnerID(runnerID), logging.String("registration_id", registrationID.String()), logging.Err(err))
+	}
+}
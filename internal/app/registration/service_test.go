@@ -0,0 +1,342 @@
+package registration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/retry"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistrationRepository stores copies of entry lists and rejects the first conflicts saves as concurrent updates
+type fakeRegistrationRepository struct {
+	entryLists map[uuid.UUID]*registration.EntryList
+	conflicts  int
+	saves      int
+}
+
+func newFakeRegistrationRepository() *fakeRegistrationRepository {
+	return &fakeRegistrationRepository{entryLists: make(map[uuid.UUID]*registration.EntryList)}
+}
+
+func (f *fakeRegistrationRepository) GetEntryList(_ context.Context, raceID uuid.UUID) (*registration.EntryList, error) {
+	l, exists := f.entryLists[raceID]
+	if !exists {
+		return nil, registration.ErrNotConfigured
+	}
+	return registration.LoadEntryList(l.RaceID(), l.Capacity(), l.OpensAt(), l.ClosesAt(), l.RequiredForResults(), l.Registrations(), l.Version())
+}
+
+func (f *fakeRegistrationRepository) SaveEntryList(_ context.Context, l *registration.EntryList) error {
+	f.saves++
+	if f.saves <= f.conflicts {
+		return registration.ErrConcurrentUpdate
+	}
+	saved, err := registration.LoadEntryList(l.RaceID(), l.Capacity(), l.OpensAt(), l.ClosesAt(), l.RequiredForResults(), l.Registrations(), l.Version()+1)
+	if err != nil {
+		return err
+	}
+	f.entryLists[l.RaceID()] = saved
+	return nil
+}
+
+type mockRaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockRaceRepository) SaveRace(_ context.Context, r race.Race) error {
+	return m.Called(r).Error(0)
+}
+
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+var (
+	now      = time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	opensAt  = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closesAt = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type fixture struct {
+	service       Service
+	repo          *fakeRegistrationRepository
+	runnerRepo    *runner.MockRepository
+	notifications *notification.MockNotificationService
+	race          race.Race
+}
+
+func newFixture(t *testing.T) fixture {
+	r, err := race.NewRace("Athens Marathon", "Athens", closesAt.AddDate(0, 1, 0), 42.195, 250)
+	require.NoError(t, err)
+	r = r.WithOrganiser("athens-events")
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+
+	f := fixture{
+		repo:          newFakeRegistrationRepository(),
+		runnerRepo:    new(runner.MockRepository),
+		notifications: new(notification.MockNotificationService),
+		race:          r,
+	}
+	f.service = NewService(f.repo, raceRepo, f.runnerRepo, f.notifications, logging.Discard)
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+func (f fixture) newRunner(t *testing.T, name string) *runner.Runner {
+	rn, err := runner.NewRunner(name, name+"@example.com")
+	require.NoError(t, err)
+	f.runnerRepo.On("GetByID", rn.ID()).Return(rn, nil)
+	return rn
+}
+
+func TestService_ConfigureRegistration(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name     string
+		raceID   uuid.UUID
+		capacity int
+		opensAt  time.Time
+		closesAt time.Time
+		wantErr  error
+	}{
+		{name: "empty race id", raceID: uuid.Nil, capacity: 10, opensAt: opensAt, closesAt: closesAt, wantErr: registration.ErrEmptyRaceID},
+		{name: "unknown race", raceID: uuid.New(), capacity: 10, opensAt: opensAt, closesAt: closesAt, wantErr: race.ErrNotFound},
+		{name: "invalid capacity", raceID: f.race.ID(), capacity: 0, opensAt: opensAt, closesAt: closesAt, wantErr: registration.ErrInvalidCapacity},
+		{name: "invalid window", raceID: f.race.ID(), capacity: 10, opensAt: closesAt, closesAt: opensAt, wantErr: registration.ErrInvalidWindow},
+		{name: "valid", raceID: f.race.ID(), capacity: 10, opensAt: opensAt, closesAt: closesAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.service.ConfigureRegistration(adminContext(), tt.raceID, tt.capacity, tt.opensAt, tt.closesAt, true)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got, err := f.service.GetEntryList(adminContext(), tt.raceID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.capacity, got.Capacity)
+			assert.True(t, got.RequiredForResults)
+			assert.True(t, got.Open)
+			assert.Empty(t, got.Confirmed)
+		})
+	}
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
+}
+
+func TestService_Authorization(t *testing.T) {
+	f := newFixture(t)
+	anna, bob := f.newRunner(t, "anna"), f.newRunner(t, "bob")
+	f.notifications.On("Notify", mock.Anything).Return(nil)
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+	otherOrganiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser})
+	asAnna := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "anna", Role: auth.RoleRunner, RunnerID: anna.ID()})
+
+	assert.ErrorIs(t, f.service.ConfigureRegistration(context.Background(), f.race.ID(), 10, opensAt, closesAt, false), auth.ErrUnauthenticated)
+	assert.ErrorIs(t, f.service.ConfigureRegistration(asAnna, f.race.ID(), 10, opensAt, closesAt, false), auth.ErrForbidden)
+	assert.ErrorIs(t, f.service.ConfigureRegistration(otherOrganiser, f.race.ID(), 10, opensAt, closesAt, false), auth.ErrForbidden)
+	require.NoError(t, f.service.ConfigureRegistration(organiser, f.race.ID(), 10, opensAt, closesAt, false))
+
+	_, err := f.service.Register(asAnna, f.race.ID(), bob.ID())
+	assert.ErrorIs(t, err, auth.ErrForbidden, "runners only register themselves")
+	_, err = f.service.Register(asAnna, f.race.ID(), anna.ID())
+	assert.NoError(t, err)
+	_, err = f.service.Register(organiser, f.race.ID(), bob.ID())
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, f.service.CancelRegistration(asAnna, f.race.ID(), bob.ID()), auth.ErrForbidden)
+	assert.ErrorIs(t, f.service.CancelRegistration(otherOrganiser, f.race.ID(), bob.ID()), auth.ErrForbidden)
+	assert.NoError(t, f.service.CancelRegistration(asAnna, f.race.ID(), anna.ID()))
+}
+
+func TestService_Register(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna, bob := f.newRunner(t, "anna"), f.newRunner(t, "bob")
+	f.notifications.On("Notify", mock.Anything).Return(nil)
+
+	_, err := f.service.Register(ctx, f.race.ID(), anna.ID())
+	assert.ErrorIs(t, err, registration.ErrNotConfigured)
+
+	require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, false))
+
+	confirmed, err := f.service.Register(ctx, f.race.ID(), anna.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed", confirmed.Status)
+	assert.Equal(t, 0, confirmed.WaitlistPosition)
+	assert.Equal(t, now, confirmed.RegisteredAt)
+
+	waitlisted, err := f.service.Register(ctx, f.race.ID(), bob.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "waitlisted", waitlisted.Status)
+	assert.Equal(t, 1, waitlisted.WaitlistPosition)
+
+	f.notifications.AssertCalled(t, "Notify", notification.Notification{
+		EmailAddress: "anna@example.com",
+		Subject:      "Registration for Athens Marathon",
+		Message:      "Your registration for Athens Marathon is confirmed.",
+	})
+	f.notifications.AssertCalled(t, "Notify", notification.Notification{
+		EmailAddress: "bob@example.com",
+		Subject:      "Registration for Athens Marathon",
+		Message:      "Athens Marathon is full. You are number 1 on the waitlist and will be notified if a place becomes available.",
+	})
+
+	_, err = f.service.Register(ctx, f.race.ID(), anna.ID())
+	assert.ErrorIs(t, err, registration.ErrAlreadyRegistered)
+
+	unknown := uuid.New()
+	f.runnerRepo.On("GetByID", unknown).Return(nil, runner.ErrNotFound)
+	_, err = f.service.Register(ctx, f.race.ID(), unknown)
+	assert.ErrorIs(t, err, runner.ErrNotFound)
+
+	_, err = f.service.Register(ctx, f.race.ID(), uuid.Nil)
+	assert.ErrorIs(t, err, registration.ErrEmptyRunnerID)
+}
+
+func TestService_Register_CancelledRace(t *testing.T) {
+	cancelled, err := race.NewRace("Athens Marathon", "Athens", closesAt.AddDate(0, 1, 0), 42.195, 250)
+	require.NoError(t, err)
+	cancelled, err = cancelled.Cancel("storm warning", now)
+	require.NoError(t, err)
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", cancelled.ID()).Return(cancelled, nil)
+	service := NewService(newFakeRegistrationRepository(), raceRepo, new(runner.MockRepository), new(notification.MockNotificationService), logging.Discard)
+
+	_, err = service.Register(adminContext(), cancelled.ID(), uuid.New())
+	assert.ErrorIs(t, err, race.ErrRaceCancelled)
+}
+
+func TestService_Register_RetriesConcurrentUpdates(t *testing.T) {
+	ctx := adminContext()
+
+	t.Run("succeeds after a conflict", func(t *testing.T) {
+		f := newFixture(t)
+		anna := f.newRunner(t, "anna")
+		f.notifications.On("Notify", mock.Anything).Return(nil)
+		require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, false))
+		f.repo.conflicts = f.repo.saves + 1
+
+		got, err := f.service.Register(ctx, f.race.ID(), anna.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "confirmed", got.Status)
+	})
+
+	t.Run("gives up after repeated conflicts", func(t *testing.T) {
+		f := newFixture(t)
+		anna := f.newRunner(t, "anna")
+		require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, false))
+		f.repo.conflicts = f.repo.saves + retry.MaxAttempts
+
+		_, err := f.service.Register(ctx, f.race.ID(), anna.ID())
+		assert.ErrorIs(t, err, registration.ErrConcurrentUpdate)
+		f.notifications.AssertNotCalled(t, "Notify", mock.Anything)
+	})
+}
+
+func TestService_CancelRegistration(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna, bob := f.newRunner(t, "anna"), f.newRunner(t, "bob")
+	f.notifications.On("Notify", mock.Anything).Return(nil)
+	require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, false))
+	_, err := f.service.Register(ctx, f.race.ID(), anna.ID())
+	require.NoError(t, err)
+	_, err = f.service.Register(ctx, f.race.ID(), bob.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, f.service.CancelRegistration(ctx, f.race.ID(), anna.ID()))
+
+	got, err := f.service.GetEntryList(ctx, f.race.ID())
+	require.NoError(t, err)
+	require.Len(t, got.Confirmed, 1)
+	assert.Equal(t, bob.ID(), got.Confirmed[0].RunnerID)
+	assert.Empty(t, got.Waitlist)
+	f.notifications.AssertCalled(t, "Notify", notification.Notification{
+		EmailAddress: "bob@example.com",
+		Subject:      "Registration for Athens Marathon",
+		Message:      "A place became available and your registration for Athens Marathon is now confirmed.",
+	})
+
+	assert.ErrorIs(t, f.service.CancelRegistration(ctx, f.race.ID(), anna.ID()), registration.ErrNotRegistered)
+	assert.ErrorIs(t, f.service.CancelRegistration(ctx, uuid.New(), anna.ID()), race.ErrNotFound)
+}
+
+func TestService_ConfigureRegistration_PromotesWaitlist(t *testing.T) {
+	f := newFixture(t)
+	ctx := adminContext()
+	anna, bob, carl := f.newRunner(t, "anna"), f.newRunner(t, "bob"), f.newRunner(t, "carl")
+	f.notifications.On("Notify", mock.Anything).Return(nil)
+	require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, false))
+	for _, rn := range []*runner.Runner{anna, bob, carl} {
+		_, err := f.service.Register(ctx, f.race.ID(), rn.ID())
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, f.service.ConfigureRegistration(ctx, f.race.ID(), 2, opensAt, closesAt, true))
+
+	got, err := f.service.GetEntryList(ctx, f.race.ID())
+	require.NoError(t, err)
+	assert.Len(t, got.Confirmed, 2)
+	require.Len(t, got.Waitlist, 1)
+	assert.Equal(t, carl.ID(), got.Waitlist[0].RunnerID)
+	assert.Equal(t, 1, got.Waitlist[0].WaitlistPosition)
+	f.notifications.AssertCalled(t, "Notify", notification.Notification{
+		EmailAddress: "bob@example.com",
+		Subject:      "Registration for Athens Marathon",
+		Message:      "A place became available and your registration for Athens Marathon is now confirmed.",
+	})
+
+	err = f.service.ConfigureRegistration(ctx, f.race.ID(), 1, opensAt, closesAt, true)
+	assert.ErrorIs(t, err, registration.ErrCapacityBelowConfirmed)
+}
@@ -0,0 +1,18 @@
+// Package request carries the metadata of the request being served, such as its identifier, through the
+// context passed to the application services
+package request
+
+import "context"
+
+type idKey struct{}
+
+// WithID returns a copy of the context carrying the identifier of the request
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFrom returns the identifier of the request carried by the context, or an empty string
+func IDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
@@ -0,0 +1,21 @@
+// Package retry contains the retry policy of the application services that store aggregates with optimistic
+// concurrency control
+package retry
+
+import "errors"
+
+// MaxAttempts is the number of times a change is applied before a concurrent update is reported
+const MaxAttempts = 3
+
+// OnConflict runs attempt again while it fails with the conflict error, reported by the repositories when another
+// request changed the aggregate after it was loaded. It returns the error of the last attempt.
+func OnConflict(conflict error, attempt func() error) error {
+	var err error
+	for i := 0; i < MaxAttempts; i++ {
+		err = attempt()
+		if !errors.Is(err, conflict) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnConflict(t *testing.T) {
+	errConflict := errors.New("changed by another request")
+	errOther := errors.New("other error")
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "should not retry a successful attempt",
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "should retry conflicts until an attempt succeeds",
+			errs:         []error{errConflict, fmt.Errorf("saving: %w", errConflict), nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "should not retry other errors",
+			errs:         []error{errOther},
+			wantErr:      errOther,
+			wantAttempts: 1,
+		},
+		{
+			name:         "should report the conflict after the last attempt",
+			errs:         []error{errConflict, errConflict, errConflict, nil},
+			wantErr:      errConflict,
+			wantAttempts: MaxAttempts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := OnConflict(errConflict, func() error {
+				attempts++
+				return tt.errs[attempts-1]
+			})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
@@ -2,38 +2,61 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
 )
 
+// Pagination limits
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Error variables for input validation
+var (
+	ErrInvalidOffset = errors.New("offset cannot be negative")
+	ErrInvalidLimit  = fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+)
+
 // Service provides runner operations.
 type Service struct {
 	repo                runner.Repository
 	notificationService notification.Service
+	logger              logging.Logger
+	metrics             metrics.Recorder
 }
 
 // NewService creates a new runner service.
-func NewService(repo runner.Repository, notificationService notification.Service) Service {
-	return Service{repo: repo, notificationService: notificationService}
+func NewService(repo runner.Repository, notificationService notification.Service, logger logging.Logger, recorder metrics.Recorder) Service {
+	return Service{repo: repo, notificationService: notificationService, logger: logger, metrics: recorder}
 }
 
 // CreateRunner creates a new runner.
-func (s Service) CreateRunner(name, email string) (uuid.UUID, error) {
+func (s Service) CreateRunner(ctx context.Context, name, email string) (uuid.UUID, error) {
 
 	r, err := runner.NewRunner(name, email)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
 
-	err = s.repo.Add(r)
+	err = s.repo.Add(ctx, r)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
+	s.metrics.RunnerCreated(ctx)
 
 	//Notification is a best effort operation, so we don't want to block the response
 	err = s.notificationService.Notify(
+		ctx,
 		notification.Notification{
 			EmailAddress: r.EmailAddress(),
 			Subject:      fmt.Sprintf("Welcome %s", r.Name()),
@@ -41,16 +64,18 @@ func (s Service) CreateRunner(name, email string) (uuid.UUID, error) {
 		},
 	)
 	if err != nil {
-		//log a warning
-		fmt.Println("Warning: Failed to send notification for runner with id: ", r.ID())
+		s.logger.Warn(ctx, "failed to send the welcome notification", logging.RunnerID(r.ID()), logging.Err(err))
 	}
 
 	return r.ID(), nil
 }
 
-// RenameRunner renames a runner.
-func (s Service) RenameRunner(id uuid.UUID, name string) error {
-	r, err := s.repo.GetByID(id)
+// RenameRunner renames a runner. Runners can only rename themselves.
+func (s Service) RenameRunner(ctx context.Context, id uuid.UUID, name string) error {
+	if err := auth.RequireRunner(ctx, id); err != nil {
+		return err
+	}
+	r, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -59,5 +84,117 @@ func (s Service) RenameRunner(id uuid.UUID, name string) error {
 	if err != nil {
 		return err
 	}
-	return s.repo.Update(r)
+	return s.repo.Update(ctx, r)
+}
+
+// UpdateRunnerProfile sets the gender and date of birth used for the race categories of a runner.
+// A zero date of birth means that it is unknown. Runners can only update their own profile.
+func (s Service) UpdateRunnerProfile(ctx context.Context, id uuid.UUID, gender string, dateOfBirth time.Time) error {
+	if err := auth.RequireRunner(ctx, id); err != nil {
+		return err
+	}
+	r, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	g, err := runner.ParseGender(gender)
+	if err != nil {
+		return err
+	}
+	err = r.UpdateProfile(g, dateOfBirth)
+	if err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, r)
+}
+
+// RunnerItem represents a runner returned by the service
+type RunnerItem struct {
+	ID           uuid.UUID
+	Name         string
+	EmailAddress string
+	Gender       string
+	DateOfBirth  time.Time
+	CreatedAt    time.Time
+}
+
+// RunnerPage represents a page of runners
+type RunnerPage struct {
+	Runners []RunnerItem
+	Total   int
+	Offset  int
+	Limit   int
+}
+
+// GetRunner returns the runner with the provided id.
+// Runners can only read themselves, and organisers and admins every runner.
+func (s Service) GetRunner(ctx context.Context, id uuid.UUID) (RunnerItem, error) {
+	principal, err := auth.RequireRole(ctx, auth.RoleRunner, auth.RoleOrganiser)
+	if err != nil {
+		return RunnerItem{}, err
+	}
+	if principal.Role == auth.RoleRunner {
+		if err := auth.RequireRunner(ctx, id); err != nil {
+			return RunnerItem{}, err
+		}
+	}
+	r, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return RunnerItem{}, err
+	}
+	return toRunnerItem(r), nil
+}
+
+// ListRunners returns a page of runners ordered by creation date.
+// A zero limit falls back to DefaultPageLimit. Only organisers and admins can list the runners.
+func (s Service) ListRunners(ctx context.Context, offset, limit int) (RunnerPage, error) {
+	if _, err := auth.RequireRole(ctx, auth.RoleOrganiser); err != nil {
+		return RunnerPage{}, err
+	}
+	if offset < 0 {
+		return RunnerPage{}, ErrInvalidOffset
+	}
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return RunnerPage{}, ErrInvalidLimit
+	}
+
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return RunnerPage{}, err
+	}
+
+	runners := []RunnerItem{}
+	if offset < len(all) {
+		//offset+limit could overflow for huge offsets, so the end is bounded by the runners left
+		end := offset + min(limit, len(all)-offset)
+		runners = make([]RunnerItem, 0, end-offset)
+		for _, r := range all[offset:end] {
+			runners = append(runners, toRunnerItem(r))
+		}
+	}
+
+	return RunnerPage{Runners: runners, Total: len(all), Offset: offset, Limit: limit}, nil
+}
+
+// DeleteRunner deletes the runner with the provided id. Runners can only delete themselves.
+func (s Service) DeleteRunner(ctx context.Context, id uuid.UUID) error {
+	if err := auth.RequireRunner(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func toRunnerItem(r *runner.Runner) RunnerItem {
+	return RunnerItem{
+		ID:           r.ID(),
+		Name:         r.Name(),
+		EmailAddress: r.EmailAddress(),
+		Gender:       string(r.Gender()),
+		DateOfBirth:  r.DateOfBirth(),
+		CreatedAt:    r.CreatedAt(),
+	}
 }
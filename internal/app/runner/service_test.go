@@ -1,13 +1,21 @@
 package runner
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateRunner(t *testing.T) {
@@ -18,7 +26,9 @@ func TestCreateRunner(t *testing.T) {
 		repoErr          error
 		notificationErr  error
 		wantErr          error
-		mockRepo         *MockRepository
+		wantWarning      bool
+		wantCreated      bool
+		mockRepo         *runner.MockRepository
 		mockNotification *notification.MockNotificationService
 	}{
 		{
@@ -28,8 +38,9 @@ func TestCreateRunner(t *testing.T) {
 			repoErr:         nil,
 			notificationErr: nil,
 			wantErr:         nil,
-			mockRepo: func() *MockRepository {
-				mockRepo := new(MockRepository)
+			wantCreated:     true,
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
 				mockRepo.On("Add", mock.Anything).Return(nil)
 				return mockRepo
 			}(),
@@ -53,8 +64,8 @@ func TestCreateRunner(t *testing.T) {
 			repoErr:         nil,
 			notificationErr: nil,
 			wantErr:         runner.ErrRunnerNameCannotBeEmpty,
-			mockRepo: func() *MockRepository {
-				mockRepo := new(MockRepository)
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
 				return mockRepo
 			}(),
 			mockNotification: func() *notification.MockNotificationService {
@@ -69,8 +80,8 @@ func TestCreateRunner(t *testing.T) {
 			repoErr:         nil,
 			notificationErr: nil,
 			wantErr:         runner.ErrInvalidEmail,
-			mockRepo: func() *MockRepository {
-				mockRepo := new(MockRepository)
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
 				return mockRepo
 			}(),
 			mockNotification: func() *notification.MockNotificationService {
@@ -85,8 +96,8 @@ func TestCreateRunner(t *testing.T) {
 			repoErr:         errors.New("repository error"),
 			notificationErr: nil,
 			wantErr:         errors.New("repository error"),
-			mockRepo: func() *MockRepository {
-				mockRepo := new(MockRepository)
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
 				mockRepo.On("Add", mock.Anything).
 					Return(errors.New("repository error"))
 				return mockRepo
@@ -103,8 +114,10 @@ func TestCreateRunner(t *testing.T) {
 			repoErr:         nil,
 			notificationErr: errors.New("notification error"),
 			wantErr:         nil,
-			mockRepo: func() *MockRepository {
-				mockRepo := new(MockRepository)
+			wantWarning:     true,
+			wantCreated:     true,
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
 				mockRepo.On("Add", mock.Anything).Return(nil)
 				return mockRepo
 			}(),
@@ -125,8 +138,11 @@ func TestCreateRunner(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			service := NewService(tt.mockRepo, tt.mockNotification)
-			_, err := service.CreateRunner(tt.runnerName, tt.email)
+			logger := new(logging.RecordingLogger)
+			recorder := new(metrics.MockRecorder)
+			recorder.On("RunnerCreated").Return()
+			service := NewService(tt.mockRepo, tt.mockNotification, logger, recorder)
+			id, err := service.CreateRunner(adminContext(), tt.runnerName, tt.email)
 
 			if (err != nil) && (tt.wantErr == nil || err.Error() != tt.wantErr.Error()) {
 				t.Errorf("CreateRunner() error = %v, wantErr %v", err, tt.wantErr)
@@ -134,25 +150,259 @@ func TestCreateRunner(t *testing.T) {
 
 			tt.mockRepo.AssertExpectations(t)
 			tt.mockNotification.AssertExpectations(t)
+			if tt.wantCreated {
+				recorder.AssertNumberOfCalls(t, "RunnerCreated", 1)
+			} else {
+				recorder.AssertNotCalled(t, "RunnerCreated")
+			}
+
+			records := logger.Records()
+			if !tt.wantWarning {
+				assert.Empty(t, records)
+				return
+			}
+			require.Len(t, records, 1)
+			assert.Equal(t, logging.LevelWarn, records[0].Level)
+			runnerID, _ := records[0].Field("runner_id")
+			assert.Equal(t, id.String(), runnerID)
+			loggedErr, _ := records[0].Field("error")
+			assert.Equal(t, tt.notificationErr, loggedErr)
+		})
+	}
+}
+
+func TestRenameRunner(t *testing.T) {
+	existing, _ := runner.NewRunner("John Doe", "john.doe@example.com")
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		newName  string
+		wantErr  error
+		mockRepo func() *runner.MockRepository
+	}{
+		{
+			name:    "Valid rename",
+			id:      existing.ID(),
+			newName: "Jane Doe",
+			wantErr: nil,
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
+				mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+				mockRepo.On("Update", existing).Return(nil)
+				return mockRepo
+			},
+		},
+		{
+			name:    "Runner not found",
+			id:      uuid.New(),
+			newName: "Jane Doe",
+			wantErr: runner.ErrNotFound,
+			mockRepo: func() *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
+				mockRepo.On("GetByID", mock.Anything).Return((*runner.Runner)(nil), runner.ErrNotFound)
+				return mockRepo
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := tt.mockRepo()
+			service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+			err := service.RenameRunner(adminContext(), tt.id, tt.newName)
+			assert.Equal(t, tt.wantErr, err)
+			mockRepo.AssertExpectations(t)
 		})
 	}
 }
 
-type MockRepository struct {
-	mock.Mock
+func TestUpdateRunnerProfile(t *testing.T) {
+	dateOfBirth := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		gender   string
+		wantErr  error
+		mockRepo func(existing *runner.Runner) *runner.MockRepository
+	}{
+		{
+			name:    "Valid profile",
+			gender:  "female",
+			wantErr: nil,
+			mockRepo: func(existing *runner.Runner) *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
+				mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+				mockRepo.On("Update", existing).Return(nil)
+				return mockRepo
+			},
+		},
+		{
+			name:    "Invalid gender",
+			gender:  "unknown",
+			wantErr: runner.ErrInvalidGender,
+			mockRepo: func(existing *runner.Runner) *runner.MockRepository {
+				mockRepo := new(runner.MockRepository)
+				mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+				return mockRepo
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, _ := runner.NewRunner("John Doe", "john.doe@example.com")
+			mockRepo := tt.mockRepo(existing)
+			service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+			err := service.UpdateRunnerProfile(adminContext(), existing.ID(), tt.gender, dateOfBirth)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, runner.Gender(tt.gender), existing.Gender())
+				assert.Equal(t, dateOfBirth, existing.DateOfBirth())
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
 }
 
-func (m *MockRepository) Add(r *runner.Runner) error {
-	args := m.Called(r)
-	return args.Error(0)
+func TestGetRunner(t *testing.T) {
+	existing, _ := runner.NewRunner("John Doe", "john.doe@example.com")
+
+	mockRepo := new(runner.MockRepository)
+	mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+	mockRepo.On("GetByID", mock.Anything).Return((*runner.Runner)(nil), runner.ErrNotFound)
+	service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	item, err := service.GetRunner(adminContext(), existing.ID())
+	assert.NoError(t, err)
+	assert.Equal(t, RunnerItem{
+		ID:           existing.ID(),
+		Name:         "John Doe",
+		EmailAddress: "john.doe@example.com",
+		CreatedAt:    existing.CreatedAt(),
+	}, item)
+
+	_, err = service.GetRunner(adminContext(), uuid.New())
+	assert.Equal(t, runner.ErrNotFound, err)
 }
 
-func (m *MockRepository) GetByID(id uuid.UUID) (*runner.Runner, error) {
-	args := m.Called(id)
-	return args.Get(0).(*runner.Runner), args.Error(1)
+func TestListRunners(t *testing.T) {
+	var runners []*runner.Runner
+	for _, name := range []string{"A", "B", "C"} {
+		r, _ := runner.NewRunner(name, "runner@example.com")
+		runners = append(runners, r)
+	}
+
+	tests := []struct {
+		name      string
+		offset    int
+		limit     int
+		wantErr   error
+		wantNames []string
+		wantLimit int
+	}{
+		{name: "Default limit", offset: 0, limit: 0, wantNames: []string{"A", "B", "C"}, wantLimit: DefaultPageLimit},
+		{name: "First page", offset: 0, limit: 2, wantNames: []string{"A", "B"}, wantLimit: 2},
+		{name: "Last page", offset: 2, limit: 2, wantNames: []string{"C"}, wantLimit: 2},
+		{name: "Offset past the end", offset: 5, limit: 2, wantNames: []string{}, wantLimit: 2},
+		{name: "Huge offset", offset: math.MaxInt, limit: MaxPageLimit, wantNames: []string{}, wantLimit: MaxPageLimit},
+		{name: "Negative offset", offset: -1, limit: 2, wantErr: ErrInvalidOffset},
+		{name: "Limit too large", offset: 0, limit: MaxPageLimit + 1, wantErr: ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(runner.MockRepository)
+			mockRepo.On("GetAll").Return(runners, nil)
+			service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			page, err := service.ListRunners(adminContext(), tt.offset, tt.limit)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			names := make([]string, len(page.Runners))
+			for i, r := range page.Runners {
+				names[i] = r.Name
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, 3, page.Total)
+			assert.Equal(t, tt.offset, page.Offset)
+			assert.Equal(t, tt.wantLimit, page.Limit)
+		})
+	}
+}
+
+func TestDeleteRunner(t *testing.T) {
+	id := uuid.New()
+	mockRepo := new(runner.MockRepository)
+	mockRepo.On("Delete", id).Return(runner.ErrNotFound)
+	service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+	err := service.DeleteRunner(adminContext(), id)
+	assert.Equal(t, runner.ErrNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunnerAuthorization(t *testing.T) {
+	existing, _ := runner.NewRunner("John Doe", "john.doe@example.com")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "No principal", ctx: context.Background(), wantErr: auth.ErrUnauthenticated},
+		{name: "Another runner", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "jane", Role: auth.RoleRunner, RunnerID: uuid.New()}), wantErr: auth.ErrForbidden},
+		{name: "Organiser", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser}), wantErr: auth.ErrForbidden},
+		{name: "The runner", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "john", Role: auth.RoleRunner, RunnerID: existing.ID()})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(runner.MockRepository)
+			mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+			mockRepo.On("Update", existing).Return(nil)
+			mockRepo.On("Delete", existing.ID()).Return(nil)
+			service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			assert.Equal(t, tt.wantErr, service.RenameRunner(tt.ctx, existing.ID(), "Jane Doe"))
+			assert.Equal(t, tt.wantErr, service.UpdateRunnerProfile(tt.ctx, existing.ID(), "male", time.Time{}))
+			assert.Equal(t, tt.wantErr, service.DeleteRunner(tt.ctx, existing.ID()))
+		})
+	}
+}
+
+func TestRunnerReadAuthorization(t *testing.T) {
+	existing, _ := runner.NewRunner("John Doe", "john.doe@example.com")
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantGetErr  error
+		wantListErr error
+	}{
+		{name: "No principal", ctx: context.Background(), wantGetErr: auth.ErrUnauthenticated, wantListErr: auth.ErrUnauthenticated},
+		{name: "Another runner", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "jane", Role: auth.RoleRunner, RunnerID: uuid.New()}), wantGetErr: auth.ErrForbidden, wantListErr: auth.ErrForbidden},
+		{name: "The runner", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "john", Role: auth.RoleRunner, RunnerID: existing.ID()}), wantListErr: auth.ErrForbidden},
+		{name: "Organiser", ctx: auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(runner.MockRepository)
+			mockRepo.On("GetByID", existing.ID()).Return(existing, nil)
+			mockRepo.On("GetAll").Return([]*runner.Runner{existing}, nil)
+			service := NewService(mockRepo, new(notification.MockNotificationService), logging.Discard, metrics.Discard)
+
+			_, err := service.GetRunner(tt.ctx, existing.ID())
+			assert.Equal(t, tt.wantGetErr, err)
+			_, err = service.ListRunners(tt.ctx, 0, 0)
+			assert.Equal(t, tt.wantListErr, err)
+		})
+	}
 }
 
-func (m *MockRepository) Update(r *runner.Runner) error {
-	args := m.Called(r)
-	return args.Error(0)
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
 }
@@ -0,0 +1,47 @@
+package timing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFormat of a file of timing reads
+type ReadFormat string
+
+// Supported timing read file formats
+const (
+	// ReadFormatCSV has a chip_id,checkpoint_id,timestamp row per read with an optional header row
+	ReadFormatCSV ReadFormat = "csv"
+	// ReadFormatLineProtocol has a line protocol point per read, such as "read,chip_id=E2001,checkpoint_id=finish 1759651200000000000"
+	ReadFormatLineProtocol ReadFormat = "lp"
+)
+
+var (
+	// ErrUnsupportedReadFormat Error when the timing read file format is not supported
+	ErrUnsupportedReadFormat = errors.New("read format must be one of csv or lp")
+	// ErrInvalidReadFile Error when the timing read file cannot be decoded
+	ErrInvalidReadFile = errors.New("invalid timing read file")
+)
+
+// ReadParser reads the timing reads of files exported by timing systems
+type ReadParser interface {
+	Parse(ctx context.Context, format ReadFormat, file io.Reader) ([]ReadItem, error)
+}
+
+// ParseReadFormat Returns the ReadFormat of the provided value, ignoring case
+func ParseReadFormat(value string) (ReadFormat, error) {
+	switch f := ReadFormat(strings.ToLower(value)); f {
+	case ReadFormatCSV, ReadFormatLineProtocol:
+		return f, nil
+	default:
+		return "", ErrUnsupportedReadFormat
+	}
+}
+
+// ReadFormatFromFilename Returns the ReadFormat matching the extension of the provided file name
+func ReadFormatFromFilename(name string) (ReadFormat, error) {
+	return ParseReadFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
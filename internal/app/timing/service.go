@@ -0,0 +1,346 @@
+// Package timing contains the service providing the use cases for the chip timing of races
+package timing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/retry"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+)
+
+// Service provides the timing operations
+type Service struct {
+	repo       timing.Repository
+	raceRepo   race.Repository
+	bibRepo    bib.Repository
+	readParser ReadParser
+	metrics    metrics.Recorder
+}
+
+// NewService creates a new Service with the given repositories, timing read file parser and metrics recorder
+func NewService(repo timing.Repository, raceRepo race.Repository, bibRepo bib.Repository, readParser ReadParser, recorder metrics.Recorder) Service {
+	return Service{repo: repo, raceRepo: raceRepo, bibRepo: bibRepo, readParser: readParser, metrics: recorder}
+}
+
+// CheckpointItem represents a timing mat at a distance of the course
+type CheckpointItem struct {
+	ID         string
+	DistanceKm float64
+}
+
+// SessionItem represents the timing settings of a race and the number of reads recorded so far
+type SessionItem struct {
+	GunTime     time.Time
+	Debounce    time.Duration
+	Checkpoints []CheckpointItem
+	Reads       int
+}
+
+// ReadItem represents a detection of a timing chip by the mat of a checkpoint
+type ReadItem struct {
+	ChipID       string
+	CheckpointID string
+	At           time.Time
+}
+
+// RecordedReadsItem represents the outcome of recording a batch of reads
+type RecordedReadsItem struct {
+	Received   int
+	Accepted   int
+	Duplicates int
+}
+
+// SplitItem represents the net time at an intermediate checkpoint
+type SplitItem struct {
+	CheckpointID string
+	DistanceKm   float64
+	Elapsed      time.Duration
+}
+
+// TimeItem represents the timing of a chip that crossed the finish line.
+// Bib is 0 and RunnerID is empty when the chip is not mapped to a bib, and Wave is empty when the runner
+// starts with the gun of the timing session.
+type TimeItem struct {
+	ChipID     string
+	Bib        int
+	RunnerID   uuid.UUID
+	Wave       string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	GunTime    time.Duration
+	NetTime    time.Duration
+	Splits     []SplitItem
+}
+
+// GeneratedResultsItem represents the outcome of generating results from the timing reads.
+// Skipped counts the runners that already have a result for the race.
+type GeneratedResultsItem struct {
+	Created        int
+	Skipped        int
+	UnmatchedChips []string
+}
+
+// ConfigureTiming sets the gun time, the debounce and the checkpoints of the race. The furthest checkpoint
+// is the finish and must be at the race distance, and checkpoints that already have reads cannot be removed.
+func (s Service) ConfigureTiming(ctx context.Context, raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []CheckpointItem) error {
+	if raceID == uuid.Nil {
+		return timing.ErrEmptyRaceID
+	}
+	domainCheckpoints := make([]timing.Checkpoint, len(checkpoints))
+	for i, c := range checkpoints {
+		var err error
+		domainCheckpoints[i], err = timing.NewCheckpoint(c.ID, c.DistanceKm)
+		if err != nil {
+			return err
+		}
+	}
+
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	if err := auth.RequireOrganiser(ctx, r); err != nil {
+		return err
+	}
+
+	return retry.OnConflict(timing.ErrConcurrentUpdate, func() error {
+		session, err := s.repo.GetSession(ctx, raceID)
+		switch {
+		case errors.Is(err, timing.ErrNotConfigured):
+			session, err = timing.NewSession(raceID, gunTime, debounce, domainCheckpoints, r.DistanceKm())
+		case err == nil:
+			err = session.Configure(gunTime, debounce, domainCheckpoints, r.DistanceKm())
+		}
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveSession(ctx, session)
+	})
+}
+
+// GetTiming returns the timing settings of the race
+func (s Service) GetTiming(ctx context.Context, raceID uuid.UUID) (SessionItem, error) {
+	if raceID == uuid.Nil {
+		return SessionItem{}, timing.ErrEmptyRaceID
+	}
+	session, err := s.repo.GetSession(ctx, raceID)
+	if err != nil {
+		return SessionItem{}, err
+	}
+
+	checkpoints := session.Checkpoints()
+	items := make([]CheckpointItem, len(checkpoints))
+	for i, c := range checkpoints {
+		items[i] = CheckpointItem{ID: c.ID(), DistanceKm: c.DistanceKm()}
+	}
+	return SessionItem{
+		GunTime:     session.GunTime(),
+		Debounce:    session.Debounce(),
+		Checkpoints: items,
+		Reads:       len(session.Reads()),
+	}, nil
+}
+
+// RecordReads adds a batch of reads to the timing of the race. Duplicates within the debounce are dropped,
+// so a batch can safely be sent again. The batch is rejected as a whole when a read is invalid.
+func (s Service) RecordReads(ctx context.Context, raceID uuid.UUID, reads []ReadItem) (RecordedReadsItem, error) {
+	if raceID == uuid.Nil {
+		return RecordedReadsItem{}, timing.ErrEmptyRaceID
+	}
+	domainReads := make([]timing.Read, len(reads))
+	for i, r := range reads {
+		var err error
+		domainReads[i], err = timing.NewRead(r.ChipID, r.CheckpointID, r.At)
+		if err != nil {
+			return RecordedReadsItem{}, err
+		}
+	}
+	if err := s.authorizeOrganiser(ctx, raceID); err != nil {
+		return RecordedReadsItem{}, err
+	}
+
+	var accepted int
+	err := retry.OnConflict(timing.ErrConcurrentUpdate, func() error {
+		session, err := s.repo.GetSession(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		accepted, err = session.Record(domainReads)
+		if err != nil {
+			return err
+		}
+		if accepted == 0 {
+			return nil
+		}
+		return s.repo.SaveSession(ctx, session)
+	})
+	if err != nil {
+		return RecordedReadsItem{}, err
+	}
+	return RecordedReadsItem{Received: len(reads), Accepted: accepted, Duplicates: len(reads) - accepted}, nil
+}
+
+// ImportReads records the reads of a file exported by the timing system, in the same way as RecordReads
+func (s Service) ImportReads(ctx context.Context, raceID uuid.UUID, format ReadFormat, file io.Reader) (RecordedReadsItem, error) {
+	if raceID == uuid.Nil {
+		return RecordedReadsItem{}, timing.ErrEmptyRaceID
+	}
+	reads, err := s.readParser.Parse(ctx, format, file)
+	if err != nil {
+		return RecordedReadsItem{}, err
+	}
+	return s.RecordReads(ctx, raceID, reads)
+}
+
+// authorizeOrganiser allows admins and the organiser of the race with the provided id, which manage its timing
+func (s Service) authorizeOrganiser(ctx context.Context, raceID uuid.UUID) error {
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return err
+	}
+	return auth.RequireOrganiser(ctx, r)
+}
+
+// GetTimes returns the gun and net times of the chips that crossed the finish line ordered by net time,
+// with the bib and the runner the chip is mapped to. Runners whose bib range is named after a wave of the
+// race are timed from the start of the wave.
+func (s Service) GetTimes(ctx context.Context, raceID uuid.UUID) ([]TimeItem, error) {
+	if raceID == uuid.Nil {
+		return nil, timing.ErrEmptyRaceID
+	}
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.repo.GetSession(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	allocation, err := s.bibRepo.GetAllocation(ctx, raceID)
+	if err != nil && !errors.Is(err, bib.ErrNotConfigured) {
+		return nil, err
+	}
+
+	finishes := session.Finishes(waveGuns(allocation, r))
+	items := make([]TimeItem, len(finishes))
+	for i, f := range finishes {
+		items[i] = TimeItem{
+			ChipID:     f.ChipID,
+			StartedAt:  f.StartedAt,
+			FinishedAt: f.FinishedAt,
+			GunTime:    f.GunTime,
+			NetTime:    f.NetTime,
+		}
+		for _, split := range f.Splits {
+			items[i].Splits = append(items[i].Splits, SplitItem{CheckpointID: split.CheckpointID, DistanceKm: split.DistanceKm, Elapsed: split.Elapsed})
+		}
+		if allocation == nil {
+			continue
+		}
+		if assignment, ok := allocation.AssignmentByChip(f.ChipID); ok {
+			items[i].Bib = assignment.Number()
+			items[i].RunnerID = assignment.RunnerID()
+			if _, inWave := r.Wave(assignment.RangeName()); inWave {
+				items[i].Wave = assignment.RangeName()
+			}
+		}
+	}
+	return items, nil
+}
+
+// GenerateResults creates a provisional finished result with the net time, the gun time and the checkpoint splits
+// for every runner whose chip crossed the finish line; the pace is derived from the net time. Runners that already have a result for the race are skipped, so
+// results can be generated again as late reads arrive; chips that are not mapped to a bib are reported.
+func (s Service) GenerateResults(ctx context.Context, raceID uuid.UUID) (GeneratedResultsItem, error) {
+	if raceID == uuid.Nil {
+		return GeneratedResultsItem{}, timing.ErrEmptyRaceID
+	}
+	r, err := s.raceRepo.GetRace(ctx, raceID)
+	if err != nil {
+		return GeneratedResultsItem{}, err
+	}
+	if err := auth.RequireOrganiser(ctx, r); err != nil {
+		return GeneratedResultsItem{}, err
+	}
+	session, err := s.repo.GetSession(ctx, raceID)
+	if err != nil {
+		return GeneratedResultsItem{}, err
+	}
+	allocation, err := s.bibRepo.GetAllocation(ctx, raceID)
+	if err != nil {
+		return GeneratedResultsItem{}, err
+	}
+	existing, err := s.raceRepo.GetResultsByRace(ctx, raceID)
+	if err != nil {
+		return GeneratedResultsItem{}, err
+	}
+	hasResult := make(map[uuid.UUID]bool, len(existing))
+	for _, result := range existing {
+		hasResult[result.RunnerID()] = true
+	}
+
+	var generated GeneratedResultsItem
+	for _, f := range session.Finishes(waveGuns(allocation, r)) {
+		assignment, ok := allocation.AssignmentByChip(f.ChipID)
+		if !ok {
+			generated.UnmatchedChips = append(generated.UnmatchedChips, f.ChipID)
+			continue
+		}
+		if hasResult[assignment.RunnerID()] {
+			generated.Skipped++
+			continue
+		}
+
+		result, err := race.NewResult(assignment.RunnerID(), raceID, f.NetTime, f.NetTime.Minutes()/r.DistanceKm(), 0, "")
+		if err != nil {
+			return generated, err
+		}
+		result, err = result.WithGunTime(f.GunTime)
+		if err != nil {
+			return generated, err
+		}
+		splits := make([]race.Split, len(f.Splits))
+		for i, split := range f.Splits {
+			splits[i], err = race.NewSplit(split.DistanceKm, split.Elapsed)
+			if err != nil {
+				return generated, err
+			}
+		}
+		result, err = result.WithSplits(splits, r.DistanceKm())
+		if err != nil {
+			return generated, err
+		}
+		if err := s.raceRepo.SaveRaceResult(ctx, result); err != nil {
+			return generated, err
+		}
+		s.metrics.ResultLogged(ctx, string(result.Status()))
+		hasResult[assignment.RunnerID()] = true
+		generated.Created++
+	}
+	return generated, nil
+}
+
+// waveGuns returns the start time of the wave of every chip whose bib range is named after a wave of the race
+func waveGuns(allocation *bib.Allocation, r race.Race) map[string]time.Time {
+	guns := make(map[string]time.Time)
+	if allocation == nil {
+		return guns
+	}
+	for _, assignment := range allocation.Assignments() {
+		if assignment.ChipID() == "" {
+			continue
+		}
+		if wave, ok := r.Wave(assignment.RangeName()); ok {
+			guns[assignment.ChipID()] = wave.StartTime()
+		}
+	}
+	return guns
+}
@@ -0,0 +1,406 @@
+package timing
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTimingRepository stores copies of sessions and rejects the first conflicts saves as concurrent updates
+type fakeTimingRepository struct {
+	sessions  map[uuid.UUID]*timing.Session
+	conflicts int
+	saves     int
+}
+
+func newFakeTimingRepository() *fakeTimingRepository {
+	return &fakeTimingRepository{sessions: make(map[uuid.UUID]*timing.Session)}
+}
+
+func (f *fakeTimingRepository) GetSession(_ context.Context, raceID uuid.UUID) (*timing.Session, error) {
+	s, exists := f.sessions[raceID]
+	if !exists {
+		return nil, timing.ErrNotConfigured
+	}
+	return timing.LoadSession(s.RaceID(), s.GunTime(), s.Debounce(), s.Checkpoints(), s.Reads(), s.Version())
+}
+
+func (f *fakeTimingRepository) SaveSession(_ context.Context, s *timing.Session) error {
+	f.saves++
+	if f.saves <= f.conflicts {
+		return timing.ErrConcurrentUpdate
+	}
+	saved, err := timing.LoadSession(s.RaceID(), s.GunTime(), s.Debounce(), s.Checkpoints(), s.Reads(), s.Version()+1)
+	if err != nil {
+		return err
+	}
+	f.sessions[s.RaceID()] = saved
+	return nil
+}
+
+type mockRaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockRaceRepository) SaveRace(_ context.Context, r race.Race) error {
+	return m.Called(r).Error(0)
+}
+
+func (m *mockRaceRepository) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	args := m.Called(search)
+	return args.Get(0).([]race.Race), args.Error(1)
+}
+
+func (m *mockRaceRepository) SaveRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
+	args := m.Called(resultID)
+	return args.Get(0).(race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) UpdateRaceResult(_ context.Context, result race.Result) error {
+	return m.Called(result).Error(0)
+}
+
+func (m *mockRaceRepository) SaveRaceWithResults(_ context.Context, r race.Race, results []race.Result) error {
+	return m.Called(r, results).Error(0)
+}
+
+func (m *mockRaceRepository) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+func (m *mockRaceRepository) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.Result), args.Error(1)
+}
+
+type mockBibRepository struct {
+	mock.Mock
+}
+
+func (m *mockBibRepository) GetAllocation(_ context.Context, raceID uuid.UUID) (*bib.Allocation, error) {
+	args := m.Called(raceID)
+	a, _ := args.Get(0).(*bib.Allocation)
+	return a, args.Error(1)
+}
+
+func (m *mockBibRepository) SaveAllocation(_ context.Context, a *bib.Allocation) error {
+	return m.Called(a).Error(0)
+}
+
+type mockReadParser struct {
+	mock.Mock
+}
+
+func (m *mockReadParser) Parse(_ context.Context, format ReadFormat, _ io.Reader) ([]ReadItem, error) {
+	args := m.Called(format)
+	reads, _ := args.Get(0).([]ReadItem)
+	return reads, args.Error(1)
+}
+
+var gun = time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+
+var checkpoints = []CheckpointItem{{ID: "start", DistanceKm: 0}, {ID: "5k", DistanceKm: 5}, {ID: "finish", DistanceKm: 10}}
+
+type fixture struct {
+	service    Service
+	repo       *fakeTimingRepository
+	raceRepo   *mockRaceRepository
+	bibRepo    *mockBibRepository
+	readParser *mockReadParser
+	metrics    *metrics.MockRecorder
+	race       race.Race
+}
+
+func newFixture(t *testing.T) fixture {
+	r, err := race.NewRace("Athens 10K", "Athens", gun, 10, 50)
+	require.NoError(t, err)
+	return newFixtureOf(r.WithOrganiser("athens-events"))
+}
+
+func newFixtureOf(r race.Race) fixture {
+	raceRepo := new(mockRaceRepository)
+	raceRepo.On("GetRace", r.ID()).Return(r, nil)
+	raceRepo.On("GetRace", mock.Anything).Return(race.Race{}, race.ErrNotFound)
+
+	recorder := new(metrics.MockRecorder)
+	recorder.On("ResultLogged", mock.Anything).Return()
+
+	f := fixture{repo: newFakeTimingRepository(), raceRepo: raceRepo, bibRepo: new(mockBibRepository), readParser: new(mockReadParser), metrics: recorder, race: r}
+	f.service = NewService(f.repo, f.raceRepo, f.bibRepo, f.readParser, f.metrics)
+	return f
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System)
+}
+
+// configured returns a fixture whose race is timed with a 5 second debounce
+func configured(t *testing.T) fixture {
+	f := newFixture(t)
+	require.NoError(t, f.service.ConfigureTiming(adminContext(), f.race.ID(), gun, 5*time.Second, checkpoints))
+	return f
+}
+
+func TestService_ConfigureTiming(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name        string
+		raceID      uuid.UUID
+		gunTime     time.Time
+		checkpoints []CheckpointItem
+		wantErr     error
+	}{
+		{name: "empty race id", raceID: uuid.Nil, gunTime: gun, checkpoints: checkpoints, wantErr: timing.ErrEmptyRaceID},
+		{name: "unknown race", raceID: uuid.New(), gunTime: gun, checkpoints: checkpoints, wantErr: race.ErrNotFound},
+		{name: "missing gun time", raceID: f.race.ID(), checkpoints: checkpoints, wantErr: timing.ErrMissingGunTime},
+		{name: "invalid checkpoint", raceID: f.race.ID(), gunTime: gun, checkpoints: []CheckpointItem{{ID: "", DistanceKm: 10}}, wantErr: timing.ErrEmptyCheckpointID},
+		{name: "finish short of the race", raceID: f.race.ID(), gunTime: gun, checkpoints: []CheckpointItem{{ID: "5k", DistanceKm: 5}}, wantErr: timing.ErrMissingFinish},
+		{name: "valid", raceID: f.race.ID(), gunTime: gun, checkpoints: checkpoints},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.service.ConfigureTiming(adminContext(), tt.raceID, tt.gunTime, time.Second, tt.checkpoints)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got, err := f.service.GetTiming(adminContext(), tt.raceID)
+			require.NoError(t, err)
+			assert.Equal(t, SessionItem{GunTime: gun, Debounce: time.Second, Checkpoints: checkpoints}, got)
+		})
+	}
+}
+
+func TestService_RecordReads(t *testing.T) {
+	f := configured(t)
+	f.repo.conflicts = 1
+	reads := []ReadItem{
+		{ChipID: "CHIP-1", CheckpointID: "finish", At: gun.Add(40 * time.Minute)},
+		{ChipID: "CHIP-1", CheckpointID: "finish", At: gun.Add(40*time.Minute + time.Second)},
+		{ChipID: "CHIP-2", CheckpointID: "finish", At: gun.Add(41 * time.Minute)},
+	}
+
+	got, err := f.service.RecordReads(adminContext(), f.race.ID(), reads)
+	require.NoError(t, err)
+	assert.Equal(t, RecordedReadsItem{Received: 3, Accepted: 2, Duplicates: 1}, got)
+
+	got, err = f.service.RecordReads(adminContext(), f.race.ID(), reads)
+	require.NoError(t, err)
+	assert.Equal(t, RecordedReadsItem{Received: 3, Duplicates: 3}, got)
+
+	_, err = f.service.RecordReads(adminContext(), f.race.ID(), []ReadItem{{ChipID: "CHIP-3", CheckpointID: "10k", At: gun}})
+	assert.ErrorIs(t, err, timing.ErrUnknownCheckpoint)
+	_, err = f.service.RecordReads(adminContext(), f.race.ID(), []ReadItem{{ChipID: "", CheckpointID: "finish", At: gun}})
+	assert.ErrorIs(t, err, timing.ErrEmptyChipID)
+	_, err = f.service.RecordReads(adminContext(), uuid.New(), reads)
+	assert.ErrorIs(t, err, race.ErrNotFound)
+
+	session, err := f.service.GetTiming(adminContext(), f.race.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, session.Reads)
+}
+
+func TestService_ImportReads(t *testing.T) {
+	f := configured(t)
+	f.readParser.On("Parse", ReadFormatCSV).Return([]ReadItem{{ChipID: "CHIP-1", CheckpointID: "finish", At: gun.Add(40 * time.Minute)}}, nil)
+	f.readParser.On("Parse", ReadFormatLineProtocol).Return(nil, ErrInvalidReadFile)
+
+	got, err := f.service.ImportReads(adminContext(), f.race.ID(), ReadFormatCSV, strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, RecordedReadsItem{Received: 1, Accepted: 1}, got)
+
+	_, err = f.service.ImportReads(adminContext(), f.race.ID(), ReadFormatLineProtocol, strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrInvalidReadFile)
+}
+
+func TestReadFormatFromFilename(t *testing.T) {
+	got, err := ReadFormatFromFilename("reads.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, ReadFormatCSV, got)
+	got, err = ReadFormatFromFilename("mat-2.lp")
+	require.NoError(t, err)
+	assert.Equal(t, ReadFormatLineProtocol, got)
+	_, err = ReadFormatFromFilename("reads.xlsx")
+	assert.ErrorIs(t, err, ErrUnsupportedReadFormat)
+}
+
+// timedRace returns a configured fixture where the chips of two runners and an unknown chip crossed the finish line
+func timedRace(t *testing.T) (fixture, uuid.UUID, uuid.UUID) {
+	f := configured(t)
+	_, err := f.service.RecordReads(adminContext(), f.race.ID(), []ReadItem{
+		{ChipID: "CHIP-1", CheckpointID: "start", At: gun.Add(20 * time.Second)},
+		{ChipID: "CHIP-1", CheckpointID: "5k", At: gun.Add(20*time.Minute + 20*time.Second)},
+		{ChipID: "CHIP-1", CheckpointID: "finish", At: gun.Add(40*time.Minute + 20*time.Second)},
+		{ChipID: "CHIP-2", CheckpointID: "finish", At: gun.Add(45 * time.Minute)},
+		{ChipID: "CHIP-9", CheckpointID: "finish", At: gun.Add(50 * time.Minute)},
+	})
+	require.NoError(t, err)
+
+	elite, err := bib.NewRange("elite", 1, 99)
+	require.NoError(t, err)
+	allocation, err := bib.NewAllocation(f.race.ID(), []bib.Range{elite})
+	require.NoError(t, err)
+	anna, bob := uuid.New(), uuid.New()
+	_, err = allocation.Assign(anna, "elite", "CHIP-1", gun)
+	require.NoError(t, err)
+	_, err = allocation.Assign(bob, "elite", "CHIP-2", gun)
+	require.NoError(t, err)
+	f.bibRepo.On("GetAllocation", f.race.ID()).Return(allocation, nil)
+	return f, anna, bob
+}
+
+func TestService_GetTimes(t *testing.T) {
+	f, anna, bob := timedRace(t)
+
+	got, err := f.service.GetTimes(adminContext(), f.race.ID())
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, TimeItem{
+		ChipID:     "CHIP-1",
+		Bib:        1,
+		RunnerID:   anna,
+		StartedAt:  gun.Add(20 * time.Second),
+		FinishedAt: gun.Add(40*time.Minute + 20*time.Second),
+		GunTime:    40*time.Minute + 20*time.Second,
+		NetTime:    40 * time.Minute,
+		Splits:     []SplitItem{{CheckpointID: "5k", DistanceKm: 5, Elapsed: 20 * time.Minute}},
+	}, got[0])
+	assert.Equal(t, bob, got[1].RunnerID)
+	assert.Equal(t, 45*time.Minute, got[1].NetTime)
+	assert.Equal(t, "CHIP-9", got[2].ChipID)
+	assert.Zero(t, got[2].Bib)
+}
+
+func TestService_GenerateResults(t *testing.T) {
+	f, anna, bob := timedRace(t)
+	dnf, err := race.NewResultWithStatus(bob, f.race.ID(), race.StatusDidNotFinish, "", 0, 0, 0, "")
+	require.NoError(t, err)
+	f.raceRepo.On("GetResultsByRace", f.race.ID()).Return([]race.Result{dnf}, nil)
+	var saved []race.Result
+	f.raceRepo.On("SaveRaceResult", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).(race.Result))
+	}).Return(nil)
+
+	got, err := f.service.GenerateResults(adminContext(), f.race.ID())
+	require.NoError(t, err)
+	assert.Equal(t, GeneratedResultsItem{Created: 1, Skipped: 1, UnmatchedChips: []string{"CHIP-9"}}, got)
+	f.metrics.AssertNumberOfCalls(t, "ResultLogged", 1)
+	f.metrics.AssertCalled(t, "ResultLogged", "finished")
+
+	require.Len(t, saved, 1)
+	result := saved[0]
+	assert.Equal(t, anna, result.RunnerID())
+	assert.Equal(t, race.StatusFinished, result.Status())
+	assert.Equal(t, race.PublicationProvisional, result.Publication())
+	assert.Equal(t, 40*time.Minute, result.FinishTime())
+	assert.Equal(t, 40*time.Minute+20*time.Second, result.GunTime())
+	assert.InDelta(t, 4.0, result.Pace(), 0.0001)
+	require.Len(t, result.Splits(), 1)
+	assert.Equal(t, 5.0, result.Splits()[0].DistanceKm())
+	assert.Equal(t, 20*time.Minute, result.Splits()[0].Elapsed())
+}
+
+func TestService_GenerateResults_Waves(t *testing.T) {
+	first, err := race.NewWave("A", gun)
+	require.NoError(t, err)
+	second, err := race.NewWave("B", gun.Add(10*time.Minute))
+	require.NoError(t, err)
+	r, err := race.NewRace("Athens 10K", "Athens", gun, 10, 50)
+	require.NoError(t, err)
+	r, err = r.WithWaves([]race.Wave{first, second})
+	require.NoError(t, err)
+	f := newFixtureOf(r)
+	require.NoError(t, f.service.ConfigureTiming(adminContext(), r.ID(), gun, 0, checkpoints))
+	_, err = f.service.RecordReads(adminContext(), r.ID(), []ReadItem{
+		{ChipID: "CHIP-A", CheckpointID: "finish", At: gun.Add(41 * time.Minute)},
+		{ChipID: "CHIP-B", CheckpointID: "start", At: gun.Add(10*time.Minute + 30*time.Second)},
+		{ChipID: "CHIP-B", CheckpointID: "finish", At: gun.Add(50*time.Minute + 30*time.Second)},
+	})
+	require.NoError(t, err)
+
+	rangeA, err := bib.NewRange("A", 1, 999)
+	require.NoError(t, err)
+	rangeB, err := bib.NewRange("B", 1000, 1999)
+	require.NoError(t, err)
+	allocation, err := bib.NewAllocation(r.ID(), []bib.Range{rangeA, rangeB})
+	require.NoError(t, err)
+	anna, bob := uuid.New(), uuid.New()
+	_, err = allocation.Assign(anna, "A", "CHIP-A", gun)
+	require.NoError(t, err)
+	_, err = allocation.Assign(bob, "B", "CHIP-B", gun)
+	require.NoError(t, err)
+	f.bibRepo.On("GetAllocation", r.ID()).Return(allocation, nil)
+
+	times, err := f.service.GetTimes(adminContext(), r.ID())
+	require.NoError(t, err)
+	require.Len(t, times, 2)
+	assert.Equal(t, "B", times[0].Wave)
+	assert.Equal(t, 40*time.Minute+30*time.Second, times[0].GunTime, "the wave B gun is 10 minutes after the session gun")
+	assert.Equal(t, 40*time.Minute, times[0].NetTime)
+	assert.Equal(t, "A", times[1].Wave)
+	assert.Equal(t, 41*time.Minute, times[1].GunTime)
+
+	f.raceRepo.On("GetResultsByRace", r.ID()).Return([]race.Result{}, nil)
+	var saved []race.Result
+	f.raceRepo.On("SaveRaceResult", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).(race.Result))
+	}).Return(nil)
+	_, err = f.service.GenerateResults(adminContext(), r.ID())
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, bob, saved[0].RunnerID())
+	assert.Equal(t, 40*time.Minute+30*time.Second, saved[0].GunTime())
+}
+
+func TestService_GenerateResults_WithoutBibs(t *testing.T) {
+	f := configured(t)
+	f.bibRepo.On("GetAllocation", f.race.ID()).Return(nil, bib.ErrNotConfigured)
+
+	_, err := f.service.GenerateResults(adminContext(), f.race.ID())
+	assert.ErrorIs(t, err, bib.ErrNotConfigured)
+	f.raceRepo.AssertNotCalled(t, "SaveRaceResult", mock.Anything)
+}
+
+func TestService_Authorization(t *testing.T) {
+	f := newFixture(t)
+	organiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})
+	otherOrganiser := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sparta-events", Role: auth.RoleOrganiser})
+	reads := []ReadItem{{ChipID: "CHIP-1", CheckpointID: "finish", At: gun.Add(40 * time.Minute)}}
+
+	assert.ErrorIs(t, f.service.ConfigureTiming(context.Background(), f.race.ID(), gun, time.Second, checkpoints), auth.ErrUnauthenticated)
+	assert.ErrorIs(t, f.service.ConfigureTiming(otherOrganiser, f.race.ID(), gun, time.Second, checkpoints), auth.ErrForbidden)
+	require.NoError(t, f.service.ConfigureTiming(organiser, f.race.ID(), gun, time.Second, checkpoints))
+
+	_, err := f.service.RecordReads(otherOrganiser, f.race.ID(), reads)
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+	got, err := f.service.RecordReads(organiser, f.race.ID(), reads)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Accepted)
+
+	_, err = f.service.GenerateResults(otherOrganiser, f.race.ID())
+	assert.ErrorIs(t, err, auth.ErrForbidden)
+}
@@ -0,0 +1,377 @@
+package bib
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyRaceID          = errors.New("race ID cannot be empty")
+	ErrEmptyRunnerID        = errors.New("runner ID cannot be empty")
+	ErrRangeNotFound        = errors.New("bib range not found")
+	ErrRangeFull            = errors.New("every bib number of the range is assigned")
+	ErrNumberOutsideRanges  = errors.New("bib number is not part of a range")
+	ErrNumberTaken          = errors.New("bib number is assigned to another runner")
+	ErrChipTaken            = errors.New("chip ID is mapped to another bib")
+	ErrAlreadyAssigned      = errors.New("runner already has a bib for the race")
+	ErrNotAssigned          = errors.New("runner has no bib for the race")
+	ErrMissingChangeReason  = errors.New("reason is required to reassign a bib")
+	ErrAssignmentOutOfRange = errors.New("ranges must keep every assigned bib in the range it was assigned from")
+)
+
+// Assignment is the bib number and the optional timing chip of a runner in a race
+type Assignment struct {
+	runnerID   uuid.UUID
+	number     int
+	rangeName  string
+	chipID     string
+	assignedAt time.Time
+}
+
+// LoadAssignment recreates an existing Assignment from stored data
+func LoadAssignment(runnerID uuid.UUID, number int, rangeName, chipID string, assignedAt time.Time) Assignment {
+	return Assignment{runnerID: runnerID, number: number, rangeName: rangeName, chipID: chipID, assignedAt: assignedAt}
+}
+
+// RunnerID returns the ID of the runner wearing the bib
+func (a Assignment) RunnerID() uuid.UUID {
+	return a.runnerID
+}
+
+// Number returns the bib number
+func (a Assignment) Number() int {
+	return a.number
+}
+
+// RangeName returns the category or wave the bib was assigned from
+func (a Assignment) RangeName() string {
+	return a.rangeName
+}
+
+// ChipID returns the ID of the RFID timing chip mapped to the bib, empty when there is none
+func (a Assignment) ChipID() string {
+	return a.chipID
+}
+
+// AssignedAt returns the time of the last change of the assignment
+func (a Assignment) AssignedAt() time.Time {
+	return a.assignedAt
+}
+
+// Change is an audit record of a bib reassignment
+type Change struct {
+	runnerID       uuid.UUID
+	previousNumber int
+	number         int
+	previousChipID string
+	chipID         string
+	reason         string
+	changedAt      time.Time
+}
+
+// LoadChange recreates an existing Change from stored data
+func LoadChange(runnerID uuid.UUID, previousNumber, number int, previousChipID, chipID, reason string, changedAt time.Time) Change {
+	return Change{
+		runnerID:       runnerID,
+		previousNumber: previousNumber,
+		number:         number,
+		previousChipID: previousChipID,
+		chipID:         chipID,
+		reason:         reason,
+		changedAt:      changedAt,
+	}
+}
+
+// RunnerID returns the ID of the runner whose bib changed
+func (c Change) RunnerID() uuid.UUID {
+	return c.runnerID
+}
+
+// PreviousNumber returns the bib number before the change
+func (c Change) PreviousNumber() int {
+	return c.previousNumber
+}
+
+// Number returns the bib number after the change
+func (c Change) Number() int {
+	return c.number
+}
+
+// PreviousChipID returns the chip ID before the change
+func (c Change) PreviousChipID() string {
+	return c.previousChipID
+}
+
+// ChipID returns the chip ID after the change
+func (c Change) ChipID() string {
+	return c.chipID
+}
+
+// Reason returns why the bib was reassigned
+func (c Change) Reason() string {
+	return c.reason
+}
+
+// ChangedAt returns the time of the change
+func (c Change) ChangedAt() time.Time {
+	return c.changedAt
+}
+
+// Allocation is the aggregate of the bibs of a race.
+// It keeps bib numbers and chip IDs unique within the race and records every reassignment.
+type Allocation struct {
+	raceID      uuid.UUID
+	ranges      []Range
+	assignments []Assignment
+	changes     []Change
+	version     int
+}
+
+// NewAllocation creates the bib allocation of a race with the provided ranges
+func NewAllocation(raceID uuid.UUID, ranges []Range) (*Allocation, error) {
+	if raceID == uuid.Nil {
+		return nil, ErrEmptyRaceID
+	}
+	sorted, err := validateRanges(ranges)
+	if err != nil {
+		return nil, err
+	}
+	return &Allocation{raceID: raceID, ranges: sorted}, nil
+}
+
+// LoadAllocation recreates an existing Allocation from stored data.
+// changes are expected in the order they were made; version is used to detect concurrent updates.
+func LoadAllocation(raceID uuid.UUID, ranges []Range, assignments []Assignment, changes []Change, version int) (*Allocation, error) {
+	a, err := NewAllocation(raceID, ranges)
+	if err != nil {
+		return nil, err
+	}
+	a.assignments = append([]Assignment(nil), assignments...)
+	a.changes = append([]Change(nil), changes...)
+	a.version = version
+	return a, nil
+}
+
+// Configure replaces the ranges of the race. Every assigned bib must stay inside a range with the name it was
+// assigned from, so ranges can grow and new ranges can be added once bibs are handed out.
+func (a *Allocation) Configure(ranges []Range) error {
+	sorted, err := validateRanges(ranges)
+	if err != nil {
+		return err
+	}
+	for _, assignment := range a.assignments {
+		r, found := findRange(sorted, assignment.rangeName)
+		if !found || !r.Contains(assignment.number) {
+			return ErrAssignmentOutOfRange
+		}
+	}
+	a.ranges = sorted
+	return nil
+}
+
+// Assign gives the runner the lowest free number of the named range and maps the optional chip to it
+func (a *Allocation) Assign(runnerID uuid.UUID, rangeName, chipID string, at time.Time) (Assignment, error) {
+	if runnerID == uuid.Nil {
+		return Assignment{}, ErrEmptyRunnerID
+	}
+	if _, assigned := a.Assignment(runnerID); assigned {
+		return Assignment{}, ErrAlreadyAssigned
+	}
+	r, found := findRange(a.ranges, rangeName)
+	if !found {
+		return Assignment{}, ErrRangeNotFound
+	}
+	chipID = strings.TrimSpace(chipID)
+	if err := a.checkChip(chipID, runnerID); err != nil {
+		return Assignment{}, err
+	}
+	number, err := a.nextFree(r)
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	assignment := Assignment{runnerID: runnerID, number: number, rangeName: r.name, chipID: chipID, assignedAt: at}
+	a.assignments = append(a.assignments, assignment)
+	return assignment, nil
+}
+
+// Reassign changes the bib number or the chip of the runner and records the change with its reason.
+// A number of 0 keeps the number when the range does not change and otherwise picks the lowest free number of
+// the named range, which defaults to the current one. Other numbers must belong to one of the ranges.
+func (a *Allocation) Reassign(runnerID uuid.UUID, number int, rangeName, chipID, reason string, at time.Time) (Assignment, error) {
+	i := a.indexOf(runnerID)
+	if i < 0 {
+		return Assignment{}, ErrNotAssigned
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return Assignment{}, ErrMissingChangeReason
+	}
+	chipID = strings.TrimSpace(chipID)
+	if err := a.checkChip(chipID, runnerID); err != nil {
+		return Assignment{}, err
+	}
+
+	previous := a.assignments[i]
+	var r Range
+	switch {
+	case number < 0:
+		return Assignment{}, ErrNumberOutsideRanges
+	case number > 0:
+		var found bool
+		r, found = a.rangeOf(number)
+		if !found {
+			return Assignment{}, ErrNumberOutsideRanges
+		}
+		if holder, taken := a.holderOf(number); taken && holder != runnerID {
+			return Assignment{}, ErrNumberTaken
+		}
+	default:
+		if strings.TrimSpace(rangeName) == "" {
+			rangeName = previous.rangeName
+		}
+		var found bool
+		r, found = findRange(a.ranges, rangeName)
+		if !found {
+			return Assignment{}, ErrRangeNotFound
+		}
+		if r.name == previous.rangeName {
+			number = previous.number
+			break
+		}
+		var err error
+		number, err = a.nextFree(r)
+		if err != nil {
+			return Assignment{}, err
+		}
+	}
+
+	a.assignments[i] = Assignment{runnerID: runnerID, number: number, rangeName: r.name, chipID: chipID, assignedAt: at}
+	a.changes = append(a.changes, Change{
+		runnerID:       runnerID,
+		previousNumber: previous.number,
+		number:         number,
+		previousChipID: previous.chipID,
+		chipID:         chipID,
+		reason:         reason,
+		changedAt:      at,
+	})
+	return a.assignments[i], nil
+}
+
+// checkChip returns ErrChipTaken when the chip is mapped to the bib of another runner
+func (a *Allocation) checkChip(chipID string, runnerID uuid.UUID) error {
+	if chipID == "" {
+		return nil
+	}
+	for _, assignment := range a.assignments {
+		if assignment.chipID == chipID && assignment.runnerID != runnerID {
+			return ErrChipTaken
+		}
+	}
+	return nil
+}
+
+// nextFree returns the lowest number of the range that is not assigned
+func (a *Allocation) nextFree(r Range) (int, error) {
+	taken := make(map[int]bool, len(a.assignments))
+	for _, assignment := range a.assignments {
+		taken[assignment.number] = true
+	}
+	for number := r.first; number <= r.last; number++ {
+		if !taken[number] {
+			return number, nil
+		}
+	}
+	return 0, ErrRangeFull
+}
+
+func (a *Allocation) rangeOf(number int) (Range, bool) {
+	for _, r := range a.ranges {
+		if r.Contains(number) {
+			return r, true
+		}
+	}
+	return Range{}, false
+}
+
+func (a *Allocation) holderOf(number int) (uuid.UUID, bool) {
+	for _, assignment := range a.assignments {
+		if assignment.number == number {
+			return assignment.runnerID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (a *Allocation) indexOf(runnerID uuid.UUID) int {
+	for i, assignment := range a.assignments {
+		if assignment.runnerID == runnerID {
+			return i
+		}
+	}
+	return -1
+}
+
+func findRange(ranges []Range, name string) (Range, bool) {
+	name = strings.TrimSpace(name)
+	for _, r := range ranges {
+		if r.name == name {
+			return r, true
+		}
+	}
+	return Range{}, false
+}
+
+// Assignment returns the bib of the runner
+func (a *Allocation) Assignment(runnerID uuid.UUID) (Assignment, bool) {
+	i := a.indexOf(runnerID)
+	if i < 0 {
+		return Assignment{}, false
+	}
+	return a.assignments[i], true
+}
+
+// AssignmentByChip returns the bib the chip is mapped to
+func (a *Allocation) AssignmentByChip(chipID string) (Assignment, bool) {
+	if chipID == "" {
+		return Assignment{}, false
+	}
+	for _, assignment := range a.assignments {
+		if assignment.chipID == chipID {
+			return assignment, true
+		}
+	}
+	return Assignment{}, false
+}
+
+// Assignments returns the bibs of the race ordered by number
+func (a *Allocation) Assignments() []Assignment {
+	assignments := append([]Assignment(nil), a.assignments...)
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].number < assignments[j].number })
+	return assignments
+}
+
+// Changes returns the reassignments of the race in the order they were made
+func (a *Allocation) Changes() []Change {
+	return append([]Change(nil), a.changes...)
+}
+
+// RaceID returns the ID of the race
+func (a *Allocation) RaceID() uuid.UUID {
+	return a.raceID
+}
+
+// Ranges returns the ranges of the race ordered by first number
+func (a *Allocation) Ranges() []Range {
+	return append([]Range(nil), a.ranges...)
+}
+
+// Version returns the version of the stored data the allocation was loaded from, 0 for a new allocation
+func (a *Allocation) Version() int {
+	return a.version
+}
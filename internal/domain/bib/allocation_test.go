@@ -0,0 +1,200 @@
+package bib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var at = time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+
+func mustRange(t *testing.T, name string, first, last int) Range {
+	r, err := NewRange(name, first, last)
+	require.NoError(t, err)
+	return r
+}
+
+func TestNewRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		rangeName string
+		first     int
+		last      int
+		wantErr   error
+	}{
+		{name: "valid", rangeName: "elite", first: 1, last: 99},
+		{name: "single number", rangeName: "guest", first: 7, last: 7},
+		{name: "empty name", rangeName: " ", first: 1, last: 99, wantErr: ErrEmptyRangeName},
+		{name: "zero first number", rangeName: "elite", first: 0, last: 99, wantErr: ErrInvalidRange},
+		{name: "ends before it starts", rangeName: "elite", first: 100, last: 99, wantErr: ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRange(tt.rangeName, tt.first, tt.last)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.first, got.First())
+			assert.Equal(t, tt.last, got.Last())
+		})
+	}
+}
+
+func TestNewAllocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		raceID  uuid.UUID
+		ranges  []Range
+		wantErr error
+	}{
+		{name: "valid", raceID: uuid.New(), ranges: []Range{mustRange(t, "open", 100, 999), mustRange(t, "elite", 1, 99)}},
+		{name: "empty race id", raceID: uuid.Nil, ranges: []Range{mustRange(t, "elite", 1, 99)}, wantErr: ErrEmptyRaceID},
+		{name: "no ranges", raceID: uuid.New(), wantErr: ErrNoRanges},
+		{name: "overlapping ranges", raceID: uuid.New(), ranges: []Range{mustRange(t, "elite", 1, 100), mustRange(t, "open", 100, 999)}, wantErr: ErrOverlappingRanges},
+		{name: "duplicate names", raceID: uuid.New(), ranges: []Range{mustRange(t, "open", 1, 99), mustRange(t, "open", 100, 999)}, wantErr: ErrDuplicateRangeName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAllocation(tt.raceID, tt.ranges)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "elite", got.Ranges()[0].Name())
+		})
+	}
+}
+
+func TestAllocation_Assign(t *testing.T) {
+	a, err := NewAllocation(uuid.New(), []Range{mustRange(t, "elite", 1, 2), mustRange(t, "open", 100, 999)})
+	require.NoError(t, err)
+	anna, bob, carl := uuid.New(), uuid.New(), uuid.New()
+
+	first, err := a.Assign(anna, "elite", "CHIP-1", at)
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Number())
+	assert.Equal(t, "CHIP-1", first.ChipID())
+	second, err := a.Assign(bob, "elite", "", at)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Number())
+
+	_, err = a.Assign(carl, "elite", "", at)
+	assert.ErrorIs(t, err, ErrRangeFull)
+	_, err = a.Assign(carl, "masters", "", at)
+	assert.ErrorIs(t, err, ErrRangeNotFound)
+	_, err = a.Assign(carl, "open", "CHIP-1", at)
+	assert.ErrorIs(t, err, ErrChipTaken)
+	_, err = a.Assign(anna, "open", "", at)
+	assert.ErrorIs(t, err, ErrAlreadyAssigned)
+	_, err = a.Assign(uuid.Nil, "open", "", at)
+	assert.ErrorIs(t, err, ErrEmptyRunnerID)
+
+	third, err := a.Assign(carl, "open", "CHIP-3", at)
+	require.NoError(t, err)
+	assert.Equal(t, 100, third.Number())
+	assert.Empty(t, a.Changes())
+
+	byChip, ok := a.AssignmentByChip("CHIP-3")
+	assert.True(t, ok)
+	assert.Equal(t, carl, byChip.RunnerID())
+	_, ok = a.AssignmentByChip("")
+	assert.False(t, ok, "bibs without a chip are not matched")
+}
+
+func TestAllocation_Reassign(t *testing.T) {
+	newAllocation := func(t *testing.T) (*Allocation, uuid.UUID, uuid.UUID) {
+		a, err := NewAllocation(uuid.New(), []Range{mustRange(t, "elite", 1, 99), mustRange(t, "open", 100, 999)})
+		require.NoError(t, err)
+		anna, bob := uuid.New(), uuid.New()
+		_, err = a.Assign(anna, "elite", "CHIP-1", at)
+		require.NoError(t, err)
+		_, err = a.Assign(bob, "open", "CHIP-2", at)
+		require.NoError(t, err)
+		return a, anna, bob
+	}
+
+	tests := []struct {
+		name       string
+		number     int
+		rangeName  string
+		chipID     string
+		reason     string
+		wantNumber int
+		wantRange  string
+		wantErr    error
+	}{
+		{name: "to a free number", number: 42, chipID: "CHIP-1", reason: "lost bib", wantNumber: 42, wantRange: "elite"},
+		{name: "to another range", rangeName: "open", chipID: "CHIP-1", reason: "moved to the open wave", wantNumber: 101, wantRange: "open"},
+		{name: "chip only", chipID: "CHIP-9", reason: "faulty chip", wantNumber: 1, wantRange: "elite"},
+		{name: "number of another runner", number: 100, reason: "lost bib", wantErr: ErrNumberTaken},
+		{name: "number outside the ranges", number: 5000, reason: "lost bib", wantErr: ErrNumberOutsideRanges},
+		{name: "chip of another runner", chipID: "CHIP-2", reason: "faulty chip", wantErr: ErrChipTaken},
+		{name: "unknown range", rangeName: "masters", reason: "moved", wantErr: ErrRangeNotFound},
+		{name: "without reason", number: 42, reason: " ", wantErr: ErrMissingChangeReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, anna, _ := newAllocation(t)
+			got, err := a.Reassign(anna, tt.number, tt.rangeName, tt.chipID, tt.reason, at.Add(time.Hour))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, a.Changes())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNumber, got.Number())
+			assert.Equal(t, tt.wantRange, got.RangeName())
+			assert.Equal(t, tt.chipID, got.ChipID())
+
+			require.Len(t, a.Changes(), 1)
+			change := a.Changes()[0]
+			assert.Equal(t, anna, change.RunnerID())
+			assert.Equal(t, 1, change.PreviousNumber())
+			assert.Equal(t, tt.wantNumber, change.Number())
+			assert.Equal(t, "CHIP-1", change.PreviousChipID())
+			assert.Equal(t, tt.chipID, change.ChipID())
+			assert.Equal(t, tt.reason, change.Reason())
+			assert.Equal(t, at.Add(time.Hour), change.ChangedAt())
+		})
+	}
+
+	t.Run("runner without bib", func(t *testing.T) {
+		a, _, _ := newAllocation(t)
+		_, err := a.Reassign(uuid.New(), 5, "", "", "lost bib", at)
+		assert.ErrorIs(t, err, ErrNotAssigned)
+	})
+}
+
+func TestAllocation_Configure(t *testing.T) {
+	a, err := NewAllocation(uuid.New(), []Range{mustRange(t, "elite", 1, 99)})
+	require.NoError(t, err)
+	_, err = a.Assign(uuid.New(), "elite", "", at)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Configure([]Range{mustRange(t, "elite", 1, 199), mustRange(t, "open", 200, 999)}))
+	assert.Len(t, a.Ranges(), 2)
+
+	assert.ErrorIs(t, a.Configure([]Range{mustRange(t, "elite", 2, 99)}), ErrAssignmentOutOfRange)
+	assert.ErrorIs(t, a.Configure([]Range{mustRange(t, "open", 1, 99)}), ErrAssignmentOutOfRange)
+	assert.Len(t, a.Ranges(), 2)
+}
+
+func TestAllocation_Assignments(t *testing.T) {
+	a, err := NewAllocation(uuid.New(), []Range{mustRange(t, "elite", 1, 99), mustRange(t, "open", 100, 999)})
+	require.NoError(t, err)
+	_, err = a.Assign(uuid.New(), "open", "", at)
+	require.NoError(t, err)
+	_, err = a.Assign(uuid.New(), "elite", "", at)
+	require.NoError(t, err)
+
+	got := a.Assignments()
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[0].Number())
+	assert.Equal(t, 100, got[1].Number())
+}
@@ -0,0 +1,76 @@
+// Package bib contains the domain entities for assigning bib numbers and timing chips to the runners of a race
+package bib
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrEmptyRangeName     = errors.New("range name cannot be empty")
+	ErrInvalidRange       = errors.New("range must start above zero and end at or after its first number")
+	ErrDuplicateRangeName = errors.New("range names must be unique")
+	ErrOverlappingRanges  = errors.New("ranges cannot overlap")
+	ErrNoRanges           = errors.New("at least one range is required")
+)
+
+// Range is a block of bib numbers reserved for a category or a wave of a race, such as 1-999 for the elite wave
+type Range struct {
+	name  string
+	first int
+	last  int
+}
+
+// NewRange creates a range of the bib numbers from first to last inclusive
+func NewRange(name string, first, last int) (Range, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Range{}, ErrEmptyRangeName
+	}
+	if first <= 0 || last < first {
+		return Range{}, ErrInvalidRange
+	}
+	return Range{name: name, first: first, last: last}, nil
+}
+
+// validateRanges checks that the ranges have unique names and do not share numbers, and returns them sorted by first number
+func validateRanges(ranges []Range) ([]Range, error) {
+	if len(ranges) == 0 {
+		return nil, ErrNoRanges
+	}
+	sorted := append([]Range(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].first < sorted[j].first })
+
+	names := make(map[string]bool, len(sorted))
+	for i, r := range sorted {
+		if names[r.name] {
+			return nil, ErrDuplicateRangeName
+		}
+		names[r.name] = true
+		if i > 0 && r.first <= sorted[i-1].last {
+			return nil, ErrOverlappingRanges
+		}
+	}
+	return sorted, nil
+}
+
+// Contains reports whether the number belongs to the range
+func (r Range) Contains(number int) bool {
+	return number >= r.first && number <= r.last
+}
+
+// Name returns the category or wave of the range
+func (r Range) Name() string {
+	return r.name
+}
+
+// First returns the first number of the range
+func (r Range) First() int {
+	return r.first
+}
+
+// Last returns the last number of the range
+func (r Range) Last() int {
+	return r.last
+}
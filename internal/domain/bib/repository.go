@@ -0,0 +1,24 @@
+package bib
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotConfigured Error when no bib ranges are configured for the race
+	ErrNotConfigured = errors.New("bib ranges are not configured for the race")
+	// ErrConcurrentUpdate Error when the allocation was changed since it was loaded
+	ErrConcurrentUpdate = errors.New("bibs of the race were changed by another request")
+)
+
+// Repository defines the storage interface for the bib allocations of races
+type Repository interface {
+	// GetAllocation returns the bib allocation of the race, or ErrNotConfigured
+	GetAllocation(ctx context.Context, raceID uuid.UUID) (*Allocation, error)
+	// SaveAllocation stores the allocation, returning ErrConcurrentUpdate when the stored version
+	// no longer matches the version the allocation was loaded from
+	SaveAllocation(ctx context.Context, allocation *Allocation) error
+}
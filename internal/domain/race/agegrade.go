@@ -0,0 +1,141 @@
+package race
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrAgeGradeUnavailable Error when there is no age grading standard for the gender, age or distance
+var ErrAgeGradeUnavailable = errors.New("no age grading standard for the gender, age or distance")
+
+//go:embed wma_road_factors.csv
+var wmaRoadFactorsCSV []byte
+
+// wmaOpenStandards contains the open class road standards per gender and distance in km
+var wmaOpenStandards = map[string]map[float64]time.Duration{
+	"female": {
+		5:       14*time.Minute + 18*time.Second,
+		10:      30*time.Minute + 4*time.Second,
+		21.0975: time.Hour + 5*time.Minute + 9*time.Second,
+		42.195:  2*time.Hour + 15*time.Minute + 25*time.Second,
+	},
+	"male": {
+		5:       12*time.Minute + 49*time.Second,
+		10:      26*time.Minute + 43*time.Second,
+		21.0975: 58*time.Minute + 23*time.Second,
+		42.195:  2*time.Hour + 2*time.Minute + 57*time.Second,
+	},
+}
+
+// ageFactorTable contains the age factors of a gender, indexed by age and by the position of the distance
+type ageFactorTable struct {
+	distancesKm []float64
+	factors     map[int][]float64
+}
+
+// wmaRoadFactors contains the embedded age factor tables per gender
+var wmaRoadFactors = mustParseAgeFactors(wmaRoadFactorsCSV)
+
+// AgeGrade represents the performance of a result adjusted for the age and the gender of the runner
+type AgeGrade struct {
+	// Factor is the WMA age factor, 1 for the open class
+	Factor float64
+	// Percent compares the result with the standard of the age and gender, 100 for a world class performance
+	Percent float64
+	// GradedTime is the equivalent open class time of the result
+	GradedTime time.Duration
+}
+
+// NewAgeGrade grades a finish time over distanceKm using the WMA road standards.
+// Standard distances between 5K and the marathon are supported; other distances in that range are
+// interpolated between the nearest standard distances.
+func NewAgeGrade(finishTime time.Duration, distanceKm float64, gender string, age int) (AgeGrade, error) {
+	if finishTime <= 0 {
+		return AgeGrade{}, ErrInvalidFinishTime
+	}
+	table, exists := wmaRoadFactors[gender]
+	if !exists {
+		return AgeGrade{}, ErrAgeGradeUnavailable
+	}
+	factors, exists := table.factors[age]
+	if !exists {
+		return AgeGrade{}, ErrAgeGradeUnavailable
+	}
+
+	distanceKm = DistanceOf(distanceKm).DistanceKm
+	distances := table.distancesKm
+	i := sort.SearchFloat64s(distances, distanceKm)
+	if i == len(distances) || (i == 0 && distances[0] != distanceKm) {
+		return AgeGrade{}, ErrAgeGradeUnavailable
+	}
+
+	standards := wmaOpenStandards[gender]
+	factor, standard := factors[i], standards[distances[i]].Seconds()
+	if distances[i] != distanceKm {
+		//factors are interpolated linearly and standards on a log-log scale, as race times follow a power law
+		lower, upper := distances[i-1], distances[i]
+		ratio := (distanceKm - lower) / (upper - lower)
+		factor = factors[i-1] + ratio*(factors[i]-factors[i-1])
+		exponent := math.Log(standard/standards[lower].Seconds()) / math.Log(upper/lower)
+		standard = standards[lower].Seconds() * math.Pow(distanceKm/lower, exponent)
+	}
+
+	graded := time.Duration(float64(finishTime) * factor)
+	return AgeGrade{
+		Factor:     factor,
+		Percent:    standard / graded.Seconds() * 100,
+		GradedTime: graded,
+	}, nil
+}
+
+// AgeGrade grades the result of a race of raceDistanceKm for a runner of the provided gender and age on the race date
+func (r Result) AgeGrade(raceDistanceKm float64, gender string, age int) (AgeGrade, error) {
+	return NewAgeGrade(r.finishTime, raceDistanceKm, gender, age)
+}
+
+// mustParseAgeFactors parses the embedded age factor tables; the tables are part of the binary,
+// so a malformed table is a programming error
+func mustParseAgeFactors(data []byte) map[string]ageFactorTable {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("race: invalid age factor table: %v", err))
+	}
+
+	header := records[0]
+	distances := make([]float64, len(header)-2)
+	for i, column := range header[2:] {
+		if distances[i], err = strconv.ParseFloat(column, 64); err != nil {
+			panic(fmt.Sprintf("race: invalid age factor distance %q", column))
+		}
+	}
+
+	tables := make(map[string]ageFactorTable)
+	for _, record := range records[1:] {
+		table, exists := tables[record[0]]
+		if !exists {
+			table = ageFactorTable{distancesKm: distances, factors: make(map[int][]float64)}
+			tables[record[0]] = table
+		}
+		age, err := strconv.Atoi(record[1])
+		if err != nil {
+			panic(fmt.Sprintf("race: invalid age factor age %q", record[1]))
+		}
+		factors := make([]float64, len(distances))
+		for i, value := range record[2:] {
+			if factors[i], err = strconv.ParseFloat(value, 64); err != nil {
+				panic(fmt.Sprintf("race: invalid age factor %q", value))
+			}
+		}
+		table.factors[age] = factors
+	}
+	return tables
+}
@@ -0,0 +1,109 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAgeGrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		finishTime  time.Duration
+		distanceKm  float64
+		gender      string
+		age         int
+		wantFactor  float64
+		wantPercent float64
+		wantGraded  time.Duration
+		wantErr     error
+	}{
+		{
+			name:        "should grade open class result against the open standard",
+			finishTime:  26*time.Minute + 43*time.Second,
+			distanceKm:  10,
+			gender:      "male",
+			age:         25,
+			wantFactor:  1,
+			wantPercent: 100,
+			wantGraded:  26*time.Minute + 43*time.Second,
+		},
+		{
+			name:        "should apply the age factor",
+			finishTime:  40 * time.Minute,
+			distanceKm:  10,
+			gender:      "male",
+			age:         50,
+			wantFactor:  0.87,
+			wantPercent: 76.77,
+			wantGraded:  34*time.Minute + 48*time.Second,
+		},
+		{
+			name:        "should match rounded standard distance",
+			finishTime:  4 * time.Hour,
+			distanceKm:  42.2,
+			gender:      "female",
+			age:         60,
+			wantFactor:  0.78,
+			wantPercent: 72.33,
+			wantGraded:  3*time.Hour + 7*time.Minute + 12*time.Second,
+		},
+		{
+			name:        "should interpolate distances between standards",
+			finishTime:  time.Hour,
+			distanceKm:  15,
+			gender:      "male",
+			age:         25,
+			wantFactor:  1,
+			wantPercent: 68.08,
+			wantGraded:  time.Hour,
+		},
+		{name: "should reject distance shorter than 5K", finishTime: 10 * time.Minute, distanceKm: 3, gender: "male", age: 30, wantErr: ErrAgeGradeUnavailable},
+		{name: "should reject distance longer than the marathon", finishTime: 5 * time.Hour, distanceKm: 50, gender: "male", age: 30, wantErr: ErrAgeGradeUnavailable},
+		{name: "should reject gender without standards", finishTime: time.Hour, distanceKm: 10, gender: "non_binary", age: 30, wantErr: ErrAgeGradeUnavailable},
+		{name: "should reject unknown gender", finishTime: time.Hour, distanceKm: 10, gender: "", age: 30, wantErr: ErrAgeGradeUnavailable},
+		{name: "should reject age outside the tables", finishTime: time.Hour, distanceKm: 10, gender: "female", age: 101, wantErr: ErrAgeGradeUnavailable},
+		{name: "should reject invalid finish time", finishTime: 0, distanceKm: 10, gender: "female", age: 30, wantErr: ErrInvalidFinishTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAgeGrade(tt.finishTime, tt.distanceKm, tt.gender, tt.age)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantFactor, got.Factor, 0.0001)
+			assert.InDelta(t, tt.wantPercent, got.Percent, 0.01)
+			assert.Equal(t, tt.wantGraded, got.GradedTime.Round(time.Second))
+		})
+	}
+}
+
+func TestWMARoadFactors(t *testing.T) {
+	for _, gender := range []string{"female", "male"} {
+		table, exists := wmaRoadFactors[gender]
+		require.True(t, exists, gender)
+		assert.Equal(t, []float64{5, 10, 21.0975, 42.195}, table.distancesKm)
+		for age := 5; age <= 100; age++ {
+			factors, exists := table.factors[age]
+			require.True(t, exists, "%s %d", gender, age)
+			for _, factor := range factors {
+				assert.True(t, factor > 0 && factor <= 1, "%s %d: %f", gender, age, factor)
+			}
+		}
+	}
+}
+
+func TestResult_AgeGrade(t *testing.T) {
+	r, err := NewResult(uuid.New(), uuid.New(), 20*time.Minute, 4, 150, "")
+	require.NoError(t, err)
+
+	grade, err := r.AgeGrade(5, "female", 40)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.94, grade.Factor, 0.0001)
+	assert.Equal(t, 18*time.Minute+48*time.Second, grade.GradedTime.Round(time.Second))
+}
@@ -0,0 +1,121 @@
+package race
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingDate               = errors.New("date is required")
+	ErrRaceCancelled             = errors.New("race is cancelled")
+	ErrMissingCancellationReason = errors.New("cancellation reason is required")
+	ErrDistanceLocked            = errors.New("distance cannot change once the race has official results")
+)
+
+// Rename returns a copy of the race with the provided name
+func (r Race) Rename(name string) (Race, error) {
+	if r.IsCancelled() {
+		return Race{}, ErrRaceCancelled
+	}
+	if name == "" {
+		return Race{}, ErrEmptyName
+	}
+	r.name = name
+	return r, nil
+}
+
+// Relocate returns a copy of the race taking place at the provided location
+func (r Race) Relocate(location string) (Race, error) {
+	if r.IsCancelled() {
+		return Race{}, ErrRaceCancelled
+	}
+	if location == "" {
+		return Race{}, ErrEmptyLocation
+	}
+	r.location = location
+	return r, nil
+}
+
+// Reschedule returns a copy of the race moved to the provided date.
+// The start waves move with the race, keeping their offsets from the race date.
+func (r Race) Reschedule(date time.Time) (Race, error) {
+	if r.IsCancelled() {
+		return Race{}, ErrRaceCancelled
+	}
+	if date.IsZero() {
+		return Race{}, ErrMissingDate
+	}
+	shift := date.Sub(r.date)
+	waves := make([]Wave, len(r.waves))
+	for i, w := range r.waves {
+		waves[i] = Wave{name: w.name, startTime: w.startTime.Add(shift)}
+	}
+	r.date = date
+	r.waves = waves
+	return r, nil
+}
+
+// CorrectDistance returns a copy of the race with the provided distance and elevation gain.
+// The distance of a race with official or amended results cannot change, since they were published
+// for that distance; provisional results have to be recalculated with Result.ForDistance.
+func (r Race) CorrectDistance(distanceKm, elevationGain float64, results []Result) (Race, error) {
+	if r.IsCancelled() {
+		return Race{}, ErrRaceCancelled
+	}
+	if distanceKm <= 0 {
+		return Race{}, ErrInvalidDistanceKm
+	}
+	if elevationGain < 0 {
+		return Race{}, ErrInvalidElevationGain
+	}
+	if distanceKm != r.distanceKm {
+		for _, result := range results {
+			if result.Publication() != PublicationProvisional {
+				return Race{}, ErrDistanceLocked
+			}
+		}
+	}
+	r.distanceKm = distanceKm
+	r.elevationGain = elevationGain
+	return r, nil
+}
+
+// Cancel returns a copy of the race cancelled at the provided time. The reason is required and
+// a cancelled race can no longer be changed.
+func (r Race) Cancel(reason string, at time.Time) (Race, error) {
+	if r.IsCancelled() {
+		return Race{}, ErrRaceCancelled
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return Race{}, ErrMissingCancellationReason
+	}
+	r.cancelledAt = at.UTC()
+	r.cancellationReason = reason
+	return r, nil
+}
+
+// IsCancelled returns whether the race is cancelled
+func (r Race) IsCancelled() bool {
+	return !r.cancelledAt.IsZero()
+}
+
+// CancelledAt returns the time the race was cancelled, which is zero for races that are not cancelled
+func (r Race) CancelledAt() time.Time {
+	return r.cancelledAt
+}
+
+// CancellationReason returns the reason the race was cancelled
+func (r Race) CancellationReason() string {
+	return r.cancellationReason
+}
+
+// ForDistance returns a copy of the result recalculated for a race of raceDistanceKm: the pace of a finished
+// result is derived again from the finish time and the splits must still fit within the race distance
+func (r Result) ForDistance(raceDistanceKm float64) (Result, error) {
+	if r.finishTime > 0 {
+		r.paceMinPerKm = r.finishTime.Minutes() / raceDistanceKm
+	}
+	return r.WithSplits(r.splits, raceDistanceKm)
+}
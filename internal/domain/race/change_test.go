@@ -0,0 +1,137 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRace_RenameAndRelocate(t *testing.T) {
+	r, err := NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	require.NoError(t, err)
+
+	renamed, err := r.Rename("Night 10K")
+	require.NoError(t, err)
+	assert.Equal(t, "Night 10K", renamed.Name())
+	assert.Equal(t, "City 10K", r.Name(), "the race is not changed in place")
+	_, err = r.Rename("")
+	assert.ErrorIs(t, err, ErrEmptyName)
+
+	relocated, err := r.Relocate("Piraeus")
+	require.NoError(t, err)
+	assert.Equal(t, "Piraeus", relocated.Location())
+	_, err = r.Relocate("")
+	assert.ErrorIs(t, err, ErrEmptyLocation)
+}
+
+func TestRace_Reschedule(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	r, err := NewRace("City 10K", "Athens", date, 10, 50)
+	require.NoError(t, err)
+	a, _ := NewWave("A", date)
+	b, _ := NewWave("B", date.Add(15*time.Minute))
+	r, err = r.WithWaves([]Wave{a, b})
+	require.NoError(t, err)
+
+	later := date.Add(7 * 24 * time.Hour)
+	rescheduled, err := r.Reschedule(later)
+	require.NoError(t, err)
+	assert.Equal(t, later, rescheduled.Date())
+	waves := rescheduled.Waves()
+	require.Len(t, waves, 2)
+	assert.Equal(t, later, waves[0].StartTime(), "waves move with the race")
+	assert.Equal(t, later.Add(15*time.Minute), waves[1].StartTime())
+	assert.Equal(t, date, r.Waves()[0].StartTime(), "the race is not changed in place")
+
+	_, err = r.Reschedule(time.Time{})
+	assert.ErrorIs(t, err, ErrMissingDate)
+}
+
+func TestRace_CorrectDistance(t *testing.T) {
+	r, err := NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	require.NoError(t, err)
+	provisional, err := NewResult(uuid.New(), r.ID(), 40*time.Minute, 4, 150, "")
+	require.NoError(t, err)
+	official, err := provisional.Publish("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		distanceKm    float64
+		elevationGain float64
+		results       []Result
+		wantErr       error
+	}{
+		{name: "without results", distanceKm: 10.2, elevationGain: 60},
+		{name: "with provisional results", distanceKm: 10.2, elevationGain: 60, results: []Result{provisional}},
+		{name: "elevation only with official results", distanceKm: 10, elevationGain: 60, results: []Result{official}},
+		{name: "distance with official results", distanceKm: 10.2, elevationGain: 60, results: []Result{provisional, official}, wantErr: ErrDistanceLocked},
+		{name: "invalid distance", distanceKm: 0, wantErr: ErrInvalidDistanceKm},
+		{name: "invalid elevation", distanceKm: 10, elevationGain: -1, wantErr: ErrInvalidElevationGain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.CorrectDistance(tt.distanceKm, tt.elevationGain, tt.results)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.distanceKm, got.DistanceKm())
+			assert.Equal(t, tt.elevationGain, got.ElevationGain())
+		})
+	}
+}
+
+func TestRace_Cancel(t *testing.T) {
+	r, err := NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	require.NoError(t, err)
+	assert.False(t, r.IsCancelled())
+
+	_, err = r.Cancel(" ", time.Now())
+	assert.ErrorIs(t, err, ErrMissingCancellationReason)
+
+	at := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	cancelled, err := r.Cancel(" storm warning ", at)
+	require.NoError(t, err)
+	assert.True(t, cancelled.IsCancelled())
+	assert.Equal(t, at, cancelled.CancelledAt())
+	assert.Equal(t, "storm warning", cancelled.CancellationReason())
+
+	_, err = cancelled.Cancel("again", at)
+	assert.ErrorIs(t, err, ErrRaceCancelled)
+	_, err = cancelled.Rename("Night 10K")
+	assert.ErrorIs(t, err, ErrRaceCancelled)
+	_, err = cancelled.Relocate("Piraeus")
+	assert.ErrorIs(t, err, ErrRaceCancelled)
+	_, err = cancelled.Reschedule(at)
+	assert.ErrorIs(t, err, ErrRaceCancelled)
+	_, err = cancelled.CorrectDistance(10, 50, nil)
+	assert.ErrorIs(t, err, ErrRaceCancelled)
+}
+
+func TestResult_ForDistance(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), 40*time.Minute, 4, 150, "")
+	require.NoError(t, err)
+	split, _ := NewSplit(5, 20*time.Minute)
+	finish, _ := NewSplit(10, 40*time.Minute)
+	result, err = result.WithSplits([]Split{split, finish}, 10)
+	require.NoError(t, err)
+
+	longer, err := result.ForDistance(12.5)
+	require.NoError(t, err)
+	assert.Equal(t, 3.2, longer.Pace())
+	assert.Len(t, longer.Splits(), 2)
+
+	_, err = result.ForDistance(8)
+	assert.ErrorIs(t, err, ErrSplitBeyondRace)
+
+	dnf, err := NewResultWithStatus(uuid.New(), uuid.New(), StatusDidNotFinish, "", 0, 0, 0, "")
+	require.NoError(t, err)
+	dnf, err = dnf.ForDistance(12.5)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, dnf.Pace())
+}
@@ -0,0 +1,278 @@
+package race
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CheckpointKind describes the purpose of a named point of a course
+type CheckpointKind string
+
+// Supported checkpoint kinds
+const (
+	CheckpointStart      CheckpointKind = "start"
+	CheckpointAidStation CheckpointKind = "aid_station"
+	CheckpointTimingMat  CheckpointKind = "timing_mat"
+	CheckpointFinish     CheckpointKind = "finish"
+)
+
+const (
+	earthRadiusM = 6371000
+	// maxCheckpointOffsetM is the furthest a checkpoint can be from the track of the course
+	maxCheckpointOffsetM = 100
+	// CourseDistanceTolerance is the relative difference allowed between the declared distance of a race
+	// and the length of its course
+	CourseDistanceTolerance = 0.02
+)
+
+var (
+	ErrInvalidCoordinates      = errors.New("latitude must be within [-90, 90] and longitude within [-180, 180]")
+	ErrCourseTooShort          = errors.New("course needs at least two track points")
+	ErrInvalidCheckpointKind   = errors.New("checkpoint kind must be one of start, aid_station, timing_mat or finish")
+	ErrEmptyCheckpointName     = errors.New("checkpoint name cannot be empty")
+	ErrDuplicateCheckpointName = errors.New("checkpoint names must be unique within the course")
+	ErrCheckpointOffCourse     = errors.New("checkpoint is more than 100 m away from the course")
+	ErrInvalidProfileStep      = errors.New("elevation profile step must be greater than 0")
+	ErrCourseDistanceMismatch  = errors.New("declared distance differs from the course length by more than 2%")
+	ErrCourseNotFound          = errors.New("race has no course")
+)
+
+// ParseCheckpointKind Returns the CheckpointKind of the provided value
+func ParseCheckpointKind(value string) (CheckpointKind, error) {
+	switch k := CheckpointKind(value); k {
+	case CheckpointStart, CheckpointAidStation, CheckpointTimingMat, CheckpointFinish:
+		return k, nil
+	default:
+		return "", ErrInvalidCheckpointKind
+	}
+}
+
+// CoursePoint is a position of the track of a course with its elevation in metres
+type CoursePoint struct {
+	lat        float64
+	lon        float64
+	elevationM float64
+}
+
+// NewCoursePoint creates a new CoursePoint and validates the coordinates
+func NewCoursePoint(lat, lon, elevationM float64) (CoursePoint, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return CoursePoint{}, ErrInvalidCoordinates
+	}
+	return CoursePoint{lat: lat, lon: lon, elevationM: elevationM}, nil
+}
+
+// Lat returns the latitude in degrees
+func (p CoursePoint) Lat() float64 {
+	return p.lat
+}
+
+// Lon returns the longitude in degrees
+func (p CoursePoint) Lon() float64 {
+	return p.lon
+}
+
+// ElevationM returns the elevation in metres
+func (p CoursePoint) ElevationM() float64 {
+	return p.elevationM
+}
+
+// CourseCheckpoint is a named point of a course, such as the start, an aid station, a timing mat or the finish.
+// Its distance is measured along the track once it is placed on a course.
+type CourseCheckpoint struct {
+	name       string
+	kind       CheckpointKind
+	position   CoursePoint
+	distanceKm float64
+}
+
+// NewCourseCheckpoint creates a new CourseCheckpoint at the provided position and validates the input
+func NewCourseCheckpoint(name string, kind CheckpointKind, position CoursePoint) (CourseCheckpoint, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return CourseCheckpoint{}, ErrEmptyCheckpointName
+	}
+	if _, err := ParseCheckpointKind(string(kind)); err != nil {
+		return CourseCheckpoint{}, err
+	}
+	return CourseCheckpoint{name: name, kind: kind, position: position}, nil
+}
+
+// Name returns the name of the checkpoint
+func (c CourseCheckpoint) Name() string {
+	return c.name
+}
+
+// Kind returns the kind of the checkpoint
+func (c CourseCheckpoint) Kind() CheckpointKind {
+	return c.kind
+}
+
+// Position returns the position of the checkpoint
+func (c CourseCheckpoint) Position() CoursePoint {
+	return c.position
+}
+
+// DistanceKm returns the distance of the checkpoint from the start of the course
+func (c CourseCheckpoint) DistanceKm() float64 {
+	return c.distanceKm
+}
+
+// ProfilePoint is the elevation of a course at a distance from the start
+type ProfilePoint struct {
+	DistanceKm float64
+	ElevationM float64
+}
+
+// Course represents the route of a race: the track and its named checkpoints.
+// The length and the elevation gain and loss are computed from the track.
+type Course struct {
+	points      []CoursePoint
+	distancesM  []float64
+	checkpoints []CourseCheckpoint
+}
+
+// NewCourse creates a new Course from its track points in running order and its checkpoints.
+// Every checkpoint is placed at the nearest point of the track, which must be within 100 m,
+// and the checkpoints are ordered by their distance along the course.
+func NewCourse(points []CoursePoint, checkpoints []CourseCheckpoint) (Course, error) {
+	if len(points) < 2 {
+		return Course{}, ErrCourseTooShort
+	}
+
+	c := Course{points: append([]CoursePoint(nil), points...), distancesM: make([]float64, len(points))}
+	for i := 1; i < len(points); i++ {
+		c.distancesM[i] = c.distancesM[i-1] + haversineM(points[i-1], points[i])
+	}
+
+	names := make(map[string]bool, len(checkpoints))
+	for _, cp := range checkpoints {
+		if names[cp.name] {
+			return Course{}, ErrDuplicateCheckpointName
+		}
+		names[cp.name] = true
+
+		distanceM, offsetM := c.locate(cp.position)
+		if offsetM > maxCheckpointOffsetM {
+			return Course{}, ErrCheckpointOffCourse
+		}
+		cp.distanceKm = distanceM / 1000
+		c.checkpoints = append(c.checkpoints, cp)
+	}
+	sort.SliceStable(c.checkpoints, func(i, j int) bool {
+		return c.checkpoints[i].distanceKm < c.checkpoints[j].distanceKm
+	})
+	return c, nil
+}
+
+// locate returns the distance along the track of the track point nearest to the position and how far it is
+func (c Course) locate(position CoursePoint) (distanceM, offsetM float64) {
+	offsetM = math.Inf(1)
+	for i, p := range c.points {
+		if d := haversineM(p, position); d < offsetM {
+			offsetM = d
+			distanceM = c.distancesM[i]
+		}
+	}
+	return distanceM, offsetM
+}
+
+// Points returns the track points of the course in running order
+func (c Course) Points() []CoursePoint {
+	return append([]CoursePoint(nil), c.points...)
+}
+
+// Checkpoints returns the checkpoints of the course ordered by distance
+func (c Course) Checkpoints() []CourseCheckpoint {
+	return append([]CourseCheckpoint(nil), c.checkpoints...)
+}
+
+// DistanceKm returns the length of the course
+func (c Course) DistanceKm() float64 {
+	if len(c.distancesM) == 0 {
+		return 0
+	}
+	return c.distancesM[len(c.distancesM)-1] / 1000
+}
+
+// ElevationGain returns the total ascent of the course in metres
+func (c Course) ElevationGain() float64 {
+	var gain float64
+	for i := 1; i < len(c.points); i++ {
+		gain += math.Max(0, c.points[i].elevationM-c.points[i-1].elevationM)
+	}
+	return gain
+}
+
+// ElevationLoss returns the total descent of the course in metres
+func (c Course) ElevationLoss() float64 {
+	var loss float64
+	for i := 1; i < len(c.points); i++ {
+		loss += math.Max(0, c.points[i-1].elevationM-c.points[i].elevationM)
+	}
+	return loss
+}
+
+// ElevationProfile returns the elevation of the course every stepKm from the start, interpolated between
+// track points, and at the finish
+func (c Course) ElevationProfile(stepKm float64) ([]ProfilePoint, error) {
+	if stepKm <= 0 {
+		return nil, ErrInvalidProfileStep
+	}
+	if len(c.points) == 0 {
+		return nil, nil
+	}
+
+	total := c.distancesM[len(c.distancesM)-1]
+	stepM := stepKm * 1000
+	var profile []ProfilePoint
+	segment := 1
+	for at := 0.0; at < total; at += stepM {
+		for segment < len(c.points)-1 && c.distancesM[segment] < at {
+			segment++
+		}
+		from, to := c.distancesM[segment-1], c.distancesM[segment]
+		elevation := c.points[segment-1].elevationM
+		if to > from {
+			ratio := (at - from) / (to - from)
+			elevation += ratio * (c.points[segment].elevationM - c.points[segment-1].elevationM)
+		}
+		profile = append(profile, ProfilePoint{DistanceKm: at / 1000, ElevationM: elevation})
+	}
+	return append(profile, ProfilePoint{DistanceKm: total / 1000, ElevationM: c.points[len(c.points)-1].elevationM}), nil
+}
+
+// NewRaceWithCourse creates a new Race run on the provided course. The elevation gain is taken from the course
+// and the declared distance must match the course length within CourseDistanceTolerance.
+func NewRaceWithCourse(name, location string, date time.Time, distanceKm float64, course Course) (Race, error) {
+	r, err := NewRace(name, location, date, distanceKm, course.ElevationGain())
+	if err != nil {
+		return Race{}, err
+	}
+	if err := r.MatchesCourse(course); err != nil {
+		return Race{}, err
+	}
+	return r, nil
+}
+
+// MatchesCourse returns ErrCourseDistanceMismatch when the declared distance of the race differs from the length
+// of the course by more than CourseDistanceTolerance
+func (r Race) MatchesCourse(c Course) error {
+	if math.Abs(c.DistanceKm()-r.distanceKm)/r.distanceKm > CourseDistanceTolerance {
+		return ErrCourseDistanceMismatch
+	}
+	return nil
+}
+
+// haversineM returns the great-circle distance in metres between two points
+func haversineM(a, b CoursePoint) float64 {
+	toRad := math.Pi / 180
+	dLat := (b.lat - a.lat) * toRad
+	dLon := (b.lon - a.lon) * toRad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.lat*toRad)*math.Cos(b.lat*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(h))
+}
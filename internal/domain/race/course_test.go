@@ -0,0 +1,141 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTrack returns a 2 km track heading north that climbs 30 m and descends 10 m
+func testTrack(t *testing.T) []CoursePoint {
+	var points []CoursePoint
+	for _, p := range []struct{ lat, ele float64 }{{38.0, 100}, {38.009, 130}, {38.018, 120}} {
+		point, err := NewCoursePoint(p.lat, 23.7, p.ele)
+		require.NoError(t, err)
+		points = append(points, point)
+	}
+	return points
+}
+
+func TestNewCoursePoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		lat     float64
+		lon     float64
+		wantErr error
+	}{
+		{name: "valid", lat: 37.98, lon: 23.72},
+		{name: "latitude out of range", lat: 91, lon: 23.72, wantErr: ErrInvalidCoordinates},
+		{name: "longitude out of range", lat: 37.98, lon: -181, wantErr: ErrInvalidCoordinates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewCoursePoint(tt.lat, tt.lon, 10)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.lat, p.Lat())
+			assert.Equal(t, tt.lon, p.Lon())
+			assert.Equal(t, 10.0, p.ElevationM())
+		})
+	}
+}
+
+func TestNewCourseCheckpoint(t *testing.T) {
+	position, _ := NewCoursePoint(38.0, 23.7, 0)
+	tests := []struct {
+		name     string
+		cpName   string
+		kind     CheckpointKind
+		wantErr  error
+		wantName string
+	}{
+		{name: "valid", cpName: " Aid 1 ", kind: CheckpointAidStation, wantName: "Aid 1"},
+		{name: "empty name", cpName: " ", kind: CheckpointStart, wantErr: ErrEmptyCheckpointName},
+		{name: "invalid kind", cpName: "Mat", kind: "mat", wantErr: ErrInvalidCheckpointKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp, err := NewCourseCheckpoint(tt.cpName, tt.kind, position)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, cp.Name())
+			assert.Equal(t, tt.kind, cp.Kind())
+		})
+	}
+}
+
+func TestNewCourse(t *testing.T) {
+	track := testTrack(t)
+	finish, _ := NewCourseCheckpoint("Finish", CheckpointFinish, track[2])
+	start, _ := NewCourseCheckpoint("Start", CheckpointStart, track[0])
+	nearAid, _ := NewCoursePoint(38.0094, 23.7, 0)
+	aid, _ := NewCourseCheckpoint("Aid 1", CheckpointAidStation, nearAid)
+
+	c, err := NewCourse(track, []CourseCheckpoint{finish, aid, start})
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, c.DistanceKm(), 0.01)
+	assert.Equal(t, 30.0, c.ElevationGain())
+	assert.Equal(t, 10.0, c.ElevationLoss())
+	assert.Len(t, c.Points(), 3)
+
+	checkpoints := c.Checkpoints()
+	require.Len(t, checkpoints, 3)
+	assert.Equal(t, "Start", checkpoints[0].Name())
+	assert.Equal(t, 0.0, checkpoints[0].DistanceKm())
+	assert.Equal(t, "Aid 1", checkpoints[1].Name())
+	assert.InDelta(t, 1.0, checkpoints[1].DistanceKm(), 0.01, "checkpoints are placed at the nearest track point")
+	assert.Equal(t, "Finish", checkpoints[2].Name())
+
+	_, err = NewCourse(track[:1], nil)
+	assert.ErrorIs(t, err, ErrCourseTooShort)
+
+	_, err = NewCourse(track, []CourseCheckpoint{start, start})
+	assert.ErrorIs(t, err, ErrDuplicateCheckpointName)
+
+	faraway, _ := NewCoursePoint(38.009, 23.71, 0)
+	off, _ := NewCourseCheckpoint("Off", CheckpointTimingMat, faraway)
+	_, err = NewCourse(track, []CourseCheckpoint{off})
+	assert.ErrorIs(t, err, ErrCheckpointOffCourse)
+}
+
+func TestCourse_ElevationProfile(t *testing.T) {
+	c, err := NewCourse(testTrack(t), nil)
+	require.NoError(t, err)
+
+	profile, err := c.ElevationProfile(0.5)
+	require.NoError(t, err)
+	require.Len(t, profile, 6, "every 500 m of the 2.0015 km track and the finish")
+	assert.Equal(t, 0.0, profile[0].DistanceKm)
+	assert.Equal(t, 100.0, profile[0].ElevationM)
+	assert.InDelta(t, 115.0, profile[1].ElevationM, 0.1, "elevation is interpolated between track points")
+	assert.InDelta(t, 125.0, profile[3].ElevationM, 0.1)
+	assert.InDelta(t, c.DistanceKm(), profile[5].DistanceKm, 0.0001)
+	assert.Equal(t, 120.0, profile[5].ElevationM)
+
+	_, err = c.ElevationProfile(0)
+	assert.ErrorIs(t, err, ErrInvalidProfileStep)
+}
+
+func TestNewRaceWithCourse(t *testing.T) {
+	c, err := NewCourse(testTrack(t), nil)
+	require.NoError(t, err)
+
+	r, err := NewRaceWithCourse("Hill 2K", "Athens", time.Now(), 2, c)
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, r.ElevationGain())
+	assert.NoError(t, r.MatchesCourse(c))
+
+	_, err = NewRaceWithCourse("Hill 2K", "Athens", time.Now(), 2.1, c)
+	assert.ErrorIs(t, err, ErrCourseDistanceMismatch)
+
+	_, err = NewRaceWithCourse("", "Athens", time.Now(), 2, c)
+	assert.ErrorIs(t, err, ErrEmptyName)
+}
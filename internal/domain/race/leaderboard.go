@@ -0,0 +1,165 @@
+package race
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Division represents the categories a runner competes in.
+// Empty values mean that the category is unknown and the runner is not ranked in it.
+type Division struct {
+	Gender   string
+	AgeGroup string
+}
+
+// Standing represents the position of a result in the leaderboard of a race.
+// GenderPosition and AgeGroupPosition are 0 when the corresponding category is unknown.
+// Runners that did not finish are not ranked and all their positions are 0.
+// Age groups are ranked within the gender of the runner.
+type Standing struct {
+	Result           Result
+	Division         Division
+	OverallPosition  int
+	GenderPosition   int
+	AgeGroupPosition int
+}
+
+// NewLeaderboard ranks the finished results of a race by the gun or the net time, following the ranking policy
+// of the race. Only the fastest result of each runner is ranked. Results with the same time share a position
+// and the following position is skipped (1, 2, 2, 4).
+// A disqualification applies to the runner, so runners with a disqualified result are never ranked, even with
+// another finished result. A finished result does rank over a result of the runner that did not finish or start.
+// Runners that are not ranked are listed after the ranked ones with their latest disqualified result, or else
+// their latest result: disqualified runners first, then runners that did not finish and runners that did not start.
+func NewLeaderboard(results []Result, divisions map[uuid.UUID]Division, policy RankingPolicy) []Standing {
+	best := make(map[uuid.UUID]Result, len(results))
+	latest := make(map[uuid.UUID]Result)
+	disqualified := make(map[uuid.UUID]Result)
+	for _, r := range results {
+		if r.Status() == StatusDisqualified {
+			current, exists := disqualified[r.RunnerID()]
+			if !exists || current.LoggedAt().Before(r.LoggedAt()) {
+				disqualified[r.RunnerID()] = r
+			}
+			continue
+		}
+		if !r.IsFinished() {
+			current, exists := latest[r.RunnerID()]
+			if !exists || current.LoggedAt().Before(r.LoggedAt()) {
+				latest[r.RunnerID()] = r
+			}
+			continue
+		}
+		current, exists := best[r.RunnerID()]
+		if !exists || faster(r, current, policy) {
+			best[r.RunnerID()] = r
+		}
+	}
+	for runnerID, r := range disqualified {
+		delete(best, runnerID)
+		latest[runnerID] = r
+	}
+
+	standings := make([]Standing, 0, len(best))
+	for runnerID, r := range best {
+		standings = append(standings, Standing{Result: r, Division: divisions[runnerID]})
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		return faster(standings[i].Result, standings[j].Result, policy)
+	})
+
+	overall := newRanker(policy)
+	genders := make(map[string]*ranker)
+	ageGroups := make(map[string]*ranker)
+	for i := range standings {
+		s := &standings[i]
+		s.OverallPosition = overall.rank(s.Result)
+		if s.Division.Gender != "" {
+			s.GenderPosition = rankerOf(genders, s.Division.Gender, policy).rank(s.Result)
+		}
+		if s.Division.AgeGroup != "" {
+			s.AgeGroupPosition = rankerOf(ageGroups, s.Division.Gender+"/"+s.Division.AgeGroup, policy).rank(s.Result)
+		}
+	}
+
+	unranked := make([]Standing, 0, len(latest))
+	for runnerID, r := range latest {
+		if _, finished := best[runnerID]; !finished {
+			unranked = append(unranked, Standing{Result: r, Division: divisions[runnerID]})
+		}
+	}
+	sort.Slice(unranked, func(i, j int) bool {
+		a, b := unranked[i].Result, unranked[j].Result
+		if unrankedOrder[a.Status()] != unrankedOrder[b.Status()] {
+			return unrankedOrder[a.Status()] < unrankedOrder[b.Status()]
+		}
+		return faster(a, b, policy)
+	})
+
+	return append(standings, unranked...)
+}
+
+// unrankedOrder is the order in which runners without a finished result are listed
+var unrankedOrder = map[Status]int{
+	StatusDisqualified: 0,
+	StatusDidNotFinish: 1,
+	StatusDidNotStart:  2,
+}
+
+// AgeGroup returns the age group of a runner of the provided age: U20, 20-24, 25-29, ..., 75-79 and 80+
+func AgeGroup(age int) string {
+	switch {
+	case age < 20:
+		return "U20"
+	case age >= 80:
+		return "80+"
+	default:
+		lower := age - age%5
+		return fmt.Sprintf("%d-%d", lower, lower+4)
+	}
+}
+
+// faster orders results by the time of the ranking policy, then by logging time and id so that ties are listed
+// deterministically
+func faster(a, b Result, policy RankingPolicy) bool {
+	if a.RankingTime(policy) != b.RankingTime(policy) {
+		return a.RankingTime(policy) < b.RankingTime(policy)
+	}
+	if !a.LoggedAt().Equal(b.LoggedAt()) {
+		return a.LoggedAt().Before(b.LoggedAt())
+	}
+	return a.ID().String() < b.ID().String()
+}
+
+// ranker assigns standard competition ranking positions to results provided in finishing order
+type ranker struct {
+	policy       RankingPolicy
+	count        int
+	lastPosition int
+	lastTime     time.Duration
+}
+
+func newRanker(policy RankingPolicy) *ranker {
+	return &ranker{policy: policy}
+}
+
+func rankerOf(rankers map[string]*ranker, key string, policy RankingPolicy) *ranker {
+	r, exists := rankers[key]
+	if !exists {
+		r = newRanker(policy)
+		rankers[key] = r
+	}
+	return r
+}
+
+func (r *ranker) rank(result Result) int {
+	r.count++
+	if result.RankingTime(r.policy) != r.lastTime {
+		r.lastPosition = r.count
+	}
+	r.lastTime = result.RankingTime(r.policy)
+	return r.lastPosition
+}
@@ -0,0 +1,156 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLeaderboard(t *testing.T) {
+	raceID := uuid.New()
+	anna, bob, carl, dana, eve := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	newResult := func(runnerID uuid.UUID, finishTime time.Duration, loggedAt time.Time) Result {
+		r, err := LoadResult(uuid.New(), runnerID, raceID, StatusFinished, "", finishTime, finishTime, 5, 150, "", nil, PublicationProvisional, "", loggedAt)
+		require.NoError(t, err)
+		return r
+	}
+	now := time.Now()
+
+	results := []Result{
+		newResult(carl, 40*time.Minute, now),
+		newResult(anna, 38*time.Minute, now),
+		newResult(bob, 40*time.Minute, now.Add(time.Second)),
+		newResult(dana, 45*time.Minute, now),
+		newResult(eve, 50*time.Minute, now),
+		newResult(dana, 41*time.Minute, now.Add(time.Minute)),
+	}
+	divisions := map[uuid.UUID]Division{
+		anna: {Gender: "female", AgeGroup: "30-34"},
+		bob:  {Gender: "male", AgeGroup: "30-34"},
+		carl: {Gender: "male", AgeGroup: "30-34"},
+		dana: {Gender: "female", AgeGroup: "30-34"},
+	}
+
+	standings := NewLeaderboard(results, divisions, RankingGunTime)
+
+	type position struct {
+		runnerID         uuid.UUID
+		finishTime       time.Duration
+		overall          int
+		genderPosition   int
+		ageGroupPosition int
+	}
+	want := []position{
+		{anna, 38 * time.Minute, 1, 1, 1},
+		{carl, 40 * time.Minute, 2, 1, 1},
+		{bob, 40 * time.Minute, 2, 1, 1},
+		{dana, 41 * time.Minute, 4, 2, 2},
+		{eve, 50 * time.Minute, 5, 0, 0},
+	}
+	got := make([]position, len(standings))
+	for i, s := range standings {
+		got[i] = position{s.Result.RunnerID(), s.Result.FinishTime(), s.OverallPosition, s.GenderPosition, s.AgeGroupPosition}
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestNewLeaderboard_NonFinishers(t *testing.T) {
+	raceID := uuid.New()
+	anna, bob, carl, dana, eve, finn := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	newResult := func(runnerID uuid.UUID, status Status, reason string, finishTime time.Duration, loggedAt time.Time) Result {
+		var pace float64
+		if finishTime > 0 {
+			pace = 5
+		}
+		r, err := LoadResult(uuid.New(), runnerID, raceID, status, reason, finishTime, finishTime, pace, 0, "", nil, PublicationProvisional, "", loggedAt)
+		require.NoError(t, err)
+		return r
+	}
+	now := time.Now()
+
+	results := []Result{
+		newResult(anna, StatusDidNotStart, "", 0, now),
+		newResult(bob, StatusDisqualified, "course cutting", 30*time.Minute, now),
+		newResult(carl, StatusFinished, "", 45*time.Minute, now),
+		newResult(carl, StatusDidNotFinish, "", 0, now.Add(time.Hour)),
+		newResult(dana, StatusDidNotStart, "", 0, now),
+		newResult(dana, StatusDidNotFinish, "", 0, now.Add(time.Hour)),
+		newResult(eve, StatusFinished, "", 40*time.Minute, now),
+		newResult(eve, StatusDisqualified, "no bib", 40*time.Minute, now.Add(time.Hour)),
+		newResult(finn, StatusDisqualified, "pacer", 35*time.Minute, now),
+		newResult(finn, StatusFinished, "", 35*time.Minute, now.Add(time.Hour)),
+	}
+
+	standings := NewLeaderboard(results, map[uuid.UUID]Division{bob: {Gender: "male", AgeGroup: "30-34"}}, RankingGunTime)
+
+	type position struct {
+		runnerID uuid.UUID
+		status   Status
+		overall  int
+		gender   int
+	}
+	want := []position{
+		{carl, StatusFinished, 1, 0},
+		{bob, StatusDisqualified, 0, 0},
+		{finn, StatusDisqualified, 0, 0},
+		{eve, StatusDisqualified, 0, 0},
+		{dana, StatusDidNotFinish, 0, 0},
+		{anna, StatusDidNotStart, 0, 0},
+	}
+	got := make([]position, len(standings))
+	for i, s := range standings {
+		got[i] = position{s.Result.RunnerID(), s.Result.Status(), s.OverallPosition, s.GenderPosition}
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestNewLeaderboard_RankingPolicy(t *testing.T) {
+	raceID := uuid.New()
+	anna, bob := uuid.New(), uuid.New()
+	newResult := func(runnerID uuid.UUID, netTime, gunTime time.Duration) Result {
+		r, err := LoadResult(uuid.New(), runnerID, raceID, StatusFinished, "", netTime, gunTime, 5, 150, "", nil, PublicationProvisional, "", time.Now())
+		require.NoError(t, err)
+		return r
+	}
+	//anna started at the back of the wave and ran faster, bob crossed the finish line first
+	results := []Result{
+		newResult(anna, 39*time.Minute, 41*time.Minute),
+		newResult(bob, 40*time.Minute, 40*time.Minute+10*time.Second),
+	}
+
+	gun := NewLeaderboard(results, nil, RankingGunTime)
+	assert.Equal(t, bob, gun[0].Result.RunnerID())
+	assert.Equal(t, anna, gun[1].Result.RunnerID())
+
+	net := NewLeaderboard(results, nil, RankingNetTime)
+	assert.Equal(t, anna, net[0].Result.RunnerID())
+	assert.Equal(t, 1, net[0].OverallPosition)
+	assert.Equal(t, 2, net[1].OverallPosition)
+}
+
+func TestNewLeaderboard_Empty(t *testing.T) {
+	assert.Empty(t, NewLeaderboard(nil, nil, RankingGunTime))
+}
+
+func TestAgeGroup(t *testing.T) {
+	tests := []struct {
+		age  int
+		want string
+	}{
+		{age: 12, want: "U20"},
+		{age: 19, want: "U20"},
+		{age: 20, want: "20-24"},
+		{age: 34, want: "30-34"},
+		{age: 35, want: "35-39"},
+		{age: 79, want: "75-79"},
+		{age: 80, want: "80+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, AgeGroup(tt.age))
+		})
+	}
+}
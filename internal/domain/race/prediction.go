@@ -0,0 +1,165 @@
+package race
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Prediction model parameters
+const (
+	// riegelExponent is the fatigue factor of Riegel's formula
+	riegelExponent = 1.06
+	// climbFlatEquivalentM is the flat distance in metres that takes as long as one metre of climb
+	climbFlatEquivalentM = 8
+	// recencyHalfLife is the age after which a performance counts half as much as a performance of today
+	recencyHalfLife = 180 * 24 * time.Hour
+	// minPredictionSpread is the relative uncertainty of a prediction for a distance that was already raced
+	minPredictionSpread = 0.02
+	// extrapolationSpread is the relative uncertainty added per unit of log distance ratio between the
+	// performance and the predicted distance
+	extrapolationSpread = 0.04
+)
+
+// ErrNoPerformances Error when there are no performances to predict from
+var ErrNoPerformances = errors.New("no results to predict from")
+
+// Performance represents a past race performance used to predict finish times
+type Performance struct {
+	FinishTime    time.Duration
+	DistanceKm    float64
+	ElevationGain float64
+	Date          time.Time
+}
+
+// PerformanceOf returns the performance of a result in the provided race
+func PerformanceOf(result Result, r Race) Performance {
+	return Performance{
+		FinishTime:    result.FinishTime(),
+		DistanceKm:    r.DistanceKm(),
+		ElevationGain: r.ElevationGain(),
+		Date:          r.Date(),
+	}
+}
+
+// Prediction represents the predicted finish time of a race.
+// Time is the average of the Riegel and the VDOT predictions and Low-High is its confidence range.
+type Prediction struct {
+	DistanceKm    float64
+	ElevationGain float64
+	Time          time.Duration
+	Low           time.Duration
+	High          time.Duration
+	Riegel        time.Duration
+	VDOTTime      time.Duration
+	VDOT          float64
+}
+
+// NewPrediction predicts the finish time of a race of distanceKm with elevationGain metres of climb on the
+// provided date. Every performance is converted to the flat equivalent distance, predicted with both models and
+// weighted by its recency and by how close its distance is to the predicted one.
+// The confidence range grows with the disagreement of the predictions and with the extrapolated distance.
+func NewPrediction(performances []Performance, distanceKm, elevationGain float64, at time.Time) (Prediction, error) {
+	if distanceKm <= 0 {
+		return Prediction{}, ErrInvalidDistanceKm
+	}
+	if elevationGain < 0 {
+		return Prediction{}, ErrInvalidElevationGain
+	}
+
+	targetKm := flatEquivalentKm(distanceKm, elevationGain)
+	type estimate struct {
+		weight, riegel, vdotTime, vdot, logRatio float64
+	}
+	estimates := make([]estimate, 0, len(performances))
+	var totalWeight float64
+	for _, p := range performances {
+		if p.FinishTime <= 0 || p.DistanceKm <= 0 {
+			continue
+		}
+		sourceKm := flatEquivalentKm(p.DistanceKm, p.ElevationGain)
+		logRatio := math.Abs(math.Log(targetKm / sourceKm))
+		age := at.Sub(p.Date)
+		if age < 0 {
+			age = 0
+		}
+		vdot := VDOT(p.FinishTime, sourceKm)
+		e := estimate{
+			weight:   math.Pow(0.5, float64(age)/float64(recencyHalfLife)) / (1 + logRatio),
+			riegel:   RiegelTime(p.FinishTime, sourceKm, targetKm).Seconds(),
+			vdotTime: VDOTTime(vdot, targetKm).Seconds(),
+			vdot:     vdot,
+			logRatio: logRatio,
+		}
+		estimates = append(estimates, e)
+		totalWeight += e.weight
+	}
+	if len(estimates) == 0 {
+		return Prediction{}, ErrNoPerformances
+	}
+
+	var riegel, vdotTime, vdot, logRatio float64
+	for _, e := range estimates {
+		riegel += e.weight * e.riegel / totalWeight
+		vdotTime += e.weight * e.vdotTime / totalWeight
+		vdot += e.weight * e.vdot / totalWeight
+		logRatio += e.weight * e.logRatio / totalWeight
+	}
+	predicted := (riegel + vdotTime) / 2
+
+	var variance float64
+	for _, e := range estimates {
+		variance += e.weight * (math.Pow(e.riegel-predicted, 2) + math.Pow(e.vdotTime-predicted, 2)) / (2 * totalWeight)
+	}
+	extrapolation := predicted * (minPredictionSpread + extrapolationSpread*logRatio)
+	spread := math.Sqrt(variance + extrapolation*extrapolation)
+
+	return Prediction{
+		DistanceKm:    distanceKm,
+		ElevationGain: elevationGain,
+		Time:          seconds(predicted),
+		Low:           seconds(predicted - spread),
+		High:          seconds(predicted + spread),
+		Riegel:        seconds(riegel),
+		VDOTTime:      seconds(vdotTime),
+		VDOT:          vdot,
+	}, nil
+}
+
+// RiegelTime predicts the time over toKm from a finishTime over fromKm with Riegel's formula
+func RiegelTime(finishTime time.Duration, fromKm, toKm float64) time.Duration {
+	return time.Duration(float64(finishTime) * math.Pow(toKm/fromKm, riegelExponent))
+}
+
+// VDOT returns the Daniels-Gilbert VDOT of a finishTime over distanceKm
+func VDOT(finishTime time.Duration, distanceKm float64) float64 {
+	minutes := finishTime.Minutes()
+	velocity := distanceKm * 1000 / minutes
+	vo2 := -4.60 + 0.182258*velocity + 0.000104*velocity*velocity
+	fraction := 0.8 + 0.1894393*math.Exp(-0.012778*minutes) + 0.2989558*math.Exp(-0.1932605*minutes)
+	return vo2 / fraction
+}
+
+// VDOTTime returns the time over distanceKm that corresponds to the provided VDOT.
+// VDOT decreases as the time over a distance increases, so the time is found by bisection.
+func VDOTTime(vdot, distanceKm float64) time.Duration {
+	low, high := time.Second, 7*24*time.Hour
+	for high-low > time.Millisecond {
+		middle := low + (high-low)/2
+		if VDOT(middle, distanceKm) > vdot {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return low + (high-low)/2
+}
+
+// flatEquivalentKm returns the flat distance that takes as long as distanceKm with elevationGain metres of climb
+func flatEquivalentKm(distanceKm, elevationGain float64) float64 {
+	return distanceKm + elevationGain*climbFlatEquivalentM/1000
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Second)
+}
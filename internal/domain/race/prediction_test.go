@@ -0,0 +1,90 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiegelTime(t *testing.T) {
+	assert.Equal(t, 20*time.Minute, RiegelTime(20*time.Minute, 5, 5))
+	assert.Equal(t, 41*time.Minute+42*time.Second, RiegelTime(20*time.Minute, 5, 10).Round(time.Second))
+}
+
+func TestVDOT(t *testing.T) {
+	assert.InDelta(t, 49.8, VDOT(20*time.Minute, 5), 0.1)
+	assert.InDelta(t, 53.5, VDOT(3*time.Hour, 42.195), 0.1)
+}
+
+func TestVDOTTime(t *testing.T) {
+	assert.Equal(t, 20*time.Minute, VDOTTime(VDOT(20*time.Minute, 5), 5).Round(time.Second))
+	assert.Equal(t, 41*time.Minute+28*time.Second, VDOTTime(VDOT(20*time.Minute, 5), 10).Round(time.Second))
+}
+
+func TestNewPrediction(t *testing.T) {
+	now := time.Now()
+	fiveK := Performance{FinishTime: 20 * time.Minute, DistanceKm: 5, Date: now}
+
+	t.Run("should predict the raced distance with the minimum spread", func(t *testing.T) {
+		got, err := NewPrediction([]Performance{fiveK}, 5, 0, now)
+		require.NoError(t, err)
+		assert.Equal(t, Prediction{
+			DistanceKm: 5,
+			Time:       20 * time.Minute,
+			Low:        19*time.Minute + 36*time.Second,
+			High:       20*time.Minute + 24*time.Second,
+			Riegel:     20 * time.Minute,
+			VDOTTime:   20 * time.Minute,
+			VDOT:       got.VDOT,
+		}, got)
+	})
+
+	t.Run("should widen the range when extrapolating", func(t *testing.T) {
+		got, err := NewPrediction([]Performance{fiveK}, 42.195, 0, now)
+		require.NoError(t, err)
+		assert.Equal(t, 3*time.Hour+11*time.Minute+49*time.Second, got.Riegel)
+		assert.Equal(t, 3*time.Hour+11*time.Minute+17*time.Second, got.VDOTTime)
+		assert.Equal(t, 3*time.Hour+11*time.Minute+33*time.Second, got.Time)
+		assert.Greater(t, float64(got.High-got.Low)/float64(got.Time), 0.2)
+	})
+
+	t.Run("should slow down for elevation gain", func(t *testing.T) {
+		flat, err := NewPrediction([]Performance{fiveK}, 42.195, 0, now)
+		require.NoError(t, err)
+		hilly, err := NewPrediction([]Performance{fiveK}, 42.195, 300, now)
+		require.NoError(t, err)
+		assert.Greater(t, hilly.Time, flat.Time)
+
+		hillyFiveK := Performance{FinishTime: 20 * time.Minute, DistanceKm: 5, ElevationGain: 100, Date: now}
+		fromHills, err := NewPrediction([]Performance{hillyFiveK}, 5, 0, now)
+		require.NoError(t, err)
+		assert.Less(t, fromHills.Time, 20*time.Minute)
+	})
+
+	t.Run("should weight recent performances more", func(t *testing.T) {
+		old := Performance{FinishTime: 24 * time.Minute, DistanceKm: 5, Date: now.AddDate(-1, 0, 0)}
+		got, err := NewPrediction([]Performance{fiveK, old}, 5, 0, now)
+		require.NoError(t, err)
+		assert.Less(t, got.Time, 22*time.Minute)
+		assert.Greater(t, got.Time, 20*time.Minute)
+		assert.Less(t, got.Low, got.Time)
+		assert.Greater(t, got.High, got.Time)
+	})
+
+	t.Run("should fail without performances", func(t *testing.T) {
+		_, err := NewPrediction(nil, 5, 0, now)
+		assert.ErrorIs(t, err, ErrNoPerformances)
+	})
+
+	t.Run("should fail on invalid distance", func(t *testing.T) {
+		_, err := NewPrediction([]Performance{fiveK}, 0, 0, now)
+		assert.ErrorIs(t, err, ErrInvalidDistanceKm)
+	})
+
+	t.Run("should fail on negative elevation gain", func(t *testing.T) {
+		_, err := NewPrediction([]Performance{fiveK}, 5, -1, now)
+		assert.ErrorIs(t, err, ErrInvalidElevationGain)
+	})
+}
@@ -22,9 +22,16 @@ type Race struct {
 	date          time.Time
 	distanceKm    float64
 	elevationGain float64
+	waves         []Wave
+	rankingPolicy RankingPolicy
+	organiser     string
+
+	cancelledAt        time.Time
+	cancellationReason string
 }
 
-// NewRace creates a new Race entity and validates the input
+// NewRace creates a new Race entity and validates the input.
+// The race has a single start at its date and ranks results by gun time until configured otherwise.
 func NewRace(name, location string, date time.Time, distanceKm, elevationGain float64) (Race, error) {
 	if name == "" {
 		return Race{}, ErrEmptyName
@@ -46,9 +53,53 @@ func NewRace(name, location string, date time.Time, distanceKm, elevationGain fl
 		date:          date,
 		distanceKm:    distanceKm,
 		elevationGain: elevationGain,
+		rankingPolicy: RankingGunTime,
 	}, nil
 }
 
+// LoadRace recreates an existing Race entity from stored data and validates it.
+// A zero cancelledAt loads a race that is not cancelled.
+func LoadRace(id uuid.UUID, name, location string, date time.Time, distanceKm, elevationGain float64, waves []Wave, rankingPolicy RankingPolicy, cancelledAt time.Time, cancellationReason string) (Race, error) {
+	r, err := NewRace(name, location, date, distanceKm, elevationGain)
+	if err != nil {
+		return Race{}, err
+	}
+	if r, err = r.WithWaves(waves); err != nil {
+		return Race{}, err
+	}
+	if r, err = r.WithRankingPolicy(rankingPolicy); err != nil {
+		return Race{}, err
+	}
+	if !cancelledAt.IsZero() {
+		if r, err = r.Cancel(cancellationReason, cancelledAt); err != nil {
+			return Race{}, err
+		}
+	}
+	r.id = id
+
+	return r, nil
+}
+
+// WithWaves returns a copy of the race with the provided start waves, ordered by start time.
+// Wave names must be unique; a race without waves has a single start.
+func (r Race) WithWaves(waves []Wave) (Race, error) {
+	sorted, err := sortWaves(waves)
+	if err != nil {
+		return Race{}, err
+	}
+	r.waves = sorted
+	return r, nil
+}
+
+// WithRankingPolicy returns a copy of the race ranking its finished results by the provided time
+func (r Race) WithRankingPolicy(policy RankingPolicy) (Race, error) {
+	if _, err := ParseRankingPolicy(string(policy)); err != nil {
+		return Race{}, err
+	}
+	r.rankingPolicy = policy
+	return r, nil
+}
+
 // ID returns the race ID
 func (r Race) ID() uuid.UUID {
 	return r.id
@@ -78,3 +129,35 @@ func (r Race) DistanceKm() float64 {
 func (r Race) ElevationGain() float64 {
 	return r.elevationGain
 }
+
+// Waves returns the start waves of the race ordered by start time
+func (r Race) Waves() []Wave {
+	return append([]Wave(nil), r.waves...)
+}
+
+// Wave returns the start wave with the provided name
+func (r Race) Wave(name string) (Wave, bool) {
+	for _, w := range r.waves {
+		if w.name == name {
+			return w, true
+		}
+	}
+	return Wave{}, false
+}
+
+// RankingPolicy returns the time the finished results of the race are ranked by
+func (r Race) RankingPolicy() RankingPolicy {
+	return r.rankingPolicy
+}
+
+// WithOrganiser returns a copy of the race managed by the organiser with the provided identity.
+// Races without organiser are only managed by administrators.
+func (r Race) WithOrganiser(organiser string) Race {
+	r.organiser = organiser
+	return r
+}
+
+// Organiser returns the identity of the organiser managing the race
+func (r Race) Organiser() string {
+	return r.organiser
+}
@@ -121,3 +121,29 @@ func TestRaceGetters(t *testing.T) {
 	assert.Equal(t, 30.5, race.DistanceKm())
 	assert.Equal(t, 500.0, race.ElevationGain())
 }
+
+func TestLoadRace(t *testing.T) {
+	now := time.Now()
+	id := uuid.New()
+
+	race, err := LoadRace(id, "Marathon", "Athens", now, 42.195, 100, nil, RankingGunTime, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, id, race.ID())
+	assert.Equal(t, "Marathon", race.Name())
+	assert.Equal(t, "Athens", race.Location())
+	assert.Equal(t, now, race.Date())
+	assert.Equal(t, 42.195, race.DistanceKm())
+	assert.Equal(t, 100.0, race.ElevationGain())
+	assert.False(t, race.IsCancelled())
+
+	_, err = LoadRace(id, "", "Athens", now, 42.195, 100, nil, RankingGunTime, time.Time{}, "")
+	assert.Equal(t, ErrEmptyName, err)
+
+	race, err = LoadRace(id, "Marathon", "Athens", now, 42.195, 100, nil, RankingGunTime, now, "storm")
+	assert.NoError(t, err)
+	assert.True(t, race.IsCancelled())
+	assert.Equal(t, "storm", race.CancellationReason())
+
+	_, err = LoadRace(id, "Marathon", "Athens", now, 42.195, 100, nil, RankingGunTime, now, "")
+	assert.Equal(t, ErrMissingCancellationReason, err)
+}
@@ -0,0 +1,90 @@
+package race
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// standardDistanceTolerance is the relative difference within which a race counts as a standard distance,
+// so that a 21.1km half marathon and a 42.2km marathon are grouped with the official distances
+const standardDistanceTolerance = 0.01
+
+// StandardDistance represents a commonly raced distance that personal records are kept for
+type StandardDistance struct {
+	Name       string
+	DistanceKm float64
+}
+
+// StandardDistances contains the distances that personal records are grouped by.
+// Races of any other distance keep their own custom record.
+var StandardDistances = []StandardDistance{
+	{Name: "5K", DistanceKm: 5},
+	{Name: "10K", DistanceKm: 10},
+	{Name: "Half Marathon", DistanceKm: 21.0975},
+	{Name: "Marathon", DistanceKm: 42.195},
+}
+
+// PersonalRecord represents the fastest result of a runner over a distance
+type PersonalRecord struct {
+	Distance   string
+	DistanceKm float64
+	Result     Result
+	Race       Race
+}
+
+// DistanceOf returns the standard distance matching distanceKm.
+// Other distances are returned as custom distances named after their length, e.g. 15K or 12.5K.
+func DistanceOf(distanceKm float64) StandardDistance {
+	for _, d := range StandardDistances {
+		if math.Abs(distanceKm-d.DistanceKm)/d.DistanceKm <= standardDistanceTolerance {
+			return d
+		}
+	}
+	return StandardDistance{Name: strconv.FormatFloat(distanceKm, 'f', -1, 64) + "K", DistanceKm: distanceKm}
+}
+
+// NewPersonalRecords returns the fastest result of each distance, ordered by distance.
+// races contains the races of the results; results of unknown races and of runners that did not finish are skipped.
+// Records compare net times, and when two results share the fastest time the earliest logged one holds the record.
+func NewPersonalRecords(results []Result, races map[uuid.UUID]Race) []PersonalRecord {
+	best := make(map[string]PersonalRecord)
+	for _, r := range results {
+		rc, known := races[r.RaceID()]
+		if !known || !r.IsFinished() {
+			continue
+		}
+		distance := DistanceOf(rc.DistanceKm())
+		current, exists := best[distance.Name]
+		if !exists || faster(r, current.Result, RankingNetTime) {
+			best[distance.Name] = PersonalRecord{Distance: distance.Name, DistanceKm: distance.DistanceKm, Result: r, Race: rc}
+		}
+	}
+
+	records := make([]PersonalRecord, 0, len(best))
+	for _, record := range best {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].DistanceKm < records[j].DistanceKm
+	})
+	return records
+}
+
+// IsPersonalRecord reports whether result, run in race r, beats the records of its distance.
+// It also returns the current record of the distance, which is nil for the first result of a distance.
+// Results of runners that did not finish are never records.
+func IsPersonalRecord(records []PersonalRecord, result Result, r Race) (bool, *PersonalRecord) {
+	if !result.IsFinished() {
+		return false, nil
+	}
+	distance := DistanceOf(r.DistanceKm())
+	for i := range records {
+		if records[i].Distance == distance.Name {
+			return result.FinishTime() < records[i].Result.FinishTime(), &records[i]
+		}
+	}
+	return true, nil
+}
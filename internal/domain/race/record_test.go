@@ -0,0 +1,102 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistanceOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		distanceKm float64
+		want       StandardDistance
+	}{
+		{name: "should match 5K", distanceKm: 5, want: StandardDistance{Name: "5K", DistanceKm: 5}},
+		{name: "should match 10K within tolerance", distanceKm: 10.05, want: StandardDistance{Name: "10K", DistanceKm: 10}},
+		{name: "should match rounded half marathon", distanceKm: 21.1, want: StandardDistance{Name: "Half Marathon", DistanceKm: 21.0975}},
+		{name: "should match rounded marathon", distanceKm: 42.2, want: StandardDistance{Name: "Marathon", DistanceKm: 42.195}},
+		{name: "should name custom distance", distanceKm: 15, want: StandardDistance{Name: "15K", DistanceKm: 15}},
+		{name: "should name fractional custom distance", distanceKm: 12.5, want: StandardDistance{Name: "12.5K", DistanceKm: 12.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DistanceOf(tt.distanceKm))
+		})
+	}
+}
+
+func TestNewPersonalRecords(t *testing.T) {
+	runnerID := uuid.New()
+	newRace := func(distanceKm float64) Race {
+		r, err := NewRace("Race", "Athens", time.Now(), distanceKm, 0)
+		require.NoError(t, err)
+		return r
+	}
+	newResult := func(r Race, finishTime time.Duration, loggedAt time.Time) Result {
+		result, err := LoadResult(uuid.New(), runnerID, r.ID(), StatusFinished, "", finishTime, finishTime, 5, 150, "", nil, PublicationProvisional, "", loggedAt)
+		require.NoError(t, err)
+		return result
+	}
+	now := time.Now()
+	fiveK, otherFiveK, half, trail, unknown := newRace(5), newRace(5.02), newRace(21.1), newRace(12.5), newRace(10)
+
+	slowFiveK := newResult(fiveK, 25*time.Minute, now)
+	fastFiveK := newResult(otherFiveK, 22*time.Minute, now.Add(time.Hour))
+	halfResult := newResult(half, 100*time.Minute, now)
+	firstTrail := newResult(trail, time.Hour, now)
+	tiedTrail := newResult(trail, time.Hour, now.Add(time.Hour))
+	unknownRace := newResult(unknown, 40*time.Minute, now)
+	disqualified, err := LoadResult(uuid.New(), runnerID, fiveK.ID(), StatusDisqualified, "course cutting", 15*time.Minute, 15*time.Minute, 3, 150, "", nil, PublicationProvisional, "", now)
+	require.NoError(t, err)
+
+	races := map[uuid.UUID]Race{fiveK.ID(): fiveK, otherFiveK.ID(): otherFiveK, half.ID(): half, trail.ID(): trail}
+	records := NewPersonalRecords([]Result{halfResult, slowFiveK, tiedTrail, fastFiveK, unknownRace, firstTrail, disqualified}, races)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, PersonalRecord{Distance: "5K", DistanceKm: 5, Result: fastFiveK, Race: otherFiveK}, records[0])
+	assert.Equal(t, PersonalRecord{Distance: "12.5K", DistanceKm: 12.5, Result: firstTrail, Race: trail}, records[1])
+	assert.Equal(t, PersonalRecord{Distance: "Half Marathon", DistanceKm: 21.0975, Result: halfResult, Race: half}, records[2])
+}
+
+func TestIsPersonalRecord(t *testing.T) {
+	r, err := NewRace("Race", "Athens", time.Now(), 10, 0)
+	require.NoError(t, err)
+	newResult := func(finishTime time.Duration) Result {
+		result, err := NewResult(uuid.New(), r.ID(), finishTime, 5, 150, "")
+		require.NoError(t, err)
+		return result
+	}
+	records := NewPersonalRecords([]Result{newResult(45 * time.Minute)}, map[uuid.UUID]Race{r.ID(): r})
+
+	tests := []struct {
+		name        string
+		records     []PersonalRecord
+		finishTime  time.Duration
+		want        bool
+		wantCurrent *PersonalRecord
+	}{
+		{name: "should set record on first result of distance", records: nil, finishTime: 50 * time.Minute, want: true},
+		{name: "should set record when faster", records: records, finishTime: 44 * time.Minute, want: true, wantCurrent: &records[0]},
+		{name: "should not set record when equal", records: records, finishTime: 45 * time.Minute, want: false, wantCurrent: &records[0]},
+		{name: "should not set record when slower", records: records, finishTime: 46 * time.Minute, want: false, wantCurrent: &records[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, current := IsPersonalRecord(tt.records, newResult(tt.finishTime), r)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCurrent, current)
+		})
+	}
+
+	t.Run("should not set record when disqualified", func(t *testing.T) {
+		disqualified, err := NewResultWithStatus(uuid.New(), r.ID(), StatusDisqualified, "course cutting", 30*time.Minute, 3, 150, "")
+		require.NoError(t, err)
+		got, current := IsPersonalRecord(records, disqualified, r)
+		assert.False(t, got)
+		assert.Nil(t, current)
+	})
+}
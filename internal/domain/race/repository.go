@@ -1,11 +1,35 @@
 package race
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound Error when no race exists with the provided id
+var ErrNotFound = errors.New("race not found")
 
 // Repository defines the storage interface for race
 type Repository interface {
-	SaveRace(Race) error
-	GetRace(raceID uuid.UUID) (Race, error)
-	SaveRaceResult(raceLog Result) error
-	GetRaceResults(runnerID uuid.UUID) ([]Result, error)
+	SaveRace(ctx context.Context, race Race) error
+	GetRace(ctx context.Context, raceID uuid.UUID) (Race, error)
+	// SearchRaces returns the races that match the search after its cursor, in the order of the search
+	SearchRaces(ctx context.Context, search Search) ([]Race, error)
+	SaveRaceResult(ctx context.Context, raceLog Result) error
+	GetResult(ctx context.Context, resultID uuid.UUID) (Result, error)
+	UpdateRaceResult(ctx context.Context, result Result) error
+	// SaveRaceWithResults stores the race and updates the provided results in one transaction, so either
+	// every change is stored or none is. It returns ErrResultNotFound when a result is missing.
+	SaveRaceWithResults(ctx context.Context, race Race, results []Result) error
+	GetRaceResults(ctx context.Context, runnerID uuid.UUID) ([]Result, error)
+	GetResultsByRace(ctx context.Context, raceID uuid.UUID) ([]Result, error)
+}
+
+// CourseRepository defines the storage interface for the courses of races
+type CourseRepository interface {
+	// SaveCourse stores the course of the race, replacing any existing course
+	SaveCourse(ctx context.Context, raceID uuid.UUID, course Course) error
+	// GetCourse returns the course of the race, or ErrCourseNotFound
+	GetCourse(ctx context.Context, raceID uuid.UUID) (Course, error)
 }
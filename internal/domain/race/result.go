@@ -1,53 +1,145 @@
 package race
 
 import (
-	"fmt"
+	"errors"
 	"github.com/google/uuid"
+	"math"
 	"time"
 )
 
-// Result represents a race record for a participant
+var (
+	ErrEmptyRunnerID       = errors.New("runnerID cannot be empty")
+	ErrEmptyRaceID         = errors.New("raceID cannot be empty")
+	ErrInvalidFinishTime   = errors.New("finishTime must be greater than 0")
+	ErrInvalidPace         = errors.New("paceMinPerKm must be greater than 0")
+	ErrInvalidHeartRateAvg = errors.New("heartRateAvg cannot be negative")
+	ErrInvalidGunTime      = errors.New("gunTime cannot be less than the net finishTime")
+)
+
+// Result represents a race record for a participant.
+// The finish time is the net (chip) time from crossing the start line, and the gun time runs from the
+// start signal of the wave of the runner; both are the same when the start line crossing is unknown.
 type Result struct {
-	id           uuid.UUID
-	runnerID     uuid.UUID
-	raceID       uuid.UUID
-	finishTime   time.Duration
-	paceMinPerKm float64 // min/km
-	heartRateAvg int
-	notes        string
-	loggedAt     time.Time
+	id                uuid.UUID
+	runnerID          uuid.UUID
+	raceID            uuid.UUID
+	status            Status
+	statusReason      string
+	finishTime        time.Duration
+	gunTime           time.Duration
+	paceMinPerKm      float64 // min/km
+	heartRateAvg      int
+	notes             string
+	splits            []Split
+	publication       Publication
+	publicationReason string
+	loggedAt          time.Time
 }
 
-// NewResult creates a new Result entity and validates the input
+// NewResult creates a new provisional Result of a runner that finished the race and validates the input
 func NewResult(runnerID, raceID uuid.UUID, finishTime time.Duration, paceMinPerKm float64, heartRateAvg int, notes string) (Result, error) {
+	return NewResultWithStatus(runnerID, raceID, StatusFinished, "", finishTime, paceMinPerKm, heartRateAvg, notes)
+}
+
+// NewResultWithStatus creates a new provisional Result with the provided status and validates the input.
+// Results of runners that did not finish or did not start have no finish time and pace.
+func NewResultWithStatus(runnerID, raceID uuid.UUID, status Status, statusReason string, finishTime time.Duration, paceMinPerKm float64, heartRateAvg int, notes string) (Result, error) {
 	if runnerID == uuid.Nil {
-		return Result{}, fmt.Errorf("runnerID cannot be empty")
+		return Result{}, ErrEmptyRunnerID
 	}
 	if raceID == uuid.Nil {
-		return Result{}, fmt.Errorf("raceID cannot be empty")
+		return Result{}, ErrEmptyRaceID
 	}
-	if finishTime <= 0 {
-		return Result{}, fmt.Errorf("finishTime must be greater than 0")
-	}
-	if paceMinPerKm <= 0 {
-		return Result{}, fmt.Errorf("paceMinPerKm must be greater than 0")
+	if err := validateStatus(status, statusReason, finishTime, paceMinPerKm); err != nil {
+		return Result{}, err
 	}
 	if heartRateAvg < 0 {
-		return Result{}, fmt.Errorf("heartRateAvg cannot be negative")
+		return Result{}, ErrInvalidHeartRateAvg
 	}
 
 	return Result{
 		id:           uuid.New(),
 		runnerID:     runnerID,
 		raceID:       raceID,
+		status:       status,
+		statusReason: statusReason,
 		finishTime:   finishTime,
+		gunTime:      finishTime,
 		paceMinPerKm: paceMinPerKm,
 		heartRateAvg: heartRateAvg,
 		notes:        notes,
+		publication:  PublicationProvisional,
 		loggedAt:     time.Now(),
 	}, nil
 }
 
+// LoadResult recreates an existing Result entity from stored data and validates it
+func LoadResult(id, runnerID, raceID uuid.UUID, status Status, statusReason string, finishTime, gunTime time.Duration, paceMinPerKm float64, heartRateAvg int, notes string, splits []Split, publication Publication, publicationReason string, loggedAt time.Time) (Result, error) {
+	r, err := NewResultWithStatus(runnerID, raceID, status, statusReason, finishTime, paceMinPerKm, heartRateAvg, notes)
+	if err != nil {
+		return Result{}, err
+	}
+	if r, err = r.WithGunTime(gunTime); err != nil {
+		return Result{}, err
+	}
+	if err := validateSplits(splits, status, finishTime); err != nil {
+		return Result{}, err
+	}
+	if _, err := ParsePublication(string(publication)); err != nil {
+		return Result{}, err
+	}
+	r.id = id
+	r.splits = append([]Split(nil), splits...)
+	r.publication = publication
+	r.publicationReason = publicationReason
+	r.loggedAt = loggedAt
+
+	return r, nil
+}
+
+// WithGunTime returns a copy of the result with the time from the start signal of the wave of the runner,
+// which cannot be less than the net finish time. Results without a finish time have no gun time either.
+func (r Result) WithGunTime(gunTime time.Duration) (Result, error) {
+	if r.finishTime == 0 && gunTime != 0 {
+		return Result{}, ErrNoFinishTime
+	}
+	if gunTime < r.finishTime {
+		return Result{}, ErrInvalidGunTime
+	}
+	r.gunTime = gunTime
+	return r, nil
+}
+
+// WithSplits returns a copy of the result with the provided splits, ordered by distance.
+// The splits must be strictly increasing and fit within the race distance and the finish time;
+// a split at the race distance must match the finish time.
+func (r Result) WithSplits(splits []Split, raceDistanceKm float64) (Result, error) {
+	if err := validateSplits(splits, r.status, r.finishTime); err != nil {
+		return Result{}, err
+	}
+	if len(splits) > 0 {
+		//splits are increasing, so the last one is the furthest
+		last := splits[len(splits)-1]
+		if last.distanceKm-raceDistanceKm > distanceToleranceKm {
+			return Result{}, ErrSplitBeyondRace
+		}
+		if math.Abs(last.distanceKm-raceDistanceKm) <= distanceToleranceKm && last.elapsed != r.finishTime {
+			return Result{}, ErrFinishSplitMismatch
+		}
+	}
+
+	r.splits = append([]Split(nil), splits...)
+	return r, nil
+}
+
+// SplitAnalysis returns the metrics derived from the splits of the result for a race of raceDistanceKm
+func (r Result) SplitAnalysis(raceDistanceKm float64) (SplitAnalysis, error) {
+	if r.finishTime <= 0 {
+		return SplitAnalysis{}, ErrNoFinishTime
+	}
+	return analyseSplits(r.splits, r.finishTime, raceDistanceKm)
+}
+
 // ID returns the race log ID
 func (r Result) ID() uuid.UUID {
 	return r.id
@@ -63,11 +155,24 @@ func (r Result) RaceID() uuid.UUID {
 	return r.raceID
 }
 
-// FinishTime returns the finish time
+// FinishTime returns the net (chip) finish time, from crossing the start line to crossing the finish line
 func (r Result) FinishTime() time.Duration {
 	return r.finishTime
 }
 
+// GunTime returns the finish time from the start signal of the wave of the runner
+func (r Result) GunTime() time.Duration {
+	return r.gunTime
+}
+
+// RankingTime returns the time the result is ranked by under the provided policy
+func (r Result) RankingTime(policy RankingPolicy) time.Duration {
+	if policy == RankingNetTime {
+		return r.finishTime
+	}
+	return r.gunTime
+}
+
 // Pace returns the paceMinPerKm
 func (r Result) Pace() float64 {
 	return r.paceMinPerKm
@@ -83,6 +188,11 @@ func (r Result) Notes() string {
 	return r.notes
 }
 
+// Splits returns the splits of the result ordered by distance
+func (r Result) Splits() []Split {
+	return append([]Split(nil), r.splits...)
+}
+
 // LoggedAt returns the logged at time
 func (r Result) LoggedAt() time.Time {
 	return r.loggedAt
@@ -118,3 +118,26 @@ func TestRaceLogValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadResult(t *testing.T) {
+	id := uuid.New()
+	runnerID := uuid.New()
+	raceID := uuid.New()
+	loggedAt := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+
+	result, err := LoadResult(id, runnerID, raceID, StatusFinished, "", time.Hour, time.Hour+time.Minute, 5.0, 150, "Good race", nil, PublicationProvisional, "", loggedAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID() != id {
+		t.Errorf("expected ID %v, got %v", id, result.ID())
+	}
+	if result.LoggedAt() != loggedAt {
+		t.Errorf("expected loggedAt %v, got %v", loggedAt, result.LoggedAt())
+	}
+
+	_, err = LoadResult(id, runnerID, raceID, StatusFinished, "", 0, 0, 5.0, 150, "Good race", nil, PublicationProvisional, "", loggedAt)
+	if err == nil {
+		t.Errorf("expected error for zero finish time")
+	}
+}
@@ -0,0 +1,145 @@
+package race
+
+import (
+	"cmp"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SortField is the field the races of a search are ordered by
+type SortField string
+
+// Fields a search can be ordered by
+const (
+	SortByDate          SortField = "date"
+	SortByName          SortField = "name"
+	SortByDistance      SortField = "distance_km"
+	SortByElevationGain SortField = "elevation_gain"
+)
+
+var (
+	ErrInvalidSortField      = errors.New("sort must be one of date, name, distance_km or elevation_gain")
+	ErrInvalidDateRange      = errors.New("date range ends before it starts")
+	ErrInvalidDistanceRange  = errors.New("minimum distance is greater than the maximum distance")
+	ErrInvalidElevationRange = errors.New("minimum elevation gain is greater than the maximum elevation gain")
+)
+
+// ParseSortField returns the sort field with the provided name
+func ParseSortField(field string) (SortField, error) {
+	switch f := SortField(field); f {
+	case SortByDate, SortByName, SortByDistance, SortByElevationGain:
+		return f, nil
+	}
+	return "", ErrInvalidSortField
+}
+
+// Cursor is the position of a race in the order of a search. The page after a cursor starts with the race
+// that follows it; the id of the race breaks ties between races with the same sort value.
+type Cursor struct {
+	ID            uuid.UUID
+	Name          string
+	Date          time.Time
+	DistanceKm    float64
+	ElevationGain float64
+}
+
+// CursorOf returns the position of the race in the order of a search
+func CursorOf(r Race) Cursor {
+	return Cursor{ID: r.id, Name: r.name, Date: r.date, DistanceKm: r.distanceKm, ElevationGain: r.elevationGain}
+}
+
+// Search represents the criteria of a race search. Zero values do not filter, so the zero Search
+// returns every race ordered by date.
+// The date range includes From and excludes To. Location matches case-insensitively and Text matches any
+// part of the name case-insensitively. A zero Limit returns every race after the cursor.
+type Search struct {
+	From             time.Time
+	To               time.Time
+	Location         string
+	MinDistanceKm    *float64
+	MaxDistanceKm    *float64
+	MinElevationGain *float64
+	MaxElevationGain *float64
+	Text             string
+	SortBy           SortField
+	Descending       bool
+	After            *Cursor
+	Limit            int
+}
+
+// Validate returns an error when the sort field is unknown or a range ends before it starts
+func (s Search) Validate() error {
+	if s.SortBy != "" {
+		if _, err := ParseSortField(string(s.SortBy)); err != nil {
+			return err
+		}
+	}
+	if !s.From.IsZero() && !s.To.IsZero() && s.To.Before(s.From) {
+		return ErrInvalidDateRange
+	}
+	if s.MinDistanceKm != nil && s.MaxDistanceKm != nil && *s.MinDistanceKm > *s.MaxDistanceKm {
+		return ErrInvalidDistanceRange
+	}
+	if s.MinElevationGain != nil && s.MaxElevationGain != nil && *s.MinElevationGain > *s.MaxElevationGain {
+		return ErrInvalidElevationRange
+	}
+	return nil
+}
+
+// Sort returns the field the races are ordered by, which defaults to the date
+func (s Search) Sort() SortField {
+	if s.SortBy == "" {
+		return SortByDate
+	}
+	return s.SortBy
+}
+
+// Matches returns whether the race meets the filters of the search. The cursor is not taken into account.
+func (s Search) Matches(r Race) bool {
+	switch {
+	case !s.From.IsZero() && r.date.Before(s.From),
+		!s.To.IsZero() && !r.date.Before(s.To),
+		s.Location != "" && !strings.EqualFold(r.location, s.Location),
+		s.MinDistanceKm != nil && r.distanceKm < *s.MinDistanceKm,
+		s.MaxDistanceKm != nil && r.distanceKm > *s.MaxDistanceKm,
+		s.MinElevationGain != nil && r.elevationGain < *s.MinElevationGain,
+		s.MaxElevationGain != nil && r.elevationGain > *s.MaxElevationGain,
+		s.Text != "" && !strings.Contains(strings.ToLower(r.name), strings.ToLower(s.Text)):
+		return false
+	}
+	return true
+}
+
+// IsAfterCursor returns whether the race comes after the cursor of the search, which is true for every race
+// without a cursor
+func (s Search) IsAfterCursor(r Race) bool {
+	return s.After == nil || s.Less(*s.After, CursorOf(r))
+}
+
+// Less returns whether the race at position a comes before the race at position b in the order of the search
+func (s Search) Less(a, b Cursor) bool {
+	c := s.compare(a, b)
+	if c == 0 {
+		c = strings.Compare(a.ID.String(), b.ID.String())
+	}
+	if s.Descending {
+		return c > 0
+	}
+	return c < 0
+}
+
+func (s Search) compare(a, b Cursor) int {
+	switch s.Sort() {
+	case SortByName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortByDistance:
+		return cmp.Compare(a.DistanceKm, b.DistanceKm)
+	case SortByElevationGain:
+		return cmp.Compare(a.ElevationGain, b.ElevationGain)
+	default:
+		return a.Date.Compare(b.Date)
+	}
+}
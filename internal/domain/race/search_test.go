@@ -0,0 +1,86 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_Validate(t *testing.T) {
+	date := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+	five, ten := 5.0, 10.0
+
+	tests := []struct {
+		name    string
+		search  Search
+		wantErr error
+	}{
+		{name: "zero search", search: Search{}},
+		{name: "known sort field", search: Search{SortBy: SortByElevationGain, Descending: true}},
+		{name: "unknown sort field", search: Search{SortBy: "location"}, wantErr: ErrInvalidSortField},
+		{name: "date range ends before it starts", search: Search{From: date, To: date.Add(-time.Hour)}, wantErr: ErrInvalidDateRange},
+		{name: "distance range", search: Search{MinDistanceKm: &five, MaxDistanceKm: &ten}},
+		{name: "inverted distance range", search: Search{MinDistanceKm: &ten, MaxDistanceKm: &five}, wantErr: ErrInvalidDistanceRange},
+		{name: "inverted elevation range", search: Search{MinElevationGain: &ten, MaxElevationGain: &five}, wantErr: ErrInvalidElevationRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.search.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestSearch_Matches(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	r, err := NewRace("Athens Night 10K", "Athens", date, 10, 50)
+	require.NoError(t, err)
+	five, ten, hundred := 5.0, 10.0, 100.0
+
+	tests := []struct {
+		name   string
+		search Search
+		want   bool
+	}{
+		{name: "zero search", search: Search{}, want: true},
+		{name: "within the date range", search: Search{From: date, To: date.Add(time.Hour)}, want: true},
+		{name: "before the date range", search: Search{From: date.Add(time.Second)}, want: false},
+		{name: "at the end of the date range", search: Search{To: date}, want: false},
+		{name: "location of another case", search: Search{Location: "athens"}, want: true},
+		{name: "other location", search: Search{Location: "Piraeus"}, want: false},
+		{name: "within the distance range", search: Search{MinDistanceKm: &five, MaxDistanceKm: &ten}, want: true},
+		{name: "below the distance range", search: Search{MaxDistanceKm: &five}, want: false},
+		{name: "within the elevation range", search: Search{MinElevationGain: &ten, MaxElevationGain: &hundred}, want: true},
+		{name: "above the elevation range", search: Search{MinElevationGain: &hundred}, want: false},
+		{name: "part of the name", search: Search{Text: "night"}, want: true},
+		{name: "not in the name", search: Search{Text: "marathon"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.search.Matches(r))
+		})
+	}
+}
+
+func TestSearch_Order(t *testing.T) {
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	early, _ := NewRace("b race", "Athens", date, 21.1, 0)
+	late, _ := NewRace("A race", "Athens", date.Add(time.Hour), 10, 0)
+
+	assert.True(t, Search{}.Less(CursorOf(early), CursorOf(late)), "races are ordered by date by default")
+	assert.True(t, Search{Descending: true}.Less(CursorOf(late), CursorOf(early)))
+	assert.True(t, Search{SortBy: SortByName}.Less(CursorOf(late), CursorOf(early)), "names are compared case-insensitively")
+	assert.True(t, Search{SortBy: SortByDistance}.Less(CursorOf(late), CursorOf(early)))
+
+	//the id breaks ties, in the direction of the search
+	twin, _ := NewRace("b race", "Athens", date, 21.1, 0)
+	byDate := Search{}
+	assert.NotEqual(t, byDate.Less(CursorOf(early), CursorOf(twin)), byDate.Less(CursorOf(twin), CursorOf(early)))
+
+	cursor := CursorOf(early)
+	after := Search{After: &cursor}
+	assert.False(t, after.IsAfterCursor(early), "the race at the cursor belongs to the previous page")
+	assert.True(t, after.IsAfterCursor(late))
+	assert.True(t, Search{}.IsAfterCursor(early))
+}
@@ -0,0 +1,160 @@
+package race
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// distanceToleranceKm absorbs floating point noise when split markers are compared with the race distance
+const distanceToleranceKm = 0.001
+
+var (
+	ErrInvalidSplitDistance  = errors.New("split distanceKm must be greater than 0")
+	ErrInvalidSplitElapsed   = errors.New("split elapsed time must be greater than 0")
+	ErrSplitsNotIncreasing   = errors.New("split distances and elapsed times must be strictly increasing")
+	ErrSplitBeyondRace       = errors.New("split distanceKm cannot exceed the race distanceKm")
+	ErrSplitBeyondFinishTime = errors.New("split elapsed time cannot exceed the finishTime")
+	ErrFinishSplitMismatch   = errors.New("split at the race distance must match the finishTime")
+	ErrNoSplits              = errors.New("result has no splits")
+	ErrInvalidRaceDistanceKm = errors.New("race distanceKm must be greater than 0")
+)
+
+// Split represents the cumulative time of a result at a distance marker
+type Split struct {
+	distanceKm float64
+	elapsed    time.Duration
+}
+
+// NewSplit creates a new Split and validates the input
+func NewSplit(distanceKm float64, elapsed time.Duration) (Split, error) {
+	if distanceKm <= 0 {
+		return Split{}, ErrInvalidSplitDistance
+	}
+	if elapsed <= 0 {
+		return Split{}, ErrInvalidSplitElapsed
+	}
+	return Split{distanceKm: distanceKm, elapsed: elapsed}, nil
+}
+
+// DistanceKm returns the distance marker of the split
+func (s Split) DistanceKm() float64 {
+	return s.distanceKm
+}
+
+// Elapsed returns the cumulative time at the distance marker
+func (s Split) Elapsed() time.Duration {
+	return s.elapsed
+}
+
+// validateSplits checks that the splits are ordered and consistent with the status and the finish time.
+// Results without a finish time can keep the splits recorded before the runner stopped.
+func validateSplits(splits []Split, status Status, finishTime time.Duration) error {
+	if status == StatusDidNotStart && len(splits) > 0 {
+		return ErrUnexpectedSplits
+	}
+	var previous Split
+	for _, s := range splits {
+		if s.distanceKm <= previous.distanceKm || s.elapsed <= previous.elapsed {
+			return ErrSplitsNotIncreasing
+		}
+		if finishTime > 0 && s.elapsed > finishTime {
+			return ErrSplitBeyondFinishTime
+		}
+		previous = s
+	}
+	return nil
+}
+
+// Segment represents the stretch of a race between two consecutive distance markers
+type Segment struct {
+	StartKm      float64
+	EndKm        float64
+	Duration     time.Duration
+	PaceMinPerKm float64
+}
+
+// SplitAnalysis contains the metrics derived from the splits of a result
+type SplitAnalysis struct {
+	Segments   []Segment
+	FirstHalf  time.Duration
+	SecondHalf time.Duration
+	// SplitPercent is the difference of the second half from the first half, negative for a negative split
+	SplitPercent float64
+	Fastest      Segment
+	Slowest      Segment
+	// PaceVariabilityPercent is the distance weighted coefficient of variation of the segment paces
+	PaceVariabilityPercent float64
+}
+
+// IsNegativeSplit reports whether the second half was faster than the first one
+func (a SplitAnalysis) IsNegativeSplit() bool {
+	return a.SplitPercent < 0
+}
+
+// analyseSplits derives the split metrics of a race of raceDistanceKm finished in finishTime.
+// The stretch between the last split and the finish line is treated as a segment.
+func analyseSplits(splits []Split, finishTime time.Duration, raceDistanceKm float64) (SplitAnalysis, error) {
+	if len(splits) == 0 {
+		return SplitAnalysis{}, ErrNoSplits
+	}
+	if raceDistanceKm <= 0 {
+		return SplitAnalysis{}, ErrInvalidRaceDistanceKm
+	}
+
+	points := make([]Split, 0, len(splits)+2)
+	points = append(points, Split{})
+	points = append(points, splits...)
+	if last := splits[len(splits)-1]; raceDistanceKm-last.distanceKm > distanceToleranceKm {
+		points = append(points, Split{distanceKm: raceDistanceKm, elapsed: finishTime})
+	}
+
+	segments := make([]Segment, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		distance := points[i].distanceKm - points[i-1].distanceKm
+		duration := points[i].elapsed - points[i-1].elapsed
+		segments = append(segments, Segment{
+			StartKm:      points[i-1].distanceKm,
+			EndKm:        points[i].distanceKm,
+			Duration:     duration,
+			PaceMinPerKm: duration.Minutes() / distance,
+		})
+	}
+
+	analysis := SplitAnalysis{Segments: segments, Fastest: segments[0], Slowest: segments[0]}
+	for _, s := range segments[1:] {
+		if s.PaceMinPerKm < analysis.Fastest.PaceMinPerKm {
+			analysis.Fastest = s
+		}
+		if s.PaceMinPerKm > analysis.Slowest.PaceMinPerKm {
+			analysis.Slowest = s
+		}
+	}
+
+	total := points[len(points)-1]
+	analysis.FirstHalf = elapsedAt(points, total.distanceKm/2)
+	analysis.SecondHalf = total.elapsed - analysis.FirstHalf
+	analysis.SplitPercent = (analysis.SecondHalf.Seconds() - analysis.FirstHalf.Seconds()) / analysis.FirstHalf.Seconds() * 100
+
+	meanPace := total.elapsed.Minutes() / total.distanceKm
+	var variance float64
+	for _, s := range segments {
+		variance += (s.EndKm - s.StartKm) * math.Pow(s.PaceMinPerKm-meanPace, 2)
+	}
+	variance /= total.distanceKm
+	analysis.PaceVariabilityPercent = math.Sqrt(variance) / meanPace * 100
+
+	return analysis, nil
+}
+
+// elapsedAt interpolates the cumulative time at distanceKm, assuming an even pace between the points
+func elapsedAt(points []Split, distanceKm float64) time.Duration {
+	for i := 1; i < len(points); i++ {
+		if points[i].distanceKm >= distanceKm {
+			previous := points[i-1]
+			ratio := (distanceKm - previous.distanceKm) / (points[i].distanceKm - previous.distanceKm)
+			return previous.elapsed + time.Duration(ratio*float64(points[i].elapsed-previous.elapsed))
+		}
+	}
+	return points[len(points)-1].elapsed
+}
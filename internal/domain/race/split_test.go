@@ -0,0 +1,128 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustSplits(t *testing.T, minutesPerMarker ...float64) []Split {
+	t.Helper()
+	splits := make([]Split, len(minutesPerMarker))
+	for i, minutes := range minutesPerMarker {
+		s, err := NewSplit(float64(i+1), time.Duration(minutes*float64(time.Minute)))
+		require.NoError(t, err)
+		splits[i] = s
+	}
+	return splits
+}
+
+func TestNewSplit(t *testing.T) {
+	_, err := NewSplit(0, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidSplitDistance)
+
+	_, err = NewSplit(1, 0)
+	assert.ErrorIs(t, err, ErrInvalidSplitElapsed)
+
+	s, err := NewSplit(1, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, s.DistanceKm())
+	assert.Equal(t, 5*time.Minute, s.Elapsed())
+}
+
+func TestResult_WithSplits(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), 25*time.Minute, 5, 150, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		splits  []Split
+		wantErr error
+	}{
+		{
+			name:   "valid splits without the finish marker",
+			splits: mustSplits(t, 5, 10, 15, 20),
+		},
+		{
+			name:   "valid splits with the finish marker",
+			splits: mustSplits(t, 5, 10, 15, 20, 25),
+		},
+		{
+			name:    "decreasing elapsed time",
+			splits:  mustSplits(t, 5, 4),
+			wantErr: ErrSplitsNotIncreasing,
+		},
+		{
+			name:    "repeated distance marker",
+			splits:  []Split{{distanceKm: 1, elapsed: time.Minute}, {distanceKm: 1, elapsed: 2 * time.Minute}},
+			wantErr: ErrSplitsNotIncreasing,
+		},
+		{
+			name:    "split after the finish time",
+			splits:  mustSplits(t, 5, 26),
+			wantErr: ErrSplitBeyondFinishTime,
+		},
+		{
+			name:    "split beyond the race distance",
+			splits:  mustSplits(t, 5, 10, 15, 20, 24, 24.5),
+			wantErr: ErrSplitBeyondRace,
+		},
+		{
+			name:    "finish marker not matching the finish time",
+			splits:  mustSplits(t, 5, 10, 15, 20, 24),
+			wantErr: ErrFinishSplitMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := result.WithSplits(tt.splits, 5)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.splits, got.Splits())
+				assert.Empty(t, result.Splits())
+			}
+		})
+	}
+}
+
+func TestResult_SplitAnalysis(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), 50*time.Minute, 5, 150, "")
+	require.NoError(t, err)
+
+	t.Run("negative split with implied finishing segment", func(t *testing.T) {
+		// 10 km: five 5:30 kilometres followed by 4:30 kilometres, the last one implied by the finish time
+		withSplits, err := result.WithSplits(mustSplits(t, 5.5, 11, 16.5, 22, 27.5, 32, 36.5, 41, 45.5), 10)
+		require.NoError(t, err)
+
+		analysis, err := withSplits.SplitAnalysis(10)
+		require.NoError(t, err)
+		assert.Len(t, analysis.Segments, 10)
+		assert.Equal(t, 27*time.Minute+30*time.Second, analysis.FirstHalf)
+		assert.Equal(t, 22*time.Minute+30*time.Second, analysis.SecondHalf)
+		assert.InDelta(t, -18.18, analysis.SplitPercent, 0.01)
+		assert.True(t, analysis.IsNegativeSplit())
+		assert.Equal(t, Segment{StartKm: 5, EndKm: 6, Duration: 4*time.Minute + 30*time.Second, PaceMinPerKm: 4.5}, analysis.Fastest)
+		assert.Equal(t, Segment{StartKm: 0, EndKm: 1, Duration: 5*time.Minute + 30*time.Second, PaceMinPerKm: 5.5}, analysis.Slowest)
+		assert.InDelta(t, 10, analysis.PaceVariabilityPercent, 0.01)
+	})
+
+	t.Run("even pace", func(t *testing.T) {
+		withSplits, err := result.WithSplits(mustSplits(t, 5, 10, 15, 20, 25, 30, 35, 40, 45, 50), 10)
+		require.NoError(t, err)
+
+		analysis, err := withSplits.SplitAnalysis(10)
+		require.NoError(t, err)
+		assert.Len(t, analysis.Segments, 10)
+		assert.InDelta(t, 0, analysis.SplitPercent, 0.0001)
+		assert.False(t, analysis.IsNegativeSplit())
+		assert.InDelta(t, 0, analysis.PaceVariabilityPercent, 0.0001)
+	})
+
+	t.Run("no splits", func(t *testing.T) {
+		_, err := result.SplitAnalysis(10)
+		assert.ErrorIs(t, err, ErrNoSplits)
+	})
+}
@@ -0,0 +1,166 @@
+package race
+
+import (
+	"errors"
+	"time"
+)
+
+// Status of a result, describing whether the runner started and finished the race
+type Status string
+
+// Supported result statuses. Only finished results are ranked.
+const (
+	StatusFinished     Status = "finished"
+	StatusDidNotFinish Status = "dnf"
+	StatusDidNotStart  Status = "dns"
+	StatusDisqualified Status = "dsq"
+)
+
+// Publication state of a result. Results are provisional until published as official;
+// an official result can then be amended.
+type Publication string
+
+// Supported publication states
+const (
+	PublicationProvisional Publication = "provisional"
+	PublicationOfficial    Publication = "official"
+	PublicationAmended     Publication = "amended"
+)
+
+var (
+	ErrInvalidStatus                = errors.New("status must be one of finished, dnf, dns or dsq")
+	ErrMissingStatusReason          = errors.New("disqualified results require a reason")
+	ErrUnexpectedFinishTime         = errors.New("only finished and disqualified results can have a finishTime")
+	ErrUnexpectedSplits             = errors.New("results of runners that did not start cannot have splits")
+	ErrNoFinishTime                 = errors.New("result has no finishTime")
+	ErrInvalidPublication           = errors.New("publication must be one of provisional, official or amended")
+	ErrInvalidPublicationTransition = errors.New("only provisional results can be published and only published results can be amended")
+	ErrMissingAmendmentReason       = errors.New("amendments require a reason")
+	ErrResultNotFound               = errors.New("result not found")
+)
+
+// ParseStatus Returns the Status of the provided value
+func ParseStatus(value string) (Status, error) {
+	switch s := Status(value); s {
+	case StatusFinished, StatusDidNotFinish, StatusDidNotStart, StatusDisqualified:
+		return s, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// ParsePublication Returns the Publication of the provided value
+func ParsePublication(value string) (Publication, error) {
+	switch p := Publication(value); p {
+	case PublicationProvisional, PublicationOfficial, PublicationAmended:
+		return p, nil
+	default:
+		return "", ErrInvalidPublication
+	}
+}
+
+// validateStatus checks the fields that each status requires.
+// Finished results need a finish time and a pace, disqualified results need a reason and keep the
+// finish time of the runner when there is one, and the remaining statuses have no finish time.
+func validateStatus(status Status, reason string, finishTime time.Duration, paceMinPerKm float64) error {
+	if _, err := ParseStatus(string(status)); err != nil {
+		return err
+	}
+
+	switch status {
+	case StatusFinished:
+		if finishTime <= 0 {
+			return ErrInvalidFinishTime
+		}
+		if paceMinPerKm <= 0 {
+			return ErrInvalidPace
+		}
+	case StatusDisqualified:
+		if reason == "" {
+			return ErrMissingStatusReason
+		}
+		if finishTime < 0 {
+			return ErrInvalidFinishTime
+		}
+		if finishTime > 0 && paceMinPerKm <= 0 {
+			return ErrInvalidPace
+		}
+	default:
+		if finishTime != 0 || paceMinPerKm != 0 {
+			return ErrUnexpectedFinishTime
+		}
+	}
+	return nil
+}
+
+// Publish returns a copy of the provisional result published as official, with an optional reason
+func (r Result) Publish(reason string) (Result, error) {
+	if r.publication != PublicationProvisional {
+		return Result{}, ErrInvalidPublicationTransition
+	}
+	r.publication = PublicationOfficial
+	r.publicationReason = reason
+	return r, nil
+}
+
+// Amend returns a copy of the published result with the corrected status and net finish time.
+// The reason of the amendment is required. The gun time keeps the delay of the runner in crossing the start line,
+// and amending a result to did not start drops its splits.
+func (r Result) Amend(status Status, statusReason string, finishTime time.Duration, paceMinPerKm float64, reason string) (Result, error) {
+	if r.publication == PublicationProvisional {
+		return Result{}, ErrInvalidPublicationTransition
+	}
+	if reason == "" {
+		return Result{}, ErrMissingAmendmentReason
+	}
+	if err := validateStatus(status, statusReason, finishTime, paceMinPerKm); err != nil {
+		return Result{}, err
+	}
+	splits := r.splits
+	if status == StatusDidNotStart {
+		splits = nil
+	}
+	if err := validateSplits(splits, status, finishTime); err != nil {
+		return Result{}, err
+	}
+
+	startDelay := r.gunTime - r.finishTime
+	if finishTime == 0 {
+		startDelay = 0
+	}
+
+	r.status = status
+	r.statusReason = statusReason
+	r.finishTime = finishTime
+	r.gunTime = finishTime + startDelay
+	r.paceMinPerKm = paceMinPerKm
+	r.splits = append([]Split(nil), splits...)
+	r.publication = PublicationAmended
+	r.publicationReason = reason
+	return r, nil
+}
+
+// Status returns the status of the result
+func (r Result) Status() Status {
+	return r.status
+}
+
+// StatusReason returns the reason of the status, such as the cause of a disqualification
+func (r Result) StatusReason() string {
+	return r.statusReason
+}
+
+// Publication returns the publication state of the result
+func (r Result) Publication() Publication {
+	return r.publication
+}
+
+// PublicationReason returns the reason of the last publication change
+func (r Result) PublicationReason() string {
+	return r.publicationReason
+}
+
+// IsFinished reports whether the runner finished the race, which is required for rankings and records
+func (r Result) IsFinished() bool {
+	return r.status == StatusFinished
+}
@@ -0,0 +1,167 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResultWithStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       Status
+		statusReason string
+		finishTime   time.Duration
+		pace         float64
+		wantErr      error
+	}{
+		{name: "finished", status: StatusFinished, finishTime: time.Hour, pace: 6},
+		{name: "finished without finish time", status: StatusFinished, wantErr: ErrInvalidFinishTime},
+		{name: "did not finish", status: StatusDidNotFinish},
+		{name: "did not finish with finish time", status: StatusDidNotFinish, finishTime: time.Hour, pace: 6, wantErr: ErrUnexpectedFinishTime},
+		{name: "did not start", status: StatusDidNotStart, statusReason: "injured"},
+		{name: "did not start with pace", status: StatusDidNotStart, pace: 6, wantErr: ErrUnexpectedFinishTime},
+		{name: "disqualified with finish time", status: StatusDisqualified, statusReason: "course cutting", finishTime: time.Hour, pace: 6},
+		{name: "disqualified without finish time", status: StatusDisqualified, statusReason: "no bib"},
+		{name: "disqualified without reason", status: StatusDisqualified, finishTime: time.Hour, pace: 6, wantErr: ErrMissingStatusReason},
+		{name: "disqualified with finish time without pace", status: StatusDisqualified, statusReason: "course cutting", finishTime: time.Hour, wantErr: ErrInvalidPace},
+		{name: "unknown status", status: "retired", wantErr: ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResultWithStatus(uuid.New(), uuid.New(), tt.status, tt.statusReason, tt.finishTime, tt.pace, 0, "")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, got.Status())
+			assert.Equal(t, tt.statusReason, got.StatusReason())
+			assert.Equal(t, tt.finishTime, got.FinishTime())
+			assert.Equal(t, tt.status == StatusFinished, got.IsFinished())
+			assert.Equal(t, PublicationProvisional, got.Publication())
+		})
+	}
+}
+
+func TestResult_WithSplits_WithoutFinishTime(t *testing.T) {
+	dnf, err := NewResultWithStatus(uuid.New(), uuid.New(), StatusDidNotFinish, "", 0, 0, 0, "")
+	require.NoError(t, err)
+
+	_, err = dnf.WithSplits(mustSplits(t, 5, 10, 15), 2)
+	assert.ErrorIs(t, err, ErrSplitBeyondRace)
+	got, err := dnf.WithSplits(mustSplits(t, 5, 10), 10)
+	require.NoError(t, err)
+	assert.Len(t, got.Splits(), 2)
+	_, err = got.SplitAnalysis(10)
+	assert.ErrorIs(t, err, ErrNoFinishTime)
+
+	_, err = dnf.WithSplits(mustSplits(t, 5, 10), 2)
+	assert.ErrorIs(t, err, ErrFinishSplitMismatch)
+
+	dns, err := NewResultWithStatus(uuid.New(), uuid.New(), StatusDidNotStart, "", 0, 0, 0, "")
+	require.NoError(t, err)
+	_, err = dns.WithSplits(mustSplits(t, 5), 10)
+	assert.ErrorIs(t, err, ErrUnexpectedSplits)
+}
+
+func TestResult_Publish(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), time.Hour, 6, 150, "")
+	require.NoError(t, err)
+
+	published, err := result.Publish("results verified")
+	require.NoError(t, err)
+	assert.Equal(t, PublicationOfficial, published.Publication())
+	assert.Equal(t, "results verified", published.PublicationReason())
+	assert.Equal(t, PublicationProvisional, result.Publication())
+
+	_, err = published.Publish("")
+	assert.ErrorIs(t, err, ErrInvalidPublicationTransition)
+}
+
+func TestResult_Amend(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), 25*time.Minute, 5, 150, "")
+	require.NoError(t, err)
+	result, err = result.WithSplits(mustSplits(t, 5, 10), 5)
+	require.NoError(t, err)
+	result, err = result.WithGunTime(25*time.Minute + 30*time.Second)
+	require.NoError(t, err)
+
+	_, err = result.Amend(StatusDisqualified, "course cutting", 25*time.Minute, 5, "referee report")
+	assert.ErrorIs(t, err, ErrInvalidPublicationTransition)
+
+	official, err := result.Publish("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		status       Status
+		statusReason string
+		finishTime   time.Duration
+		pace         float64
+		reason       string
+		wantSplits   int
+		wantGunTime  time.Duration
+		wantErr      error
+	}{
+		{name: "disqualify", status: StatusDisqualified, statusReason: "course cutting", finishTime: 25 * time.Minute, pace: 5, reason: "referee report", wantSplits: 2, wantGunTime: 25*time.Minute + 30*time.Second},
+		{name: "correct the finish time", status: StatusFinished, finishTime: 26 * time.Minute, pace: 5.2, reason: "timing error", wantSplits: 2, wantGunTime: 26*time.Minute + 30*time.Second},
+		{name: "did not start drops the splits", status: StatusDidNotStart, reason: "wrong bib", wantSplits: 0},
+		{name: "finish time before the splits", status: StatusFinished, finishTime: 8 * time.Minute, pace: 1.6, reason: "timing error", wantErr: ErrSplitBeyondFinishTime},
+		{name: "missing reason", status: StatusDidNotFinish, wantErr: ErrMissingAmendmentReason},
+		{name: "invalid status", status: StatusDidNotFinish, finishTime: time.Hour, pace: 5, reason: "timing error", wantErr: ErrUnexpectedFinishTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := official.Amend(tt.status, tt.statusReason, tt.finishTime, tt.pace, tt.reason)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, got.Status())
+			assert.Equal(t, tt.statusReason, got.StatusReason())
+			assert.Equal(t, tt.finishTime, got.FinishTime())
+			assert.Equal(t, tt.wantGunTime, got.GunTime())
+			assert.Equal(t, PublicationAmended, got.Publication())
+			assert.Equal(t, tt.reason, got.PublicationReason())
+			assert.Len(t, got.Splits(), tt.wantSplits)
+
+			again, err := got.Amend(StatusFinished, "", 25*time.Minute, 5, "appeal upheld")
+			require.NoError(t, err)
+			assert.Equal(t, PublicationAmended, again.Publication())
+		})
+	}
+}
+
+func TestResult_WithGunTime(t *testing.T) {
+	result, err := NewResult(uuid.New(), uuid.New(), 40*time.Minute, 4, 150, "")
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Minute, result.GunTime(), "the gun time defaults to the net time")
+
+	withGun, err := result.WithGunTime(41 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 41*time.Minute, withGun.GunTime())
+	assert.Equal(t, 40*time.Minute, withGun.RankingTime(RankingNetTime))
+	assert.Equal(t, 41*time.Minute, withGun.RankingTime(RankingGunTime))
+
+	_, err = result.WithGunTime(39 * time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidGunTime)
+
+	dnf, err := NewResultWithStatus(uuid.New(), uuid.New(), StatusDidNotFinish, "", 0, 0, 0, "")
+	require.NoError(t, err)
+	_, err = dnf.WithGunTime(time.Hour)
+	assert.ErrorIs(t, err, ErrNoFinishTime)
+}
+
+func TestParseStatus(t *testing.T) {
+	got, err := ParseStatus("dsq")
+	require.NoError(t, err)
+	assert.Equal(t, StatusDisqualified, got)
+
+	_, err = ParseStatus("DNF")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
@@ -0,0 +1,79 @@
+package race
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RankingPolicy selects the time finished results of a race are ranked by
+type RankingPolicy string
+
+// Supported ranking policies. Gun time ranking follows the competition rules, where the official time
+// runs from the start signal of the wave; net time ranking uses the time from crossing the start line.
+const (
+	RankingGunTime RankingPolicy = "gun"
+	RankingNetTime RankingPolicy = "net"
+)
+
+var (
+	ErrInvalidRankingPolicy = errors.New("ranking policy must be one of gun or net")
+	ErrEmptyWaveName        = errors.New("wave name cannot be empty")
+	ErrMissingWaveStart     = errors.New("wave start time is required")
+	ErrDuplicateWave        = errors.New("wave names must be unique within the race")
+)
+
+// ParseRankingPolicy Returns the RankingPolicy of the provided value
+func ParseRankingPolicy(value string) (RankingPolicy, error) {
+	switch p := RankingPolicy(value); p {
+	case RankingGunTime, RankingNetTime:
+		return p, nil
+	default:
+		return "", ErrInvalidRankingPolicy
+	}
+}
+
+// Wave represents a group of runners released by the same start signal
+type Wave struct {
+	name      string
+	startTime time.Time
+}
+
+// NewWave creates a new Wave and validates the input
+func NewWave(name string, startTime time.Time) (Wave, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Wave{}, ErrEmptyWaveName
+	}
+	if startTime.IsZero() {
+		return Wave{}, ErrMissingWaveStart
+	}
+	return Wave{name: name, startTime: startTime.UTC()}, nil
+}
+
+// Name returns the name of the wave
+func (w Wave) Name() string {
+	return w.name
+}
+
+// StartTime returns the time of the start signal of the wave
+func (w Wave) StartTime() time.Time {
+	return w.startTime
+}
+
+// sortWaves returns a copy of the waves ordered by start time, or ErrDuplicateWave
+func sortWaves(waves []Wave) ([]Wave, error) {
+	seen := make(map[string]bool, len(waves))
+	for _, w := range waves {
+		if seen[w.name] {
+			return nil, ErrDuplicateWave
+		}
+		seen[w.name] = true
+	}
+	sorted := append([]Wave(nil), waves...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].startTime.Before(sorted[j].startTime)
+	})
+	return sorted, nil
+}
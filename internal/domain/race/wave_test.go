@@ -0,0 +1,79 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWave(t *testing.T) {
+	start := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		waveName  string
+		startTime time.Time
+		wantErr   error
+	}{
+		{name: "valid", waveName: "A", startTime: start},
+		{name: "empty name", waveName: " ", startTime: start, wantErr: ErrEmptyWaveName},
+		{name: "missing start", waveName: "A", wantErr: ErrMissingWaveStart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWave(tt.waveName, tt.startTime)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.waveName, w.Name())
+			assert.Equal(t, tt.startTime, w.StartTime())
+		})
+	}
+}
+
+func TestRace_WithWaves(t *testing.T) {
+	start := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	r, err := NewRace("City 10K", "Athens", start, 10, 50)
+	require.NoError(t, err)
+	assert.Empty(t, r.Waves())
+	assert.Equal(t, RankingGunTime, r.RankingPolicy())
+
+	a, _ := NewWave("A", start)
+	b, _ := NewWave("B", start.Add(10*time.Minute))
+	r, err = r.WithWaves([]Wave{b, a})
+	require.NoError(t, err)
+	assert.Equal(t, []Wave{a, b}, r.Waves(), "waves are ordered by start time")
+	got, ok := r.Wave("B")
+	assert.True(t, ok)
+	assert.Equal(t, b, got)
+	_, ok = r.Wave("C")
+	assert.False(t, ok)
+
+	_, err = r.WithWaves([]Wave{a, a})
+	assert.ErrorIs(t, err, ErrDuplicateWave)
+}
+
+func TestRace_WithRankingPolicy(t *testing.T) {
+	r, err := NewRace("City 10K", "Athens", time.Now(), 10, 50)
+	require.NoError(t, err)
+
+	r, err = r.WithRankingPolicy(RankingNetTime)
+	require.NoError(t, err)
+	assert.Equal(t, RankingNetTime, r.RankingPolicy())
+
+	_, err = r.WithRankingPolicy("chip")
+	assert.ErrorIs(t, err, ErrInvalidRankingPolicy)
+}
+
+func TestParseRankingPolicy(t *testing.T) {
+	for _, value := range []string{"gun", "net"} {
+		p, err := ParseRankingPolicy(value)
+		assert.NoError(t, err)
+		assert.Equal(t, RankingPolicy(value), p)
+	}
+	_, err := ParseRankingPolicy("")
+	assert.ErrorIs(t, err, ErrInvalidRankingPolicy)
+}
@@ -0,0 +1,255 @@
+package registration
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCapacity        = errors.New("capacity must be greater than zero")
+	ErrInvalidWindow          = errors.New("registration must close after it opens")
+	ErrCapacityBelowConfirmed = errors.New("capacity cannot be lower than the number of confirmed registrations")
+	ErrRegistrationClosed     = errors.New("registration is not open")
+	ErrAlreadyRegistered      = errors.New("runner is already registered for the race")
+	ErrNotRegistered          = errors.New("runner is not registered for the race")
+)
+
+// EntryList is the aggregate of the registrations of a race.
+// It enforces the capacity of the race and the registration window, and keeps a first in, first out waitlist
+// of the runners that registered after the race was full.
+type EntryList struct {
+	raceID             uuid.UUID
+	capacity           int
+	opensAt            time.Time
+	closesAt           time.Time
+	requiredForResults bool
+	registrations      []Registration
+	version            int
+}
+
+// NewEntryList creates the entry list of a race and validates the input.
+// requiredForResults makes a confirmed registration a requirement for logging a result of the race.
+func NewEntryList(raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool) (*EntryList, error) {
+	if raceID == uuid.Nil {
+		return nil, ErrEmptyRaceID
+	}
+	if err := validateSettings(capacity, opensAt, closesAt); err != nil {
+		return nil, err
+	}
+
+	return &EntryList{
+		raceID:             raceID,
+		capacity:           capacity,
+		opensAt:            opensAt,
+		closesAt:           closesAt,
+		requiredForResults: requiredForResults,
+	}, nil
+}
+
+// LoadEntryList recreates an existing EntryList from stored data and validates it.
+// version is the version of the stored data, which is used to detect concurrent updates.
+func LoadEntryList(raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool, registrations []Registration, version int) (*EntryList, error) {
+	l, err := NewEntryList(raceID, capacity, opensAt, closesAt, requiredForResults)
+	if err != nil {
+		return nil, err
+	}
+	l.registrations = append([]Registration(nil), registrations...)
+	sort.SliceStable(l.registrations, func(i, j int) bool {
+		return l.registrations[i].registeredAt.Before(l.registrations[j].registeredAt)
+	})
+	l.version = version
+
+	return l, nil
+}
+
+func validateSettings(capacity int, opensAt, closesAt time.Time) error {
+	if capacity <= 0 {
+		return ErrInvalidCapacity
+	}
+	if !closesAt.After(opensAt) {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Configure changes the capacity and the registration window.
+// The capacity cannot drop below the confirmed registrations; when it grows, waitlisted runners are confirmed
+// in registration order and returned.
+func (l *EntryList) Configure(capacity int, opensAt, closesAt time.Time, requiredForResults bool, at time.Time) ([]Registration, error) {
+	if err := validateSettings(capacity, opensAt, closesAt); err != nil {
+		return nil, err
+	}
+	if capacity < len(l.Confirmed()) {
+		return nil, ErrCapacityBelowConfirmed
+	}
+
+	l.capacity = capacity
+	l.opensAt = opensAt
+	l.closesAt = closesAt
+	l.requiredForResults = requiredForResults
+	return l.promote(at), nil
+}
+
+// Register registers the runner at the provided time. The registration is confirmed while there are places
+// left and waitlisted otherwise. A runner that cancelled can register again at the end of the list.
+func (l *EntryList) Register(runnerID uuid.UUID, at time.Time) (Registration, error) {
+	if runnerID == uuid.Nil {
+		return Registration{}, ErrEmptyRunnerID
+	}
+	if !l.IsOpen(at) {
+		return Registration{}, ErrRegistrationClosed
+	}
+	if _, registered := l.Registration(runnerID); registered {
+		return Registration{}, ErrAlreadyRegistered
+	}
+
+	status := StatusConfirmed
+	if len(l.Confirmed()) >= l.capacity {
+		status = StatusWaitlisted
+	}
+	r := Registration{
+		id:           uuid.New(),
+		raceID:       l.raceID,
+		runnerID:     runnerID,
+		status:       status,
+		registeredAt: at,
+		updatedAt:    at,
+	}
+	l.registrations = append(l.registrations, r)
+	return r, nil
+}
+
+// Cancel cancels the registration of the runner. When a confirmed registration is cancelled, the first
+// waitlisted runner takes its place and its registration is returned.
+func (l *EntryList) Cancel(runnerID uuid.UUID, at time.Time) (Registration, *Registration, error) {
+	i := l.indexOf(runnerID)
+	if i < 0 {
+		return Registration{}, nil, ErrNotRegistered
+	}
+
+	l.registrations[i].status = StatusCancelled
+	l.registrations[i].updatedAt = at
+	cancelled := l.registrations[i]
+
+	promoted := l.promote(at)
+	if len(promoted) == 0 {
+		return cancelled, nil, nil
+	}
+	return cancelled, &promoted[0], nil
+}
+
+// promote confirms waitlisted registrations in registration order while there are places left
+func (l *EntryList) promote(at time.Time) []Registration {
+	var promoted []Registration
+	confirmed := len(l.Confirmed())
+	for i := range l.registrations {
+		if confirmed >= l.capacity {
+			break
+		}
+		if l.registrations[i].status == StatusWaitlisted {
+			l.registrations[i].status = StatusConfirmed
+			l.registrations[i].updatedAt = at
+			promoted = append(promoted, l.registrations[i])
+			confirmed++
+		}
+	}
+	return promoted
+}
+
+// indexOf returns the index of the active registration of the runner, or -1 when there is none
+func (l *EntryList) indexOf(runnerID uuid.UUID) int {
+	for i, r := range l.registrations {
+		if r.runnerID == runnerID && r.IsActive() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Registration returns the active registration of the runner
+func (l *EntryList) Registration(runnerID uuid.UUID) (Registration, bool) {
+	i := l.indexOf(runnerID)
+	if i < 0 {
+		return Registration{}, false
+	}
+	return l.registrations[i], true
+}
+
+// IsConfirmed reports whether the runner has a confirmed registration
+func (l *EntryList) IsConfirmed(runnerID uuid.UUID) bool {
+	r, registered := l.Registration(runnerID)
+	return registered && r.status == StatusConfirmed
+}
+
+// WaitlistPosition returns the 1-based position of the runner in the waitlist, or 0 when the runner is not waitlisted
+func (l *EntryList) WaitlistPosition(runnerID uuid.UUID) int {
+	for i, r := range l.Waitlist() {
+		if r.runnerID == runnerID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// IsOpen reports whether runners can register at the provided time
+func (l *EntryList) IsOpen(at time.Time) bool {
+	return !at.Before(l.opensAt) && at.Before(l.closesAt)
+}
+
+// Confirmed returns the confirmed registrations in registration order
+func (l *EntryList) Confirmed() []Registration {
+	return l.withStatus(StatusConfirmed)
+}
+
+// Waitlist returns the waitlisted registrations in waitlist order
+func (l *EntryList) Waitlist() []Registration {
+	return l.withStatus(StatusWaitlisted)
+}
+
+func (l *EntryList) withStatus(status Status) []Registration {
+	var registrations []Registration
+	for _, r := range l.registrations {
+		if r.status == status {
+			registrations = append(registrations, r)
+		}
+	}
+	return registrations
+}
+
+// Registrations returns every registration of the race, including the cancelled ones, in registration order
+func (l *EntryList) Registrations() []Registration {
+	return append([]Registration(nil), l.registrations...)
+}
+
+// RaceID returns the ID of the race
+func (l *EntryList) RaceID() uuid.UUID {
+	return l.raceID
+}
+
+// Capacity returns the maximum number of confirmed registrations
+func (l *EntryList) Capacity() int {
+	return l.capacity
+}
+
+// OpensAt returns the time registration opens
+func (l *EntryList) OpensAt() time.Time {
+	return l.opensAt
+}
+
+// ClosesAt returns the time registration closes
+func (l *EntryList) ClosesAt() time.Time {
+	return l.closesAt
+}
+
+// RequiredForResults reports whether results of the race can only be logged for confirmed runners
+func (l *EntryList) RequiredForResults() bool {
+	return l.requiredForResults
+}
+
+// Version returns the version of the stored data the entry list was loaded from, 0 for a new entry list
+func (l *EntryList) Version() int {
+	return l.version
+}
@@ -0,0 +1,164 @@
+package registration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	opensAt  = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closesAt = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestNewEntryList(t *testing.T) {
+	tests := []struct {
+		name     string
+		raceID   uuid.UUID
+		capacity int
+		opensAt  time.Time
+		closesAt time.Time
+		wantErr  error
+	}{
+		{name: "valid", raceID: uuid.New(), capacity: 100, opensAt: opensAt, closesAt: closesAt},
+		{name: "empty race id", raceID: uuid.Nil, capacity: 100, opensAt: opensAt, closesAt: closesAt, wantErr: ErrEmptyRaceID},
+		{name: "zero capacity", raceID: uuid.New(), capacity: 0, opensAt: opensAt, closesAt: closesAt, wantErr: ErrInvalidCapacity},
+		{name: "closes before it opens", raceID: uuid.New(), capacity: 100, opensAt: closesAt, closesAt: opensAt, wantErr: ErrInvalidWindow},
+		{name: "closes when it opens", raceID: uuid.New(), capacity: 100, opensAt: opensAt, closesAt: opensAt, wantErr: ErrInvalidWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEntryList(tt.raceID, tt.capacity, tt.opensAt, tt.closesAt, true)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.capacity, got.Capacity())
+			assert.True(t, got.RequiredForResults())
+			assert.Equal(t, 0, got.Version())
+		})
+	}
+}
+
+func TestEntryList_Register(t *testing.T) {
+	l, err := NewEntryList(uuid.New(), 2, opensAt, closesAt, false)
+	require.NoError(t, err)
+	at := opensAt.Add(time.Hour)
+	anna, bob, carl, dana := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	first, err := l.Register(anna, at)
+	require.NoError(t, err)
+	assert.Equal(t, StatusConfirmed, first.Status())
+	assert.Equal(t, l.RaceID(), first.RaceID())
+	_, err = l.Register(bob, at.Add(time.Minute))
+	require.NoError(t, err)
+	third, err := l.Register(carl, at.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, StatusWaitlisted, third.Status())
+	_, err = l.Register(dana, at.Add(3*time.Minute))
+	require.NoError(t, err)
+
+	assert.True(t, l.IsConfirmed(anna))
+	assert.False(t, l.IsConfirmed(carl))
+	assert.Equal(t, 1, l.WaitlistPosition(carl))
+	assert.Equal(t, 2, l.WaitlistPosition(dana))
+	assert.Equal(t, 0, l.WaitlistPosition(anna))
+
+	_, err = l.Register(anna, at)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+	_, err = l.Register(carl, at)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+	_, err = l.Register(uuid.Nil, at)
+	assert.ErrorIs(t, err, ErrEmptyRunnerID)
+	_, err = l.Register(uuid.New(), opensAt.Add(-time.Second))
+	assert.ErrorIs(t, err, ErrRegistrationClosed)
+	_, err = l.Register(uuid.New(), closesAt)
+	assert.ErrorIs(t, err, ErrRegistrationClosed)
+}
+
+func TestEntryList_Cancel(t *testing.T) {
+	l, err := NewEntryList(uuid.New(), 1, opensAt, closesAt, false)
+	require.NoError(t, err)
+	at := opensAt.Add(time.Hour)
+	anna, bob, carl := uuid.New(), uuid.New(), uuid.New()
+	for i, runnerID := range []uuid.UUID{anna, bob, carl} {
+		_, err := l.Register(runnerID, at.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+	}
+
+	t.Run("cancelling a waitlisted registration promotes nobody", func(t *testing.T) {
+		cancelled, promoted, err := l.Cancel(carl, at.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, StatusCancelled, cancelled.Status())
+		assert.Equal(t, carl, cancelled.RunnerID())
+		assert.Nil(t, promoted)
+	})
+
+	t.Run("cancelling a confirmed registration promotes the first waitlisted runner", func(t *testing.T) {
+		cancelled, promoted, err := l.Cancel(anna, closesAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, anna, cancelled.RunnerID())
+		require.NotNil(t, promoted)
+		assert.Equal(t, bob, promoted.RunnerID())
+		assert.Equal(t, StatusConfirmed, promoted.Status())
+		assert.Equal(t, closesAt.Add(time.Hour), promoted.UpdatedAt())
+		assert.True(t, l.IsConfirmed(bob))
+		assert.Empty(t, l.Waitlist())
+	})
+
+	t.Run("cancelling twice", func(t *testing.T) {
+		_, _, err := l.Cancel(anna, at)
+		assert.ErrorIs(t, err, ErrNotRegistered)
+	})
+
+	t.Run("registering again after cancelling", func(t *testing.T) {
+		again, err := l.Register(carl, at.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, StatusWaitlisted, again.Status())
+		assert.Len(t, l.Registrations(), 4)
+	})
+}
+
+func TestEntryList_Configure(t *testing.T) {
+	l, err := NewEntryList(uuid.New(), 1, opensAt, closesAt, false)
+	require.NoError(t, err)
+	at := opensAt.Add(time.Hour)
+	anna, bob, carl := uuid.New(), uuid.New(), uuid.New()
+	for i, runnerID := range []uuid.UUID{anna, bob, carl} {
+		_, err := l.Register(runnerID, at.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+	}
+
+	promoted, err := l.Configure(2, opensAt, closesAt, true, at.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, promoted, 1)
+	assert.Equal(t, bob, promoted[0].RunnerID())
+	assert.Equal(t, 1, l.WaitlistPosition(carl))
+	assert.True(t, l.RequiredForResults())
+
+	_, err = l.Configure(1, opensAt, closesAt, true, at)
+	assert.ErrorIs(t, err, ErrCapacityBelowConfirmed)
+	_, err = l.Configure(5, closesAt, opensAt, true, at)
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+	assert.Equal(t, 2, l.Capacity())
+}
+
+func TestLoadEntryList(t *testing.T) {
+	raceID := uuid.New()
+	later, err := LoadRegistration(uuid.New(), raceID, uuid.New(), StatusWaitlisted, opensAt.Add(time.Hour), opensAt.Add(time.Hour))
+	require.NoError(t, err)
+	earlier, err := LoadRegistration(uuid.New(), raceID, uuid.New(), StatusWaitlisted, opensAt, opensAt)
+	require.NoError(t, err)
+
+	l, err := LoadEntryList(raceID, 1, opensAt, closesAt, false, []Registration{later, earlier}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []Registration{earlier, later}, l.Waitlist())
+	assert.Equal(t, 3, l.Version())
+
+	_, err = LoadRegistration(uuid.New(), raceID, uuid.New(), "pending", opensAt, opensAt)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
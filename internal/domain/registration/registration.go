@@ -0,0 +1,95 @@
+// Package registration contains the domain entities for registering runners to races
+package registration
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of a registration
+type Status string
+
+// Supported registration statuses
+const (
+	StatusConfirmed  Status = "confirmed"
+	StatusWaitlisted Status = "waitlisted"
+	StatusCancelled  Status = "cancelled"
+)
+
+var (
+	ErrEmptyRaceID   = errors.New("race ID cannot be empty")
+	ErrEmptyRunnerID = errors.New("runner ID cannot be empty")
+	ErrInvalidStatus = errors.New("status must be one of confirmed, waitlisted or cancelled")
+)
+
+// Registration represents the entry of a runner to a race.
+// A registration is confirmed while the race has places left and waitlisted otherwise.
+type Registration struct {
+	id           uuid.UUID
+	raceID       uuid.UUID
+	runnerID     uuid.UUID
+	status       Status
+	registeredAt time.Time
+	updatedAt    time.Time
+}
+
+// LoadRegistration recreates an existing Registration entity from stored data and validates it
+func LoadRegistration(id, raceID, runnerID uuid.UUID, status Status, registeredAt, updatedAt time.Time) (Registration, error) {
+	if raceID == uuid.Nil {
+		return Registration{}, ErrEmptyRaceID
+	}
+	if runnerID == uuid.Nil {
+		return Registration{}, ErrEmptyRunnerID
+	}
+	switch status {
+	case StatusConfirmed, StatusWaitlisted, StatusCancelled:
+	default:
+		return Registration{}, ErrInvalidStatus
+	}
+
+	return Registration{
+		id:           id,
+		raceID:       raceID,
+		runnerID:     runnerID,
+		status:       status,
+		registeredAt: registeredAt,
+		updatedAt:    updatedAt,
+	}, nil
+}
+
+// ID returns the registration ID
+func (r Registration) ID() uuid.UUID {
+	return r.id
+}
+
+// RaceID returns the ID of the race
+func (r Registration) RaceID() uuid.UUID {
+	return r.raceID
+}
+
+// RunnerID returns the ID of the registered runner
+func (r Registration) RunnerID() uuid.UUID {
+	return r.runnerID
+}
+
+// Status returns the status of the registration
+func (r Registration) Status() Status {
+	return r.status
+}
+
+// RegisteredAt returns the time the runner registered, which orders the waitlist
+func (r Registration) RegisteredAt() time.Time {
+	return r.registeredAt
+}
+
+// UpdatedAt returns the time of the last status change
+func (r Registration) UpdatedAt() time.Time {
+	return r.updatedAt
+}
+
+// IsActive reports whether the registration is confirmed or waitlisted
+func (r Registration) IsActive() bool {
+	return r.status != StatusCancelled
+}
@@ -0,0 +1,24 @@
+package registration
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotConfigured Error when registration is not configured for the race
+	ErrNotConfigured = errors.New("registration is not configured for the race")
+	// ErrConcurrentUpdate Error when the entry list was changed since it was loaded
+	ErrConcurrentUpdate = errors.New("registrations of the race were changed by another request")
+)
+
+// Repository defines the storage interface for the entry lists of races
+type Repository interface {
+	// GetEntryList returns the entry list of the race, or ErrNotConfigured
+	GetEntryList(ctx context.Context, raceID uuid.UUID) (*EntryList, error)
+	// SaveEntryList stores the entry list, returning ErrConcurrentUpdate when the stored version
+	// no longer matches the version the entry list was loaded from
+	SaveEntryList(ctx context.Context, entryList *EntryList) error
+}
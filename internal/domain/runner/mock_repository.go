@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+// GetByID returns the mocked runner of the provided id
+func (m *MockRepository) GetByID(_ context.Context, id uuid.UUID) (*Runner, error) {
+	args := m.Called(id)
+	r, _ := args.Get(0).(*Runner)
+	return r, args.Error(1)
+}
+
+// GetByIDs returns the mocked runners of the provided ids
+func (m *MockRepository) GetByIDs(_ context.Context, ids []uuid.UUID) ([]*Runner, error) {
+	args := m.Called(ids)
+	runners, _ := args.Get(0).([]*Runner)
+	return runners, args.Error(1)
+}
+
+// GetAll returns the mocked runners
+func (m *MockRepository) GetAll(context.Context) ([]*Runner, error) {
+	args := m.Called()
+	runners, _ := args.Get(0).([]*Runner)
+	return runners, args.Error(1)
+}
+
+// Add records the provided runner
+func (m *MockRepository) Add(_ context.Context, r *Runner) error {
+	return m.Called(r).Error(0)
+}
+
+// Update records the provided runner
+func (m *MockRepository) Update(_ context.Context, r *Runner) error {
+	return m.Called(r).Error(0)
+}
+
+// Delete records the provided id
+func (m *MockRepository) Delete(_ context.Context, id uuid.UUID) error {
+	return m.Called(id).Error(0)
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"errors"
+	"time"
+)
+
+// Gender of a runner, used for the gender categories of race leaderboards
+type Gender string
+
+// Supported genders. GenderUnspecified excludes the runner from gender rankings.
+const (
+	GenderUnspecified Gender = ""
+	GenderFemale      Gender = "female"
+	GenderMale        Gender = "male"
+	GenderNonBinary   Gender = "non_binary"
+)
+
+var (
+	// ErrInvalidGender Error when the gender is not one of the supported values
+	ErrInvalidGender = errors.New("gender must be one of female, male, non_binary or empty")
+	// ErrInvalidDateOfBirth Error when the date of birth is in the future
+	ErrInvalidDateOfBirth = errors.New("date of birth cannot be in the future")
+)
+
+// ParseGender Returns the Gender of the provided value
+func ParseGender(value string) (Gender, error) {
+	switch g := Gender(value); g {
+	case GenderUnspecified, GenderFemale, GenderMale, GenderNonBinary:
+		return g, nil
+	default:
+		return GenderUnspecified, ErrInvalidGender
+	}
+}
+
+func validateProfile(gender Gender, dateOfBirth time.Time) error {
+	if _, err := ParseGender(string(gender)); err != nil {
+		return err
+	}
+	if dateOfBirth.After(time.Now()) {
+		return ErrInvalidDateOfBirth
+	}
+	return nil
+}
@@ -1,10 +1,22 @@
 package runner
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound Error when no runner exists with the provided id
+var ErrNotFound = errors.New("runner not found")
 
 // Repository Interface for runners
 type Repository interface {
-	GetByID(id uuid.UUID) (*Runner, error)
-	Add(runner *Runner) error
-	Update(runner *Runner) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Runner, error)
+	// GetByIDs returns the runners with the provided ids in any order, skipping the ids of unknown runners
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*Runner, error)
+	GetAll(ctx context.Context) ([]*Runner, error)
+	Add(ctx context.Context, runner *Runner) error
+	Update(ctx context.Context, runner *Runner) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -18,6 +18,8 @@ type Runner struct {
 	id           uuid.UUID
 	name         string
 	emailAddress emailAddress
+	gender       Gender
+	dateOfBirth  time.Time
 	createdAt    time.Time
 }
 
@@ -47,7 +49,7 @@ func NewRunner(name, emailAddress string) (*Runner, error) {
 }
 
 // LoadRunner Loads an existing Runner
-func LoadRunner(id uuid.UUID, name, emailAddress string, createdAt time.Time) (*Runner, error) {
+func LoadRunner(id uuid.UUID, name, emailAddress string, gender Gender, dateOfBirth, createdAt time.Time) (*Runner, error) {
 
 	//validate the name
 	if name == "" {
@@ -60,10 +62,17 @@ func LoadRunner(id uuid.UUID, name, emailAddress string, createdAt time.Time) (*
 		return nil, err
 	}
 
+	//validate the profile
+	if err := validateProfile(gender, dateOfBirth); err != nil {
+		return nil, err
+	}
+
 	return &Runner{
 		id:           id,
 		name:         name,
 		emailAddress: email,
+		gender:       gender,
+		dateOfBirth:  dateOfBirth,
 		createdAt:    createdAt,
 	}, nil
 }
@@ -77,6 +86,17 @@ func (r *Runner) Rename(name string) error {
 	return nil
 }
 
+// UpdateProfile Sets the gender and date of birth of the runner.
+// A zero date of birth means that it is unknown.
+func (r *Runner) UpdateProfile(gender Gender, dateOfBirth time.Time) error {
+	if err := validateProfile(gender, dateOfBirth); err != nil {
+		return err
+	}
+	r.gender = gender
+	r.dateOfBirth = dateOfBirth
+	return nil
+}
+
 // ID Returns the ID of the runner
 func (r *Runner) ID() uuid.UUID {
 	return r.id
@@ -92,7 +112,30 @@ func (r *Runner) EmailAddress() string {
 	return r.emailAddress.String()
 }
 
+// Gender Returns the gender of the runner
+func (r *Runner) Gender() Gender {
+	return r.gender
+}
+
+// DateOfBirth Returns the date of birth of the runner, zero when unknown
+func (r *Runner) DateOfBirth() time.Time {
+	return r.dateOfBirth
+}
+
+// AgeOn Returns the age of the runner in completed years on the provided date.
+// The second return value is false when the date of birth is unknown.
+func (r *Runner) AgeOn(date time.Time) (int, bool) {
+	if r.dateOfBirth.IsZero() {
+		return 0, false
+	}
+	age := date.Year() - r.dateOfBirth.Year()
+	if date.Month() < r.dateOfBirth.Month() || (date.Month() == r.dateOfBirth.Month() && date.Day() < r.dateOfBirth.Day()) {
+		age--
+	}
+	return age, true
+}
+
 // CreatedAt Returns the creation date of the runner
-func (r *Runner) CreatedAt() any {
+func (r *Runner) CreatedAt() time.Time {
 	return r.createdAt
 }
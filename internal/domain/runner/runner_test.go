@@ -96,6 +96,8 @@ func TestLoadRunner(t *testing.T) {
 		id         uuid.UUID
 		runnerName string
 		email      string
+		gender     Gender
+		birth      time.Time
 		createdAt  time.Time
 		wantErr    error
 	}{
@@ -123,11 +125,30 @@ func TestLoadRunner(t *testing.T) {
 			createdAt:  time.Now().UTC(),
 			wantErr:    ErrInvalidEmail,
 		},
+		{
+			name:       "Valid profile",
+			id:         uuid.New(),
+			runnerName: "John Doe",
+			email:      "john.doe@example.com",
+			gender:     GenderMale,
+			birth:      time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			createdAt:  time.Now().UTC(),
+			wantErr:    nil,
+		},
+		{
+			name:       "Invalid gender",
+			id:         uuid.New(),
+			runnerName: "John Doe",
+			email:      "john.doe@example.com",
+			gender:     "unknown",
+			createdAt:  time.Now().UTC(),
+			wantErr:    ErrInvalidGender,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			runner, err := LoadRunner(tt.id, tt.runnerName, tt.email, tt.createdAt)
+			runner, err := LoadRunner(tt.id, tt.runnerName, tt.email, tt.gender, tt.birth, tt.createdAt)
 			if err != tt.wantErr {
 				t.Errorf("LoadRunner() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -142,6 +163,9 @@ func TestLoadRunner(t *testing.T) {
 				if runner.EmailAddress() != tt.email {
 					t.Errorf("LoadRunner() emailAddress = %v, want %v", runner.EmailAddress(), tt.email)
 				}
+				if runner.Gender() != tt.gender || !runner.DateOfBirth().Equal(tt.birth) {
+					t.Errorf("LoadRunner() profile = %v %v, want %v %v", runner.Gender(), runner.DateOfBirth(), tt.gender, tt.birth)
+				}
 				if runner.CreatedAt() != tt.createdAt {
 					t.Errorf("LoadRunner() createdAt = %v, want %v", runner.CreatedAt(), tt.createdAt)
 				}
@@ -149,3 +173,97 @@ func TestLoadRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestRunner_UpdateProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		gender  Gender
+		birth   time.Time
+		wantErr error
+	}{
+		{
+			name:    "Valid profile",
+			gender:  GenderFemale,
+			birth:   time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantErr: nil,
+		},
+		{
+			name:    "Unknown profile",
+			gender:  GenderUnspecified,
+			wantErr: nil,
+		},
+		{
+			name:    "Invalid gender",
+			gender:  "other",
+			wantErr: ErrInvalidGender,
+		},
+		{
+			name:    "Date of birth in the future",
+			gender:  GenderFemale,
+			birth:   time.Now().AddDate(1, 0, 0),
+			wantErr: ErrInvalidDateOfBirth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, _ := NewRunner("John Doe", "john.doe@example.com")
+			err := runner.UpdateProfile(tt.gender, tt.birth)
+			if err != tt.wantErr {
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (runner.Gender() != tt.gender || !runner.DateOfBirth().Equal(tt.birth)) {
+				t.Errorf("UpdateProfile() profile = %v %v, want %v %v", runner.Gender(), runner.DateOfBirth(), tt.gender, tt.birth)
+			}
+		})
+	}
+}
+
+func TestRunner_AgeOn(t *testing.T) {
+	tests := []struct {
+		name    string
+		birth   time.Time
+		date    time.Time
+		wantAge int
+		wantOk  bool
+	}{
+		{
+			name:    "Before birthday",
+			birth:   time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC),
+			date:    time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC),
+			wantAge: 33,
+			wantOk:  true,
+		},
+		{
+			name:    "On birthday",
+			birth:   time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC),
+			date:    time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+			wantAge: 34,
+			wantOk:  true,
+		},
+		{
+			name:    "Leap year race date",
+			birth:   time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC),
+			date:    time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			wantAge: 33,
+			wantOk:  true,
+		},
+		{
+			name:   "Unknown date of birth",
+			date:   time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, _ := NewRunner("John Doe", "john.doe@example.com")
+			_ = runner.UpdateProfile(GenderMale, tt.birth)
+			age, ok := runner.AgeOn(tt.date)
+			if age != tt.wantAge || ok != tt.wantOk {
+				t.Errorf("AgeOn() = %v %v, want %v %v", age, ok, tt.wantAge, tt.wantOk)
+			}
+		})
+	}
+}
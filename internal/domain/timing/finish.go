@@ -0,0 +1,111 @@
+package timing
+
+import (
+	"sort"
+	"time"
+)
+
+// Split is the net time of a chip at an intermediate checkpoint
+type Split struct {
+	CheckpointID string
+	DistanceKm   float64
+	Elapsed      time.Duration
+}
+
+// Finish is the timing of a chip that crossed the finish line.
+// GunTime is measured from the gun of the wave of the chip and NetTime from the moment the chip crossed the start line,
+// which is the gun time when the start mat has no read of the chip.
+type Finish struct {
+	ChipID     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	GunTime    time.Duration
+	NetTime    time.Duration
+	Splits     []Split
+}
+
+// Finishes returns the timing of every chip with a finish read after its gun, ordered by net time.
+// waveGuns holds the gun of the chips that started in a wave; the other chips start with the gun of the session.
+// The finish is the first finish read, the start is the last start read before it, and each split is
+// the first read of the checkpoint between the two. Splits that are not later than the previous one
+// are misreads and are left out.
+func (s *Session) Finishes(waveGuns map[string]time.Time) []Finish {
+	gunOf := func(chipID string) time.Time {
+		if gun, ok := waveGuns[chipID]; ok {
+			return gun
+		}
+		return s.gunTime
+	}
+
+	byChip := make(map[string][]Read)
+	for _, read := range s.reads {
+		if read.at.Before(gunOf(read.chipID)) {
+			continue
+		}
+		byChip[read.chipID] = append(byChip[read.chipID], read)
+	}
+
+	finish := s.Finish()
+	var finishes []Finish
+	for chipID, reads := range byChip {
+		gun := gunOf(chipID)
+		finishedAt, finished := firstAt(reads, finish.id, gun, time.Time{})
+		if !finished {
+			continue
+		}
+
+		startedAt := gun
+		for _, read := range reads {
+			c, _ := s.checkpoint(read.checkpointID)
+			if c.IsStart() && read.at.Before(finishedAt) {
+				startedAt = read.at
+			}
+		}
+
+		var splits []Split
+		var previous time.Duration
+		for _, c := range s.checkpoints {
+			if c.IsStart() || c.id == finish.id {
+				continue
+			}
+			at, ok := firstAt(reads, c.id, startedAt, finishedAt)
+			if !ok || at.Sub(startedAt) <= previous {
+				continue
+			}
+			previous = at.Sub(startedAt)
+			splits = append(splits, Split{CheckpointID: c.id, DistanceKm: c.distanceKm, Elapsed: previous})
+		}
+
+		finishes = append(finishes, Finish{
+			ChipID:     chipID,
+			StartedAt:  startedAt,
+			FinishedAt: finishedAt,
+			GunTime:    finishedAt.Sub(gun),
+			NetTime:    finishedAt.Sub(startedAt),
+			Splits:     splits,
+		})
+	}
+
+	sort.Slice(finishes, func(i, j int) bool {
+		if finishes[i].NetTime != finishes[j].NetTime {
+			return finishes[i].NetTime < finishes[j].NetTime
+		}
+		return finishes[i].ChipID < finishes[j].ChipID
+	})
+	return finishes
+}
+
+// firstAt returns the first of the time ordered reads at the checkpoint after from and, unless until is zero,
+// before until
+func firstAt(reads []Read, checkpointID string, from, until time.Time) (time.Time, bool) {
+	for _, read := range reads {
+		if read.checkpointID != checkpointID || !read.at.After(from) {
+			continue
+		}
+		if !until.IsZero() && !read.at.Before(until) {
+			return time.Time{}, false
+		}
+		return read.at, true
+	}
+	return time.Time{}, false
+}
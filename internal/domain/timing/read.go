@@ -0,0 +1,88 @@
+// Package timing contains the domain entities for the chip timing of races
+package timing
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptyChipID         = errors.New("chip ID cannot be empty")
+	ErrEmptyCheckpointID   = errors.New("checkpoint ID cannot be empty")
+	ErrMissingReadTime     = errors.New("read time is required")
+	ErrInvalidCheckpointKm = errors.New("checkpoint distance cannot be negative")
+)
+
+// Checkpoint is a timing mat at a distance of the course. The mat at 0 km is the start line and
+// the furthest mat is the finish line.
+type Checkpoint struct {
+	id         string
+	distanceKm float64
+}
+
+// NewCheckpoint creates a new Checkpoint and validates the input
+func NewCheckpoint(id string, distanceKm float64) (Checkpoint, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Checkpoint{}, ErrEmptyCheckpointID
+	}
+	if distanceKm < 0 || math.IsNaN(distanceKm) {
+		return Checkpoint{}, ErrInvalidCheckpointKm
+	}
+	return Checkpoint{id: id, distanceKm: distanceKm}, nil
+}
+
+// ID returns the ID the timing system reports for the mat
+func (c Checkpoint) ID() string {
+	return c.id
+}
+
+// DistanceKm returns the distance of the mat from the start line
+func (c Checkpoint) DistanceKm() float64 {
+	return c.distanceKm
+}
+
+// IsStart reports whether the mat is on the start line
+func (c Checkpoint) IsStart() bool {
+	return c.distanceKm == 0
+}
+
+// Read is a detection of a timing chip by the mat of a checkpoint
+type Read struct {
+	chipID       string
+	checkpointID string
+	at           time.Time
+}
+
+// NewRead creates a new Read and validates the input
+func NewRead(chipID, checkpointID string, at time.Time) (Read, error) {
+	chipID = strings.TrimSpace(chipID)
+	if chipID == "" {
+		return Read{}, ErrEmptyChipID
+	}
+	checkpointID = strings.TrimSpace(checkpointID)
+	if checkpointID == "" {
+		return Read{}, ErrEmptyCheckpointID
+	}
+	if at.IsZero() {
+		return Read{}, ErrMissingReadTime
+	}
+	return Read{chipID: chipID, checkpointID: checkpointID, at: at.UTC()}, nil
+}
+
+// ChipID returns the ID of the chip that was detected
+func (r Read) ChipID() string {
+	return r.chipID
+}
+
+// CheckpointID returns the ID of the checkpoint that detected the chip
+func (r Read) CheckpointID() string {
+	return r.checkpointID
+}
+
+// At returns the time of the detection
+func (r Read) At() time.Time {
+	return r.at
+}
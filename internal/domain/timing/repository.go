@@ -0,0 +1,24 @@
+package timing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotConfigured Error when no timing is configured for the race
+	ErrNotConfigured = errors.New("timing is not configured for the race")
+	// ErrConcurrentUpdate Error when the session was changed since it was loaded
+	ErrConcurrentUpdate = errors.New("timing of the race was changed by another request")
+)
+
+// Repository defines the storage interface for the timing sessions of races
+type Repository interface {
+	// GetSession returns the timing session of the race, or ErrNotConfigured
+	GetSession(ctx context.Context, raceID uuid.UUID) (*Session, error)
+	// SaveSession stores the session, returning ErrConcurrentUpdate when the stored version
+	// no longer matches the version the session was loaded from
+	SaveSession(ctx context.Context, session *Session) error
+}
@@ -0,0 +1,231 @@
+package timing
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyRaceID          = errors.New("race ID cannot be empty")
+	ErrMissingGunTime       = errors.New("gun time is required")
+	ErrInvalidDebounce      = errors.New("debounce cannot be negative")
+	ErrNoCheckpoints        = errors.New("at least the finish checkpoint is required")
+	ErrDuplicateCheckpoint  = errors.New("checkpoint IDs and distances must be unique")
+	ErrCheckpointBeyondRace = errors.New("checkpoint is beyond the race distance")
+	ErrMissingFinish        = errors.New("the furthest checkpoint must be the finish at the race distance")
+	ErrCheckpointInUse      = errors.New("checkpoint with reads cannot be removed")
+	ErrUnknownCheckpoint    = errors.New("read is from a checkpoint that is not configured")
+	ErrResultFromTiming     = errors.New("finish times of a timed race come from the timing reads")
+)
+
+// finishToleranceKm is how far the finish checkpoint may be from the race distance
+const finishToleranceKm = 0.05
+
+// Session is the chip timing of a race: the checkpoints, the gun time of the start and the reads of the mats.
+// Reads of a chip at a checkpoint within the debounce of an earlier read are duplicates and are dropped.
+type Session struct {
+	raceID      uuid.UUID
+	gunTime     time.Time
+	debounce    time.Duration
+	checkpoints []Checkpoint
+	reads       []Read
+	version     int
+}
+
+// NewSession creates the timing session of a race of raceDistanceKm and validates the input
+func NewSession(raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []Checkpoint, raceDistanceKm float64) (*Session, error) {
+	if raceID == uuid.Nil {
+		return nil, ErrEmptyRaceID
+	}
+	s := &Session{raceID: raceID}
+	if err := s.Configure(gunTime, debounce, checkpoints, raceDistanceKm); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadSession recreates an existing Session from stored data
+func LoadSession(raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []Checkpoint, reads []Read, version int) (*Session, error) {
+	if raceID == uuid.Nil {
+		return nil, ErrEmptyRaceID
+	}
+	if err := validateSettings(gunTime, debounce); err != nil {
+		return nil, err
+	}
+	sorted, err := sortCheckpoints(checkpoints)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{raceID: raceID, gunTime: gunTime.UTC(), debounce: debounce, checkpoints: sorted, version: version}
+	for _, read := range reads {
+		if _, ok := s.checkpoint(read.checkpointID); !ok {
+			return nil, ErrUnknownCheckpoint
+		}
+	}
+	s.reads = append([]Read(nil), reads...)
+	sortReads(s.reads)
+	return s, nil
+}
+
+// Configure changes the gun time, the debounce and the checkpoints of the session.
+// Checkpoints that already have reads cannot be removed.
+func (s *Session) Configure(gunTime time.Time, debounce time.Duration, checkpoints []Checkpoint, raceDistanceKm float64) error {
+	if err := validateSettings(gunTime, debounce); err != nil {
+		return err
+	}
+	sorted, err := sortCheckpoints(checkpoints)
+	if err != nil {
+		return err
+	}
+	finish := sorted[len(sorted)-1]
+	if finish.distanceKm-raceDistanceKm > finishToleranceKm {
+		return ErrCheckpointBeyondRace
+	}
+	if math.Abs(finish.distanceKm-raceDistanceKm) > finishToleranceKm {
+		return ErrMissingFinish
+	}
+
+	configured := make(map[string]bool, len(sorted))
+	for _, c := range sorted {
+		configured[c.id] = true
+	}
+	for _, read := range s.reads {
+		if !configured[read.checkpointID] {
+			return ErrCheckpointInUse
+		}
+	}
+
+	s.gunTime = gunTime.UTC()
+	s.debounce = debounce
+	s.checkpoints = sorted
+	return nil
+}
+
+func validateSettings(gunTime time.Time, debounce time.Duration) error {
+	if gunTime.IsZero() {
+		return ErrMissingGunTime
+	}
+	if debounce < 0 {
+		return ErrInvalidDebounce
+	}
+	return nil
+}
+
+// sortCheckpoints returns the checkpoints ordered by distance after checking that they are unique
+func sortCheckpoints(checkpoints []Checkpoint) ([]Checkpoint, error) {
+	if len(checkpoints) == 0 {
+		return nil, ErrNoCheckpoints
+	}
+	sorted := append([]Checkpoint(nil), checkpoints...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].distanceKm < sorted[j].distanceKm })
+	ids := make(map[string]bool, len(sorted))
+	for i, c := range sorted {
+		if ids[c.id] || i > 0 && c.distanceKm == sorted[i-1].distanceKm {
+			return nil, ErrDuplicateCheckpoint
+		}
+		ids[c.id] = true
+	}
+	if sorted[len(sorted)-1].IsStart() {
+		return nil, ErrNoCheckpoints
+	}
+	return sorted, nil
+}
+
+func sortReads(reads []Read) {
+	sort.SliceStable(reads, func(i, j int) bool { return reads[i].at.Before(reads[j].at) })
+}
+
+// Record adds the reads to the session and returns the number of reads that were accepted.
+// Reads of a chip at a checkpoint within the debounce of a stored or an earlier read of the batch are
+// dropped, as are exact repeats, so sending the same batch twice accepts nothing the second time.
+// The batch is rejected as a whole when a read is from an unknown checkpoint.
+func (s *Session) Record(reads []Read) (int, error) {
+	for _, read := range reads {
+		if _, ok := s.checkpoint(read.checkpointID); !ok {
+			return 0, ErrUnknownCheckpoint
+		}
+	}
+
+	type key struct{ chipID, checkpointID string }
+	seen := make(map[key][]time.Time)
+	for _, read := range s.reads {
+		k := key{read.chipID, read.checkpointID}
+		seen[k] = append(seen[k], read.at)
+	}
+
+	batch := append([]Read(nil), reads...)
+	sortReads(batch)
+	var accepted int
+	for _, read := range batch {
+		k := key{read.chipID, read.checkpointID}
+		if withinDebounce(seen[k], read.at, s.debounce) {
+			continue
+		}
+		seen[k] = append(seen[k], read.at)
+		s.reads = append(s.reads, read)
+		accepted++
+	}
+	sortReads(s.reads)
+	return accepted, nil
+}
+
+func withinDebounce(times []time.Time, at time.Time, debounce time.Duration) bool {
+	for _, t := range times {
+		d := at.Sub(t)
+		if d < 0 {
+			d = -d
+		}
+		if d <= debounce {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Session) checkpoint(id string) (Checkpoint, bool) {
+	for _, c := range s.checkpoints {
+		if c.id == id {
+			return c, true
+		}
+	}
+	return Checkpoint{}, false
+}
+
+// Finish returns the finish checkpoint, which is the furthest one
+func (s *Session) Finish() Checkpoint {
+	return s.checkpoints[len(s.checkpoints)-1]
+}
+
+// RaceID returns the ID of the race
+func (s *Session) RaceID() uuid.UUID {
+	return s.raceID
+}
+
+// GunTime returns the time the race was started
+func (s *Session) GunTime() time.Time {
+	return s.gunTime
+}
+
+// Debounce returns the window within which repeated reads of a chip at a checkpoint are duplicates
+func (s *Session) Debounce() time.Duration {
+	return s.debounce
+}
+
+// Checkpoints returns the checkpoints ordered by distance
+func (s *Session) Checkpoints() []Checkpoint {
+	return append([]Checkpoint(nil), s.checkpoints...)
+}
+
+// Reads returns the accepted reads ordered by time
+func (s *Session) Reads() []Read {
+	return append([]Read(nil), s.reads...)
+}
+
+// Version returns the version of the stored data the session was loaded from, 0 for a new session
+func (s *Session) Version() int {
+	return s.version
+}
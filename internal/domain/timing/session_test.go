@@ -0,0 +1,213 @@
+package timing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gun = time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+
+func mustCheckpoint(t *testing.T, id string, distanceKm float64) Checkpoint {
+	c, err := NewCheckpoint(id, distanceKm)
+	require.NoError(t, err)
+	return c
+}
+
+func mustRead(t *testing.T, chipID, checkpointID string, after time.Duration) Read {
+	r, err := NewRead(chipID, checkpointID, gun.Add(after))
+	require.NoError(t, err)
+	return r
+}
+
+// newSession returns the session of a 10K with a start mat, a 5K mat and a finish mat
+func newSession(t *testing.T, debounce time.Duration) *Session {
+	s, err := NewSession(uuid.New(), gun, debounce, []Checkpoint{
+		mustCheckpoint(t, "finish", 10),
+		mustCheckpoint(t, "start", 0),
+		mustCheckpoint(t, "5k", 5),
+	}, 10)
+	require.NoError(t, err)
+	return s
+}
+
+func TestNewRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		chipID       string
+		checkpointID string
+		at           time.Time
+		wantErr      error
+	}{
+		{name: "valid", chipID: "E200341", checkpointID: "finish", at: gun},
+		{name: "empty chip", chipID: " ", checkpointID: "finish", at: gun, wantErr: ErrEmptyChipID},
+		{name: "empty checkpoint", chipID: "E200341", checkpointID: "", at: gun, wantErr: ErrEmptyCheckpointID},
+		{name: "missing time", chipID: "E200341", checkpointID: "finish", wantErr: ErrMissingReadTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRead(tt.chipID, tt.checkpointID, tt.at)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	tests := []struct {
+		name        string
+		gunTime     time.Time
+		debounce    time.Duration
+		checkpoints []Checkpoint
+		wantErr     error
+	}{
+		{name: "finish only", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "finish", 10)}},
+		{name: "finish within tolerance", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "finish", 10.03)}},
+		{name: "missing gun time", checkpoints: []Checkpoint{mustCheckpoint(t, "finish", 10)}, wantErr: ErrMissingGunTime},
+		{name: "negative debounce", gunTime: gun, debounce: -time.Second, checkpoints: []Checkpoint{mustCheckpoint(t, "finish", 10)}, wantErr: ErrInvalidDebounce},
+		{name: "no checkpoints", gunTime: gun, wantErr: ErrNoCheckpoints},
+		{name: "start only", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "start", 0)}, wantErr: ErrNoCheckpoints},
+		{name: "duplicate ID", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "mat", 5), mustCheckpoint(t, "mat", 10)}, wantErr: ErrDuplicateCheckpoint},
+		{name: "duplicate distance", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "a", 10), mustCheckpoint(t, "b", 10)}, wantErr: ErrDuplicateCheckpoint},
+		{name: "beyond the race", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "finish", 10.5)}, wantErr: ErrCheckpointBeyondRace},
+		{name: "finish short of the race", gunTime: gun, checkpoints: []Checkpoint{mustCheckpoint(t, "5k", 5)}, wantErr: ErrMissingFinish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSession(uuid.New(), tt.gunTime, tt.debounce, tt.checkpoints, 10)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "finish", s.Finish().ID())
+		})
+	}
+}
+
+func TestSession_Record(t *testing.T) {
+	s := newSession(t, 5*time.Second)
+
+	accepted, err := s.Record([]Read{
+		mustRead(t, "A", "finish", 40*time.Minute+2*time.Second),
+		mustRead(t, "A", "finish", 40*time.Minute),
+		mustRead(t, "A", "5k", 20*time.Minute),
+		mustRead(t, "B", "finish", 40*time.Minute+time.Second),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, accepted)
+
+	//the same batch again is fully deduplicated
+	accepted, err = s.Record([]Read{
+		mustRead(t, "A", "finish", 40*time.Minute),
+		mustRead(t, "B", "finish", 40*time.Minute+4*time.Second),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, accepted)
+
+	//a later crossing outside the debounce is kept
+	accepted, err = s.Record([]Read{mustRead(t, "A", "finish", 41*time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, accepted)
+
+	_, err = s.Record([]Read{mustRead(t, "A", "finish", 42*time.Minute), mustRead(t, "A", "10k", 42*time.Minute)})
+	assert.ErrorIs(t, err, ErrUnknownCheckpoint)
+	assert.Len(t, s.Reads(), 4)
+	assert.True(t, s.Reads()[0].At().Equal(gun.Add(20*time.Minute)), "reads are ordered by time")
+}
+
+func TestSession_Configure(t *testing.T) {
+	s := newSession(t, 0)
+	_, err := s.Record([]Read{mustRead(t, "A", "5k", 20*time.Minute)})
+	require.NoError(t, err)
+
+	err = s.Configure(gun, 0, []Checkpoint{mustCheckpoint(t, "finish", 10)}, 10)
+	assert.ErrorIs(t, err, ErrCheckpointInUse)
+
+	later := gun.Add(time.Minute)
+	err = s.Configure(later, time.Second, []Checkpoint{mustCheckpoint(t, "5k", 5), mustCheckpoint(t, "finish", 10)}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, later, s.GunTime())
+	assert.Len(t, s.Checkpoints(), 2)
+}
+
+func TestSession_Finishes(t *testing.T) {
+	s := newSession(t, 0)
+	_, err := s.Record([]Read{
+		//A crossed the start line 30s after the gun, lingered on the mat and ran a 5K split
+		mustRead(t, "A", "start", 25*time.Second),
+		mustRead(t, "A", "start", 30*time.Second),
+		mustRead(t, "A", "5k", 20*time.Minute),
+		mustRead(t, "A", "finish", 40*time.Minute+30*time.Second),
+		mustRead(t, "A", "finish", 41*time.Minute),
+		//B has no start read, so the net time is the gun time, and a 5K misread after the finish
+		mustRead(t, "B", "finish", 40*time.Minute),
+		mustRead(t, "B", "5k", 45*time.Minute),
+		//C did not finish
+		mustRead(t, "C", "start", time.Second),
+		mustRead(t, "C", "5k", 25*time.Minute),
+		//D was read by the finish mat before the gun only
+		mustRead(t, "D", "finish", -time.Hour),
+	})
+	require.NoError(t, err)
+
+	finishes := s.Finishes(nil)
+	require.Len(t, finishes, 2)
+
+	assert.Equal(t, Finish{
+		ChipID:     "A",
+		StartedAt:  gun.Add(30 * time.Second),
+		FinishedAt: gun.Add(40*time.Minute + 30*time.Second),
+		GunTime:    40*time.Minute + 30*time.Second,
+		NetTime:    40 * time.Minute,
+		Splits:     []Split{{CheckpointID: "5k", DistanceKm: 5, Elapsed: 19*time.Minute + 30*time.Second}},
+	}, finishes[0])
+	assert.Equal(t, Finish{
+		ChipID:     "B",
+		StartedAt:  gun,
+		FinishedAt: gun.Add(40 * time.Minute),
+		GunTime:    40 * time.Minute,
+		NetTime:    40 * time.Minute,
+	}, finishes[1])
+}
+
+func TestSession_Finishes_Waves(t *testing.T) {
+	s := newSession(t, 0)
+	_, err := s.Record([]Read{
+		//W started in the second wave, 10 minutes after the gun, and crossed the start mat 20s later
+		mustRead(t, "W", "start", 10*time.Minute+20*time.Second),
+		mustRead(t, "W", "finish", 50*time.Minute+20*time.Second),
+		//X started in the second wave without a start read
+		mustRead(t, "X", "finish", 52*time.Minute),
+		//Y was read by the finish mat while warming up before the second wave started
+		mustRead(t, "Y", "finish", 5*time.Minute),
+	})
+	require.NoError(t, err)
+
+	secondWave := gun.Add(10 * time.Minute)
+	finishes := s.Finishes(map[string]time.Time{"W": secondWave, "X": secondWave, "Y": secondWave})
+	require.Len(t, finishes, 2)
+	assert.Equal(t, "W", finishes[0].ChipID)
+	assert.Equal(t, 40*time.Minute+20*time.Second, finishes[0].GunTime)
+	assert.Equal(t, 40*time.Minute, finishes[0].NetTime)
+	assert.Equal(t, "X", finishes[1].ChipID)
+	assert.Equal(t, secondWave, finishes[1].StartedAt)
+	assert.Equal(t, 42*time.Minute, finishes[1].GunTime)
+	assert.Equal(t, 42*time.Minute, finishes[1].NetTime)
+}
+
+func TestLoadSession(t *testing.T) {
+	raceID := uuid.New()
+	checkpoints := []Checkpoint{mustCheckpoint(t, "finish", 10)}
+	reads := []Read{mustRead(t, "A", "finish", 41*time.Minute), mustRead(t, "B", "finish", 40*time.Minute)}
+
+	s, err := LoadSession(raceID, gun, time.Second, checkpoints, reads, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Version())
+	assert.Equal(t, "B", s.Reads()[0].ChipID())
+
+	_, err = LoadSession(raceID, gun, time.Second, checkpoints, []Read{mustRead(t, "A", "5k", time.Minute)}, 3)
+	assert.ErrorIs(t, err, ErrUnknownCheckpoint)
+}
@@ -0,0 +1,215 @@
+package activity
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// FIT protocol constants used by the decoder. Only the record messages are decoded; every other
+// message is skipped using its definition.
+const (
+	fitMinHeaderSize      = 12
+	fitRecordMessage      = 20
+	fitFieldHeartRate     = 3
+	fitFieldDistance      = 5
+	fitFieldPositionLat   = 0
+	fitFieldPositionLong  = 1
+	fitFieldTimestamp     = 253
+	fitDistanceScale      = 100
+	fitHeaderCompressed   = 0x80
+	fitHeaderDefinition   = 0x40
+	fitHeaderDeveloper    = 0x20
+	fitLocalTypeMask      = 0x0F
+	fitCompressedTypeMask = 0x60
+	fitCompressedOffset   = 0x1F
+)
+
+// fitEpoch is the reference time of FIT timestamps
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// semicircleDegrees converts FIT positions to degrees
+const semicircleDegrees = 180.0 / (1 << 31)
+
+type fitField struct {
+	number uint8
+	size   int
+}
+
+type fitDefinition struct {
+	order         binary.ByteOrder
+	globalMessage uint16
+	fields        []fitField
+	developerSize int
+}
+
+type fitDecoder struct {
+	r             *bufio.Reader
+	remaining     int
+	definitions   map[uint8]fitDefinition
+	lastTimestamp uint32
+	samples       []sample
+}
+
+func parseFIT(r io.Reader) ([]sample, error) {
+	d := fitDecoder{r: bufio.NewReader(r), definitions: make(map[uint8]fitDefinition)}
+	if err := d.readFileHeader(); err != nil {
+		return nil, err
+	}
+	for d.remaining > 0 {
+		if err := d.readRecord(); err != nil {
+			return nil, err
+		}
+	}
+	return d.samples, nil
+}
+
+func (d *fitDecoder) readFileHeader() error {
+	size, err := d.r.ReadByte()
+	if err != nil || size < fitMinHeaderSize {
+		return errInvalid("missing FIT file header")
+	}
+	header := make([]byte, size-1)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return errInvalid("truncated FIT file header")
+	}
+	if string(header[7:11]) != ".FIT" {
+		return errInvalid("missing .FIT signature")
+	}
+	d.remaining = int(binary.LittleEndian.Uint32(header[3:7]))
+	return nil
+}
+
+func (d *fitDecoder) read(n int) ([]byte, error) {
+	if n > d.remaining {
+		return nil, errInvalid("FIT record exceeds the data size")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, errInvalid("truncated FIT data")
+	}
+	d.remaining -= n
+	return b, nil
+}
+
+func (d *fitDecoder) readRecord() error {
+	b, err := d.read(1)
+	if err != nil {
+		return err
+	}
+	header := b[0]
+
+	switch {
+	case header&fitHeaderCompressed != 0:
+		localType := (header & fitCompressedTypeMask) >> 5
+		offset := uint32(header & fitCompressedOffset)
+		timestamp := d.lastTimestamp&^fitCompressedOffset + offset
+		if offset < d.lastTimestamp&fitCompressedOffset {
+			timestamp += fitCompressedOffset + 1
+		}
+		return d.readData(localType, &timestamp)
+	case header&fitHeaderDefinition != 0:
+		return d.readDefinition(header&fitLocalTypeMask, header&fitHeaderDeveloper != 0)
+	default:
+		return d.readData(header&fitLocalTypeMask, nil)
+	}
+}
+
+func (d *fitDecoder) readDefinition(localType uint8, hasDeveloperFields bool) error {
+	b, err := d.read(5)
+	if err != nil {
+		return err
+	}
+	def := fitDefinition{order: binary.LittleEndian}
+	if b[1] == 1 {
+		def.order = binary.BigEndian
+	}
+	def.globalMessage = def.order.Uint16(b[2:4])
+
+	fields, err := d.read(int(b[4]) * 3)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(fields); i += 3 {
+		def.fields = append(def.fields, fitField{number: fields[i], size: int(fields[i+1])})
+	}
+
+	if hasDeveloperFields {
+		n, err := d.read(1)
+		if err != nil {
+			return err
+		}
+		developerFields, err := d.read(int(n[0]) * 3)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(developerFields); i += 3 {
+			def.developerSize += int(developerFields[i+1])
+		}
+	}
+
+	d.definitions[localType] = def
+	return nil
+}
+
+// readData decodes a data message; timestamp is set for compressed timestamp headers
+func (d *fitDecoder) readData(localType uint8, timestamp *uint32) error {
+	def, ok := d.definitions[localType]
+	if !ok {
+		return errInvalid("FIT data message without definition")
+	}
+
+	s := sample{}
+	var latitude, longitude *int32
+	for _, field := range def.fields {
+		value, err := d.read(field.size)
+		if err != nil {
+			return err
+		}
+		//the timestamp of any message is the reference of the following compressed timestamps
+		if field.number == fitFieldTimestamp && field.size == 4 {
+			if v := def.order.Uint32(value); v != 0xFFFFFFFF {
+				timestamp = &v
+			}
+			continue
+		}
+		if def.globalMessage != fitRecordMessage {
+			continue
+		}
+		switch {
+		case field.number == fitFieldDistance && field.size == 4:
+			if v := def.order.Uint32(value); v != 0xFFFFFFFF {
+				s.distanceM, s.hasDistance = float64(v)/fitDistanceScale, true
+			}
+		case field.number == fitFieldHeartRate && field.size == 1:
+			if value[0] != 0xFF {
+				s.heartRate = int(value[0])
+			}
+		case field.number == fitFieldPositionLat && field.size == 4:
+			if v := int32(def.order.Uint32(value)); v != 0x7FFFFFFF {
+				latitude = &v
+			}
+		case field.number == fitFieldPositionLong && field.size == 4:
+			if v := int32(def.order.Uint32(value)); v != 0x7FFFFFFF {
+				longitude = &v
+			}
+		}
+	}
+	if _, err := d.read(def.developerSize); err != nil {
+		return err
+	}
+
+	if timestamp != nil {
+		d.lastTimestamp = *timestamp
+	}
+	if def.globalMessage != fitRecordMessage || timestamp == nil {
+		return nil
+	}
+	s.at = fitEpoch.Add(time.Duration(*timestamp) * time.Second)
+	if latitude != nil && longitude != nil {
+		s.lat, s.lon, s.hasPosition = float64(*latitude)*semicircleDegrees, float64(*longitude)*semicircleDegrees, true
+	}
+	d.samples = append(d.samples, s)
+	return nil
+}
@@ -0,0 +1,49 @@
+package activity
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// gpxFile maps the track points of a GPX 1.1 file; heart rate is read from the Garmin TrackPointExtension
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat       float64   `xml:"lat,attr"`
+	Lon       float64   `xml:"lon,attr"`
+	Time      time.Time `xml:"time"`
+	HeartRate int       `xml:"extensions>TrackPointExtension>hr"`
+}
+
+func parseGPX(r io.Reader) ([]sample, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errInvalid(err.Error())
+	}
+
+	var samples []sample
+	for _, track := range file.Tracks {
+		for _, segment := range track.Segments {
+			for _, p := range segment.Points {
+				if p.Time.IsZero() {
+					continue
+				}
+				samples = append(samples, sample{
+					at:          p.Time,
+					lat:         p.Lat,
+					lon:         p.Lon,
+					hasPosition: true,
+					heartRate:   p.HeartRate,
+				})
+			}
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,49 @@
+// Package activity implements the activity Parser port for GPX, TCX and FIT files
+package activity
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+)
+
+// maxFileSize bounds the size of the activity files that are read in memory
+const maxFileSize = 32 << 20
+
+// Parser Implements the activity Parser port
+type Parser struct{}
+
+// NewParser Constructor
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse reads the activity file in the provided format and summarises it
+func (p Parser) Parse(_ context.Context, format activity.Format, file io.Reader) (activity.Activity, error) {
+	var (
+		samples []sample
+		err     error
+	)
+	file = io.LimitReader(file, maxFileSize)
+	switch format {
+	case activity.FormatGPX:
+		samples, err = parseGPX(file)
+	case activity.FormatTCX:
+		samples, err = parseTCX(file)
+	case activity.FormatFIT:
+		samples, err = parseFIT(file)
+	default:
+		return activity.Activity{}, activity.ErrUnsupportedFormat
+	}
+	if err != nil {
+		return activity.Activity{}, err
+	}
+	return summarise(samples)
+}
+
+// errInvalid wraps activity.ErrInvalidFile with the reason the file cannot be decoded
+func errInvalid(reason string) error {
+	return fmt.Errorf("%w: %s", activity.ErrInvalidFile, reason)
+}
@@ -0,0 +1,182 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// latitudeStep is the latitude difference in degrees of ~250m along a meridian
+const latitudeStep = 250 / (earthRadiusM * 3.141592653589793 / 180)
+
+var start = time.Date(2025, 3, 9, 8, 0, 0, 0, time.UTC)
+
+func gpxFixture() string {
+	var points strings.Builder
+	for i := 0; i <= 9; i++ {
+		fmt.Fprintf(&points, `<trkpt lat="%f" lon="23.7"><time>%s</time><extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>%d</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>`,
+			37.9+float64(i)*latitudeStep, start.Add(time.Duration(i)*75*time.Second).Format(time.RFC3339), 140+i)
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+<trk><trkseg>` + points.String() + `</trkseg></trk></gpx>`
+}
+
+func tcxFixture() string {
+	var points strings.Builder
+	for i := 0; i <= 10; i++ {
+		fmt.Fprintf(&points, `<Trackpoint><Time>%s</Time><DistanceMeters>%d</DistanceMeters><HeartRateBpm><Value>150</Value></HeartRateBpm></Trackpoint>`,
+			start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339), i*250)
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+<Activities><Activity Sport="Running"><Lap><Track>` + points.String() + `</Track></Lap></Activity></Activities>
+</TrainingCenterDatabase>`
+}
+
+// fitFixture encodes a FIT file with a file id message and record messages of 100m every 30 seconds.
+// The second half of the records uses compressed timestamp headers.
+func fitFixture() []byte {
+	var data bytes.Buffer
+	timestamp := uint32(start.Sub(fitEpoch).Seconds())
+
+	//file_id message (global 0) with a serial number, skipped by the decoder
+	data.Write([]byte{0x40, 0, 0, 0, 0, 1, 3, 4, 0x8C})
+	data.WriteByte(0x00)
+	_ = binary.Write(&data, binary.LittleEndian, uint32(12345))
+
+	//record definition on local type 1: timestamp, distance, heart rate and one developer byte
+	data.Write([]byte{0x61, 0, 0, fitRecordMessage, 0, 3, fitFieldTimestamp, 4, 0x86, fitFieldDistance, 4, 0x86, fitFieldHeartRate, 1, 0x02, 1, 0, 1, 0})
+	//record definition on local type 2 without timestamp, used with compressed timestamp headers
+	data.Write([]byte{0x42, 0, 0, fitRecordMessage, 0, 2, fitFieldDistance, 4, 0x86, fitFieldHeartRate, 1, 0x02})
+
+	for i := 0; i <= 20; i++ {
+		ts := timestamp + uint32(i*30)
+		if i <= 10 {
+			data.WriteByte(0x01)
+			_ = binary.Write(&data, binary.LittleEndian, ts)
+		} else {
+			data.WriteByte(0x80 | 2<<5 | byte(ts&0x1F))
+		}
+		_ = binary.Write(&data, binary.LittleEndian, uint32(i*100*fitDistanceScale))
+		data.WriteByte(byte(150 + i%2*10))
+		if i <= 10 {
+			data.WriteByte(0xAA)
+		}
+	}
+
+	header := bytes.NewBuffer([]byte{fitMinHeaderSize, 0x10, 0, 0})
+	_ = binary.Write(header, binary.LittleEndian, uint32(data.Len()))
+	header.WriteString(".FIT")
+	return append(header.Bytes(), append(data.Bytes(), 0, 0)...)
+}
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     activity.Format
+		file       []byte
+		want       activity.Activity
+		distanceKm float64
+		wantErr    error
+	}{
+		{
+			name:       "should parse gpx using positions",
+			format:     activity.FormatGPX,
+			file:       []byte(gpxFixture()),
+			distanceKm: 2.25,
+			want: activity.Activity{
+				Duration:     11*time.Minute + 15*time.Second,
+				HeartRateAvg: 145,
+				Splits: []activity.Split{
+					{DistanceKm: 1, Elapsed: 5 * time.Minute},
+					{DistanceKm: 2, Elapsed: 10 * time.Minute},
+				},
+			},
+		},
+		{
+			name:       "should parse tcx using recorded distance",
+			format:     activity.FormatTCX,
+			file:       []byte(tcxFixture()),
+			distanceKm: 2.5,
+			want: activity.Activity{
+				Duration:     10 * time.Minute,
+				HeartRateAvg: 150,
+				Splits: []activity.Split{
+					{DistanceKm: 1, Elapsed: 4 * time.Minute},
+					{DistanceKm: 2, Elapsed: 8 * time.Minute},
+				},
+			},
+		},
+		{
+			name:       "should parse fit records",
+			format:     activity.FormatFIT,
+			file:       fitFixture(),
+			distanceKm: 2,
+			want: activity.Activity{
+				Duration:     10 * time.Minute,
+				HeartRateAvg: 155,
+				Splits: []activity.Split{
+					{DistanceKm: 1, Elapsed: 5 * time.Minute},
+					{DistanceKm: 2, Elapsed: 10 * time.Minute},
+				},
+			},
+		},
+		{
+			name:    "should reject unsupported format",
+			format:  "csv",
+			file:    []byte("a,b"),
+			wantErr: activity.ErrUnsupportedFormat,
+		},
+		{
+			name:    "should reject malformed xml",
+			format:  activity.FormatGPX,
+			file:    []byte("<gpx><trk>"),
+			wantErr: activity.ErrInvalidFile,
+		},
+		{
+			name:    "should reject file without fit signature",
+			format:  activity.FormatFIT,
+			file:    []byte("not a fit file at all"),
+			wantErr: activity.ErrInvalidFile,
+		},
+		{
+			name:    "should reject truncated fit file",
+			format:  activity.FormatFIT,
+			file:    fitFixture()[:40],
+			wantErr: activity.ErrInvalidFile,
+		},
+		{
+			name:    "should reject activity without samples",
+			format:  activity.FormatGPX,
+			file:    []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`),
+			wantErr: activity.ErrEmptyActivity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParser().Parse(context.Background(), tt.format, bytes.NewReader(tt.file))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.distanceKm, got.DistanceKm, 0.001)
+			assert.Equal(t, tt.want.Duration, got.Duration)
+			assert.Equal(t, tt.want.HeartRateAvg, got.HeartRateAvg)
+			require.Len(t, got.Splits, len(tt.want.Splits))
+			for i, split := range tt.want.Splits {
+				assert.Equal(t, split.DistanceKm, got.Splits[i].DistanceKm)
+				assert.InDelta(t, split.Elapsed.Seconds(), got.Splits[i].Elapsed.Seconds(), 1)
+			}
+		})
+	}
+}
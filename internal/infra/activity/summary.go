@@ -0,0 +1,100 @@
+package activity
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+)
+
+const earthRadiusM = 6371000
+
+// sample is a single timed record of an activity file.
+// Distance is the cumulative distance reported by the device, used in preference to the position.
+type sample struct {
+	at          time.Time
+	distanceM   float64
+	hasDistance bool
+	lat, lon    float64
+	hasPosition bool
+	heartRate   int
+}
+
+// summarise derives the duration, distance, average heart rate and whole kilometre splits of the samples
+func summarise(samples []sample) (activity.Activity, error) {
+	if len(samples) < 2 {
+		return activity.Activity{}, activity.ErrEmptyActivity
+	}
+
+	start := samples[0].at
+	var (
+		result        activity.Activity
+		distanceM     float64
+		previousAt    = start
+		previousDistM float64
+		lastLat       float64
+		lastLon       float64
+		hasLastPos    bool
+		heartRateSum  int
+		heartRateN    int
+	)
+	nextMarkerKm := 1
+	for _, s := range samples {
+		if s.at.Before(previousAt) {
+			return activity.Activity{}, errInvalid("samples are not in chronological order")
+		}
+		switch {
+		case s.hasDistance:
+			distanceM = math.Max(distanceM, s.distanceM)
+		case s.hasPosition && hasLastPos:
+			distanceM += haversineM(lastLat, lastLon, s.lat, s.lon)
+		}
+		if s.hasPosition {
+			lastLat, lastLon, hasLastPos = s.lat, s.lon, true
+		}
+		if s.heartRate > 0 {
+			heartRateSum += s.heartRate
+			heartRateN++
+		}
+
+		//interpolate the time of every kilometre marker crossed since the previous sample
+		for distanceM >= float64(nextMarkerKm)*1000 {
+			ratio := (float64(nextMarkerKm)*1000 - previousDistM) / (distanceM - previousDistM)
+			elapsed := previousAt.Add(time.Duration(ratio * float64(s.at.Sub(previousAt)))).Sub(start).Round(time.Millisecond)
+			if elapsed > lastSplitElapsed(result.Splits) {
+				result.Splits = append(result.Splits, activity.Split{DistanceKm: float64(nextMarkerKm), Elapsed: elapsed})
+			}
+			nextMarkerKm++
+		}
+
+		previousAt = s.at
+		previousDistM = distanceM
+	}
+
+	result.Duration = samples[len(samples)-1].at.Sub(start).Round(time.Millisecond)
+	if result.Duration <= 0 {
+		return activity.Activity{}, activity.ErrEmptyActivity
+	}
+	result.DistanceKm = distanceM / 1000
+	if heartRateN > 0 {
+		result.HeartRateAvg = int(math.Round(float64(heartRateSum) / float64(heartRateN)))
+	}
+	return result, nil
+}
+
+func lastSplitElapsed(splits []activity.Split) time.Duration {
+	if len(splits) == 0 {
+		return 0
+	}
+	return splits[len(splits)-1].Elapsed
+}
+
+// haversineM returns the great-circle distance in metres between two positions in degrees
+func haversineM(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(a))
+}
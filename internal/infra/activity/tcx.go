@@ -0,0 +1,55 @@
+package activity
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// tcxFile maps the track points of a Garmin Training Center XML file
+type tcxFile struct {
+	Activities []struct {
+		Laps []struct {
+			Tracks []struct {
+				Points []tcxPoint `xml:"Trackpoint"`
+			} `xml:"Track"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+type tcxPoint struct {
+	Time           time.Time `xml:"Time"`
+	Lat            *float64  `xml:"Position>LatitudeDegrees"`
+	Lon            *float64  `xml:"Position>LongitudeDegrees"`
+	DistanceMeters *float64  `xml:"DistanceMeters"`
+	HeartRate      int       `xml:"HeartRateBpm>Value"`
+}
+
+func parseTCX(r io.Reader) ([]sample, error) {
+	var file tcxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errInvalid(err.Error())
+	}
+
+	var samples []sample
+	for _, a := range file.Activities {
+		for _, lap := range a.Laps {
+			for _, track := range lap.Tracks {
+				for _, p := range track.Points {
+					if p.Time.IsZero() {
+						continue
+					}
+					s := sample{at: p.Time, heartRate: p.HeartRate}
+					if p.DistanceMeters != nil {
+						s.distanceM, s.hasDistance = *p.DistanceMeters, true
+					}
+					if p.Lat != nil && p.Lon != nil {
+						s.lat, s.lon, s.hasPosition = *p.Lat, *p.Lon, true
+					}
+					samples = append(samples, s)
+				}
+			}
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/sha256"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+)
+
+// APIKeys maps API keys to the principals holding them. Only the SHA-256 hashes of the keys are kept, so the
+// lookup time does not depend on how much of a key matches.
+type APIKeys struct {
+	principals map[[sha256.Size]byte]auth.Principal
+}
+
+// NewAPIKeys Constructor
+func NewAPIKeys(keys map[string]auth.Principal) APIKeys {
+	principals := make(map[[sha256.Size]byte]auth.Principal, len(keys))
+	for key, principal := range keys {
+		principals[sha256.Sum256([]byte(key))] = principal
+	}
+	return APIKeys{principals: principals}
+}
+
+// Authenticate returns the principal holding the key
+func (k APIKeys) Authenticate(key string) (auth.Principal, error) {
+	principal, ok := k.principals[sha256.Sum256([]byte(key))]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+	return principal, nil
+}
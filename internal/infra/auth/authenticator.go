@@ -0,0 +1,32 @@
+// Package auth implements the auth Authenticator port with API keys and HMAC signed JWTs
+package auth
+
+import (
+	"context"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+)
+
+// Authenticator Implements the auth Authenticator port, verifying API keys with the APIKeys and bearer tokens
+// with the JWT
+type Authenticator struct {
+	apiKeys APIKeys
+	jwt     *JWT
+}
+
+// NewAuthenticator Constructor. A nil jwt rejects every bearer token.
+func NewAuthenticator(apiKeys APIKeys, jwt *JWT) Authenticator {
+	return Authenticator{apiKeys: apiKeys, jwt: jwt}
+}
+
+// Authenticate returns the principal of the API key, or of the bearer token when no API key is provided
+func (a Authenticator) Authenticate(_ context.Context, credentials auth.Credentials) (auth.Principal, error) {
+	switch {
+	case credentials.APIKey != "":
+		return a.apiKeys.Authenticate(credentials.APIKey)
+	case credentials.BearerToken != "" && a.jwt != nil:
+		return a.jwt.Authenticate(credentials.BearerToken)
+	default:
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	organiser := auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser}
+	runner := auth.Principal{Subject: "anna", Role: auth.RoleRunner, RunnerID: uuid.New()}
+	jwt := NewJWT(secret)
+	token, err := jwt.Issue(runner, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	apiKeys := NewAPIKeys(map[string]auth.Principal{"organiser-key": organiser})
+
+	tests := []struct {
+		name          string
+		authenticator Authenticator
+		credentials   auth.Credentials
+		want          auth.Principal
+		wantErr       error
+	}{
+		{name: "api key", authenticator: NewAuthenticator(apiKeys, jwt), credentials: auth.Credentials{APIKey: "organiser-key"}, want: organiser},
+		{name: "unknown api key", authenticator: NewAuthenticator(apiKeys, jwt), credentials: auth.Credentials{APIKey: "organiser-key2"}, wantErr: auth.ErrInvalidCredentials},
+		{name: "api key over bearer token", authenticator: NewAuthenticator(apiKeys, jwt), credentials: auth.Credentials{APIKey: "organiser-key", BearerToken: token}, want: organiser},
+		{name: "bearer token", authenticator: NewAuthenticator(apiKeys, jwt), credentials: auth.Credentials{BearerToken: token}, want: runner},
+		{name: "bearer token without jwt secret", authenticator: NewAuthenticator(apiKeys, nil), credentials: auth.Credentials{BearerToken: token}, wantErr: auth.ErrInvalidCredentials},
+		{name: "no credentials", authenticator: NewAuthenticator(apiKeys, jwt), wantErr: auth.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.authenticator.Authenticate(context.Background(), tt.credentials)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+)
+
+// JWT verifies and issues JSON Web Tokens signed with HMAC SHA-256 (HS256).
+// The subject, the role and the runner id of the principal are carried by the sub, role and runner_id claims,
+// and every token must expire.
+type JWT struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewJWT Constructor
+func NewJWT(secret []byte) *JWT {
+	return &JWT{secret: secret, now: time.Now}
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	RunnerID  string `json:"runner_id,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf,omitempty"`
+}
+
+const algorithmHS256 = "HS256"
+
+// Authenticate returns the principal of a valid token
+func (j *JWT) Authenticate(token string) (auth.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return auth.Principal{}, fmt.Errorf("%w: malformed token", auth.ErrInvalidCredentials)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != algorithmHS256 {
+		return auth.Principal{}, fmt.Errorf("%w: token must be signed with %s", auth.ErrInvalidCredentials, algorithmHS256)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, j.sign(parts[0]+"."+parts[1])) {
+		return auth.Principal{}, fmt.Errorf("%w: invalid token signature", auth.ErrInvalidCredentials)
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return auth.Principal{}, fmt.Errorf("%w: malformed token claims", auth.ErrInvalidCredentials)
+	}
+	now := j.now().Unix()
+	if claims.ExpiresAt == 0 || now >= claims.ExpiresAt {
+		return auth.Principal{}, fmt.Errorf("%w: token is expired", auth.ErrInvalidCredentials)
+	}
+	if now < claims.NotBefore {
+		return auth.Principal{}, fmt.Errorf("%w: token is not valid yet", auth.ErrInvalidCredentials)
+	}
+	if claims.Subject == "" {
+		return auth.Principal{}, fmt.Errorf("%w: token has no subject", auth.ErrInvalidCredentials)
+	}
+
+	principal := auth.Principal{Subject: claims.Subject, Role: auth.Role(claims.Role)}
+	if claims.RunnerID != "" {
+		principal.RunnerID, err = uuid.Parse(claims.RunnerID)
+		if err != nil {
+			return auth.Principal{}, fmt.Errorf("%w: invalid runner_id claim", auth.ErrInvalidCredentials)
+		}
+	}
+	return principal, nil
+}
+
+// Issue returns a token for the principal that expires at the provided time
+func (j *JWT) Issue(principal auth.Principal, expiresAt time.Time) (string, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: algorithmHS256, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims := jwtClaims{Subject: principal.Subject, Role: string(principal.Role), ExpiresAt: expiresAt.Unix()}
+	if principal.RunnerID != uuid.Nil {
+		claims.RunnerID = principal.RunnerID.String()
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(j.sign(signed)), nil
+}
+
+func (j *JWT) sign(signed string) []byte {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
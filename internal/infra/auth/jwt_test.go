@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestJWT_Authenticate(t *testing.T) {
+	now := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	issuer := NewJWT(secret)
+	runner := auth.Principal{Subject: "anna", Role: auth.RoleRunner, RunnerID: uuid.New()}
+
+	valid, err := issuer.Issue(runner, now.Add(time.Hour))
+	require.NoError(t, err)
+	expired, err := issuer.Issue(runner, now)
+	require.NoError(t, err)
+	forged, err := NewJWT([]byte("another secret of at least 32 bytes")).Issue(runner, now.Add(time.Hour))
+	require.NoError(t, err)
+	parts := strings.Split(valid, ".")
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	noSubject, err := issuer.Issue(auth.Principal{Role: auth.RoleAdmin}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    auth.Principal
+		wantErr error
+	}{
+		{name: "valid token", token: valid, want: runner},
+		{name: "expired token", token: expired, wantErr: auth.ErrInvalidCredentials},
+		{name: "token signed with another secret", token: forged, wantErr: auth.ErrInvalidCredentials},
+		{name: "unsigned token", token: unsigned, wantErr: auth.ErrInvalidCredentials},
+		{name: "token without subject", token: noSubject, wantErr: auth.ErrInvalidCredentials},
+		{name: "malformed token", token: "not-a-token", wantErr: auth.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewJWT(secret)
+			verifier.now = func() time.Time { return now }
+
+			got, err := verifier.Authenticate(tt.token)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
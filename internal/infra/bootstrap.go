@@ -2,34 +2,233 @@
 package infra
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	appAuth "github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	appCourse "github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	appLogging "github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	appMetrics "github.com/pkritiotis/go-clean-architecture-example/internal/app/metrics"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	appTiming "github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	activityparser "github.com/pkritiotis/go-clean-architecture-example/internal/infra/activity"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/config"
+	coursefiles "github.com/pkritiotis/go-clean-architecture-example/internal/infra/course"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/metrics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/notification/async"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/notification/console"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/notification/noop"
+	bibmemrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/bib"
 	racememrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/race"
+	registrationmemrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/registration"
 	runnermemrep "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/runner"
+	timingmemrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/timing"
+	bibmysqlrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/migrations"
+	racemysqlrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/race"
+	registrationmysqlrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/registration"
+	runnermysqlrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/runner"
+	timingmysqlrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite"
+	bibsqliterepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/bib"
+	racesqliterepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/race"
+	registrationsqliterepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/registration"
+	runnersqliterepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/runner"
+	timingsqliterepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/timing"
+	timingfiles "github.com/pkritiotis/go-clean-architecture-example/internal/infra/timing"
 )
 
 // Services contains the exposed services of interface adapters
 type Services struct {
-	NotificationService notification.Service
-	RunnerRepository    runner.Repository
-	RaceRepository      race.Repository
-	Server              *http.Server
+	NotificationService    notification.Service
+	ActivityParser         activity.Parser
+	ReadParser             appTiming.ReadParser
+	RouteCodec             appCourse.RouteCodec
+	Authenticator          appAuth.Authenticator
+	RunnerRepository       runner.Repository
+	RaceRepository         race.Repository
+	CourseRepository       race.CourseRepository
+	RegistrationRepository registration.Repository
+	BibRepository          bib.Repository
+	TimingRepository       timing.Repository
+	MetricsRecorder        appMetrics.Recorder
+	// Metrics instrument the providers and the HTTP server, nil when the metrics are disabled
+	Metrics *metrics.Metrics
+	Server  *http.Server
+
+	// closers release the providers on shutdown, in reverse order of creation
+	closers []func(ctx context.Context) error
+}
+
+// NewLogger creates the logger writing to w in the configured format, from the configured level
+func NewLogger(cfg config.Logging, w io.Writer) (*logging.Logger, error) {
+	level, err := appLogging.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewLogger(w, cfg.Format, level)
+}
+
+// NewInfraProviders Instantiates the infra services selected by the provided configuration, reporting their
+// operational events to the logger
+func NewInfraProviders(ctx context.Context, cfg config.Config, logger appLogging.Logger) (Services, error) {
+	if err := cfg.Validate(); err != nil {
+		return Services{}, err
+	}
+
+	services := Services{ActivityParser: activityparser.NewParser(), ReadParser: timingfiles.NewParser(), RouteCodec: coursefiles.NewCodec(), Authenticator: newAuthenticator(cfg.Auth), MetricsRecorder: appMetrics.Discard}
+	if cfg.Metrics.Enabled {
+		services.Metrics = metrics.New()
+		services.MetricsRecorder = services.Metrics
+	}
+
+	switch cfg.Notifier.Type {
+	case config.NotifierNone:
+		services.NotificationService = noop.NewNotificationService()
+	default:
+		services.NotificationService = console.NewNotificationService(logger)
+	}
+	//Count the failed deliveries, and the notifications the queue rejects when they are sent in the background
+	if services.Metrics != nil {
+		services.NotificationService = metrics.NewNotificationService(services.NotificationService, services.Metrics)
+	}
+	if cfg.Notifier.QueueSize > 0 {
+		dispatcher := async.NewNotificationService(services.NotificationService, cfg.Notifier.QueueSize, logger)
+		services.NotificationService = dispatcher
+		services.closers = append(services.closers, dispatcher.Close)
+		if services.Metrics != nil {
+			services.NotificationService = metrics.NewNotificationService(dispatcher, services.Metrics)
+		}
+	}
+
+	switch cfg.Storage.Backend {
+	case config.BackendMySQL:
+		db, err := openMySQL(ctx, cfg.Storage.DSN)
+		if err != nil {
+			return Services{}, err
+		}
+		services.closers = append(services.closers, func(context.Context) error { return db.Close() })
+		raceRepo := racemysqlrepo.NewRepository(db)
+		services.RaceRepository = raceRepo
+		services.CourseRepository = raceRepo
+		services.RunnerRepository = runnermysqlrepo.NewRepository(db)
+		services.RegistrationRepository = registrationmysqlrepo.NewRepository(db)
+		services.BibRepository = bibmysqlrepo.NewRepository(db)
+		services.TimingRepository = timingmysqlrepo.NewRepository(db)
+	case config.BackendSQLite:
+		db, err := sqlite.Open(ctx, cfg.Storage.DSN)
+		if err != nil {
+			return Services{}, err
+		}
+		services.closers = append(services.closers, func(context.Context) error { return db.Close() })
+		raceRepo := racesqliterepo.NewRepository(db)
+		services.RaceRepository = raceRepo
+		services.CourseRepository = raceRepo
+		services.RunnerRepository = runnersqliterepo.NewRepository(db)
+		services.RegistrationRepository = registrationsqliterepo.NewRepository(db)
+		services.BibRepository = bibsqliterepo.NewRepository(db)
+		services.TimingRepository = timingsqliterepo.NewRepository(db)
+	default:
+		raceRepo := racememrepo.NewRepository()
+		services.RaceRepository = raceRepo
+		services.CourseRepository = raceRepo
+		services.RunnerRepository = runnermemrep.NewRepository()
+		services.RegistrationRepository = registrationmemrepo.NewRepository()
+		services.BibRepository = bibmemrepo.NewRepository()
+		services.TimingRepository = timingmemrepo.NewRepository()
+	}
+	if services.Metrics != nil {
+		services.RunnerRepository = metrics.NewRunnerRepository(services.RunnerRepository, services.Metrics)
+		services.RaceRepository = metrics.NewRaceRepository(services.RaceRepository, services.Metrics)
+	}
+
+	return services, nil
+}
+
+// Shutdown flushes the pending notifications and closes the storage connections, giving up on the pending
+// notifications when the context is done
+func (s Services) Shutdown(ctx context.Context) error {
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i](ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// newAuthenticator creates the authenticator of the configured API keys and JWT secret.
+// The configuration is validated, so the runner ids of the keys are known to parse.
+func newAuthenticator(cfg config.Auth) auth.Authenticator {
+	keys := make(map[string]appAuth.Principal, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		principal := appAuth.Principal{Subject: key.Subject, Role: appAuth.Role(key.Role)}
+		if key.RunnerID != "" {
+			principal.RunnerID = uuid.MustParse(key.RunnerID)
+		}
+		keys[key.Key] = principal
+	}
+	var jwt *auth.JWT
+	if cfg.JWTSecret != "" {
+		jwt = auth.NewJWT([]byte(cfg.JWTSecret))
+	}
+	return auth.NewAuthenticator(auth.NewAPIKeys(keys), jwt)
+}
+
+// openMySQL opens a MySQL connection pool and applies the pending schema migrations
+func openMySQL(ctx context.Context, dsn string) (*sql.DB, error) {
+	mysqlConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mysql dsn: %w", err)
+	}
+	//The repositories scan DATETIME columns into time.Time
+	mysqlConfig.ParseTime = true
+
+	db, err := sql.Open("mysql", mysqlConfig.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("opening mysql connection: %w", err)
+	}
+	if err := migrations.Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating mysql schema: %w", err)
+	}
+	return db, nil
 }
 
-// NewInfraProviders Instantiates the infra services
-func NewInfraProviders() Services {
-	return Services{
-		NotificationService: console.NewNotificationService(),
-		RaceRepository:      racememrepo.NewRepository(),
-		RunnerRepository:    runnermemrep.NewRepository(),
+// NewHTTPServer creates a new server, instrumented and serving the metrics unless they are nil
+func NewHTTPServer(appServices app.Services, cfg config.HTTP, authCfg config.Auth, logger appLogging.Logger, m *metrics.Metrics) *http.Server {
+	httpCfg := http.Config{
+		RequestTimeout:    time.Duration(cfg.RequestTimeout),
+		AuthEnabled:       authCfg.Enabled,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout),
+		ReadTimeout:       time.Duration(cfg.ReadTimeout),
+		WriteTimeout:      time.Duration(cfg.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.IdleTimeout),
+		ShutdownTimeout:   time.Duration(cfg.ShutdownTimeout),
+		MaxBodyBytes:      int64(cfg.MaxBodyBytes),
+		Logger:            logger,
+	}
+	if m != nil {
+		httpCfg.Metrics = m
 	}
+	return http.NewServer(appServices, httpCfg)
 }
 
-// NewHTTPServer creates a new server
-func NewHTTPServer(appServices app.Services) *http.Server {
-	return http.NewServer(appServices)
+// NewTimingDropFolder creates the folder the timing system drops its read files into
+func NewTimingDropFolder(appServices app.Services, cfg config.Timing, logger appLogging.Logger) timingfiles.DropFolder {
+	return timingfiles.NewDropFolder(cfg.DropDir, time.Duration(cfg.DropInterval), appServices.TimingService, logger)
 }
@@ -0,0 +1,425 @@
+// Package config contains the configuration of the infrastructure providers
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Storage backends
+const (
+	BackendMemory = "memory"
+	BackendMySQL  = "mysql"
+	BackendSQLite = "sqlite"
+)
+
+// Notifier types
+const (
+	NotifierConsole = "console"
+	NotifierNone    = "none"
+)
+
+// Supported log formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// Supported log levels
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Environment variables that override the configuration
+const (
+	EnvConfigFile            = "RACETRACKER_CONFIG_FILE"
+	EnvHTTPAddress           = "RACETRACKER_HTTP_ADDRESS"
+	EnvHTTPRequestTimeout    = "RACETRACKER_HTTP_REQUEST_TIMEOUT"
+	EnvHTTPReadHeaderTimeout = "RACETRACKER_HTTP_READ_HEADER_TIMEOUT"
+	EnvHTTPReadTimeout       = "RACETRACKER_HTTP_READ_TIMEOUT"
+	EnvHTTPWriteTimeout      = "RACETRACKER_HTTP_WRITE_TIMEOUT"
+	EnvHTTPIdleTimeout       = "RACETRACKER_HTTP_IDLE_TIMEOUT"
+	EnvHTTPShutdownTimeout   = "RACETRACKER_HTTP_SHUTDOWN_TIMEOUT"
+	EnvHTTPMaxBodyBytes      = "RACETRACKER_HTTP_MAX_BODY_BYTES"
+	EnvStorageBackend        = "RACETRACKER_STORAGE_BACKEND"
+	EnvStorageDSN            = "RACETRACKER_STORAGE_DSN"
+	EnvNotifier              = "RACETRACKER_NOTIFIER"
+	EnvNotifierQueueSize     = "RACETRACKER_NOTIFIER_QUEUE_SIZE"
+	EnvTimingDropDir         = "RACETRACKER_TIMING_DROP_DIR"
+	EnvTimingDropInterval    = "RACETRACKER_TIMING_DROP_INTERVAL"
+	EnvAuthEnabled           = "RACETRACKER_AUTH_ENABLED"
+	EnvAuthJWTSecret         = "RACETRACKER_AUTH_JWT_SECRET"
+	EnvLogLevel              = "RACETRACKER_LOG_LEVEL"
+	EnvLogFormat             = "RACETRACKER_LOG_FORMAT"
+	EnvMetricsEnabled        = "RACETRACKER_METRICS_ENABLED"
+)
+
+// MinJWTSecretLength is the minimum length in bytes of the secret that signs the bearer tokens
+const MinJWTSecretLength = 32
+
+// Config contains the settings used to select and configure the infrastructure providers
+type Config struct {
+	HTTP     HTTP     `yaml:"http" json:"http"`
+	Storage  Storage  `yaml:"storage" json:"storage"`
+	Notifier Notifier `yaml:"notifier" json:"notifier"`
+	Timing   Timing   `yaml:"timing" json:"timing"`
+	Auth     Auth     `yaml:"auth" json:"auth"`
+	Logging  Logging  `yaml:"logging" json:"logging"`
+	Metrics  Metrics  `yaml:"metrics" json:"metrics"`
+}
+
+// HTTP contains the settings of the HTTP server
+type HTTP struct {
+	Address string `yaml:"address" json:"address"`
+	// RequestTimeout bounds the time a request may spend in the application services, zero disables it
+	RequestTimeout Duration `yaml:"request_timeout" json:"request_timeout"`
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound the connections, zero disables them
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" json:"read_header_timeout"`
+	ReadTimeout       Duration `yaml:"read_timeout" json:"read_timeout"`
+	WriteTimeout      Duration `yaml:"write_timeout" json:"write_timeout"`
+	IdleTimeout       Duration `yaml:"idle_timeout" json:"idle_timeout"`
+	// ShutdownTimeout bounds the time in-flight requests and pending notifications are given on shutdown,
+	// zero waits for them
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+	// MaxBodyBytes bounds the size of the request bodies other than multipart uploads, zero disables it
+	MaxBodyBytes int `yaml:"max_body_bytes" json:"max_body_bytes"`
+}
+
+// Storage contains the settings of the storage backend
+type Storage struct {
+	Backend string `yaml:"backend" json:"backend"`
+	DSN     string `yaml:"dsn" json:"dsn"`
+}
+
+// Notifier contains the settings of the notification service
+type Notifier struct {
+	Type string `yaml:"type" json:"type"`
+	// QueueSize is the number of notifications waiting to be sent in the background, zero sends them synchronously
+	QueueSize int `yaml:"queue_size" json:"queue_size"`
+}
+
+// Timing contains the settings of the chip timing integration
+type Timing struct {
+	// DropDir is the folder the timing system drops its read files into, empty disables it
+	DropDir string `yaml:"drop_dir" json:"drop_dir"`
+	// DropInterval is the time between two scans of the drop folder
+	DropInterval Duration `yaml:"drop_interval" json:"drop_interval"`
+}
+
+// Auth contains the settings of the authentication of the HTTP API
+type Auth struct {
+	// Enabled requires the callers of the API to authenticate, and is on by default. When disabled credentials are
+	// ignored and every request is anonymous, so only the reads open to everyone succeed.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// APIKeys are the keys accepted in the X-API-Key header
+	APIKeys []APIKey `yaml:"api_keys" json:"api_keys"`
+	// JWTSecret verifies the HS256 signed bearer tokens, empty disables them
+	JWTSecret string `yaml:"jwt_secret" json:"jwt_secret"`
+}
+
+// Logging contains the settings of the logs
+type Logging struct {
+	// Level is the minimum level of the logged events at startup, one of debug, info, warn or error
+	Level string `yaml:"level" json:"level"`
+	// Format is the output format of the logs, json or text
+	Format string `yaml:"format" json:"format"`
+}
+
+// Metrics contains the settings of the Prometheus metrics
+type Metrics struct {
+	// Enabled instruments the service and serves its metrics on /metrics
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// APIKey contains an API key and the identity of its holder.
+// Role is one of runner, organiser or admin, and RunnerID is required for the runner role.
+type APIKey struct {
+	Key      string `yaml:"key" json:"key"`
+	Subject  string `yaml:"subject" json:"subject"`
+	Role     string `yaml:"role" json:"role"`
+	RunnerID string `yaml:"runner_id" json:"runner_id"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Address:           ":8080",
+			RequestTimeout:    Duration(30 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			ReadTimeout:       Duration(time.Minute),
+			WriteTimeout:      Duration(time.Minute),
+			IdleTimeout:       Duration(2 * time.Minute),
+			ShutdownTimeout:   Duration(15 * time.Second),
+			MaxBodyBytes:      1 << 20,
+		},
+		Storage:  Storage{Backend: BackendMemory},
+		Notifier: Notifier{Type: NotifierConsole, QueueSize: 256},
+		Timing:   Timing{DropInterval: Duration(5 * time.Second)},
+		Logging:  Logging{Level: LogLevelInfo, Format: LogFormatText},
+		Metrics:  Metrics{Enabled: true},
+		Auth:     Auth{Enabled: true},
+	}
+}
+
+// Load builds the configuration from the defaults, the optional configuration file
+// referenced by RACETRACKER_CONFIG_FILE and the environment variable overrides, and validates it
+func Load() (Config, error) {
+	return load(os.LookupEnv, os.ReadFile)
+}
+
+func load(lookupEnv func(string) (string, bool), readFile func(string) ([]byte, error)) (Config, error) {
+	cfg := Default()
+
+	if path, ok := lookupEnv(EnvConfigFile); ok && path != "" {
+		content, err := readFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := decode(path, content, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	overrides := []struct {
+		env    string
+		target encoding.TextUnmarshaler
+	}{
+		{EnvHTTPAddress, (*stringValue)(&cfg.HTTP.Address)},
+		{EnvHTTPRequestTimeout, &cfg.HTTP.RequestTimeout},
+		{EnvHTTPReadHeaderTimeout, &cfg.HTTP.ReadHeaderTimeout},
+		{EnvHTTPReadTimeout, &cfg.HTTP.ReadTimeout},
+		{EnvHTTPWriteTimeout, &cfg.HTTP.WriteTimeout},
+		{EnvHTTPIdleTimeout, &cfg.HTTP.IdleTimeout},
+		{EnvHTTPShutdownTimeout, &cfg.HTTP.ShutdownTimeout},
+		{EnvHTTPMaxBodyBytes, (*intValue)(&cfg.HTTP.MaxBodyBytes)},
+		{EnvStorageBackend, (*stringValue)(&cfg.Storage.Backend)},
+		{EnvStorageDSN, (*stringValue)(&cfg.Storage.DSN)},
+		{EnvNotifier, (*stringValue)(&cfg.Notifier.Type)},
+		{EnvNotifierQueueSize, (*intValue)(&cfg.Notifier.QueueSize)},
+		{EnvTimingDropDir, (*stringValue)(&cfg.Timing.DropDir)},
+		{EnvTimingDropInterval, &cfg.Timing.DropInterval},
+		{EnvAuthEnabled, (*boolValue)(&cfg.Auth.Enabled)},
+		{EnvAuthJWTSecret, (*stringValue)(&cfg.Auth.JWTSecret)},
+		{EnvLogLevel, (*stringValue)(&cfg.Logging.Level)},
+		{EnvLogFormat, (*stringValue)(&cfg.Logging.Format)},
+		{EnvMetricsEnabled, (*boolValue)(&cfg.Metrics.Enabled)},
+	}
+	var errs []error
+	for _, o := range overrides {
+		if value, ok := lookupEnv(o.env); ok {
+			if err := o.target.UnmarshalText([]byte(value)); err != nil {
+				errs = append(errs, fmt.Errorf("parsing %s: %w", o.env, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func decode(path string, content []byte, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err := decoder.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the configuration and reports all problems at once
+func (c Config) Validate() error {
+	var problems []string
+
+	if c.HTTP.Address == "" {
+		problems = append(problems, "http.address cannot be empty")
+	}
+	timeouts := []struct {
+		name    string
+		timeout Duration
+	}{
+		{"http.request_timeout", c.HTTP.RequestTimeout},
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+	}
+	for _, t := range timeouts {
+		if t.timeout < 0 {
+			problems = append(problems, t.name+" cannot be negative")
+		}
+	}
+	if c.HTTP.WriteTimeout > 0 && c.HTTP.WriteTimeout < c.HTTP.RequestTimeout {
+		problems = append(problems, "http.write_timeout cannot be shorter than http.request_timeout")
+	}
+	if c.HTTP.MaxBodyBytes < 0 {
+		problems = append(problems, "http.max_body_bytes cannot be negative")
+	}
+
+	switch c.Storage.Backend {
+	case BackendMemory:
+	case BackendMySQL, BackendSQLite:
+		if c.Storage.DSN == "" {
+			problems = append(problems, fmt.Sprintf("storage.dsn is required for storage backend %q", c.Storage.Backend))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("storage.backend %q is not one of %q, %q, %q", c.Storage.Backend, BackendMemory, BackendMySQL, BackendSQLite))
+	}
+
+	switch c.Notifier.Type {
+	case NotifierConsole, NotifierNone:
+	default:
+		problems = append(problems, fmt.Sprintf("notifier.type %q is not one of %q, %q", c.Notifier.Type, NotifierConsole, NotifierNone))
+	}
+	if c.Notifier.QueueSize < 0 {
+		problems = append(problems, "notifier.queue_size cannot be negative")
+	}
+
+	if c.Timing.DropDir != "" && c.Timing.DropInterval <= 0 {
+		problems = append(problems, "timing.drop_interval must be positive when timing.drop_dir is set")
+	}
+
+	problems = append(problems, c.Auth.validate()...)
+
+	switch c.Logging.Level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		problems = append(problems, fmt.Sprintf("logging.level %q is not one of %q, %q, %q, %q", c.Logging.Level, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError))
+	}
+	switch c.Logging.Format {
+	case LogFormatJSON, LogFormatText:
+	default:
+		problems = append(problems, fmt.Sprintf("logging.format %q is not one of %q, %q", c.Logging.Format, LogFormatJSON, LogFormatText))
+	}
+
+	if len(problems) > 0 {
+		return ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (a Auth) validate() []string {
+	var problems []string
+
+	if a.Enabled && len(a.APIKeys) == 0 && a.JWTSecret == "" {
+		problems = append(problems, "auth.api_keys or auth.jwt_secret is required when auth is enabled")
+	}
+	if a.JWTSecret != "" && len(a.JWTSecret) < MinJWTSecretLength {
+		problems = append(problems, fmt.Sprintf("auth.jwt_secret must be at least %d bytes", MinJWTSecretLength))
+	}
+
+	keys := make(map[string]bool)
+	for i, key := range a.APIKeys {
+		switch {
+		case key.Key == "":
+			problems = append(problems, fmt.Sprintf("auth.api_keys[%d].key cannot be empty", i))
+		case keys[key.Key]:
+			problems = append(problems, fmt.Sprintf("auth.api_keys[%d].key is a duplicate", i))
+		}
+		keys[key.Key] = true
+		if key.Subject == "" {
+			problems = append(problems, fmt.Sprintf("auth.api_keys[%d].subject cannot be empty", i))
+		}
+		switch key.Role {
+		case "runner":
+			if _, err := uuid.Parse(key.RunnerID); err != nil {
+				problems = append(problems, fmt.Sprintf("auth.api_keys[%d].runner_id must be a runner id for the runner role", i))
+			}
+		case "organiser", "admin":
+		default:
+			problems = append(problems, fmt.Sprintf("auth.api_keys[%d].role %q is not one of %q, %q, %q", i, key.Role, "runner", "organiser", "admin"))
+		}
+	}
+	return problems
+}
+
+// Duration is a time.Duration configured with strings such as "30s" or "1m30s"
+type Duration time.Duration
+
+// UnmarshalText parses the duration from its string representation
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText returns the string representation of the duration
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// stringValue lets plain string settings be overridden like the other settings
+type stringValue string
+
+func (s *stringValue) UnmarshalText(text []byte) error {
+	*s = stringValue(text)
+	return nil
+}
+
+// intValue lets integer settings be overridden like the other settings
+type intValue int
+
+func (i *intValue) UnmarshalText(text []byte) error {
+	parsed, err := strconv.Atoi(string(text))
+	if err != nil {
+		return err
+	}
+	*i = intValue(parsed)
+	return nil
+}
+
+// boolValue lets boolean settings be overridden with values such as "true" or "0"
+type boolValue bool
+
+func (b *boolValue) UnmarshalText(text []byte) error {
+	parsed, err := strconv.ParseBool(string(text))
+	if err != nil {
+		return err
+	}
+	*b = boolValue(parsed)
+	return nil
+}
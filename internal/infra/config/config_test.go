@@ -0,0 +1,373 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+func filesFrom(files map[string]string) func(string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, errors.New("file not found")
+		}
+		return []byte(content), nil
+	}
+}
+
+// testJWTSecret is a valid JWT secret, required by the defaults since they enable auth
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// defaultWithSecret returns the defaults completed with the JWT secret
+func defaultWithSecret() Config {
+	cfg := Default()
+	cfg.Auth.JWTSecret = testJWTSecret
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		files   map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "should use defaults",
+			env:  map[string]string{EnvAuthJWTSecret: testJWTSecret},
+			want: defaultWithSecret(),
+		},
+		{
+			name:    "should require credentials since auth is enabled by default",
+			env:     map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "should disable auth on explicit opt-out",
+			env:  map[string]string{EnvAuthEnabled: "false"},
+			want: func() Config {
+				cfg := Default()
+				cfg.Auth.Enabled = false
+				return cfg
+			}(),
+		},
+		{
+			name: "should read yaml file",
+			env:  map[string]string{EnvConfigFile: "config.yaml", EnvAuthJWTSecret: testJWTSecret},
+			files: map[string]string{"config.yaml": `
+http:
+  address: ":9090"
+  request_timeout: 5s
+  read_header_timeout: 1s
+  read_timeout: 10s
+  write_timeout: 10s
+  idle_timeout: 30s
+  shutdown_timeout: 20s
+  max_body_bytes: 65536
+storage:
+  backend: mysql
+  dsn: user:password@tcp(localhost:3306)/races?parseTime=true
+notifier:
+  type: none
+  queue_size: 10
+timing:
+  drop_dir: /var/lib/racetracker/reads
+  drop_interval: 1s
+logging:
+  level: debug
+  format: json
+metrics:
+  enabled: false
+`},
+			want: Config{
+				HTTP: HTTP{
+					Address:           ":9090",
+					RequestTimeout:    Duration(5 * time.Second),
+					ReadHeaderTimeout: Duration(time.Second),
+					ReadTimeout:       Duration(10 * time.Second),
+					WriteTimeout:      Duration(10 * time.Second),
+					IdleTimeout:       Duration(30 * time.Second),
+					ShutdownTimeout:   Duration(20 * time.Second),
+					MaxBodyBytes:      65536,
+				},
+				Storage:  Storage{Backend: BackendMySQL, DSN: "user:password@tcp(localhost:3306)/races?parseTime=true"},
+				Notifier: Notifier{Type: NotifierNone, QueueSize: 10},
+				Timing:   Timing{DropDir: "/var/lib/racetracker/reads", DropInterval: Duration(time.Second)},
+				Logging:  Logging{Level: LogLevelDebug, Format: LogFormatJSON},
+				Metrics:  Metrics{Enabled: false},
+				Auth:     Auth{Enabled: true, JWTSecret: testJWTSecret},
+			},
+		},
+		{
+			name:  "should read json file and keep defaults for missing values",
+			env:   map[string]string{EnvConfigFile: "config.json", EnvAuthJWTSecret: testJWTSecret},
+			files: map[string]string{"config.json": `{"http": {"address": ":9090"}}`},
+			want: func() Config {
+				cfg := defaultWithSecret()
+				cfg.HTTP.Address = ":9090"
+				return cfg
+			}(),
+		},
+		{
+			name: "should let environment override the file",
+			env: map[string]string{
+				EnvConfigFile:          "config.yaml",
+				EnvHTTPAddress:         ":7070",
+				EnvHTTPRequestTimeout:  "1m",
+				EnvHTTPShutdownTimeout: "0s",
+				EnvHTTPMaxBodyBytes:    "0",
+				EnvNotifierQueueSize:   "0",
+				EnvStorageBackend:      "mysql",
+				EnvStorageDSN:          "dsn",
+				EnvNotifier:            "none",
+				EnvTimingDropDir:       "reads",
+				EnvTimingDropInterval:  "10s",
+				EnvLogLevel:            "warn",
+				EnvLogFormat:           "json",
+				EnvMetricsEnabled:      "false",
+				EnvAuthJWTSecret:       testJWTSecret,
+			},
+			files: map[string]string{"config.yaml": "http:\n  address: \":9090\"\n"},
+			want: func() Config {
+				cfg := defaultWithSecret()
+				cfg.HTTP.Address = ":7070"
+				cfg.HTTP.RequestTimeout = Duration(time.Minute)
+				cfg.HTTP.ShutdownTimeout = 0
+				cfg.HTTP.MaxBodyBytes = 0
+				cfg.Storage = Storage{Backend: BackendMySQL, DSN: "dsn"}
+				cfg.Notifier = Notifier{Type: NotifierNone}
+				cfg.Timing = Timing{DropDir: "reads", DropInterval: Duration(10 * time.Second)}
+				cfg.Logging = Logging{Level: LogLevelWarn, Format: LogFormatJSON}
+				cfg.Metrics = Metrics{Enabled: false}
+				return cfg
+			}(),
+		},
+		{
+			name: "should read the auth settings and let environment override them",
+			env: map[string]string{
+				EnvConfigFile:    "config.yaml",
+				EnvAuthEnabled:   "true",
+				EnvAuthJWTSecret: testJWTSecret,
+			},
+			files: map[string]string{"config.yaml": `
+auth:
+  api_keys:
+    - key: organiser-key
+      subject: athens-events
+      role: organiser
+    - key: runner-key
+      subject: anna
+      role: runner
+      runner_id: 6f1c7c7e-5d3b-4f7e-9a51-8d2f0b8a9c11
+`},
+			want: func() Config {
+				cfg := Default()
+				cfg.Auth = Auth{
+					Enabled: true,
+					APIKeys: []APIKey{
+						{Key: "organiser-key", Subject: "athens-events", Role: "organiser"},
+						{Key: "runner-key", Subject: "anna", Role: "runner", RunnerID: "6f1c7c7e-5d3b-4f7e-9a51-8d2f0b8a9c11"},
+					},
+					JWTSecret: testJWTSecret,
+				}
+				return cfg
+			}(),
+		},
+		{
+			name:    "should fail on invalid integer",
+			env:     map[string]string{EnvNotifierQueueSize: "many"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on invalid boolean",
+			env:     map[string]string{EnvAuthEnabled: "yes please"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on invalid duration",
+			env:     map[string]string{EnvHTTPRequestTimeout: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on missing file",
+			env:     map[string]string{EnvConfigFile: "missing.yaml"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on unknown fields",
+			env:     map[string]string{EnvConfigFile: "config.yaml"},
+			files:   map[string]string{"config.yaml": "storage:\n  engine: mysql\n"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on unsupported extension",
+			env:     map[string]string{EnvConfigFile: "config.toml"},
+			files:   map[string]string{"config.toml": ""},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on invalid values",
+			env:     map[string]string{EnvStorageBackend: "postgres"},
+			wantErr: true,
+		},
+		{
+			name: "should select the sqlite backend",
+			env:  map[string]string{EnvStorageBackend: "sqlite", EnvStorageDSN: "races.db", EnvAuthJWTSecret: testJWTSecret},
+			want: func() Config {
+				cfg := defaultWithSecret()
+				cfg.Storage = Storage{Backend: BackendSQLite, DSN: "races.db"}
+				return cfg
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(envFrom(tt.env), filesFrom(tt.files))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad_ReportsEveryInvalidOverride(t *testing.T) {
+	_, err := load(envFrom(map[string]string{
+		EnvNotifierQueueSize:  "many",
+		EnvHTTPRequestTimeout: "soon",
+	}), filesFrom(nil))
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, EnvNotifierQueueSize)
+	assert.ErrorContains(t, err, EnvHTTPRequestTimeout)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		wantProblems []string
+	}{
+		{
+			name:   "should accept defaults with credentials",
+			config: defaultWithSecret(),
+		},
+		{
+			name:   "should require credentials with the defaults",
+			config: Default(),
+			wantProblems: []string{
+				"auth.api_keys or auth.jwt_secret is required when auth is enabled",
+			},
+		},
+		{
+			name: "should report every problem",
+			config: Config{
+				HTTP:     HTTP{Address: "", RequestTimeout: Duration(-time.Second), ShutdownTimeout: Duration(-time.Second), MaxBodyBytes: -1},
+				Storage:  Storage{Backend: BackendMySQL},
+				Notifier: Notifier{Type: "sms", QueueSize: -1},
+				Timing:   Timing{DropDir: "reads"},
+				Logging:  Logging{Level: "trace", Format: "xml"},
+			},
+			wantProblems: []string{
+				"http.address cannot be empty",
+				"http.request_timeout cannot be negative",
+				"http.shutdown_timeout cannot be negative",
+				"http.max_body_bytes cannot be negative",
+				`storage.dsn is required for storage backend "mysql"`,
+				`notifier.type "sms" is not one of "console", "none"`,
+				"notifier.queue_size cannot be negative",
+				"timing.drop_interval must be positive when timing.drop_dir is set",
+				`logging.level "trace" is not one of "debug", "info", "warn", "error"`,
+				`logging.format "xml" is not one of "json", "text"`,
+			},
+		},
+		{
+			name: "should reject a write timeout shorter than the request timeout",
+			config: func() Config {
+				cfg := defaultWithSecret()
+				cfg.HTTP.WriteTimeout = Duration(10 * time.Second)
+				return cfg
+			}(),
+			wantProblems: []string{
+				"http.write_timeout cannot be shorter than http.request_timeout",
+			},
+		},
+		{
+			name: "should reject unknown backend",
+			config: Config{
+				HTTP:     HTTP{Address: ":8080"},
+				Storage:  Storage{Backend: "postgres"},
+				Notifier: Notifier{Type: NotifierConsole},
+				Logging:  Logging{Level: LogLevelInfo, Format: LogFormatText},
+			},
+			wantProblems: []string{
+				`storage.backend "postgres" is not one of "memory", "mysql", "sqlite"`,
+			},
+		},
+		{
+			name: "should report auth problems",
+			config: Config{
+				HTTP:     HTTP{Address: ":8080"},
+				Storage:  Storage{Backend: BackendMemory},
+				Notifier: Notifier{Type: NotifierConsole},
+				Logging:  Logging{Level: LogLevelInfo, Format: LogFormatText},
+				Auth: Auth{
+					Enabled: true,
+					APIKeys: []APIKey{
+						{Key: "", Subject: "athens-events", Role: "organiser"},
+						{Key: "runner-key", Subject: "anna", Role: "runner"},
+						{Key: "runner-key", Subject: "", Role: "timer"},
+					},
+					JWTSecret: "short",
+				},
+			},
+			wantProblems: []string{
+				"auth.jwt_secret must be at least 32 bytes",
+				"auth.api_keys[0].key cannot be empty",
+				"auth.api_keys[1].runner_id must be a runner id for the runner role",
+				"auth.api_keys[2].key is a duplicate",
+				"auth.api_keys[2].subject cannot be empty",
+				`auth.api_keys[2].role "timer" is not one of "runner", "organiser", "admin"`,
+			},
+		},
+		{
+			name: "should require credentials when auth is enabled",
+			config: Config{
+				HTTP:     HTTP{Address: ":8080"},
+				Storage:  Storage{Backend: BackendMemory},
+				Notifier: Notifier{Type: NotifierConsole},
+				Auth:     Auth{Enabled: true},
+				Logging:  Logging{Level: LogLevelInfo, Format: LogFormatText},
+			},
+			wantProblems: []string{
+				"auth.api_keys or auth.jwt_secret is required when auth is enabled",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantProblems == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantProblems, validationErr.Problems)
+		})
+	}
+}
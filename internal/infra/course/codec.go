@@ -0,0 +1,50 @@
+// Package course implements the course RouteCodec port for GPX and GeoJSON files
+package course
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+)
+
+// maxFileSize bounds the size of the route files that are read in memory
+const maxFileSize = 32 << 20
+
+// Codec Implements the course RouteCodec port
+type Codec struct{}
+
+// NewCodec Constructor
+func NewCodec() Codec {
+	return Codec{}
+}
+
+// Decode reads the track and the checkpoints of the route file in the provided format
+func (c Codec) Decode(_ context.Context, format course.RouteFormat, file io.Reader) (course.RouteItem, error) {
+	file = io.LimitReader(file, maxFileSize)
+	switch format {
+	case course.RouteFormatGPX:
+		return decodeGPX(file)
+	case course.RouteFormatGeoJSON:
+		return decodeGeoJSON(file)
+	default:
+		return course.RouteItem{}, course.ErrUnsupportedRouteFormat
+	}
+}
+
+// Encode writes the route to w in the provided format
+func (c Codec) Encode(_ context.Context, format course.RouteFormat, route course.RouteItem, w io.Writer) error {
+	switch format {
+	case course.RouteFormatGPX:
+		return encodeGPX(route, w)
+	case course.RouteFormatGeoJSON:
+		return encodeGeoJSON(route, w)
+	default:
+		return course.ErrUnsupportedRouteFormat
+	}
+}
+
+func errInvalid(reason string) error {
+	return fmt.Errorf("%w: %s", course.ErrInvalidRouteFile, reason)
+}
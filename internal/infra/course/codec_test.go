@@ -0,0 +1,140 @@
+package course
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gpxWithTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="38.0" lon="23.7"><name>Start</name><type>start</type></wpt>
+  <wpt lat="38.009" lon="23.7"><name>Aid 1</name><type>aid_station</type></wpt>
+  <trk>
+    <name>Hill 2K</name>
+    <trkseg>
+      <trkpt lat="38.0" lon="23.7"><ele>100</ele></trkpt>
+      <trkpt lat="38.009" lon="23.7"><ele>130</ele></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="38.018" lon="23.7"><ele>120</ele></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const gpxWithRoute = `<gpx version="1.1" creator="test">
+  <rte>
+    <rtept lat="38.0" lon="23.7"></rtept>
+    <rtept lat="38.009" lon="23.7"><ele>130</ele></rtept>
+  </rte>
+</gpx>`
+
+const geoJSON = `{"type":"FeatureCollection","features":[
+  {"type":"Feature","properties":{"name":"Hill 2K"},"geometry":{"type":"LineString","coordinates":[[23.7,38.0,100],[23.7,38.009,130],[23.7,38.018]]}},
+  {"type":"Feature","properties":{"name":"Start","kind":"start"},"geometry":{"type":"Point","coordinates":[23.7,38.0]}}
+]}`
+
+func TestCodec_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  course.RouteFormat
+		file    string
+		want    course.RouteItem
+		wantErr error
+	}{
+		{
+			name:   "gpx track with waypoints",
+			format: course.RouteFormatGPX,
+			file:   gpxWithTrack,
+			want: course.RouteItem{
+				Name: "Hill 2K",
+				Points: []course.PointItem{
+					{Lat: 38.0, Lon: 23.7, ElevationM: 100},
+					{Lat: 38.009, Lon: 23.7, ElevationM: 130},
+					{Lat: 38.018, Lon: 23.7, ElevationM: 120},
+				},
+				Checkpoints: []course.CheckpointItem{
+					{Name: "Start", Kind: "start", Lat: 38.0, Lon: 23.7},
+					{Name: "Aid 1", Kind: "aid_station", Lat: 38.009, Lon: 23.7},
+				},
+			},
+		},
+		{
+			name:   "gpx route without track",
+			format: course.RouteFormatGPX,
+			file:   gpxWithRoute,
+			want: course.RouteItem{Points: []course.PointItem{
+				{Lat: 38.0, Lon: 23.7},
+				{Lat: 38.009, Lon: 23.7, ElevationM: 130},
+			}},
+		},
+		{
+			name:   "geojson feature collection",
+			format: course.RouteFormatGeoJSON,
+			file:   geoJSON,
+			want: course.RouteItem{
+				Name: "Hill 2K",
+				Points: []course.PointItem{
+					{Lat: 38.0, Lon: 23.7, ElevationM: 100},
+					{Lat: 38.009, Lon: 23.7, ElevationM: 130},
+					{Lat: 38.018, Lon: 23.7},
+				},
+				Checkpoints: []course.CheckpointItem{{Name: "Start", Kind: "start", Lat: 38.0, Lon: 23.7}},
+			},
+		},
+		{name: "malformed gpx", format: course.RouteFormatGPX, file: "<gpx><trk>", wantErr: course.ErrInvalidRouteFile},
+		{name: "geojson geometry only", format: course.RouteFormatGeoJSON, file: `{"type":"LineString","coordinates":[]}`, wantErr: course.ErrInvalidRouteFile},
+		{
+			name:    "geojson position without latitude",
+			format:  course.RouteFormatGeoJSON,
+			file:    `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[23.7]}}]}`,
+			wantErr: course.ErrInvalidRouteFile,
+		},
+		{name: "unsupported format", format: "kml", file: "<kml/>", wantErr: course.ErrUnsupportedRouteFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCodec().Decode(context.Background(), tt.format, strings.NewReader(tt.file))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCodec_Encode(t *testing.T) {
+	route := course.RouteItem{
+		Name: "Hill 2K",
+		Points: []course.PointItem{
+			{Lat: 38.0, Lon: 23.7, ElevationM: 100},
+			{Lat: 38.018, Lon: 23.7, ElevationM: 120},
+		},
+		Checkpoints: []course.CheckpointItem{{Name: "Finish", Kind: "finish", Lat: 38.018, Lon: 23.7, DistanceKm: 2}},
+	}
+	//the distance of the checkpoints is not part of the route files
+	want := route
+	want.Checkpoints = []course.CheckpointItem{{Name: "Finish", Kind: "finish", Lat: 38.018, Lon: 23.7}}
+
+	for _, format := range []course.RouteFormat{course.RouteFormatGPX, course.RouteFormatGeoJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			codec := NewCodec()
+			var buf bytes.Buffer
+			require.NoError(t, codec.Encode(context.Background(), format, route, &buf))
+
+			got, err := codec.Decode(context.Background(), format, &buf)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, NewCodec().Encode(context.Background(), "kml", route, &buf), course.ErrUnsupportedRouteFormat)
+}
@@ -0,0 +1,121 @@
+package course
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+)
+
+// geoJSONCollection maps a GeoJSON feature collection. The course is the first LineString feature and the
+// checkpoints are the Point features, with their name and kind in the properties.
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Properties geoJSONProperties `json:"properties"`
+	Geometry   geoJSONGeometry   `json:"geometry"`
+}
+
+type geoJSONProperties struct {
+	Name string `json:"name,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func decodeGeoJSON(r io.Reader) (course.RouteItem, error) {
+	var collection geoJSONCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return course.RouteItem{}, errInvalid(err.Error())
+	}
+	if collection.Type != "FeatureCollection" {
+		return course.RouteItem{}, errInvalid("expected a FeatureCollection")
+	}
+
+	var route course.RouteItem
+	for _, f := range collection.Features {
+		switch f.Geometry.Type {
+		case "LineString":
+			if route.Points != nil {
+				continue
+			}
+			var positions [][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &positions); err != nil {
+				return course.RouteItem{}, errInvalid(err.Error())
+			}
+			route.Name = f.Properties.Name
+			route.Points = make([]course.PointItem, 0, len(positions))
+			for _, position := range positions {
+				p, err := toPoint(position)
+				if err != nil {
+					return course.RouteItem{}, err
+				}
+				route.Points = append(route.Points, p)
+			}
+		case "Point":
+			var position []float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &position); err != nil {
+				return course.RouteItem{}, errInvalid(err.Error())
+			}
+			p, err := toPoint(position)
+			if err != nil {
+				return course.RouteItem{}, err
+			}
+			route.Checkpoints = append(route.Checkpoints, course.CheckpointItem{
+				Name: f.Properties.Name,
+				Kind: f.Properties.Kind,
+				Lat:  p.Lat,
+				Lon:  p.Lon,
+			})
+		}
+	}
+	return route, nil
+}
+
+// toPoint reads a GeoJSON position, which is longitude first with an optional elevation
+func toPoint(position []float64) (course.PointItem, error) {
+	switch len(position) {
+	case 2:
+		return course.PointItem{Lat: position[1], Lon: position[0]}, nil
+	case 3:
+		return course.PointItem{Lat: position[1], Lon: position[0], ElevationM: position[2]}, nil
+	default:
+		return course.PointItem{}, errInvalid("positions must have a longitude, a latitude and an optional elevation")
+	}
+}
+
+func encodeGeoJSON(route course.RouteItem, w io.Writer) error {
+	positions := make([][]float64, len(route.Points))
+	for i, p := range route.Points {
+		positions[i] = []float64{p.Lon, p.Lat, p.ElevationM}
+	}
+	line, err := json.Marshal(positions)
+	if err != nil {
+		return err
+	}
+
+	collection := geoJSONCollection{Type: "FeatureCollection", Features: []geoJSONFeature{{
+		Type:       "Feature",
+		Properties: geoJSONProperties{Name: route.Name},
+		Geometry:   geoJSONGeometry{Type: "LineString", Coordinates: line},
+	}}}
+	for _, c := range route.Checkpoints {
+		point, err := json.Marshal([]float64{c.Lon, c.Lat})
+		if err != nil {
+			return err
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Properties: geoJSONProperties{Name: c.Name, Kind: c.Kind},
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: point},
+		})
+	}
+	return json.NewEncoder(w).Encode(collection)
+}
@@ -0,0 +1,102 @@
+package course
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+)
+
+// gpxFile maps the tracks, routes and waypoints of a GPX 1.1 file. The course is read from the tracks,
+// or from the routes when the file has no track, and the checkpoints from the waypoints, whose type is
+// the kind of the checkpoint.
+type gpxFile struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Namespace string        `xml:"xmlns,attr"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+	Routes    []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name     string `xml:"name,omitempty"`
+	Segments []struct {
+		Points []gpxPoint `xml:"trkpt"`
+	} `xml:"trkseg"`
+}
+
+type gpxPoint struct {
+	Lat       float64 `xml:"lat,attr"`
+	Lon       float64 `xml:"lon,attr"`
+	Elevation float64 `xml:"ele"`
+}
+
+type gpxWaypoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Name string  `xml:"name"`
+	Type string  `xml:"type"`
+}
+
+func decodeGPX(r io.Reader) (course.RouteItem, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return course.RouteItem{}, errInvalid(err.Error())
+	}
+
+	var route course.RouteItem
+	for _, track := range file.Tracks {
+		if route.Name == "" {
+			route.Name = track.Name
+		}
+		for _, segment := range track.Segments {
+			route.Points = appendGPXPoints(route.Points, segment.Points)
+		}
+	}
+	if len(route.Points) == 0 {
+		for _, rte := range file.Routes {
+			route.Points = appendGPXPoints(route.Points, rte.Points)
+		}
+	}
+	for _, w := range file.Waypoints {
+		route.Checkpoints = append(route.Checkpoints, course.CheckpointItem{Name: w.Name, Kind: w.Type, Lat: w.Lat, Lon: w.Lon})
+	}
+	return route, nil
+}
+
+func appendGPXPoints(points []course.PointItem, gpxPoints []gpxPoint) []course.PointItem {
+	for _, p := range gpxPoints {
+		points = append(points, course.PointItem{Lat: p.Lat, Lon: p.Lon, ElevationM: p.Elevation})
+	}
+	return points
+}
+
+func encodeGPX(route course.RouteItem, w io.Writer) error {
+	file := gpxFile{
+		Version:   "1.1",
+		Creator:   "racetracker",
+		Namespace: "http://www.topografix.com/GPX/1/1",
+		Tracks:    []gpxTrack{{Name: route.Name}},
+	}
+	for _, c := range route.Checkpoints {
+		file.Waypoints = append(file.Waypoints, gpxWaypoint{Lat: c.Lat, Lon: c.Lon, Name: c.Name, Type: c.Kind})
+	}
+	segment := make([]gpxPoint, len(route.Points))
+	for i, p := range route.Points {
+		segment[i] = gpxPoint{Lat: p.Lat, Lon: p.Lon, Elevation: p.ElevationM}
+	}
+	file.Tracks[0].Segments = append(file.Tracks[0].Segments, struct {
+		Points []gpxPoint `xml:"trkpt"`
+	}{Points: segment})
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(file)
+}
@@ -0,0 +1,91 @@
+// Package analytics contains the http handlers of the analytics of the race history of runners
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/analytics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type analyticsService interface {
+	PredictFinishTimes(ctx context.Context, runnerID uuid.UUID, distancesKm []float64, elevationGain float64) ([]analytics.PredictionItem, error)
+}
+
+// Handler analytics http request service
+type Handler struct {
+	analyticsService analyticsService
+}
+
+// NewHandler Constructor
+func NewHandler(service analyticsService) Handler {
+	return Handler{analyticsService: service}
+}
+
+// PredictionResponse represents the response model of a predicted finish time
+type PredictionResponse struct {
+	DistanceKm    float64 `json:"distance_km"`
+	ElevationGain float64 `json:"elevation_gain"`
+	FinishTimeMs  int64   `json:"finish_time_ms"`
+	LowMs         int64   `json:"low_ms"`
+	HighMs        int64   `json:"high_ms"`
+	Pace          float64 `json:"pace"`
+	RiegelMs      int64   `json:"riegel_ms"`
+	VDOTMs        int64   `json:"vdot_ms"`
+	VDOT          float64 `json:"vdot"`
+}
+
+// GetPredictions handles requests to predict the finish times of a runner.
+// The distance_km query parameter can be repeated; the standard distances are predicted when it is missing.
+// The optional elevation_gain query parameter is the climb of the predicted races in metres.
+func (h Handler) GetPredictions(w http.ResponseWriter, r *http.Request) {
+	runnerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.InvalidID(w, "id")
+		return
+	}
+
+	query := r.URL.Query()
+	distances := make([]float64, len(query["distance_km"]))
+	for i, value := range query["distance_km"] {
+		distances[i], err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "distance_km", "distance_km must be a number")
+			return
+		}
+	}
+	var elevationGain float64
+	if value := query.Get("elevation_gain"); value != "" {
+		elevationGain, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "elevation_gain", "elevation_gain must be a number")
+			return
+		}
+	}
+
+	predictions, err := h.analyticsService.PredictFinishTimes(r.Context(), runnerID, distances, elevationGain)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	models := make([]PredictionResponse, len(predictions))
+	for i, p := range predictions {
+		models[i] = PredictionResponse{
+			DistanceKm:    p.DistanceKm,
+			ElevationGain: p.ElevationGain,
+			FinishTimeMs:  p.FinishTime.Milliseconds(),
+			LowMs:         p.Low.Milliseconds(),
+			HighMs:        p.High.Milliseconds(),
+			Pace:          p.PaceMinPerKm,
+			RiegelMs:      p.Riegel.Milliseconds(),
+			VDOTMs:        p.VDOTTime.Milliseconds(),
+			VDOT:          p.VDOT,
+		}
+	}
+	response.JSON(w, http.StatusOK, models)
+}
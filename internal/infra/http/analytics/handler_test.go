@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/analytics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAnalyticsService struct {
+	mock.Mock
+}
+
+func (m *mockAnalyticsService) PredictFinishTimes(_ context.Context, runnerID uuid.UUID, distancesKm []float64, elevationGain float64) ([]analytics.PredictionItem, error) {
+	args := m.Called(runnerID, distancesKm, elevationGain)
+	return args.Get(0).([]analytics.PredictionItem), args.Error(1)
+}
+
+func TestHandler_GetPredictions(t *testing.T) {
+	runnerID := uuid.New()
+	prediction := analytics.PredictionItem{
+		DistanceKm:    10,
+		ElevationGain: 50,
+		FinishTime:    42 * time.Minute,
+		Low:           40 * time.Minute,
+		High:          44 * time.Minute,
+		PaceMinPerKm:  4.2,
+		Riegel:        42*time.Minute + 30*time.Second,
+		VDOTTime:      41*time.Minute + 30*time.Second,
+		VDOT:          49.8,
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		query          string
+		mockSetup      func(m *mockAnalyticsService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:  "should return predictions",
+			id:    runnerID.String(),
+			query: "?distance_km=10&elevation_gain=50",
+			mockSetup: func(m *mockAnalyticsService) {
+				m.On("PredictFinishTimes", runnerID, []float64{10}, 50.0).Return([]analytics.PredictionItem{prediction}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `[{"distance_km":10,"elevation_gain":50,"finish_time_ms":2520000,"low_ms":2400000,"high_ms":2640000,
+				"pace":4.2,"riegel_ms":2550000,"vdot_ms":2490000,"vdot":49.8}]`,
+		},
+		{
+			name: "should predict default distances",
+			id:   runnerID.String(),
+			mockSetup: func(m *mockAnalyticsService) {
+				m.On("PredictFinishTimes", runnerID, []float64{}, 0.0).Return([]analytics.PredictionItem{}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `[]`,
+		},
+		{
+			name:  "should return unprocessable entity without results",
+			id:    runnerID.String(),
+			query: "?distance_km=5&distance_km=10",
+			mockSetup: func(m *mockAnalyticsService) {
+				m.On("PredictFinishTimes", runnerID, []float64{5, 10}, 0.0).Return([]analytics.PredictionItem(nil), race.ErrNoPerformances)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody:       `{"error":{"code":"no_results","message":"` + race.ErrNoPerformances.Error() + `"}}`,
+		},
+		{
+			name: "should return not found for unknown runner",
+			id:   runnerID.String(),
+			mockSetup: func(m *mockAnalyticsService) {
+				m.On("PredictFinishTimes", runnerID, []float64{}, 0.0).Return([]analytics.PredictionItem(nil), runner.ErrNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":{"code":"runner_not_found","message":"` + runner.ErrNotFound.Error() + `"}}`,
+		},
+		{
+			name:           "should return bad request on invalid distance",
+			id:             runnerID.String(),
+			query:          "?distance_km=far",
+			mockSetup:      func(*mockAnalyticsService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_parameter","message":"distance_km must be a number","field":"distance_km"}}`,
+		},
+		{
+			name:           "should return bad request on invalid elevation gain",
+			id:             runnerID.String(),
+			query:          "?elevation_gain=high",
+			mockSetup:      func(*mockAnalyticsService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_parameter","message":"elevation_gain must be a number","field":"elevation_gain"}}`,
+		},
+		{
+			name:           "should return bad request on invalid id",
+			id:             "invalid",
+			mockSetup:      func(*mockAnalyticsService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_id","message":"invalid id format","field":"id"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockAnalyticsService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/runners/"+tt.id+"/predictions"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+			handler.GetPredictions(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Package bib contains the http handlers of the bib numbers and timing chips of races
+package bib
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type bibService interface {
+	ConfigureRanges(ctx context.Context, raceID uuid.UUID, ranges []bib.RangeItem) error
+	GetRanges(ctx context.Context, raceID uuid.UUID) ([]bib.RangeItem, error)
+	AssignBib(ctx context.Context, raceID, runnerID uuid.UUID, rangeName, chipID string) (bib.BibItem, error)
+	ReassignBib(ctx context.Context, raceID, runnerID uuid.UUID, number int, rangeName, chipID, reason string) (bib.BibItem, error)
+	ExportBibs(ctx context.Context, raceID uuid.UUID) ([]bib.BibItem, error)
+	GetBibChanges(ctx context.Context, raceID uuid.UUID) ([]bib.BibChangeItem, error)
+}
+
+// Handler bib http request service
+type Handler struct {
+	bibService bibService
+}
+
+// NewHandler Constructor
+func NewHandler(service bibService) Handler {
+	return Handler{bibService: service}
+}
+
+// RangeModel represents a block of bib numbers reserved for a category or a wave
+type RangeModel struct {
+	Name  string `json:"name"`
+	First int    `json:"first"`
+	Last  int    `json:"last"`
+}
+
+// ConfigureRangesRequestModel represents the request model for configuring the bib ranges of a race
+type ConfigureRangesRequestModel struct {
+	Ranges []RangeModel `json:"ranges"`
+}
+
+// ConfigureRanges handles requests to set the bib ranges of a race
+func (h Handler) ConfigureRanges(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var rangesRequest ConfigureRangesRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&rangesRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	ranges := make([]bib.RangeItem, len(rangesRequest.Ranges))
+	for i, model := range rangesRequest.Ranges {
+		ranges[i] = bib.RangeItem{Name: model.Name, First: model.First, Last: model.Last}
+	}
+	if err := h.bibService.ConfigureRanges(r.Context(), raceID, ranges); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetRanges handles requests to get the bib ranges of a race
+func (h Handler) GetRanges(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	ranges, err := h.bibService.GetRanges(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	models := make([]RangeModel, len(ranges))
+	for i, item := range ranges {
+		models[i] = RangeModel{Name: item.Name, First: item.First, Last: item.Last}
+	}
+	response.JSON(w, http.StatusOK, models)
+}
+
+// BibResponse represents the response model of the bib of a runner
+type BibResponse struct {
+	Number     int       `json:"bib"`
+	Range      string    `json:"range"`
+	ChipID     string    `json:"chip_id,omitempty"`
+	RunnerID   uuid.UUID `json:"runner_id"`
+	RunnerName string    `json:"runner_name,omitempty"`
+	Gender     string    `json:"gender,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+func toBibResponse(item bib.BibItem) BibResponse {
+	return BibResponse{
+		Number:     item.Number,
+		Range:      item.Range,
+		ChipID:     item.ChipID,
+		RunnerID:   item.RunnerID,
+		RunnerName: item.RunnerName,
+		Gender:     item.Gender,
+		AssignedAt: item.AssignedAt,
+	}
+}
+
+// AssignBibRequestModel represents the request model for assigning a bib to a registered runner.
+// ChipID is the optional RFID timing chip mapped to the bib.
+type AssignBibRequestModel struct {
+	RunnerID string `json:"runner_id"`
+	Range    string `json:"range"`
+	ChipID   string `json:"chip_id"`
+}
+
+// AssignBib handles requests to assign the next free bib of a range to a registered runner
+func (h Handler) AssignBib(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var assignRequest AssignBibRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&assignRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+	runnerID, err := uuid.Parse(assignRequest.RunnerID)
+	if err != nil {
+		response.InvalidID(w, "runner_id")
+		return
+	}
+
+	assigned, err := h.bibService.AssignBib(r.Context(), raceID, runnerID, assignRequest.Range, assignRequest.ChipID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, toBibResponse(assigned))
+}
+
+// ReassignBibRequestModel represents the request model for reassigning the bib of a runner.
+// A bib of 0 keeps the number unless the range changes; the reason is required and audited.
+type ReassignBibRequestModel struct {
+	Number int    `json:"bib"`
+	Range  string `json:"range"`
+	ChipID string `json:"chip_id"`
+	Reason string `json:"reason"`
+}
+
+// ReassignBib handles requests to change the bib number, the range or the chip of a runner
+func (h Handler) ReassignBib(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+	runnerID, err := uuid.Parse(mux.Vars(r)["runnerID"])
+	if err != nil {
+		response.InvalidID(w, "runner_id")
+		return
+	}
+
+	var reassignRequest ReassignBibRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&reassignRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	reassigned, err := h.bibService.ReassignBib(
+		r.Context(),
+		raceID,
+		runnerID,
+		reassignRequest.Number,
+		reassignRequest.Range,
+		reassignRequest.ChipID,
+		reassignRequest.Reason,
+	)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toBibResponse(reassigned))
+}
+
+// csvHeader is the header row of the CSV export of the bibs
+var csvHeader = []string{"bib", "chip_id", "runner_id", "runner_name", "gender", "range"}
+
+// ExportBibs handles requests to export the bibs of a race ordered by number for the timing company.
+// The export is JSON by default and CSV with format=csv or an Accept header of text/csv.
+func (h Handler) ExportBibs(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" && r.Header.Get("Accept") == "text/csv" {
+		format = "csv"
+	}
+	if format != "" && format != "json" && format != "csv" {
+		response.BadRequest(w, response.CodeInvalidParameter, "format", "format must be json or csv")
+		return
+	}
+
+	bibs, err := h.bibService.ExportBibs(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if format != "csv" {
+		models := make([]BibResponse, len(bibs))
+		for i, item := range bibs {
+			models[i] = toBibResponse(item)
+		}
+		response.JSON(w, http.StatusOK, models)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="bibs-`+raceID.String()+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	writer := csv.NewWriter(w)
+	_ = writer.Write(csvHeader)
+	for _, item := range bibs {
+		_ = writer.Write([]string{strconv.Itoa(item.Number), item.ChipID, item.RunnerID.String(), item.RunnerName, item.Gender, item.Range})
+	}
+	writer.Flush()
+}
+
+// BibChangeResponse represents the response model of an audited bib reassignment
+type BibChangeResponse struct {
+	RunnerID       uuid.UUID `json:"runner_id"`
+	PreviousNumber int       `json:"previous_bib"`
+	Number         int       `json:"bib"`
+	PreviousChipID string    `json:"previous_chip_id,omitempty"`
+	ChipID         string    `json:"chip_id,omitempty"`
+	Reason         string    `json:"reason"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+// GetBibChanges handles requests to get the audit trail of the bib reassignments of a race
+func (h Handler) GetBibChanges(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	changes, err := h.bibService.GetBibChanges(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	models := make([]BibChangeResponse, len(changes))
+	for i, c := range changes {
+		models[i] = BibChangeResponse{
+			RunnerID:       c.RunnerID,
+			PreviousNumber: c.PreviousNumber,
+			Number:         c.Number,
+			PreviousChipID: c.PreviousChipID,
+			ChipID:         c.ChipID,
+			Reason:         c.Reason,
+			ChangedAt:      c.ChangedAt,
+		}
+	}
+	response.JSON(w, http.StatusOK, models)
+}
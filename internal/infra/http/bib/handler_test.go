@@ -0,0 +1,345 @@
+package bib
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/bib"
+	domainBib "github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	domainRegistration "github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockBibService struct {
+	mock.Mock
+}
+
+func (m *mockBibService) ConfigureRanges(_ context.Context, raceID uuid.UUID, ranges []bib.RangeItem) error {
+	return m.Called(raceID, ranges).Error(0)
+}
+
+func (m *mockBibService) GetRanges(_ context.Context, raceID uuid.UUID) ([]bib.RangeItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]bib.RangeItem), args.Error(1)
+}
+
+func (m *mockBibService) AssignBib(_ context.Context, raceID, runnerID uuid.UUID, rangeName, chipID string) (bib.BibItem, error) {
+	args := m.Called(raceID, runnerID, rangeName, chipID)
+	return args.Get(0).(bib.BibItem), args.Error(1)
+}
+
+func (m *mockBibService) ReassignBib(_ context.Context, raceID, runnerID uuid.UUID, number int, rangeName, chipID, reason string) (bib.BibItem, error) {
+	args := m.Called(raceID, runnerID, number, rangeName, chipID, reason)
+	return args.Get(0).(bib.BibItem), args.Error(1)
+}
+
+func (m *mockBibService) ExportBibs(_ context.Context, raceID uuid.UUID) ([]bib.BibItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]bib.BibItem), args.Error(1)
+}
+
+func (m *mockBibService) GetBibChanges(_ context.Context, raceID uuid.UUID) ([]bib.BibChangeItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]bib.BibChangeItem), args.Error(1)
+}
+
+var assignedAt = time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+
+func TestHandler_ConfigureRanges(t *testing.T) {
+	raceID := uuid.New()
+	ranges := []bib.RangeItem{{Name: "elite", First: 1, Last: 99}, {Name: "open", First: 100, Last: 999}}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockBibService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "configures the ranges",
+			body: `{"ranges":[{"name":"elite","first":1,"last":99},{"name":"open","first":100,"last":999}]}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("ConfigureRanges", raceID, ranges).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "overlapping ranges",
+			body: `{"ranges":[{"name":"elite","first":1,"last":99},{"name":"open","first":100,"last":999}]}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("ConfigureRanges", raceID, ranges).Return(domainBib.ErrOverlappingRanges)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"overlapping_ranges","message":"ranges cannot overlap","field":"ranges"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockBibService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/races/"+raceID.String()+"/bibs/ranges", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.ConfigureRanges(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_AssignBib(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockBibService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "assigns a bib",
+			body: `{"runner_id":"` + runnerID.String() + `","range":"elite","chip_id":"CHIP-1"}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("AssignBib", raceID, runnerID, "elite", "CHIP-1").Return(bib.BibItem{
+					Number: 1, Range: "elite", ChipID: "CHIP-1", RunnerID: runnerID, RunnerName: "Anna", Gender: "female", AssignedAt: assignedAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"bib":1,"range":"elite","chip_id":"CHIP-1","runner_id":"` + runnerID.String() +
+				`","runner_name":"Anna","gender":"female","assigned_at":"2025-10-01T08:00:00Z"}`,
+		},
+		{
+			name: "runner not registered",
+			body: `{"runner_id":"` + runnerID.String() + `","range":"elite"}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("AssignBib", raceID, runnerID, "elite", "").Return(bib.BibItem{}, domainRegistration.ErrNotRegistered)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":{"code":"not_registered","message":"runner is not registered for the race","field":"runner_id"}}`,
+		},
+		{
+			name: "range full",
+			body: `{"runner_id":"` + runnerID.String() + `","range":"elite"}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("AssignBib", raceID, runnerID, "elite", "").Return(bib.BibItem{}, domainBib.ErrRangeFull)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"range_full","message":"every bib number of the range is assigned","field":"range"}}`,
+		},
+		{
+			name:           "invalid runner ID",
+			body:           `{"runner_id":"invalid","range":"elite"}`,
+			mockSetup:      func(*mockBibService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid runner_id format","field":"runner_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockBibService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/bibs", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.AssignBib(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ReassignBib(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		runnerID       string
+		body           string
+		mockSetup      func(m *mockBibService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "reassigns the bib",
+			runnerID: runnerID.String(),
+			body:     `{"bib":42,"chip_id":"CHIP-42","reason":"lost bib"}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("ReassignBib", raceID, runnerID, 42, "", "CHIP-42", "lost bib").Return(bib.BibItem{
+					Number: 42, Range: "elite", ChipID: "CHIP-42", RunnerID: runnerID, AssignedAt: assignedAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"bib":42,"range":"elite","chip_id":"CHIP-42","runner_id":"` + runnerID.String() +
+				`","assigned_at":"2025-10-01T08:00:00Z"}`,
+		},
+		{
+			name:     "bib taken",
+			runnerID: runnerID.String(),
+			body:     `{"bib":42,"reason":"lost bib"}`,
+			mockSetup: func(m *mockBibService) {
+				m.On("ReassignBib", raceID, runnerID, 42, "", "", "lost bib").Return(bib.BibItem{}, domainBib.ErrNumberTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"bib_taken","message":"bib number is assigned to another runner","field":"bib"}}`,
+		},
+		{
+			name:           "invalid runner ID",
+			runnerID:       "invalid",
+			body:           `{"bib":42,"reason":"lost bib"}`,
+			mockSetup:      func(*mockBibService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid runner_id format","field":"runner_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockBibService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/races/"+raceID.String()+"/bibs/"+tt.runnerID, bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String(), "runnerID": tt.runnerID})
+			w := httptest.NewRecorder()
+			handler.ReassignBib(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ExportBibs(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+	bibs := []bib.BibItem{
+		{Number: 7, Range: "elite", ChipID: "CHIP-7", RunnerID: runnerID, RunnerName: "Anna, Jr.", Gender: "female", AssignedAt: assignedAt},
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		accept          string
+		mockSetup       func(m *mockBibService)
+		expectedStatus  int
+		expectedType    string
+		expectedBody    string
+		expectedJSONish bool
+	}{
+		{
+			name: "exports JSON by default",
+			mockSetup: func(m *mockBibService) {
+				m.On("ExportBibs", raceID).Return(bibs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedBody: `[{"bib":7,"range":"elite","chip_id":"CHIP-7","runner_id":"` + runnerID.String() +
+				`","runner_name":"Anna, Jr.","gender":"female","assigned_at":"2025-10-01T08:00:00Z"}]`,
+			expectedJSONish: true,
+		},
+		{
+			name:  "exports CSV",
+			query: "?format=csv",
+			mockSetup: func(m *mockBibService) {
+				m.On("ExportBibs", raceID).Return(bibs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv",
+			expectedBody:   "bib,chip_id,runner_id,runner_name,gender,range\n7,CHIP-7," + runnerID.String() + ",\"Anna, Jr.\",female,elite\n",
+		},
+		{
+			name:   "exports CSV for a text/csv Accept header",
+			accept: "text/csv",
+			mockSetup: func(m *mockBibService) {
+				m.On("ExportBibs", raceID).Return([]bib.BibItem{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv",
+			expectedBody:   "bib,chip_id,runner_id,runner_name,gender,range\n",
+		},
+		{
+			name:            "unknown format",
+			query:           "?format=xml",
+			mockSetup:       func(*mockBibService) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedType:    "application/json",
+			expectedBody:    `{"error":{"code":"invalid_parameter","message":"format must be json or csv","field":"format"}}`,
+			expectedJSONish: true,
+		},
+		{
+			name: "ranges not configured",
+			mockSetup: func(m *mockBibService) {
+				m.On("ExportBibs", raceID).Return([]bib.BibItem(nil), domainBib.ErrNotConfigured)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedType:    "application/json",
+			expectedBody:    `{"error":{"code":"bib_ranges_not_configured","message":"bib ranges are not configured for the race"}}`,
+			expectedJSONish: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockBibService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/bibs"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.ExportBibs(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			if tt.expectedJSONish {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetBibChanges(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+	mockService := new(mockBibService)
+	mockService.On("GetBibChanges", raceID).Return([]bib.BibChangeItem{{
+		RunnerID: runnerID, PreviousNumber: 1, Number: 42, PreviousChipID: "CHIP-1", ChipID: "CHIP-42", Reason: "lost bib", ChangedAt: assignedAt,
+	}}, nil)
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/bibs/changes", nil)
+	req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+	w := httptest.NewRecorder()
+	handler.GetBibChanges(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"runner_id":"`+runnerID.String()+`","previous_bib":1,"bib":42,"previous_chip_id":"CHIP-1",
+		"chip_id":"CHIP-42","reason":"lost bib","changed_at":"2025-10-01T08:00:00Z"}]`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
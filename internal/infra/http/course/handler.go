@@ -0,0 +1,182 @@
+// Package course contains the http handlers of the courses of races
+package course
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type courseService interface {
+	SetCourse(ctx context.Context, raceID uuid.UUID, format course.RouteFormat, file io.Reader, validateDistance bool) (course.CourseItem, error)
+	GetCourse(ctx context.Context, raceID uuid.UUID, profileStepKm float64) (course.CourseItem, error)
+	ExportCourse(ctx context.Context, raceID uuid.UUID, format course.RouteFormat, w io.Writer) error
+}
+
+// maxUploadSize bounds the size of the route files accepted by SetCourse
+const maxUploadSize = 32 << 20
+
+// contentTypes are the media types of the exported route files
+var contentTypes = map[course.RouteFormat]string{
+	course.RouteFormatGPX:     "application/gpx+xml",
+	course.RouteFormatGeoJSON: "application/geo+json",
+}
+
+// Handler course http request service
+type Handler struct {
+	courseService courseService
+}
+
+// NewHandler Constructor
+func NewHandler(service courseService) Handler {
+	return Handler{courseService: service}
+}
+
+// CheckpointModel represents a named point of the course at a distance from the start
+type CheckpointModel struct {
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	Lat        float64 `json:"lat"`
+	Lon        float64 `json:"lon"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// ProfilePointModel represents the elevation of the course at a distance from the start
+type ProfilePointModel struct {
+	DistanceKm float64 `json:"distance_km"`
+	ElevationM float64 `json:"elevation_m"`
+}
+
+// CourseResponse represents the response model of the course of a race
+type CourseResponse struct {
+	RaceID           uuid.UUID           `json:"race_id"`
+	DistanceKm       float64             `json:"distance_km"`
+	ElevationGain    float64             `json:"elevation_gain"`
+	ElevationLoss    float64             `json:"elevation_loss"`
+	Checkpoints      []CheckpointModel   `json:"checkpoints"`
+	ElevationProfile []ProfilePointModel `json:"elevation_profile"`
+}
+
+func toCourseResponse(item course.CourseItem) CourseResponse {
+	model := CourseResponse{
+		RaceID:           item.RaceID,
+		DistanceKm:       item.DistanceKm,
+		ElevationGain:    item.ElevationGain,
+		ElevationLoss:    item.ElevationLoss,
+		Checkpoints:      make([]CheckpointModel, len(item.Checkpoints)),
+		ElevationProfile: make([]ProfilePointModel, len(item.Profile)),
+	}
+	for i, c := range item.Checkpoints {
+		model.Checkpoints[i] = CheckpointModel{Name: c.Name, Kind: c.Kind, Lat: c.Lat, Lon: c.Lon, DistanceKm: c.DistanceKm}
+	}
+	for i, p := range item.Profile {
+		model.ElevationProfile[i] = ProfilePointModel{DistanceKm: p.DistanceKm, ElevationM: p.ElevationM}
+	}
+	return model
+}
+
+// SetCourse handles multipart requests that attach the route of a GPX or GeoJSON file to a race.
+// The form contains the file, an optional format that defaults to the file extension and an optional
+// validate_distance flag that rejects a course whose length does not match the distance of the race.
+func (h Handler) SetCourse(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, response.CodeMissingParameter, "file", "a multipart file field is required")
+		return
+	}
+	defer file.Close()
+
+	var validateDistance bool
+	if value := r.FormValue("validate_distance"); value != "" {
+		if validateDistance, err = strconv.ParseBool(value); err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "validate_distance", "validate_distance must be true or false")
+			return
+		}
+	}
+	var format course.RouteFormat
+	if value := r.FormValue("format"); value != "" {
+		format, err = course.ParseRouteFormat(value)
+	} else {
+		format, err = course.RouteFormatFromFilename(header.Filename)
+	}
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	item, err := h.courseService.SetCourse(r.Context(), raceID, format, file, validateDistance)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toCourseResponse(item))
+}
+
+// GetCourse handles requests to get the course of a race with its elevation profile.
+// The profile_step_km query parameter sets the distance between the profile points and defaults to 1 km.
+func (h Handler) GetCourse(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var profileStepKm float64
+	if value := r.URL.Query().Get("profile_step_km"); value != "" {
+		if profileStepKm, err = strconv.ParseFloat(value, 64); err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "profile_step_km", "profile_step_km must be a number")
+			return
+		}
+	}
+
+	item, err := h.courseService.GetCourse(r.Context(), raceID, profileStepKm)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toCourseResponse(item))
+}
+
+// ExportCourse handles requests to download the course of a race as a GPX file, or as GeoJSON with format=geojson
+func (h Handler) ExportCourse(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+	format := course.RouteFormatGPX
+	if value := r.URL.Query().Get("format"); value != "" {
+		if format, err = course.ParseRouteFormat(value); err != nil {
+			response.Error(w, err)
+			return
+		}
+	}
+
+	//the file is buffered so that errors are still reported as JSON
+	var file bytes.Buffer
+	if err := h.courseService.ExportCourse(r.Context(), raceID, format, &file); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="course-`+raceID.String()+`.`+string(format)+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = file.WriteTo(w)
+}
@@ -0,0 +1,279 @@
+package course
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCourseService struct {
+	mock.Mock
+}
+
+func (m *mockCourseService) SetCourse(_ context.Context, raceID uuid.UUID, format course.RouteFormat, _ io.Reader, validateDistance bool) (course.CourseItem, error) {
+	args := m.Called(raceID, format, validateDistance)
+	return args.Get(0).(course.CourseItem), args.Error(1)
+}
+
+func (m *mockCourseService) GetCourse(_ context.Context, raceID uuid.UUID, profileStepKm float64) (course.CourseItem, error) {
+	args := m.Called(raceID, profileStepKm)
+	return args.Get(0).(course.CourseItem), args.Error(1)
+}
+
+func (m *mockCourseService) ExportCourse(_ context.Context, raceID uuid.UUID, format course.RouteFormat, w io.Writer) error {
+	args := m.Called(raceID, format)
+	if args.Error(0) == nil {
+		_, _ = io.WriteString(w, "route")
+	}
+	return args.Error(0)
+}
+
+func newCourseItem(raceID uuid.UUID) course.CourseItem {
+	return course.CourseItem{
+		RaceID:        raceID,
+		DistanceKm:    2,
+		ElevationGain: 30,
+		ElevationLoss: 10,
+		Checkpoints:   []course.CheckpointItem{{Name: "Start", Kind: "start", Lat: 38, Lon: 23.7}},
+		Profile:       []course.ProfileItem{{DistanceKm: 0, ElevationM: 100}, {DistanceKm: 2, ElevationM: 120}},
+	}
+}
+
+const courseBody = `{"race_id":"%s","distance_km":2,"elevation_gain":30,"elevation_loss":10,
+"checkpoints":[{"name":"Start","kind":"start","lat":38,"lon":23.7,"distance_km":0}],
+"elevation_profile":[{"distance_km":0,"elevation_m":100},{"distance_km":2,"elevation_m":120}]}`
+
+func TestHandler_SetCourse(t *testing.T) {
+	raceID := uuid.New()
+	newRequest := func(filename string, fields map[string]string) *http.Request {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range fields {
+			_ = writer.WriteField(k, v)
+		}
+		if filename != "" {
+			part, _ := writer.CreateFormFile("file", filename)
+			_, _ = part.Write([]byte("route"))
+		}
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPut, "/races/"+raceID.String()+"/course", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+	}
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		mockSetup      func(m *mockCourseService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should set the course using the file extension",
+			req:  newRequest("marathon.gpx", nil),
+			mockSetup: func(m *mockCourseService) {
+				m.On("SetCourse", raceID, course.RouteFormatGPX, false).Return(newCourseItem(raceID), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       fmt.Sprintf(courseBody, raceID),
+		},
+		{
+			name: "should prefer the format field and validate the distance",
+			req:  newRequest("route.txt", map[string]string{"format": "geojson", "validate_distance": "true"}),
+			mockSetup: func(m *mockCourseService) {
+				m.On("SetCourse", raceID, course.RouteFormatGeoJSON, true).Return(newCourseItem(raceID), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       fmt.Sprintf(courseBody, raceID),
+		},
+		{
+			name: "should report a distance mismatch",
+			req:  newRequest("marathon.gpx", map[string]string{"validate_distance": "1"}),
+			mockSetup: func(m *mockCourseService) {
+				m.On("SetCourse", raceID, course.RouteFormatGPX, true).Return(course.CourseItem{}, race.ErrCourseDistanceMismatch)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody:       `{"error":{"code":"course_distance_mismatch","message":"declared distance differs from the course length by more than 2%","field":"file"}}`,
+		},
+		{
+			name:           "should reject an invalid validate_distance",
+			req:            newRequest("marathon.gpx", map[string]string{"validate_distance": "maybe"}),
+			mockSetup:      func(*mockCourseService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_parameter","message":"validate_distance must be true or false","field":"validate_distance"}}`,
+		},
+		{
+			name:           "should reject unsupported formats",
+			req:            newRequest("marathon.kml", nil),
+			mockSetup:      func(*mockCourseService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"unsupported_format","message":"route format must be one of gpx or geojson","field":"format"}}`,
+		},
+		{
+			name:           "should require a file",
+			req:            newRequest("", nil),
+			mockSetup:      func(*mockCourseService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"missing_parameter","message":"a multipart file field is required","field":"file"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockCourseService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.SetCourse(w, tt.req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetCourse(t *testing.T) {
+	raceID := uuid.New()
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mockCourseService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should return the course with the default profile step",
+			mockSetup: func(m *mockCourseService) {
+				m.On("GetCourse", raceID, 0.0).Return(newCourseItem(raceID), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       fmt.Sprintf(courseBody, raceID),
+		},
+		{
+			name:  "should pass the profile step",
+			query: "?profile_step_km=0.5",
+			mockSetup: func(m *mockCourseService) {
+				m.On("GetCourse", raceID, 0.5).Return(newCourseItem(raceID), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       fmt.Sprintf(courseBody, raceID),
+		},
+		{
+			name:           "should reject an invalid profile step",
+			query:          "?profile_step_km=km",
+			mockSetup:      func(*mockCourseService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_parameter","message":"profile_step_km must be a number","field":"profile_step_km"}}`,
+		},
+		{
+			name: "should report a race without course",
+			mockSetup: func(m *mockCourseService) {
+				m.On("GetCourse", raceID, 0.0).Return(course.CourseItem{}, race.ErrCourseNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":{"code":"course_not_found","message":"race has no course"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockCourseService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/course"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.GetCourse(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ExportCourse(t *testing.T) {
+	raceID := uuid.New()
+	tests := []struct {
+		name            string
+		query           string
+		mockSetup       func(m *mockCourseService)
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "should export gpx by default",
+			mockSetup: func(m *mockCourseService) {
+				m.On("ExportCourse", raceID, course.RouteFormatGPX).Return(nil)
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/gpx+xml",
+			wantBody:        "route",
+		},
+		{
+			name:  "should export geojson",
+			query: "?format=geojson",
+			mockSetup: func(m *mockCourseService) {
+				m.On("ExportCourse", raceID, course.RouteFormatGeoJSON).Return(nil)
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/geo+json",
+			wantBody:        "route",
+		},
+		{
+			name:            "should reject unsupported formats",
+			query:           "?format=kml",
+			mockSetup:       func(*mockCourseService) {},
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        `{"error":{"code":"unsupported_format","message":"route format must be one of gpx or geojson","field":"format"}}` + "\n",
+		},
+		{
+			name: "should report a race without course as json",
+			mockSetup: func(m *mockCourseService) {
+				m.On("ExportCourse", raceID, course.RouteFormatGPX).Return(race.ErrCourseNotFound)
+			},
+			wantStatusCode:  http.StatusNotFound,
+			wantContentType: "application/json",
+			wantBody:        `{"error":{"code":"course_not_found","message":"race has no course"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockCourseService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/course/export"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.ExportCourse(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func fmtRace(body string, raceID uuid.UUID) string {
+	return fmt.Sprintf(body, raceID)
+}
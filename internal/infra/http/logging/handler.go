@@ -0,0 +1,59 @@
+// Package logging contains the http handlers that administer the logging of the service
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type loggingService interface {
+	GetLevel(ctx context.Context) (logging.Level, error)
+	SetLevel(ctx context.Context, level string) (logging.Level, error)
+}
+
+// Handler logging http request service
+type Handler struct {
+	loggingService loggingService
+}
+
+// NewHandler Constructor
+func NewHandler(service loggingService) Handler {
+	return Handler{loggingService: service}
+}
+
+// LevelModel represents the minimum level of the logged events: debug, info, warn or error
+type LevelModel struct {
+	Level string `json:"level"`
+}
+
+// GetLevel handles requests to read the level of the logged events
+func (h Handler) GetLevel(w http.ResponseWriter, r *http.Request) {
+	level, err := h.loggingService.GetLevel(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, LevelModel{Level: string(level)})
+}
+
+// SetLevel handles requests to change the level of the logged events until the next restart
+func (h Handler) SetLevel(w http.ResponseWriter, r *http.Request) {
+	var levelRequest LevelModel
+	if err := json.NewDecoder(r.Body).Decode(&levelRequest); err != nil {
+		response.MalformedBody(w, err)
+		return
+	}
+
+	level, err := h.loggingService.SetLevel(r.Context(), levelRequest.Level)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, LevelModel{Level: string(level)})
+}
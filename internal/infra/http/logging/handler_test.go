@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLoggingService struct {
+	mock.Mock
+}
+
+func (m *mockLoggingService) GetLevel(_ context.Context) (logging.Level, error) {
+	args := m.Called()
+	return args.Get(0).(logging.Level), args.Error(1)
+}
+
+func (m *mockLoggingService) SetLevel(_ context.Context, level string) (logging.Level, error) {
+	args := m.Called(level)
+	return args.Get(0).(logging.Level), args.Error(1)
+}
+
+func TestHandler_GetLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(m *mockLoggingService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should return the level",
+			mockSetup: func(m *mockLoggingService) {
+				m.On("GetLevel").Return(logging.LevelInfo, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"level":"info"}`,
+		},
+		{
+			name: "should forbid callers other than admins",
+			mockSetup: func(m *mockLoggingService) {
+				m.On("GetLevel").Return(logging.Level(""), auth.ErrForbidden)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"error":{"code":"forbidden","message":"not allowed to perform this operation"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockLoggingService)
+			tt.mockSetup(service)
+			rr := httptest.NewRecorder()
+
+			NewHandler(service).GetLevel(rr, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SetLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockLoggingService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should change the level",
+			body: `{"level":"debug"}`,
+			mockSetup: func(m *mockLoggingService) {
+				m.On("SetLevel", "debug").Return(logging.LevelDebug, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"level":"debug"}`,
+		},
+		{
+			name: "should reject unknown levels",
+			body: `{"level":"verbose"}`,
+			mockSetup: func(m *mockLoggingService) {
+				m.On("SetLevel", "verbose").Return(logging.Level(""), logging.ErrInvalidLevel)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_level","message":"level must be one of \"debug\", \"info\", \"warn\" or \"error\"","field":"level"}}`,
+		},
+		{
+			name:           "should reject malformed bodies",
+			body:           `{`,
+			mockSetup:      func(*mockLoggingService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"malformed_body","message":"unexpected EOF"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockLoggingService)
+			tt.mockSetup(service)
+			rr := httptest.NewRecorder()
+
+			NewHandler(service).SetLevel(rr, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatusCode, rr.Code)
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/request"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+// Middleware decorates a handler with behaviour shared by every request
+type Middleware func(http.Handler) http.Handler
+
+// chain wraps the handler with the middlewares, the first middleware being the outermost
+func chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// requestIDHeader carries the identifier of a request, from the caller or generated by the server
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the identifiers accepted from callers
+const maxRequestIDLength = 128
+
+// requestIDMiddleware propagates the X-Request-ID header of every request, or generates one when the header is
+// missing or invalid, returns it in the response and passes it to the application services through the context.
+func requestIDMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(requestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(request.WithID(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID accepts non-empty identifiers of printable ASCII characters without spaces
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// responseWriter records the status and the size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// recordResponse wraps w in a responseWriter, reusing w when it already is one
+func recordResponse(w http.ResponseWriter) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+	return &responseWriter{ResponseWriter: w}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// accessLogMiddleware logs every request with its method, the route template it matched, its status, latency and
+// response size. The route template rather than the path keeps identifiers out of the logged route.
+func accessLogMiddleware(logger logging.Logger, router *mux.Router) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := recordResponse(w)
+			defer func() {
+				status := rw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				logger.Info(r.Context(), "http request",
+					logging.String("method", r.Method),
+					logging.String("route", routeTemplate(router, r)),
+					logging.Int("status", status),
+					logging.Any("latency", time.Since(start)),
+					logging.Int("bytes", rw.bytes),
+				)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// metricsMiddleware records the count and the latency of every request by its method, the route template it
+// matched and its status
+func metricsMiddleware(metrics RequestMetrics, router *mux.Router) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := recordResponse(w)
+			defer func() {
+				status := rw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				metrics.ObserveRequest(r.Method, routeTemplate(router, r), status, time.Since(start))
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the route matching the request, or "unmatched"
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return "unmatched"
+	}
+	template, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
+
+// errPanic is reported to the callers of requests whose handler panicked
+var errPanic = errors.New("handler panicked")
+
+// recoveryMiddleware turns the panics of the handlers into a JSON 500 response, logging the panic with its stack.
+// The response is left as is when the handler already started writing it.
+func recoveryMiddleware(logger logging.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := recordResponse(w)
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				logger.Error(r.Context(), "http handler panicked",
+					logging.String("panic", fmt.Sprint(recovered)),
+					logging.String("stack", string(debug.Stack())),
+				)
+				if rw.status == 0 {
+					response.Error(rw, errPanic)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// bodyLimitMiddleware bounds the request bodies to limit bytes, rejecting larger bodies with 413 Request Entity
+// Too Large. Multipart uploads are bounded by the upload limit of their handlers instead. A zero limit disables it.
+func bodyLimitMiddleware(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				response.RequestTooLarge(w, limit)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeoutMiddleware bounds the context of every request by the provided timeout.
+// A zero timeout disables the deadline.
+func timeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authMiddleware authenticates the caller of every request with the X-API-Key header or the bearer token of the
+// Authorization header, and passes its principal to the application services through the request context.
+// Requests without credentials carry no principal, so only the operations open to everyone succeed, and
+// requests with invalid credentials are rejected. When disabled the credentials are ignored and every request
+// carries no principal, so a disabled API only serves the operations open to everyone.
+func authMiddleware(authenticator authService, enabled bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			credentials := auth.Credentials{APIKey: r.Header.Get("X-API-Key")}
+			if authorization := r.Header.Get("Authorization"); authorization != "" {
+				scheme, token, _ := strings.Cut(authorization, " ")
+				if !strings.EqualFold(scheme, "Bearer") || token == "" {
+					response.Error(w, auth.ErrInvalidCredentials)
+					return
+				}
+				credentials.BearerToken = token
+			}
+			if credentials.APIKey == "" && credentials.BearerToken == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), credentials)
+			if err != nil {
+				response.Error(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
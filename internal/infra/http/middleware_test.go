@@ -0,0 +1,453 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/request"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler := chain(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		order = append(order, "handler")
+	}), trace("first"), trace("second"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"first", "second", "handler"}, order)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{
+			name:     "should propagate the request id of the caller",
+			header:   "7f3c-checkout-42",
+			wantSame: true,
+		},
+		{
+			name:   "should generate a request id when missing",
+			header: "",
+		},
+		{
+			name:   "should replace an invalid request id",
+			header: "two words",
+		},
+		{
+			name:   "should replace a request id that is too long",
+			header: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contextID string
+			handler := requestIDMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				contextID = request.IDFrom(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.NotEmpty(t, contextID)
+			assert.Equal(t, contextID, rr.Header().Get(requestIDHeader))
+			if tt.wantSame {
+				assert.Equal(t, tt.header, contextID)
+			} else {
+				assert.NotEqual(t, tt.header, contextID)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/runners/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "created")
+	}).Methods(http.MethodPost)
+	router.HandleFunc("/empty", func(http.ResponseWriter, *http.Request) {})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantRoute  string
+		wantStatus int
+		wantBytes  int
+	}{
+		{
+			name:       "should log the route template instead of the path",
+			method:     http.MethodPost,
+			path:       "/runners/6f1c7c7e-5d3b-4f7e-9a51-8d2f0b8a9c11",
+			wantRoute:  "/runners/{id}",
+			wantStatus: http.StatusCreated,
+			wantBytes:  7,
+		},
+		{
+			name:       "should log an implicit 200 for empty responses",
+			method:     http.MethodGet,
+			path:       "/empty",
+			wantRoute:  "/empty",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should log requests that match no route",
+			method:     http.MethodGet,
+			path:       "/unknown",
+			wantRoute:  "unmatched",
+			wantStatus: http.StatusNotFound,
+			wantBytes:  19,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := new(logging.RecordingLogger)
+			handler := accessLogMiddleware(logger, router)(router)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			records := logger.Records()
+			require.Len(t, records, 1)
+			assert.Equal(t, logging.LevelInfo, records[0].Level)
+			assert.Equal(t, "http request", records[0].Message)
+			for key, want := range map[string]any{"method": tt.method, "route": tt.wantRoute, "status": tt.wantStatus, "bytes": tt.wantBytes} {
+				got, _ := records[0].Field(key)
+				assert.Equal(t, want, got, key)
+			}
+			_, hasLatency := records[0].Field("latency")
+			assert.True(t, hasLatency)
+		})
+	}
+}
+
+// observedRequest is a request recorded by recordingMetrics
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+// recordingMetrics records the requests it observes and serves a fixed body as metrics
+type recordingMetrics struct {
+	observed []observedRequest
+}
+
+func (m *recordingMetrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.observed = append(m.observed, observedRequest{method: method, route: route, status: status})
+}
+
+func (m *recordingMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "# metrics")
+	})
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/runners/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods(http.MethodPost)
+	router.HandleFunc("/empty", func(http.ResponseWriter, *http.Request) {})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   observedRequest
+	}{
+		{
+			name:   "should record the route template instead of the path",
+			method: http.MethodPost,
+			path:   "/runners/6f1c7c7e-5d3b-4f7e-9a51-8d2f0b8a9c11",
+			want:   observedRequest{method: http.MethodPost, route: "/runners/{id}", status: http.StatusCreated},
+		},
+		{
+			name:   "should record an implicit 200 for empty responses",
+			method: http.MethodGet,
+			path:   "/empty",
+			want:   observedRequest{method: http.MethodGet, route: "/empty", status: http.StatusOK},
+		},
+		{
+			name:   "should record requests that match no route",
+			method: http.MethodGet,
+			path:   "/unknown",
+			want:   observedRequest{method: http.MethodGet, route: "unmatched", status: http.StatusNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := new(recordingMetrics)
+			handler := metricsMiddleware(metrics, router)(router)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, []observedRequest{tt.want}, metrics.observed)
+		})
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "should return a JSON 500 when the handler panics",
+			handler: func(http.ResponseWriter, *http.Request) {
+				panic("nil map")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":{"code":"internal_error","message":"Internal Server Error"}}`,
+		},
+		{
+			name: "should keep the response the handler started writing",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("nil map")
+			},
+			wantStatus: http.StatusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := new(logging.RecordingLogger)
+			rr := httptest.NewRecorder()
+
+			recoveryMiddleware(logger)(tt.handler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+			records := logger.Records()
+			require.Len(t, records, 1)
+			assert.Equal(t, logging.LevelError, records[0].Level)
+			panicValue, _ := records[0].Field("panic")
+			assert.Equal(t, "nil map", panicValue)
+			stack, _ := records[0].Field("stack")
+			assert.NotEmpty(t, stack)
+		})
+	}
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int64
+		body        string
+		contentType string
+		unknownSize bool
+		wantStatus  int
+	}{
+		{
+			name:       "should accept a body within the limit",
+			limit:      16,
+			body:       `{"name":"anna"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should reject a declared length over the limit",
+			limit:      8,
+			body:       `{"name":"anna"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "should reject a streamed body over the limit",
+			limit:       8,
+			body:        `{"name":"anna"}`,
+			unknownSize: true,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "should leave multipart uploads to their handlers",
+			limit:       8,
+			body:        `{"name":"anna"}`,
+			contentType: "multipart/form-data; boundary=x",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "should accept any body when disabled",
+			limit:      0,
+			body:       `{"name":"anna"}`,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := bodyLimitMiddleware(tt.limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					response.MalformedBody(w, err)
+				}
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.unknownSize {
+				req.ContentLength = -1
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{
+			name:         "should set a deadline on the request context",
+			timeout:      time.Second,
+			wantDeadline: true,
+		},
+		{
+			name:         "should leave the request context untouched when disabled",
+			timeout:      0,
+			wantDeadline: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			handler := timeoutMiddleware(tt.timeout)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantDeadline, hasDeadline)
+		})
+	}
+}
+
+type mockAuthService struct {
+	mock.Mock
+}
+
+func (m *mockAuthService) Authenticate(_ context.Context, credentials auth.Credentials) (auth.Principal, error) {
+	args := m.Called(credentials)
+	return args.Get(0).(auth.Principal), args.Error(1)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	organiser := auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser}
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		headers       map[string]string
+		mockSetup     func(m *mockAuthService)
+		wantStatus    int
+		wantPrincipal *auth.Principal
+	}{
+		{
+			name:       "should ignore the credentials without a principal when disabled",
+			enabled:    false,
+			headers:    map[string]string{"X-API-Key": "organiser-key"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should pass requests without credentials without a principal",
+			enabled:    true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "should authenticate the api key",
+			enabled: true,
+			headers: map[string]string{"X-API-Key": "organiser-key"},
+			mockSetup: func(m *mockAuthService) {
+				m.On("Authenticate", auth.Credentials{APIKey: "organiser-key"}).Return(organiser, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &organiser,
+		},
+		{
+			name:    "should authenticate the bearer token",
+			enabled: true,
+			headers: map[string]string{"Authorization": "Bearer token"},
+			mockSetup: func(m *mockAuthService) {
+				m.On("Authenticate", auth.Credentials{BearerToken: "token"}).Return(organiser, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &organiser,
+		},
+		{
+			name:    "should reject invalid credentials",
+			enabled: true,
+			headers: map[string]string{"X-API-Key": "unknown"},
+			mockSetup: func(m *mockAuthService) {
+				m.On("Authenticate", auth.Credentials{APIKey: "unknown"}).Return(auth.Principal{}, auth.ErrInvalidCredentials)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "should reject other authorization schemes",
+			enabled:    true,
+			headers:    map[string]string{"Authorization": "Basic YW5uYTpzZWNyZXQ="},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := new(mockAuthService)
+			if tt.mockSetup != nil {
+				tt.mockSetup(authService)
+			}
+			var principal *auth.Principal
+			handler := authMiddleware(authService, tt.enabled)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				if p, ok := auth.PrincipalFrom(r.Context()); ok {
+					principal = &p
+				}
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantPrincipal, principal)
+			authService.AssertExpectations(t)
+		})
+	}
+}
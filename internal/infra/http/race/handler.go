@@ -2,21 +2,38 @@
 package race
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 type raceTrackerService interface {
-	CreateRace(name, location string, date time.Time, distanceKm, elevationGain float64) (uuid.UUID, error)
-	AddResult(runnerID, raceID uuid.UUID, finishTime time.Duration, heartRateAvg int, notes string) (uuid.UUID, error)
-	GetResults(runnerID uuid.UUID) ([]race.ResultItem, error)
+	CreateRace(ctx context.Context, name, location string, date time.Time, distanceKm, elevationGain float64, waves []race.WaveItem, rankingPolicy string) (uuid.UUID, error)
+	GetRace(ctx context.Context, raceID uuid.UUID) (race.RaceItem, error)
+	ListRaces(ctx context.Context, filter race.RaceFilterItem) (race.RacePage, error)
+	UpdateRace(ctx context.Context, raceID uuid.UUID, changes race.RaceChangesItem) (race.RaceItem, error)
+	CancelRace(ctx context.Context, raceID uuid.UUID, reason string) (race.RaceItem, error)
+	AddResult(ctx context.Context, runnerID, raceID uuid.UUID, status, statusReason string, finishTime, gunTime time.Duration, heartRateAvg int, notes string, splits []race.SplitItem) (race.AddedResultItem, error)
+	GetResults(ctx context.Context, runnerID uuid.UUID, status string) ([]race.ResultItem, error)
+	PublishResults(ctx context.Context, raceID uuid.UUID, reason string) (int, error)
+	AmendResult(ctx context.Context, raceID, resultID uuid.UUID, status, statusReason string, finishTime time.Duration, reason string) error
+	GetPersonalRecords(ctx context.Context, runnerID uuid.UUID) ([]race.PersonalRecordItem, error)
+	GetLeaderboard(ctx context.Context, raceID uuid.UUID) ([]race.StandingItem, error)
+	ImportResult(ctx context.Context, runnerID, raceID uuid.UUID, format activity.Format, file io.Reader, notes string) (race.ImportedResultItem, error)
 }
 
+// maxUploadSize bounds the size of the activity files accepted by ImportResult
+const maxUploadSize = 32 << 20
+
 // Handler raceTracker http request service
 type Handler struct {
 	raceTrackerService raceTrackerService
@@ -27,13 +44,22 @@ func NewHandler(service raceTrackerService) Handler {
 	return Handler{raceTrackerService: service}
 }
 
-// CreateRaceRequestModel represents the request model expected for creating a race
+// WaveModel represents a group of runners released by the same start signal
+type WaveModel struct {
+	Name      string    `json:"name"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// CreateRaceRequestModel represents the request model expected for creating a race.
+// RankingPolicy is one of gun or net and defaults to gun.
 type CreateRaceRequestModel struct {
-	Name          string    `json:"name"`
-	Location      string    `json:"location"`
-	Date          time.Time `json:"date"`
-	DistanceKm    float64   `json:"distance_km"`
-	ElevationGain float64   `json:"elevation_gain"`
+	Name          string      `json:"name"`
+	Location      string      `json:"location"`
+	Date          time.Time   `json:"date"`
+	DistanceKm    float64     `json:"distance_km"`
+	ElevationGain float64     `json:"elevation_gain"`
+	Waves         []WaveModel `json:"waves"`
+	RankingPolicy string      `json:"ranking_policy"`
 }
 
 // CreateRace handles requests to create a new race
@@ -41,138 +67,727 @@ func (h Handler) CreateRace(w http.ResponseWriter, r *http.Request) {
 	var raceRequest CreateRaceRequestModel
 	decodeErr := json.NewDecoder(r.Body).Decode(&raceRequest)
 	if decodeErr != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, decodeErr.Error())
+		response.MalformedBody(w, decodeErr)
 		return
 	}
 
+	waves := make([]race.WaveItem, len(raceRequest.Waves))
+	for i, wave := range raceRequest.Waves {
+		waves[i] = race.WaveItem{Name: wave.Name, StartTime: wave.StartTime}
+	}
+
 	id, err := h.raceTrackerService.CreateRace(
+		r.Context(),
 		raceRequest.Name,
 		raceRequest.Location,
 		raceRequest.Date,
 		raceRequest.DistanceKm,
 		raceRequest.ElevationGain,
+		waves,
+		raceRequest.RankingPolicy,
 	)
 
 	if err != nil {
-		switch err {
-		case race.ErrEmptyRaceID, race.ErrEmptyRunnerID, race.ErrInvalidAvgHR, race.ErrInvalidFinishTime:
-			w.WriteHeader(http.StatusBadRequest)
-		default:
-			w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, "/races/"+id.String(), id)
+}
+
+// RaceResponse represents the response model of a race.
+// Organiser is only set for races created by an organiser, and the cancellation fields only for cancelled races.
+type RaceResponse struct {
+	ID                 uuid.UUID   `json:"id"`
+	Name               string      `json:"name"`
+	Location           string      `json:"location"`
+	Date               time.Time   `json:"date"`
+	DistanceKm         float64     `json:"distance_km"`
+	ElevationGain      float64     `json:"elevation_gain"`
+	Waves              []WaveModel `json:"waves,omitempty"`
+	RankingPolicy      string      `json:"ranking_policy"`
+	Organiser          string      `json:"organiser,omitempty"`
+	CancelledAt        *time.Time  `json:"cancelled_at,omitempty"`
+	CancellationReason string      `json:"cancellation_reason,omitempty"`
+}
+
+// GetRace handles requests to retrieve a race
+func (h Handler) GetRace(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	item, err := h.raceTrackerService.GetRace(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRaceResponse(item))
+}
+
+func toRaceResponse(item race.RaceItem) RaceResponse {
+	raceResponse := RaceResponse{
+		ID:                 item.ID,
+		Name:               item.Name,
+		Location:           item.Location,
+		Date:               item.Date,
+		DistanceKm:         item.DistanceKm,
+		ElevationGain:      item.ElevationGain,
+		RankingPolicy:      item.RankingPolicy,
+		Organiser:          item.Organiser,
+		CancellationReason: item.CancellationReason,
+	}
+	if !item.CancelledAt.IsZero() {
+		raceResponse.CancelledAt = &item.CancelledAt
+	}
+	for _, wave := range item.Waves {
+		raceResponse.Waves = append(raceResponse.Waves, WaveModel{Name: wave.Name, StartTime: wave.StartTime})
+	}
+	return raceResponse
+}
+
+// RaceListResponse represents the response model of a page of races.
+// NextCursor is omitted on the last page.
+type RaceListResponse struct {
+	Races      []RaceResponse `json:"races"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Limit      int            `json:"limit"`
+}
+
+// ListRaces handles requests to list races filtered by the query parameters
+func (h Handler) ListRaces(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := race.RaceFilterItem{
+		Location: query.Get("location"),
+		Text:     query.Get("q"),
+		Sort:     query.Get("sort"),
+		Order:    query.Get("order"),
+		Cursor:   query.Get("cursor"),
+	}
+	var err error
+	if filter.From, err = dateQueryParam(r, "date_from", false); err != nil {
+		response.BadRequest(w, response.CodeInvalidParameter, "date_from", "date_from must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		return
+	}
+	if filter.To, err = dateQueryParam(r, "date_to", true); err != nil {
+		response.BadRequest(w, response.CodeInvalidParameter, "date_to", "date_to must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		return
+	}
+	bounds := []struct {
+		name  string
+		bound **float64
+	}{
+		{"min_distance_km", &filter.MinDistanceKm},
+		{"max_distance_km", &filter.MaxDistanceKm},
+		{"min_elevation_gain", &filter.MinElevationGain},
+		{"max_elevation_gain", &filter.MaxElevationGain},
+	}
+	for _, b := range bounds {
+		if *b.bound, err = floatQueryParam(r, b.name); err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, b.name, b.name+" must be a number")
+			return
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "limit", "limit query parameter must be an integer")
+			return
+		}
+	}
+
+	page, err := h.raceTrackerService.ListRaces(r.Context(), filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	list := RaceListResponse{Races: make([]RaceResponse, len(page.Races)), NextCursor: page.NextCursor, Limit: page.Limit}
+	for i, item := range page.Races {
+		list.Races[i] = toRaceResponse(item)
+	}
+	response.JSON(w, http.StatusOK, list)
+}
+
+// dateQueryParam parses a date or an RFC 3339 time query parameter, returning the zero time when it is missing.
+// A date is the start of the day in UTC, or the start of the next day when it ends an inclusive range.
+func dateQueryParam(r *http.Request, name string, endOfRange bool) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfRange {
+			return date.AddDate(0, 0, 1), nil
 		}
-		fmt.Fprint(w, err.Error())
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// floatQueryParam parses a number query parameter, returning nil when it is missing
+func floatQueryParam(r *http.Request, name string) (*float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
+
+// ReplaceRaceRequestModel represents the request model for replacing the details of a race.
+// Every field is required; the waves move with the date of the race.
+type ReplaceRaceRequestModel struct {
+	Name          string    `json:"name"`
+	Location      string    `json:"location"`
+	Date          time.Time `json:"date"`
+	DistanceKm    float64   `json:"distance_km"`
+	ElevationGain float64   `json:"elevation_gain"`
+}
+
+// ReplaceRace handles requests to replace the details of a race
+func (h Handler) ReplaceRace(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var replaceRequest ReplaceRaceRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&replaceRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
 		return
 	}
 
-	w.Write([]byte(id.String()))
+	h.updateRace(w, r, raceID, race.RaceChangesItem{
+		Name:          &replaceRequest.Name,
+		Location:      &replaceRequest.Location,
+		Date:          &replaceRequest.Date,
+		DistanceKm:    &replaceRequest.DistanceKm,
+		ElevationGain: &replaceRequest.ElevationGain,
+	})
 }
 
-// AddResultRequestModel represents the request model for adding a race result
+// UpdateRaceRequestModel represents the request model for changing some details of a race.
+// Omitted fields are left unchanged.
+type UpdateRaceRequestModel struct {
+	Name          *string    `json:"name"`
+	Location      *string    `json:"location"`
+	Date          *time.Time `json:"date"`
+	DistanceKm    *float64   `json:"distance_km"`
+	ElevationGain *float64   `json:"elevation_gain"`
+}
+
+// UpdateRace handles requests to change some details of a race
+func (h Handler) UpdateRace(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var updateRequest UpdateRaceRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&updateRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	h.updateRace(w, r, raceID, race.RaceChangesItem(updateRequest))
+}
+
+func (h Handler) updateRace(w http.ResponseWriter, r *http.Request, raceID uuid.UUID, changes race.RaceChangesItem) {
+	item, err := h.raceTrackerService.UpdateRace(r.Context(), raceID, changes)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRaceResponse(item))
+}
+
+// CancelRaceRequestModel represents the request model for cancelling a race
+type CancelRaceRequestModel struct {
+	Reason string `json:"reason"`
+}
+
+// CancelRace handles requests to cancel a race
+func (h Handler) CancelRace(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var cancelRequest CancelRaceRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&cancelRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	item, err := h.raceTrackerService.CancelRace(r.Context(), raceID, cancelRequest.Reason)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRaceResponse(item))
+}
+
+// SplitModel represents the cumulative time of a result at a distance marker
+type SplitModel struct {
+	DistanceKm float64 `json:"distance_km"`
+	ElapsedMs  int64   `json:"elapsed_ms"`
+}
+
+// AddResultRequestModel represents the request model for adding a race result.
+// Status is one of finished, dnf, dns or dsq and defaults to finished. FinishTimeMs is the net (chip) time
+// and GunTimeMs, the time from the start signal of the wave, defaults to it.
 type AddResultRequestModel struct {
-	RunnerID     string  `json:"runner_id"`
-	RaceID       string  `json:"race_id"`
-	FinishTimeMs int64   `json:"finish_time_ms"`
-	Pace         float64 `json:"pace"`
-	HeartRateAvg int     `json:"heart_rate_avg"`
-	Notes        string  `json:"notes"`
+	RunnerID     string       `json:"runner_id"`
+	RaceID       string       `json:"race_id"`
+	Status       string       `json:"status"`
+	StatusReason string       `json:"status_reason"`
+	FinishTimeMs int64        `json:"finish_time_ms"`
+	GunTimeMs    int64        `json:"gun_time_ms"`
+	Pace         float64      `json:"pace"`
+	HeartRateAvg int          `json:"heart_rate_avg"`
+	Notes        string       `json:"notes"`
+	Splits       []SplitModel `json:"splits"`
+}
+
+// AddResultResponse represents the response model of a created result
+type AddResultResponse struct {
+	ID             uuid.UUID `json:"id"`
+	PersonalRecord bool      `json:"personal_record"`
 }
 
-// AddResult handles requests to add a new race result
+// AddResult handles requests to add a new race result.
+// The race ID of the path takes precedence over the race_id of the body.
 func (h Handler) AddResult(w http.ResponseWriter, r *http.Request) {
 	var resultRequest AddResultRequestModel
 	decodeErr := json.NewDecoder(r.Body).Decode(&resultRequest)
 	if decodeErr != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, decodeErr.Error())
+		response.MalformedBody(w, decodeErr)
 		return
 	}
 
 	runnerID, err := uuid.Parse(resultRequest.RunnerID)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, "Invalid runner ID format")
+		response.InvalidID(w, "runner_id")
 		return
 	}
 
-	raceID, err := uuid.Parse(resultRequest.RaceID)
+	raceIDStr := resultRequest.RaceID
+	if pathRaceID, ok := mux.Vars(r)["raceID"]; ok {
+		if raceIDStr != "" && raceIDStr != pathRaceID {
+			response.BadRequest(w, response.CodeInvalidParameter, "race_id", "race_id does not match the race of the path")
+			return
+		}
+		raceIDStr = pathRaceID
+	}
+	raceID, err := uuid.Parse(raceIDStr)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, "Invalid race ID format")
+		response.InvalidID(w, "race_id")
 		return
 	}
 
 	finishTime := time.Duration(resultRequest.FinishTimeMs) * time.Millisecond
+	splits := make([]race.SplitItem, len(resultRequest.Splits))
+	for i, split := range resultRequest.Splits {
+		splits[i] = race.SplitItem{DistanceKm: split.DistanceKm, Elapsed: time.Duration(split.ElapsedMs) * time.Millisecond}
+	}
 
-	id, err := h.raceTrackerService.AddResult(
+	added, err := h.raceTrackerService.AddResult(
+		r.Context(),
 		runnerID,
 		raceID,
+		resultRequest.Status,
+		resultRequest.StatusReason,
 		finishTime,
+		time.Duration(resultRequest.GunTimeMs)*time.Millisecond,
 		resultRequest.HeartRateAvg,
 		resultRequest.Notes,
+		splits,
 	)
 
 	if err != nil {
-		if errors.Is(err, race.ErrEmptyRunnerID) || errors.Is(err, race.ErrEmptyRaceID) || errors.Is(err, race.ErrInvalidFinishTime) || errors.Is(err, race.ErrInvalidAvgHR) {
-			w.WriteHeader(http.StatusBadRequest)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
-		}
-		fmt.Fprint(w, err.Error())
+		response.Error(w, err)
 		return
 	}
 
-	w.Write([]byte(id.String()))
+	response.JSON(w, http.StatusCreated, AddResultResponse{ID: added.ID, PersonalRecord: added.PersonalRecord})
+}
+
+// SegmentResponse represents the response model of the stretch between two distance markers
+type SegmentResponse struct {
+	StartKm float64 `json:"start_km"`
+	EndKm   float64 `json:"end_km"`
+	TimeMs  int64   `json:"time_ms"`
+	Pace    float64 `json:"pace"`
+}
+
+// SplitAnalysisResponse represents the response model of the metrics derived from the splits of a result
+type SplitAnalysisResponse struct {
+	Segments               []SegmentResponse `json:"segments"`
+	FirstHalfMs            int64             `json:"first_half_ms"`
+	SecondHalfMs           int64             `json:"second_half_ms"`
+	SplitPercent           float64           `json:"split_percent"`
+	NegativeSplit          bool              `json:"negative_split"`
+	Fastest                SegmentResponse   `json:"fastest"`
+	Slowest                SegmentResponse   `json:"slowest"`
+	PaceVariabilityPercent float64           `json:"pace_variability_percent"`
 }
 
 // ResultResponse represents the response model for race results
 type ResultResponse struct {
-	ID           uuid.UUID `json:"id"`
-	RunnerID     uuid.UUID `json:"runner_id"`
-	RaceID       uuid.UUID `json:"race_id"`
-	FinishTime   int64     `json:"finish_time_ms"`
-	Pace         float64   `json:"pace"`
-	HeartRateAvg int       `json:"heart_rate_avg"`
-	Notes        string    `json:"notes"`
+	ID                uuid.UUID              `json:"id"`
+	RunnerID          uuid.UUID              `json:"runner_id"`
+	RaceID            uuid.UUID              `json:"race_id"`
+	Status            string                 `json:"status"`
+	StatusReason      string                 `json:"status_reason,omitempty"`
+	Publication       string                 `json:"publication"`
+	PublicationReason string                 `json:"publication_reason,omitempty"`
+	FinishTime        int64                  `json:"finish_time_ms"`
+	GunTime           int64                  `json:"gun_time_ms"`
+	Pace              float64                `json:"pace"`
+	HeartRateAvg      int                    `json:"heart_rate_avg"`
+	Notes             string                 `json:"notes"`
+	Splits            []SplitModel           `json:"splits,omitempty"`
+	SplitAnalysis     *SplitAnalysisResponse `json:"split_analysis,omitempty"`
+	AgeGrade          *AgeGradeResponse      `json:"age_grade,omitempty"`
 }
 
-// GetRaceResults handles requests to retrieve race results for a runner
+// AgeGradeResponse represents the response model of a result adjusted for the age and the gender of the runner
+type AgeGradeResponse struct {
+	Factor       float64 `json:"factor"`
+	Percent      float64 `json:"percent"`
+	GradedTimeMs int64   `json:"graded_time_ms"`
+}
+
+func toAgeGradeResponse(grade *race.AgeGradeItem) *AgeGradeResponse {
+	if grade == nil {
+		return nil
+	}
+	return &AgeGradeResponse{Factor: grade.Factor, Percent: grade.Percent, GradedTimeMs: grade.GradedTime.Milliseconds()}
+}
+
+// GetRaceResults handles requests to retrieve the race results of a runner, optionally filtered by status
 func (h Handler) GetRaceResults(w http.ResponseWriter, r *http.Request) {
-	runnerIDStr := r.URL.Query().Get("runner_id")
-	if runnerIDStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, "runner_id query parameter is required")
+	runnerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.InvalidID(w, "id")
 		return
 	}
 
-	runnerID, err := uuid.Parse(runnerIDStr)
+	results, err := h.raceTrackerService.GetResults(r.Context(), runnerID, r.URL.Query().Get("status"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, "Invalid runner ID format")
+		response.Error(w, err)
 		return
 	}
 
-	results, err := h.raceTrackerService.GetResults(runnerID)
+	resultsResponse := make([]ResultResponse, len(results))
+	for i, result := range results {
+		resultsResponse[i] = toResultResponse(result)
+	}
+
+	response.JSON(w, http.StatusOK, resultsResponse)
+}
+
+func toResultResponse(result race.ResultItem) ResultResponse {
+	model := ResultResponse{
+		ID:                result.ID,
+		RunnerID:          result.RunnerID,
+		RaceID:            result.RaceID,
+		Status:            result.Status,
+		StatusReason:      result.StatusReason,
+		Publication:       result.Publication,
+		PublicationReason: result.PublicationReason,
+		FinishTime:        result.FinishTime.Milliseconds(),
+		GunTime:           result.GunTime.Milliseconds(),
+		Pace:              result.PaceMinPerKm,
+		HeartRateAvg:      result.HeartRateAvg,
+		Notes:             result.Notes,
+		AgeGrade:          toAgeGradeResponse(result.AgeGrade),
+	}
+	for _, split := range result.Splits {
+		model.Splits = append(model.Splits, SplitModel{DistanceKm: split.DistanceKm, ElapsedMs: split.Elapsed.Milliseconds()})
+	}
+	if analysis := result.SplitAnalysis; analysis != nil {
+		model.SplitAnalysis = &SplitAnalysisResponse{
+			Segments:               make([]SegmentResponse, len(analysis.Segments)),
+			FirstHalfMs:            analysis.FirstHalf.Milliseconds(),
+			SecondHalfMs:           analysis.SecondHalf.Milliseconds(),
+			SplitPercent:           analysis.SplitPercent,
+			NegativeSplit:          analysis.NegativeSplit,
+			Fastest:                toSegmentResponse(analysis.Fastest),
+			Slowest:                toSegmentResponse(analysis.Slowest),
+			PaceVariabilityPercent: analysis.PaceVariabilityPercent,
+		}
+		for i, segment := range analysis.Segments {
+			model.SplitAnalysis.Segments[i] = toSegmentResponse(segment)
+		}
+	}
+	return model
+}
+
+func toSegmentResponse(segment race.SegmentItem) SegmentResponse {
+	return SegmentResponse{
+		StartKm: segment.StartKm,
+		EndKm:   segment.EndKm,
+		TimeMs:  segment.Duration.Milliseconds(),
+		Pace:    segment.PaceMinPerKm,
+	}
+}
+
+// StandingResponse represents the response model of a result of the leaderboard.
+// The gender and age-group fields are omitted when the category of the runner is unknown,
+// and the position is 0 for runners that did not finish.
+type StandingResponse struct {
+	Position         int               `json:"position"`
+	GenderPosition   int               `json:"gender_position,omitempty"`
+	AgeGroupPosition int               `json:"age_group_position,omitempty"`
+	ResultID         uuid.UUID         `json:"result_id"`
+	RunnerID         uuid.UUID         `json:"runner_id"`
+	RunnerName       string            `json:"runner_name,omitempty"`
+	Gender           string            `json:"gender,omitempty"`
+	AgeGroup         string            `json:"age_group,omitempty"`
+	Status           string            `json:"status"`
+	FinishTime       int64             `json:"finish_time_ms"`
+	GunTime          int64             `json:"gun_time_ms"`
+	Pace             float64           `json:"pace"`
+	AgeGrade         *AgeGradeResponse `json:"age_grade,omitempty"`
+}
+
+// LeaderboardResponse represents the response model of a race leaderboard
+type LeaderboardResponse struct {
+	RaceID    uuid.UUID          `json:"race_id"`
+	Standings []StandingResponse `json:"standings"`
+}
+
+// GetLeaderboard handles requests to retrieve the ranked results of a race
+func (h Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprint(w, err.Error())
+		response.InvalidID(w, "race_id")
 		return
 	}
 
-	response := make([]ResultResponse, len(results))
-	for i, result := range results {
-		response[i] = ResultResponse{
-			ID:           result.ID,
-			RunnerID:     result.RunnerID,
-			RaceID:       result.RaceID,
-			FinishTime:   result.FinishTime.Milliseconds(),
-			Pace:         result.PaceMinPerKm,
-			HeartRateAvg: result.HeartRateAvg,
-			Notes:        result.Notes,
+	standings, err := h.raceTrackerService.GetLeaderboard(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaderboard := LeaderboardResponse{RaceID: raceID, Standings: make([]StandingResponse, len(standings))}
+	for i, s := range standings {
+		leaderboard.Standings[i] = StandingResponse{
+			Position:         s.Position,
+			GenderPosition:   s.GenderPosition,
+			AgeGroupPosition: s.AgeGroupPosition,
+			ResultID:         s.ResultID,
+			RunnerID:         s.RunnerID,
+			RunnerName:       s.RunnerName,
+			Gender:           s.Gender,
+			AgeGroup:         s.AgeGroup,
+			Status:           s.Status,
+			FinishTime:       s.FinishTime.Milliseconds(),
+			GunTime:          s.GunTime.Milliseconds(),
+			Pace:             s.PaceMinPerKm,
+			AgeGrade:         toAgeGradeResponse(s.AgeGrade),
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	response.JSON(w, http.StatusOK, leaderboard)
+}
+
+// PublishResultsRequestModel represents the request model for publishing the results of a race
+type PublishResultsRequestModel struct {
+	Reason string `json:"reason"`
+}
+
+// PublishResultsResponse represents the response model of published results
+type PublishResultsResponse struct {
+	Published int `json:"published"`
+}
+
+// PublishResults handles requests to publish the provisional results of a race as official.
+// The body with the reason of the publication is optional.
+func (h Handler) PublishResults(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var publishRequest PublishResultsRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&publishRequest); decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	published, err := h.raceTrackerService.PublishResults(r.Context(), raceID, publishRequest.Reason)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, PublishResultsResponse{Published: published})
+}
+
+// AmendResultRequestModel represents the request model for amending a published result
+type AmendResultRequestModel struct {
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason"`
+	FinishTimeMs int64  `json:"finish_time_ms"`
+	Reason       string `json:"reason"`
+}
+
+// AmendResult handles requests to correct the status and the finish time of a published result
+func (h Handler) AmendResult(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+	resultID, err := uuid.Parse(mux.Vars(r)["resultID"])
+	if err != nil {
+		response.InvalidID(w, "result_id")
+		return
+	}
+
+	var amendRequest AmendResultRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&amendRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	err = h.raceTrackerService.AmendResult(
+		r.Context(),
+		raceID,
+		resultID,
+		amendRequest.Status,
+		amendRequest.StatusReason,
+		time.Duration(amendRequest.FinishTimeMs)*time.Millisecond,
+		amendRequest.Reason,
+	)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ImportResultResponse represents the response model of a result created from an activity file
+type ImportResultResponse struct {
+	ID                 uuid.UUID    `json:"id"`
+	PersonalRecord     bool         `json:"personal_record"`
+	FinishTime         int64        `json:"finish_time_ms"`
+	HeartRateAvg       int          `json:"heart_rate_avg"`
+	Splits             []SplitModel `json:"splits,omitempty"`
+	RecordedDistanceKm float64      `json:"recorded_distance_km"`
+	RaceDistanceKm     float64      `json:"race_distance_km"`
+	DistanceMismatch   bool         `json:"distance_mismatch"`
+}
+
+// ImportResult handles multipart requests that create a result from a GPX, TCX or FIT activity file.
+// The form contains the runner_id, the file, optional notes and an optional format that defaults to the file extension.
+func (h Handler) ImportResult(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, response.CodeMissingParameter, "file", "a multipart file field is required")
+		return
+	}
+	defer file.Close()
+
+	runnerID, err := uuid.Parse(r.FormValue("runner_id"))
+	if err != nil {
+		response.InvalidID(w, "runner_id")
+		return
+	}
+
+	var format activity.Format
+	if value := r.FormValue("format"); value != "" {
+		format, err = activity.ParseFormat(value)
+	} else {
+		format, err = activity.FormatFromFilename(header.Filename)
+	}
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	imported, err := h.raceTrackerService.ImportResult(r.Context(), runnerID, raceID, format, file, r.FormValue("notes"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	model := ImportResultResponse{
+		ID:                 imported.ResultID,
+		PersonalRecord:     imported.PersonalRecord,
+		FinishTime:         imported.FinishTime.Milliseconds(),
+		HeartRateAvg:       imported.HeartRateAvg,
+		RecordedDistanceKm: imported.RecordedKm,
+		RaceDistanceKm:     imported.RaceDistanceKm,
+		DistanceMismatch:   imported.DistanceMismatch,
+	}
+	for _, split := range imported.Splits {
+		model.Splits = append(model.Splits, SplitModel{DistanceKm: split.DistanceKm, ElapsedMs: split.Elapsed.Milliseconds()})
+	}
+	response.JSON(w, http.StatusCreated, model)
+}
+
+// PersonalRecordResponse represents the response model of the fastest result of a runner over a distance
+type PersonalRecordResponse struct {
+	Distance     string    `json:"distance"`
+	DistanceKm   float64   `json:"distance_km"`
+	ResultID     uuid.UUID `json:"result_id"`
+	RaceID       uuid.UUID `json:"race_id"`
+	RaceName     string    `json:"race_name"`
+	RaceDate     time.Time `json:"race_date"`
+	FinishTimeMs int64     `json:"finish_time_ms"`
+	Pace         float64   `json:"pace"`
+}
+
+// GetPersonalRecords handles requests to list the personal records of a runner
+func (h Handler) GetPersonalRecords(w http.ResponseWriter, r *http.Request) {
+	runnerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.InvalidID(w, "id")
+		return
+	}
+
+	records, err := h.raceTrackerService.GetPersonalRecords(r.Context(), runnerID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	models := make([]PersonalRecordResponse, len(records))
+	for i, record := range records {
+		models[i] = PersonalRecordResponse{
+			Distance:     record.Distance,
+			DistanceKm:   record.DistanceKm,
+			ResultID:     record.ResultID,
+			RaceID:       record.RaceID,
+			RaceName:     record.RaceName,
+			RaceDate:     record.RaceDate,
+			FinishTimeMs: record.FinishTime.Milliseconds(),
+			Pace:         record.PaceMinPerKm,
+		}
+	}
+	response.JSON(w, http.StatusOK, models)
 }
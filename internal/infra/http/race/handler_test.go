@@ -2,12 +2,21 @@ package race
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/race"
+	domainRace "github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	domainRunner "github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -35,9 +44,9 @@ func TestHandler_CreateRace(t *testing.T) {
 			},
 			mockSetup: func(m *mockRaceTrackerService) {
 				expectedID := uuid.New()
-				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5).Return(expectedID, nil)
+				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5, []race.WaveItem{}, "").Return(expectedID, nil)
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusCreated,
 			expectedBody:   uuid.New().String(), // Will be replaced in test with actual mock return
 		},
 		{
@@ -50,16 +59,65 @@ func TestHandler_CreateRace(t *testing.T) {
 				"elevation_gain": 350.5,
 			},
 			mockSetup: func(m *mockRaceTrackerService) {
-				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5).Return(uuid.UUID{}, errors.New("service error"))
+				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5, []race.WaveItem{}, "").Return(uuid.UUID{}, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "service error",
+			expectedBody:   `"code":"internal_error"`,
+		},
+		{
+			name: "domain validation error",
+			requestBody: map[string]interface{}{
+				"name":           "",
+				"location":       "Berlin",
+				"date":           testDate,
+				"distance_km":    42.195,
+				"elevation_gain": 350.5,
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("CreateRace", "", "Berlin", testDate, 42.195, 350.5, []race.WaveItem{}, "").Return(uuid.UUID{}, domainRace.ErrEmptyName)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"empty_name"`,
+		},
+		{
+			name: "race with waves ranked by net time",
+			requestBody: map[string]interface{}{
+				"name":           "Marathon 2023",
+				"location":       "Berlin",
+				"date":           testDate,
+				"distance_km":    42.195,
+				"elevation_gain": 350.5,
+				"waves":          []map[string]interface{}{{"name": "A", "start_time": testDate}, {"name": "B", "start_time": testDate.Add(15 * time.Minute)}},
+				"ranking_policy": "net",
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				waves := []race.WaveItem{{Name: "A", StartTime: testDate}, {Name: "B", StartTime: testDate.Add(15 * time.Minute)}}
+				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5, waves, "net").Return(uuid.New(), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "duplicate wave",
+			requestBody: map[string]interface{}{
+				"name":           "Marathon 2023",
+				"location":       "Berlin",
+				"date":           testDate,
+				"distance_km":    42.195,
+				"elevation_gain": 350.5,
+				"waves":          []map[string]interface{}{{"name": "A", "start_time": testDate}, {"name": "A", "start_time": testDate}},
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				waves := []race.WaveItem{{Name: "A", StartTime: testDate}, {Name: "A", StartTime: testDate}}
+				m.On("CreateRace", "Marathon 2023", "Berlin", testDate, 42.195, 350.5, waves, "").Return(uuid.UUID{}, domainRace.ErrDuplicateWave)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"duplicate_wave"`,
 		},
 		{
 			name:        "invalid request body",
 			requestBody: map[string]interface{}{},
 			mockSetup: func(m *mockRaceTrackerService) {
-				m.On("CreateRace", "", "", time.Time{}, 0.0, 0.0).Return(uuid.UUID{}, race.ErrEmptyRaceID)
+				m.On("CreateRace", "", "", time.Time{}, 0.0, 0.0, []race.WaveItem{}, "").Return(uuid.UUID{}, race.ErrEmptyRaceID)
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   race.ErrEmptyRaceID.Error(),
@@ -80,11 +138,13 @@ func TestHandler_CreateRace(t *testing.T) {
 			handler.CreateRace(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 			if tt.name == "successful race creation" {
 				// Special case for successful UUID response
-				assert.NotEmpty(t, w.Body.String())
-				_, err := uuid.Parse(w.Body.String())
-				assert.NoError(t, err)
+				var created response.CreatedResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+				assert.NotEqual(t, uuid.Nil, created.ID)
+				assert.Equal(t, "/races/"+created.ID.String(), w.Header().Get("Location"))
 			} else if tt.expectedBody != "" {
 				assert.Contains(t, w.Body.String(), tt.expectedBody)
 			}
@@ -92,6 +152,77 @@ func TestHandler_CreateRace(t *testing.T) {
 	}
 }
 
+func TestHandler_GetLeaderboard(t *testing.T) {
+	raceID := uuid.New()
+	standing := race.StandingItem{
+		Position:       1,
+		GenderPosition: 1,
+		ResultID:       uuid.New(),
+		RunnerID:       uuid.New(),
+		RunnerName:     "Anna",
+		Gender:         "female",
+		Status:         "finished",
+		FinishTime:     3 * time.Hour,
+		GunTime:        3*time.Hour + time.Minute,
+		PaceMinPerKm:   4.26,
+		AgeGrade:       &race.AgeGradeItem{Factor: 0.9, Percent: 83.6, GradedTime: 162 * time.Minute},
+	}
+
+	tests := []struct {
+		name           string
+		raceID         string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "successful leaderboard",
+			raceID: raceID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetLeaderboard", raceID).Return([]race.StandingItem{standing}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"race_id":"` + raceID.String() + `","standings":[{"position":1,"gender_position":1,"result_id":"` + standing.ResultID.String() +
+				`","runner_id":"` + standing.RunnerID.String() + `","runner_name":"Anna","gender":"female","status":"finished","finish_time_ms":10800000,"gun_time_ms":10860000,"pace":4.26,
+				"age_grade":{"factor":0.9,"percent":83.6,"graded_time_ms":9720000}}]}`,
+		},
+		{
+			name:   "race not found",
+			raceID: raceID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetLeaderboard", raceID).Return([]race.StandingItem(nil), domainRace.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"code":"race_not_found","message":"race not found"}}`,
+		},
+		{
+			name:           "invalid race ID",
+			raceID:         "invalid",
+			mockSetup:      func(m *mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid race_id format","field":"race_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races/"+tt.raceID+"/results", nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": tt.raceID})
+			w := httptest.NewRecorder()
+
+			handler.GetLeaderboard(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestHandler_AddResult(t *testing.T) {
 	validRunnerID := uuid.New()
 	validRaceID := uuid.New()
@@ -103,6 +234,35 @@ func TestHandler_AddResult(t *testing.T) {
 		expectedStatus int
 		expectedBody   string
 	}{
+		{
+			name: "result with gun time",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"finish_time_ms": int64(7200000),
+				"gun_time_ms":    int64(7230000),
+				"heart_rate_avg": 155,
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, 2*time.Hour+30*time.Second, 155, "", []race.SplitItem{}).Return(race.AddedResultItem{ID: uuid.New()}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "gun time before the net time",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"finish_time_ms": int64(7200000),
+				"gun_time_ms":    int64(7100000),
+				"heart_rate_avg": 155,
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, 7100*time.Second, 155, "", []race.SplitItem{}).Return(race.AddedResultItem{}, domainRace.ErrInvalidGunTime)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"invalid_gun_time"`,
+		},
 		{
 			name: "successful result addition",
 			requestBody: map[string]interface{}{
@@ -114,9 +274,9 @@ func TestHandler_AddResult(t *testing.T) {
 			},
 			mockSetup: func(m *mockRaceTrackerService) {
 				expectedID := uuid.New()
-				m.On("AddResult", validRunnerID, validRaceID, 2*time.Hour, 155, "Great race").Return(expectedID, nil)
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, time.Duration(0), 155, "Great race", []race.SplitItem{}).Return(race.AddedResultItem{ID: expectedID, PersonalRecord: true}, nil)
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusCreated,
 			expectedBody:   uuid.New().String(), // Will be replaced in test with actual mock return
 		},
 		{
@@ -129,10 +289,85 @@ func TestHandler_AddResult(t *testing.T) {
 				"notes":          "Great race",
 			},
 			mockSetup: func(m *mockRaceTrackerService) {
-				m.On("AddResult", validRunnerID, validRaceID, 2*time.Hour, 155, "Great race").Return(uuid.UUID{}, errors.New("service error"))
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, time.Duration(0), 155, "Great race", []race.SplitItem{}).Return(race.AddedResultItem{}, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "service error",
+			expectedBody:   `"code":"internal_error"`,
+		},
+		{
+			name: "result with splits",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"finish_time_ms": int64(7200000),
+				"heart_rate_avg": 155,
+				"notes":          "Great race",
+				"splits":         []map[string]interface{}{{"distance_km": 10, "elapsed_ms": 3000000}, {"distance_km": 20, "elapsed_ms": 6100000}},
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				splits := []race.SplitItem{{DistanceKm: 10, Elapsed: 50 * time.Minute}, {DistanceKm: 20, Elapsed: 6100 * time.Second}}
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, time.Duration(0), 155, "Great race", splits).Return(race.AddedResultItem{ID: uuid.New()}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid splits",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"finish_time_ms": int64(7200000),
+				"heart_rate_avg": 155,
+				"notes":          "Great race",
+				"splits":         []map[string]interface{}{{"distance_km": 10, "elapsed_ms": 8000000}},
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				splits := []race.SplitItem{{DistanceKm: 10, Elapsed: 8000 * time.Second}}
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, time.Duration(0), 155, "Great race", splits).Return(race.AddedResultItem{}, domainRace.ErrSplitBeyondFinishTime)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"split_beyond_finish_time"`,
+		},
+		{
+			name: "race not found",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"finish_time_ms": int64(7200000),
+				"heart_rate_avg": 155,
+				"notes":          "Great race",
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AddResult", validRunnerID, validRaceID, "", "", 2*time.Hour, time.Duration(0), 155, "Great race", []race.SplitItem{}).Return(race.AddedResultItem{}, domainRace.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   domainRace.ErrNotFound.Error(),
+		},
+		{
+			name: "did not finish",
+			requestBody: map[string]interface{}{
+				"runner_id":     validRunnerID.String(),
+				"race_id":       validRaceID.String(),
+				"status":        "dnf",
+				"status_reason": "cramps",
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AddResult", validRunnerID, validRaceID, "dnf", "cramps", time.Duration(0), time.Duration(0), 0, "", []race.SplitItem{}).Return(race.AddedResultItem{ID: uuid.New()}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "disqualified without reason",
+			requestBody: map[string]interface{}{
+				"runner_id":      validRunnerID.String(),
+				"race_id":        validRaceID.String(),
+				"status":         "dsq",
+				"finish_time_ms": int64(7200000),
+			},
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AddResult", validRunnerID, validRaceID, "dsq", "", 2*time.Hour, time.Duration(0), 0, "", []race.SplitItem{}).Return(race.AddedResultItem{}, domainRace.ErrMissingStatusReason)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"missing_status_reason","message":"disqualified results require a reason","field":"status_reason"`,
 		},
 		{
 			name: "invalid runner ID",
@@ -145,7 +380,7 @@ func TestHandler_AddResult(t *testing.T) {
 			},
 			mockSetup:      func(*mockRaceTrackerService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Invalid runner ID format",
+			expectedBody:   "invalid runner_id format",
 		},
 		{
 			name: "invalid race ID",
@@ -158,7 +393,7 @@ func TestHandler_AddResult(t *testing.T) {
 			},
 			mockSetup:      func(*mockRaceTrackerService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Invalid race ID format",
+			expectedBody:   "invalid race_id format",
 		},
 		{
 			name:           "invalid request body",
@@ -185,9 +420,10 @@ func TestHandler_AddResult(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.name == "successful result addition" {
 				// Special case for successful UUID response
-				assert.NotEmpty(t, w.Body.String())
-				_, err := uuid.Parse(w.Body.String())
-				assert.NoError(t, err)
+				var created AddResultResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+				assert.NotEqual(t, uuid.Nil, created.ID)
+				assert.True(t, created.PersonalRecord)
 			} else if tt.expectedBody != "" {
 				assert.Contains(t, w.Body.String(), tt.expectedBody)
 			}
@@ -195,21 +431,778 @@ func TestHandler_AddResult(t *testing.T) {
 	}
 }
 
+func TestHandler_GetRaceResults(t *testing.T) {
+	runnerID := uuid.New()
+	result := race.ResultItem{
+		ID:           uuid.New(),
+		RunnerID:     runnerID,
+		RaceID:       uuid.New(),
+		Status:       "finished",
+		Publication:  "official",
+		FinishTime:   10 * time.Minute,
+		GunTime:      10*time.Minute + 15*time.Second,
+		PaceMinPerKm: 5,
+		HeartRateAvg: 150,
+		AgeGrade:     &race.AgeGradeItem{Factor: 0.95, Percent: 60.5, GradedTime: 9*time.Minute + 30*time.Second},
+		Splits:       []race.SplitItem{{DistanceKm: 1, Elapsed: 6 * time.Minute}},
+		SplitAnalysis: &race.SplitAnalysisItem{
+			Segments: []race.SegmentItem{
+				{StartKm: 0, EndKm: 1, Duration: 6 * time.Minute, PaceMinPerKm: 6},
+				{StartKm: 1, EndKm: 2, Duration: 4 * time.Minute, PaceMinPerKm: 4},
+			},
+			FirstHalf:              6 * time.Minute,
+			SecondHalf:             4 * time.Minute,
+			SplitPercent:           -33.3,
+			NegativeSplit:          true,
+			Fastest:                race.SegmentItem{StartKm: 1, EndKm: 2, Duration: 4 * time.Minute, PaceMinPerKm: 4},
+			Slowest:                race.SegmentItem{StartKm: 0, EndKm: 1, Duration: 6 * time.Minute, PaceMinPerKm: 6},
+			PaceVariabilityPercent: 20,
+		},
+	}
+	mockService := new(mockRaceTrackerService)
+	mockService.On("GetResults", runnerID, "").Return([]race.ResultItem{result}, nil)
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/runners/"+runnerID.String()+"/results", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": runnerID.String()})
+	w := httptest.NewRecorder()
+	handler.GetRaceResults(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{
+		"id":"`+result.ID.String()+`","runner_id":"`+runnerID.String()+`","race_id":"`+result.RaceID.String()+`",
+		"status":"finished","publication":"official","finish_time_ms":600000,"gun_time_ms":615000,"pace":5,"heart_rate_avg":150,"notes":"",
+		"splits":[{"distance_km":1,"elapsed_ms":360000}],
+		"split_analysis":{
+			"segments":[{"start_km":0,"end_km":1,"time_ms":360000,"pace":6},{"start_km":1,"end_km":2,"time_ms":240000,"pace":4}],
+			"first_half_ms":360000,"second_half_ms":240000,"split_percent":-33.3,"negative_split":true,
+			"fastest":{"start_km":1,"end_km":2,"time_ms":240000,"pace":4},
+			"slowest":{"start_km":0,"end_km":1,"time_ms":360000,"pace":6},
+			"pace_variability_percent":20
+		},
+		"age_grade":{"factor":0.95,"percent":60.5,"graded_time_ms":570000}
+	}]`, w.Body.String())
+}
+
+func TestHandler_GetRaceResults_Status(t *testing.T) {
+	runnerID := uuid.New()
+	result := race.ResultItem{
+		ID:                uuid.New(),
+		RunnerID:          runnerID,
+		RaceID:            uuid.New(),
+		Status:            "dsq",
+		StatusReason:      "course cutting",
+		Publication:       "amended",
+		PublicationReason: "referee report",
+	}
+
+	tests := []struct {
+		name           string
+		status         string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "filters by status",
+			status: "dsq",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetResults", runnerID, "dsq").Return([]race.ResultItem{result}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":"` + result.ID.String() + `","runner_id":"` + runnerID.String() + `","race_id":"` + result.RaceID.String() + `",
+				"status":"dsq","status_reason":"course cutting","publication":"amended","publication_reason":"referee report",
+				"finish_time_ms":0,"gun_time_ms":0,"pace":0,"heart_rate_avg":0,"notes":""}]`,
+		},
+		{
+			name:   "invalid status",
+			status: "retired",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetResults", runnerID, "retired").Return([]race.ResultItem(nil), domainRace.ErrInvalidStatus)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_status","message":"status must be one of finished, dnf, dns or dsq","field":"status"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/runners/"+runnerID.String()+"/results?status="+tt.status, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": runnerID.String()})
+			w := httptest.NewRecorder()
+			handler.GetRaceResults(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_PublishResults(t *testing.T) {
+	raceID := uuid.New()
+
+	tests := []struct {
+		name           string
+		raceID         string
+		body           string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "publishes with reason",
+			raceID: raceID.String(),
+			body:   `{"reason":"results verified"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("PublishResults", raceID, "results verified").Return(3, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"published":3}`,
+		},
+		{
+			name:   "publishes without body",
+			raceID: raceID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("PublishResults", raceID, "").Return(0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"published":0}`,
+		},
+		{
+			name:   "race not found",
+			raceID: raceID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("PublishResults", raceID, "").Return(0, domainRace.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"code":"race_not_found","message":"race not found"}}`,
+		},
+		{
+			name:           "invalid race ID",
+			raceID:         "invalid",
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid race_id format","field":"race_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+tt.raceID+"/results/publish", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": tt.raceID})
+			w := httptest.NewRecorder()
+			handler.PublishResults(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_AmendResult(t *testing.T) {
+	raceID := uuid.New()
+	resultID := uuid.New()
+
+	tests := []struct {
+		name           string
+		resultID       string
+		body           string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "amends the result",
+			resultID: resultID.String(),
+			body:     `{"status":"dsq","status_reason":"course cutting","finish_time_ms":3600000,"reason":"referee report"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AmendResult", raceID, resultID, "dsq", "course cutting", time.Hour, "referee report").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "provisional result",
+			resultID: resultID.String(),
+			body:     `{"status":"dnf","reason":"timing error"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AmendResult", raceID, resultID, "dnf", "", time.Duration(0), "timing error").Return(domainRace.ErrInvalidPublicationTransition)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"invalid_publication_transition","message":"only provisional results can be published and only published results can be amended"}}`,
+		},
+		{
+			name:     "missing reason",
+			resultID: resultID.String(),
+			body:     `{"status":"dnf"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AmendResult", raceID, resultID, "dnf", "", time.Duration(0), "").Return(domainRace.ErrMissingAmendmentReason)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"missing_amendment_reason","message":"amendments require a reason","field":"reason"}}`,
+		},
+		{
+			name:     "result not found",
+			resultID: resultID.String(),
+			body:     `{"status":"dnf","reason":"timing error"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("AmendResult", raceID, resultID, "dnf", "", time.Duration(0), "timing error").Return(domainRace.ErrResultNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"code":"result_not_found","message":"result not found"}}`,
+		},
+		{
+			name:           "invalid result ID",
+			resultID:       "invalid",
+			body:           `{"status":"dnf","reason":"timing error"}`,
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid result_id format","field":"result_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/results/"+tt.resultID+"/amendments", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String(), "resultID": tt.resultID})
+			w := httptest.NewRecorder()
+			handler.AmendResult(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ImportResult(t *testing.T) {
+	runnerID := uuid.New()
+	raceID := uuid.New()
+	resultID := uuid.New()
+
+	newRequest := func(raceID string, fields map[string]string, filename string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for k, v := range fields {
+			_ = writer.WriteField(k, v)
+		}
+		if filename != "" {
+			part, _ := writer.CreateFormFile("file", filename)
+			_, _ = part.Write([]byte("activity"))
+		}
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/races/"+raceID+"/results/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return mux.SetURLVars(req, map[string]string{"raceID": raceID})
+	}
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		mockSetup      func(m *mockRaceTrackerService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should import activity using the file extension",
+			req:  newRequest(raceID.String(), map[string]string{"runner_id": runnerID.String(), "notes": "hot"}, "morning.gpx"),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ImportResult", runnerID, raceID, activity.FormatGPX, "hot").Return(race.ImportedResultItem{
+					ResultID:         resultID,
+					FinishTime:       10 * time.Minute,
+					HeartRateAvg:     150,
+					Splits:           []race.SplitItem{{DistanceKm: 1, Elapsed: 5 * time.Minute}},
+					RecordedKm:       2.2,
+					RaceDistanceKm:   2,
+					DistanceMismatch: true,
+				}, nil)
+			},
+			wantStatusCode: http.StatusCreated,
+			wantBody: `{"id":"` + resultID.String() + `","personal_record":false,"finish_time_ms":600000,"heart_rate_avg":150,
+				"splits":[{"distance_km":1,"elapsed_ms":300000}],
+				"recorded_distance_km":2.2,"race_distance_km":2,"distance_mismatch":true}`,
+		},
+		{
+			name: "should prefer the explicit format",
+			req:  newRequest(raceID.String(), map[string]string{"runner_id": runnerID.String(), "format": "fit"}, "activity.bin"),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ImportResult", runnerID, raceID, activity.FormatFIT, "").Return(race.ImportedResultItem{ResultID: resultID}, nil)
+			},
+			wantStatusCode: http.StatusCreated,
+			wantBody: `{"id":"` + resultID.String() + `","personal_record":false,"finish_time_ms":0,"heart_rate_avg":0,
+				"recorded_distance_km":0,"race_distance_km":0,"distance_mismatch":false}`,
+		},
+		{
+			name: "should return bad request on invalid activity file",
+			req:  newRequest(raceID.String(), map[string]string{"runner_id": runnerID.String()}, "activity.tcx"),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ImportResult", runnerID, raceID, activity.FormatTCX, "").Return(race.ImportedResultItem{}, activity.ErrInvalidFile)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_activity_file","message":"` + activity.ErrInvalidFile.Error() + `","field":"file"}}`,
+		},
+		{
+			name:           "should return bad request on unsupported format",
+			req:            newRequest(raceID.String(), map[string]string{"runner_id": runnerID.String()}, "activity.csv"),
+			mockSetup:      func(*mockRaceTrackerService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"unsupported_format","message":"` + activity.ErrUnsupportedFormat.Error() + `","field":"format"}}`,
+		},
+		{
+			name:           "should return bad request on missing file",
+			req:            newRequest(raceID.String(), map[string]string{"runner_id": runnerID.String()}, ""),
+			mockSetup:      func(*mockRaceTrackerService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"missing_parameter","message":"a multipart file field is required","field":"file"}}`,
+		},
+		{
+			name:           "should return bad request on invalid runner id",
+			req:            newRequest(raceID.String(), map[string]string{"runner_id": "invalid"}, "activity.gpx"),
+			mockSetup:      func(*mockRaceTrackerService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_id","message":"invalid runner_id format","field":"runner_id"}}`,
+		},
+		{
+			name:           "should return bad request on invalid race id",
+			req:            newRequest("invalid", map[string]string{"runner_id": runnerID.String()}, "activity.gpx"),
+			mockSetup:      func(*mockRaceTrackerService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_id","message":"invalid race_id format","field":"race_id"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.ImportResult(w, tt.req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetPersonalRecords(t *testing.T) {
+	runnerID := uuid.New()
+	record := race.PersonalRecordItem{
+		Distance:     "10K",
+		DistanceKm:   10,
+		ResultID:     uuid.New(),
+		RaceID:       uuid.New(),
+		RaceName:     "City 10K",
+		RaceDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		FinishTime:   45 * time.Minute,
+		PaceMinPerKm: 4.5,
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(m *mockRaceTrackerService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should return records",
+			id:   runnerID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetPersonalRecords", runnerID).Return([]race.PersonalRecordItem{record}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `[{"distance":"10K","distance_km":10,"result_id":"` + record.ResultID.String() + `","race_id":"` + record.RaceID.String() + `",
+				"race_name":"City 10K","race_date":"2025-03-01T00:00:00Z","finish_time_ms":2700000,"pace":4.5}]`,
+		},
+		{
+			name: "should return empty list without results",
+			id:   runnerID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetPersonalRecords", runnerID).Return([]race.PersonalRecordItem{}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `[]`,
+		},
+		{
+			name: "should return not found for unknown runner",
+			id:   runnerID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("GetPersonalRecords", runnerID).Return([]race.PersonalRecordItem(nil), domainRunner.ErrNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":{"code":"runner_not_found","message":"` + domainRunner.ErrNotFound.Error() + `"}}`,
+		},
+		{
+			name:           "should return bad request on invalid id",
+			id:             "invalid",
+			mockSetup:      func(*mockRaceTrackerService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"invalid_id","message":"invalid id format","field":"id"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/runners/"+tt.id+"/records", nil), map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+			handler.GetPersonalRecords(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ListRaces(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	minDistance, maxElevation := 5.0, 100.0
+	page := race.RacePage{
+		Races:      []race.RaceItem{{ID: raceID, Name: "City 10K", Location: "Athens", Date: date, DistanceKm: 10, ElevationGain: 50, RankingPolicy: "gun", Organiser: "athens-events"}},
+		NextCursor: "next",
+		Limit:      1,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "lists the races with the filters",
+			query: "?date_from=2025-10-01&date_to=2025-10-31&location=Athens&min_distance_km=5&max_elevation_gain=100&q=10k&sort=distance_km&order=desc&cursor=abc&limit=1",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ListRaces", race.RaceFilterItem{
+					From:             time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+					To:               time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+					Location:         "Athens",
+					MinDistanceKm:    &minDistance,
+					MaxElevationGain: &maxElevation,
+					Text:             "10k",
+					Sort:             "distance_km",
+					Order:            "desc",
+					Cursor:           "abc",
+					Limit:            1,
+				}).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"races":[{"id":"%s","name":"City 10K","location":"Athens","date":"2025-10-05T08:00:00Z",
+				"distance_km":10,"elevation_gain":50,"ranking_policy":"gun","organiser":"athens-events"}],"next_cursor":"next","limit":1}`, raceID),
+		},
+		{
+			name:  "accepts times in the date range",
+			query: "?date_to=2025-10-05T12:00:00Z",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ListRaces", race.RaceFilterItem{To: time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)}).Return(race.RacePage{Limit: 20}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"races":[],"limit":20}`,
+		},
+		{
+			name:  "ignores the runner_id parameter",
+			query: "?runner_id=" + runnerID.String(),
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ListRaces", race.RaceFilterItem{}).Return(race.RacePage{Limit: 20}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"races":[],"limit":20}`,
+		},
+		{
+			name:           "invalid date",
+			query:          "?date_from=yesterday",
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_parameter","message":"date_from must be a date (YYYY-MM-DD) or an RFC 3339 time","field":"date_from"}}`,
+		},
+		{
+			name:           "invalid distance",
+			query:          "?max_distance_km=far",
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_parameter","message":"max_distance_km must be a number","field":"max_distance_km"}}`,
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=abc",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ListRaces", race.RaceFilterItem{Cursor: "abc"}).Return(race.RacePage{}, race.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_cursor","message":"cursor is not valid for this search","field":"cursor"}}`,
+		},
+		{
+			name:  "unknown sort field",
+			query: "?sort=location",
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("ListRaces", race.RaceFilterItem{Sort: "location"}).Return(race.RacePage{}, domainRace.ErrInvalidSortField)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_sort","message":"sort must be one of date, name, distance_km or elevation_gain","field":"sort"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ListRaces(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_UpdateRace(t *testing.T) {
+	raceID := uuid.New()
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	name, location, distance, elevation := "Night 10K", "Athens", 10.5, 50.0
+	updated := race.RaceItem{ID: raceID, Name: "Night 10K", Location: "Athens", Date: date, DistanceKm: 10.5, ElevationGain: 50, RankingPolicy: "gun"}
+	updatedBody := fmt.Sprintf(`{"id":"%s","name":"Night 10K","location":"Athens","date":"2025-10-05T08:00:00Z","distance_km":10.5,"elevation_gain":50,"ranking_policy":"gun"}`, raceID)
+
+	tests := []struct {
+		name           string
+		method         string
+		raceID         string
+		body           string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "replaces every detail",
+			method: http.MethodPut,
+			raceID: raceID.String(),
+			body:   `{"name":"Night 10K","location":"Athens","date":"2025-10-05T08:00:00Z","distance_km":10.5,"elevation_gain":50}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("UpdateRace", raceID, race.RaceChangesItem{Name: &name, Location: &location, Date: &date, DistanceKm: &distance, ElevationGain: &elevation}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   updatedBody,
+		},
+		{
+			name:   "replacing without a date",
+			method: http.MethodPut,
+			raceID: raceID.String(),
+			body:   `{"name":"Night 10K","location":"Athens","distance_km":10.5,"elevation_gain":50}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("UpdateRace", raceID, mock.Anything).Return(race.RaceItem{}, domainRace.ErrMissingDate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"missing_date","message":"date is required","field":"date"}}`,
+		},
+		{
+			name:   "changes only the provided details",
+			method: http.MethodPatch,
+			raceID: raceID.String(),
+			body:   `{"distance_km":10.5}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("UpdateRace", raceID, race.RaceChangesItem{DistanceKm: &distance}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   updatedBody,
+		},
+		{
+			name:   "distance with official results",
+			method: http.MethodPatch,
+			raceID: raceID.String(),
+			body:   `{"distance_km":10.5}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("UpdateRace", raceID, race.RaceChangesItem{DistanceKm: &distance}).Return(race.RaceItem{}, domainRace.ErrDistanceLocked)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"distance_locked","message":"distance cannot change once the race has official results","field":"distance_km"}}`,
+		},
+		{
+			name:   "cancelled race",
+			method: http.MethodPatch,
+			raceID: raceID.String(),
+			body:   `{"name":"Night 10K"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("UpdateRace", raceID, race.RaceChangesItem{Name: &name}).Return(race.RaceItem{}, domainRace.ErrRaceCancelled)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"race_cancelled","message":"race is cancelled"}}`,
+		},
+		{
+			name:           "invalid race ID",
+			method:         http.MethodPatch,
+			raceID:         "invalid",
+			body:           `{}`,
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid race_id format","field":"race_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(tt.method, "/races/"+tt.raceID, bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": tt.raceID})
+			w := httptest.NewRecorder()
+			if tt.method == http.MethodPut {
+				handler.ReplaceRace(w, req)
+			} else {
+				handler.UpdateRace(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_CancelRace(t *testing.T) {
+	raceID := uuid.New()
+	date := time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+	cancelledAt := date.Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockRaceTrackerService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "cancels the race",
+			body: `{"reason":"storm warning"}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("CancelRace", raceID, "storm warning").Return(race.RaceItem{
+					ID: raceID, Name: "City 10K", Location: "Athens", Date: date, DistanceKm: 10, RankingPolicy: "gun",
+					CancelledAt: cancelledAt, CancellationReason: "storm warning",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","name":"City 10K","location":"Athens","date":"2025-10-05T08:00:00Z","distance_km":10,
+				"elevation_gain":0,"ranking_policy":"gun","cancelled_at":"2025-10-05T07:00:00Z","cancellation_reason":"storm warning"}`, raceID),
+		},
+		{
+			name: "without reason",
+			body: `{}`,
+			mockSetup: func(m *mockRaceTrackerService) {
+				m.On("CancelRace", raceID, "").Return(race.RaceItem{}, domainRace.ErrMissingCancellationReason)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"missing_cancellation_reason","message":"cancellation reason is required","field":"reason"}}`,
+		},
+		{
+			name:           "malformed body",
+			body:           `{`,
+			mockSetup:      func(*mockRaceTrackerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"malformed_body","message":"unexpected EOF"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRaceTrackerService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/cancellation", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.CancelRace(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 type mockRaceTrackerService struct {
 	mock.Mock
 }
 
-func (m *mockRaceTrackerService) CreateRace(name, location string, date time.Time, distanceKm, elevationGain float64) (uuid.UUID, error) {
-	args := m.Called(name, location, date, distanceKm, elevationGain)
+func (m *mockRaceTrackerService) CreateRace(_ context.Context, name, location string, date time.Time, distanceKm, elevationGain float64, waves []race.WaveItem, rankingPolicy string) (uuid.UUID, error) {
+	args := m.Called(name, location, date, distanceKm, elevationGain, waves, rankingPolicy)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *mockRaceTrackerService) AddResult(runnerID, raceID uuid.UUID, finishTime time.Duration, heartRateAvg int, notes string) (uuid.UUID, error) {
-	args := m.Called(runnerID, raceID, finishTime, heartRateAvg, notes)
-	return args.Get(0).(uuid.UUID), args.Error(1)
+func (m *mockRaceTrackerService) GetRace(_ context.Context, raceID uuid.UUID) (race.RaceItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(race.RaceItem), args.Error(1)
 }
 
-func (m *mockRaceTrackerService) GetResults(runnerID uuid.UUID) ([]race.ResultItem, error) {
-	args := m.Called(runnerID)
+func (m *mockRaceTrackerService) ListRaces(_ context.Context, filter race.RaceFilterItem) (race.RacePage, error) {
+	args := m.Called(filter)
+	return args.Get(0).(race.RacePage), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) UpdateRace(_ context.Context, raceID uuid.UUID, changes race.RaceChangesItem) (race.RaceItem, error) {
+	args := m.Called(raceID, changes)
+	return args.Get(0).(race.RaceItem), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) CancelRace(_ context.Context, raceID uuid.UUID, reason string) (race.RaceItem, error) {
+	args := m.Called(raceID, reason)
+	return args.Get(0).(race.RaceItem), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) AddResult(_ context.Context, runnerID, raceID uuid.UUID, status, statusReason string, finishTime, gunTime time.Duration, heartRateAvg int, notes string, splits []race.SplitItem) (race.AddedResultItem, error) {
+	args := m.Called(runnerID, raceID, status, statusReason, finishTime, gunTime, heartRateAvg, notes, splits)
+	return args.Get(0).(race.AddedResultItem), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) GetResults(_ context.Context, runnerID uuid.UUID, status string) ([]race.ResultItem, error) {
+	args := m.Called(runnerID, status)
 	return args.Get(0).([]race.ResultItem), args.Error(1)
 }
+
+func (m *mockRaceTrackerService) PublishResults(_ context.Context, raceID uuid.UUID, reason string) (int, error) {
+	args := m.Called(raceID, reason)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) AmendResult(_ context.Context, raceID, resultID uuid.UUID, status, statusReason string, finishTime time.Duration, reason string) error {
+	return m.Called(raceID, resultID, status, statusReason, finishTime, reason).Error(0)
+}
+
+func (m *mockRaceTrackerService) GetLeaderboard(_ context.Context, raceID uuid.UUID) ([]race.StandingItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]race.StandingItem), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) ImportResult(_ context.Context, runnerID, raceID uuid.UUID, format activity.Format, _ io.Reader, notes string) (race.ImportedResultItem, error) {
+	args := m.Called(runnerID, raceID, format, notes)
+	return args.Get(0).(race.ImportedResultItem), args.Error(1)
+}
+
+func (m *mockRaceTrackerService) GetPersonalRecords(_ context.Context, runnerID uuid.UUID) ([]race.PersonalRecordItem, error) {
+	args := m.Called(runnerID)
+	return args.Get(0).([]race.PersonalRecordItem), args.Error(1)
+}
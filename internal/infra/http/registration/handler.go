@@ -0,0 +1,191 @@
+// Package registration contains the http handlers of the registrations of runners to races
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type registrationService interface {
+	ConfigureRegistration(ctx context.Context, raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool) error
+	Register(ctx context.Context, raceID, runnerID uuid.UUID) (registration.RegistrationItem, error)
+	CancelRegistration(ctx context.Context, raceID, runnerID uuid.UUID) error
+	GetEntryList(ctx context.Context, raceID uuid.UUID) (registration.EntryListItem, error)
+}
+
+// Handler registration http request service
+type Handler struct {
+	registrationService registrationService
+}
+
+// NewHandler Constructor
+func NewHandler(service registrationService) Handler {
+	return Handler{registrationService: service}
+}
+
+// ConfigureRegistrationRequestModel represents the request model for configuring the registration of a race.
+// Runners can register from opens_at until closes_at; required_for_results restricts results to confirmed runners.
+type ConfigureRegistrationRequestModel struct {
+	Capacity           int       `json:"capacity"`
+	OpensAt            time.Time `json:"opens_at"`
+	ClosesAt           time.Time `json:"closes_at"`
+	RequiredForResults bool      `json:"required_for_results"`
+}
+
+// ConfigureRegistration handles requests to set the capacity and the registration window of a race
+func (h Handler) ConfigureRegistration(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var configureRequest ConfigureRegistrationRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&configureRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	err = h.registrationService.ConfigureRegistration(
+		r.Context(),
+		raceID,
+		configureRequest.Capacity,
+		configureRequest.OpensAt,
+		configureRequest.ClosesAt,
+		configureRequest.RequiredForResults,
+	)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// RegistrationResponse represents the response model of a registration.
+// WaitlistPosition is only set for waitlisted registrations.
+type RegistrationResponse struct {
+	ID               uuid.UUID `json:"id"`
+	RunnerID         uuid.UUID `json:"runner_id"`
+	Status           string    `json:"status"`
+	WaitlistPosition int       `json:"waitlist_position,omitempty"`
+	RegisteredAt     time.Time `json:"registered_at"`
+}
+
+// EntryListResponse represents the response model of the registrations of a race
+type EntryListResponse struct {
+	RaceID             uuid.UUID              `json:"race_id"`
+	Capacity           int                    `json:"capacity"`
+	OpensAt            time.Time              `json:"opens_at"`
+	ClosesAt           time.Time              `json:"closes_at"`
+	RequiredForResults bool                   `json:"required_for_results"`
+	Open               bool                   `json:"open"`
+	Confirmed          []RegistrationResponse `json:"confirmed"`
+	Waitlist           []RegistrationResponse `json:"waitlist"`
+}
+
+// GetEntryList handles requests to get the registration settings, the confirmed runners and the waitlist of a race
+func (h Handler) GetEntryList(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	entryList, err := h.registrationService.GetEntryList(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, EntryListResponse{
+		RaceID:             entryList.RaceID,
+		Capacity:           entryList.Capacity,
+		OpensAt:            entryList.OpensAt,
+		ClosesAt:           entryList.ClosesAt,
+		RequiredForResults: entryList.RequiredForResults,
+		Open:               entryList.Open,
+		Confirmed:          toRegistrationResponses(entryList.Confirmed),
+		Waitlist:           toRegistrationResponses(entryList.Waitlist),
+	})
+}
+
+func toRegistrationResponses(items []registration.RegistrationItem) []RegistrationResponse {
+	models := make([]RegistrationResponse, len(items))
+	for i, item := range items {
+		models[i] = toRegistrationResponse(item)
+	}
+	return models
+}
+
+func toRegistrationResponse(item registration.RegistrationItem) RegistrationResponse {
+	return RegistrationResponse{
+		ID:               item.ID,
+		RunnerID:         item.RunnerID,
+		Status:           item.Status,
+		WaitlistPosition: item.WaitlistPosition,
+		RegisteredAt:     item.RegisteredAt,
+	}
+}
+
+// RegisterRequestModel represents the request model for registering a runner to a race
+type RegisterRequestModel struct {
+	RunnerID string `json:"runner_id"`
+}
+
+// Register handles requests to register a runner to a race.
+// The registration is confirmed while the race has places left and waitlisted otherwise.
+func (h Handler) Register(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var registerRequest RegisterRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&registerRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+	runnerID, err := uuid.Parse(registerRequest.RunnerID)
+	if err != nil {
+		response.InvalidID(w, "runner_id")
+		return
+	}
+
+	registered, err := h.registrationService.Register(r.Context(), raceID, runnerID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, toRegistrationResponse(registered))
+}
+
+// CancelRegistration handles requests to cancel the registration of a runner to a race
+func (h Handler) CancelRegistration(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+	runnerID, err := uuid.Parse(mux.Vars(r)["runnerID"])
+	if err != nil {
+		response.InvalidID(w, "runner_id")
+		return
+	}
+
+	if err := h.registrationService.CancelRegistration(r.Context(), raceID, runnerID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,298 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/registration"
+	domainRace "github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	domainRegistration "github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRegistrationService struct {
+	mock.Mock
+}
+
+func (m *mockRegistrationService) ConfigureRegistration(_ context.Context, raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool) error {
+	return m.Called(raceID, capacity, opensAt, closesAt, requiredForResults).Error(0)
+}
+
+func (m *mockRegistrationService) Register(_ context.Context, raceID, runnerID uuid.UUID) (registration.RegistrationItem, error) {
+	args := m.Called(raceID, runnerID)
+	return args.Get(0).(registration.RegistrationItem), args.Error(1)
+}
+
+func (m *mockRegistrationService) CancelRegistration(_ context.Context, raceID, runnerID uuid.UUID) error {
+	return m.Called(raceID, runnerID).Error(0)
+}
+
+func (m *mockRegistrationService) GetEntryList(_ context.Context, raceID uuid.UUID) (registration.EntryListItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(registration.EntryListItem), args.Error(1)
+}
+
+var (
+	opensAt  = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closesAt = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestHandler_ConfigureRegistration(t *testing.T) {
+	raceID := uuid.New()
+
+	tests := []struct {
+		name           string
+		raceID         string
+		body           string
+		mockSetup      func(m *mockRegistrationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "configures the registration",
+			raceID: raceID.String(),
+			body:   `{"capacity":500,"opens_at":"2025-01-01T00:00:00Z","closes_at":"2025-03-01T00:00:00Z","required_for_results":true}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("ConfigureRegistration", raceID, 500, opensAt, closesAt, true).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "capacity below the confirmed registrations",
+			raceID: raceID.String(),
+			body:   `{"capacity":1,"opens_at":"2025-01-01T00:00:00Z","closes_at":"2025-03-01T00:00:00Z"}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("ConfigureRegistration", raceID, 1, opensAt, closesAt, false).Return(domainRegistration.ErrCapacityBelowConfirmed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"error":{"code":"capacity_below_confirmed","message":"` + domainRegistration.ErrCapacityBelowConfirmed.Error() +
+				`","field":"capacity"}}`,
+		},
+		{
+			name:   "unknown race",
+			raceID: raceID.String(),
+			body:   `{"capacity":10,"opens_at":"2025-01-01T00:00:00Z","closes_at":"2025-03-01T00:00:00Z"}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("ConfigureRegistration", raceID, 10, opensAt, closesAt, false).Return(domainRace.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"code":"race_not_found","message":"race not found"}}`,
+		},
+		{
+			name:           "invalid race ID",
+			raceID:         "invalid",
+			body:           `{"capacity":10}`,
+			mockSetup:      func(*mockRegistrationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid race_id format","field":"race_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRegistrationService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/races/"+tt.raceID+"/registration", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": tt.raceID})
+			w := httptest.NewRecorder()
+			handler.ConfigureRegistration(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetEntryList(t *testing.T) {
+	raceID, registrationID, runnerID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(m *mockRegistrationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "returns the entry list",
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("GetEntryList", raceID).Return(registration.EntryListItem{
+					RaceID:    raceID,
+					Capacity:  1,
+					OpensAt:   opensAt,
+					ClosesAt:  closesAt,
+					Open:      true,
+					Confirmed: []registration.RegistrationItem{},
+					Waitlist: []registration.RegistrationItem{
+						{ID: registrationID, RaceID: raceID, RunnerID: runnerID, Status: "waitlisted", WaitlistPosition: 1, RegisteredAt: opensAt},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"race_id":"` + raceID.String() + `","capacity":1,"opens_at":"2025-01-01T00:00:00Z",
+				"closes_at":"2025-03-01T00:00:00Z","required_for_results":false,"open":true,"confirmed":[],
+				"waitlist":[{"id":"` + registrationID.String() + `","runner_id":"` + runnerID.String() + `","status":"waitlisted",
+				"waitlist_position":1,"registered_at":"2025-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name: "registration not configured",
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("GetEntryList", raceID).Return(registration.EntryListItem{}, domainRegistration.ErrNotConfigured)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{"error":{"code":"registration_not_configured","message":"` +
+				domainRegistration.ErrNotConfigured.Error() + `"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRegistrationService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/registration", nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.GetEntryList(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Register(t *testing.T) {
+	raceID, registrationID, runnerID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockRegistrationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "confirmed registration",
+			body: `{"runner_id":"` + runnerID.String() + `"}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("Register", raceID, runnerID).Return(registration.RegistrationItem{
+					ID: registrationID, RaceID: raceID, RunnerID: runnerID, Status: "confirmed", RegisteredAt: opensAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":"` + registrationID.String() + `","runner_id":"` + runnerID.String() +
+				`","status":"confirmed","registered_at":"2025-01-01T00:00:00Z"}`,
+		},
+		{
+			name: "registration closed",
+			body: `{"runner_id":"` + runnerID.String() + `"}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("Register", raceID, runnerID).Return(registration.RegistrationItem{}, domainRegistration.ErrRegistrationClosed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"registration_closed","message":"registration is not open"}}`,
+		},
+		{
+			name: "already registered",
+			body: `{"runner_id":"` + runnerID.String() + `"}`,
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("Register", raceID, runnerID).Return(registration.RegistrationItem{}, domainRegistration.ErrAlreadyRegistered)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"code":"already_registered","message":"runner is already registered for the race"}}`,
+		},
+		{
+			name:           "invalid runner ID",
+			body:           `{"runner_id":"invalid"}`,
+			mockSetup:      func(*mockRegistrationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid runner_id format","field":"runner_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRegistrationService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/registrations", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.Register(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_CancelRegistration(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		runnerID       string
+		mockSetup      func(m *mockRegistrationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "cancels the registration",
+			runnerID: runnerID.String(),
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("CancelRegistration", raceID, runnerID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "runner not registered",
+			runnerID: runnerID.String(),
+			mockSetup: func(m *mockRegistrationService) {
+				m.On("CancelRegistration", raceID, runnerID).Return(domainRegistration.ErrNotRegistered)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":{"code":"not_registered","message":"runner is not registered for the race","field":"runner_id"}}`,
+		},
+		{
+			name:           "invalid runner ID",
+			runnerID:       "invalid",
+			mockSetup:      func(*mockRegistrationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"invalid_id","message":"invalid runner_id format","field":"runner_id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockRegistrationService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/races/"+raceID.String()+"/registrations/"+tt.runnerID, nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String(), "runnerID": tt.runnerID})
+			w := httptest.NewRecorder()
+			handler.CancelRegistration(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/analytics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	appCourse "github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	appRace "github.com/pkritiotis/go-clean-architecture-example/internal/app/race"
+	appRunner "github.com/pkritiotis/go-clean-architecture-example/internal/app/runner"
+	appTiming "github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+)
+
+// Error codes of request problems detected by the handlers
+const (
+	CodeMalformedBody    = "malformed_body"
+	CodeInvalidID        = "invalid_id"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeMissingParameter = "missing_parameter"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeRequestTimeout   = "request_timeout"
+	CodeRequestTooLarge  = "request_too_large"
+	CodeInternal         = "internal_error"
+)
+
+// mapping associates an application or domain error with its HTTP representation
+type mapping struct {
+	err    error
+	status int
+	code   string
+	field  string
+}
+
+// mappings is the single place where errors are translated to HTTP status codes and error codes.
+// Errors are matched with errors.Is in order, so wrapped errors are supported.
+var mappings = []mapping{
+	// requests
+	{context.DeadlineExceeded, http.StatusServiceUnavailable, CodeRequestTimeout, ""},
+
+	// authentication
+	{auth.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated", ""},
+	{auth.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", ""},
+	{auth.ErrForbidden, http.StatusForbidden, "forbidden", ""},
+
+	// logging
+	{logging.ErrInvalidLevel, http.StatusBadRequest, "invalid_level", "level"},
+
+	// runners
+	{runner.ErrNotFound, http.StatusNotFound, "runner_not_found", ""},
+	{runner.ErrInvalidEmail, http.StatusBadRequest, "invalid_email", "email_address"},
+	{runner.ErrRunnerNameCannotBeEmpty, http.StatusBadRequest, "empty_name", "name"},
+	{runner.ErrInvalidGender, http.StatusBadRequest, "invalid_gender", "gender"},
+	{runner.ErrInvalidDateOfBirth, http.StatusBadRequest, "invalid_date_of_birth", "date_of_birth"},
+	{appRunner.ErrInvalidOffset, http.StatusBadRequest, "invalid_offset", "offset"},
+	{appRunner.ErrInvalidLimit, http.StatusBadRequest, "invalid_limit", "limit"},
+
+	// races
+	{race.ErrNotFound, http.StatusNotFound, "race_not_found", ""},
+	{race.ErrEmptyName, http.StatusBadRequest, "empty_name", "name"},
+	{race.ErrEmptyLocation, http.StatusBadRequest, "empty_location", "location"},
+	{race.ErrInvalidDistanceKm, http.StatusBadRequest, "invalid_distance", "distance_km"},
+	{race.ErrInvalidElevationGain, http.StatusBadRequest, "invalid_elevation_gain", "elevation_gain"},
+	{race.ErrInvalidRankingPolicy, http.StatusBadRequest, "invalid_ranking_policy", "ranking_policy"},
+	{race.ErrEmptyWaveName, http.StatusBadRequest, "empty_wave_name", "waves"},
+	{race.ErrMissingWaveStart, http.StatusBadRequest, "missing_wave_start", "waves"},
+	{race.ErrDuplicateWave, http.StatusBadRequest, "duplicate_wave", "waves"},
+	{race.ErrMissingDate, http.StatusBadRequest, "missing_date", "date"},
+	{race.ErrRaceCancelled, http.StatusConflict, "race_cancelled", ""},
+	{race.ErrMissingCancellationReason, http.StatusBadRequest, "missing_cancellation_reason", "reason"},
+	{race.ErrDistanceLocked, http.StatusConflict, "distance_locked", "distance_km"},
+	{race.ErrInvalidSortField, http.StatusBadRequest, "invalid_sort", "sort"},
+	{race.ErrInvalidDateRange, http.StatusBadRequest, "invalid_date_range", "date_to"},
+	{race.ErrInvalidDistanceRange, http.StatusBadRequest, "invalid_distance_range", "max_distance_km"},
+	{race.ErrInvalidElevationRange, http.StatusBadRequest, "invalid_elevation_range", "max_elevation_gain"},
+	{appRace.ErrInvalidLimit, http.StatusBadRequest, "invalid_limit", "limit"},
+	{appRace.ErrInvalidOrder, http.StatusBadRequest, "invalid_order", "order"},
+	{appRace.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor", "cursor"},
+
+	// results
+	{race.ErrEmptyRunnerID, http.StatusBadRequest, "empty_runner_id", "runner_id"},
+	{race.ErrEmptyRaceID, http.StatusBadRequest, "empty_race_id", "race_id"},
+	{race.ErrInvalidFinishTime, http.StatusBadRequest, "invalid_finish_time", "finish_time_ms"},
+	{race.ErrInvalidGunTime, http.StatusBadRequest, "invalid_gun_time", "gun_time_ms"},
+	{race.ErrNoFinishTime, http.StatusBadRequest, "missing_finish_time", "finish_time_ms"},
+	{race.ErrInvalidPace, http.StatusBadRequest, "invalid_pace", "pace"},
+	{race.ErrInvalidHeartRateAvg, http.StatusBadRequest, "invalid_heart_rate", "heart_rate_avg"},
+	{race.ErrInvalidSplitDistance, http.StatusBadRequest, "invalid_split", "splits"},
+	{race.ErrInvalidSplitElapsed, http.StatusBadRequest, "invalid_split", "splits"},
+	{race.ErrSplitsNotIncreasing, http.StatusBadRequest, "splits_not_increasing", "splits"},
+	{race.ErrSplitBeyondRace, http.StatusBadRequest, "split_beyond_race", "splits"},
+	{race.ErrSplitBeyondFinishTime, http.StatusBadRequest, "split_beyond_finish_time", "splits"},
+	{race.ErrFinishSplitMismatch, http.StatusBadRequest, "finish_split_mismatch", "splits"},
+	{race.ErrInvalidStatus, http.StatusBadRequest, "invalid_status", "status"},
+	{race.ErrMissingStatusReason, http.StatusBadRequest, "missing_status_reason", "status_reason"},
+	{race.ErrUnexpectedFinishTime, http.StatusBadRequest, "unexpected_finish_time", "finish_time_ms"},
+	{race.ErrUnexpectedSplits, http.StatusBadRequest, "unexpected_splits", "splits"},
+	{race.ErrResultNotFound, http.StatusNotFound, "result_not_found", ""},
+	{race.ErrInvalidPublicationTransition, http.StatusConflict, "invalid_publication_transition", ""},
+	{race.ErrMissingAmendmentReason, http.StatusBadRequest, "missing_amendment_reason", "reason"},
+	{activity.ErrUnsupportedFormat, http.StatusBadRequest, "unsupported_format", "format"},
+	{activity.ErrInvalidFile, http.StatusBadRequest, "invalid_activity_file", "file"},
+	{activity.ErrEmptyActivity, http.StatusBadRequest, "empty_activity", "file"},
+	{appRace.ErrEmptyRunnerID, http.StatusBadRequest, "empty_runner_id", "runner_id"},
+	{appRace.ErrEmptyRaceID, http.StatusBadRequest, "empty_race_id", "race_id"},
+	{appRace.ErrInvalidFinishTime, http.StatusBadRequest, "invalid_finish_time", "finish_time_ms"},
+	{appRace.ErrInvalidAvgHR, http.StatusBadRequest, "invalid_heart_rate", "heart_rate_avg"},
+	{appRace.ErrCourseMismatch, http.StatusUnprocessableEntity, "course_distance_mismatch", "distance_km"},
+
+	// courses
+	{race.ErrCourseNotFound, http.StatusNotFound, "course_not_found", ""},
+	{race.ErrInvalidCoordinates, http.StatusBadRequest, "invalid_coordinates", "file"},
+	{race.ErrCourseTooShort, http.StatusBadRequest, "course_too_short", "file"},
+	{race.ErrInvalidCheckpointKind, http.StatusBadRequest, "invalid_checkpoint_kind", "file"},
+	{race.ErrEmptyCheckpointName, http.StatusBadRequest, "empty_checkpoint_name", "file"},
+	{race.ErrDuplicateCheckpointName, http.StatusBadRequest, "duplicate_checkpoint_name", "file"},
+	{race.ErrCheckpointOffCourse, http.StatusBadRequest, "checkpoint_off_course", "file"},
+	{race.ErrCourseDistanceMismatch, http.StatusUnprocessableEntity, "course_distance_mismatch", "file"},
+	{race.ErrInvalidProfileStep, http.StatusBadRequest, "invalid_profile_step", "profile_step_km"},
+	{appCourse.ErrUnsupportedRouteFormat, http.StatusBadRequest, "unsupported_format", "format"},
+	{appCourse.ErrInvalidRouteFile, http.StatusBadRequest, "invalid_route_file", "file"},
+
+	// registrations
+	{registration.ErrNotConfigured, http.StatusNotFound, "registration_not_configured", ""},
+	{registration.ErrEmptyRaceID, http.StatusBadRequest, "empty_race_id", "race_id"},
+	{registration.ErrEmptyRunnerID, http.StatusBadRequest, "empty_runner_id", "runner_id"},
+	{registration.ErrInvalidCapacity, http.StatusBadRequest, "invalid_capacity", "capacity"},
+	{registration.ErrInvalidWindow, http.StatusBadRequest, "invalid_window", "closes_at"},
+	{registration.ErrCapacityBelowConfirmed, http.StatusConflict, "capacity_below_confirmed", "capacity"},
+	{registration.ErrRegistrationClosed, http.StatusConflict, "registration_closed", ""},
+	{registration.ErrAlreadyRegistered, http.StatusConflict, "already_registered", ""},
+	{registration.ErrNotRegistered, http.StatusUnprocessableEntity, "not_registered", "runner_id"},
+	{registration.ErrConcurrentUpdate, http.StatusConflict, "concurrent_update", ""},
+
+	// bibs
+	{bib.ErrNotConfigured, http.StatusNotFound, "bib_ranges_not_configured", ""},
+	{bib.ErrEmptyRaceID, http.StatusBadRequest, "empty_race_id", "race_id"},
+	{bib.ErrEmptyRunnerID, http.StatusBadRequest, "empty_runner_id", "runner_id"},
+	{bib.ErrEmptyRangeName, http.StatusBadRequest, "empty_range_name", "ranges"},
+	{bib.ErrInvalidRange, http.StatusBadRequest, "invalid_range", "ranges"},
+	{bib.ErrDuplicateRangeName, http.StatusBadRequest, "duplicate_range_name", "ranges"},
+	{bib.ErrOverlappingRanges, http.StatusBadRequest, "overlapping_ranges", "ranges"},
+	{bib.ErrNoRanges, http.StatusBadRequest, "missing_ranges", "ranges"},
+	{bib.ErrAssignmentOutOfRange, http.StatusConflict, "assigned_bib_outside_ranges", "ranges"},
+	{bib.ErrRangeNotFound, http.StatusBadRequest, "range_not_found", "range"},
+	{bib.ErrRangeFull, http.StatusConflict, "range_full", "range"},
+	{bib.ErrNumberOutsideRanges, http.StatusBadRequest, "bib_outside_ranges", "bib"},
+	{bib.ErrNumberTaken, http.StatusConflict, "bib_taken", "bib"},
+	{bib.ErrChipTaken, http.StatusConflict, "chip_taken", "chip_id"},
+	{bib.ErrAlreadyAssigned, http.StatusConflict, "bib_already_assigned", ""},
+	{bib.ErrNotAssigned, http.StatusNotFound, "bib_not_assigned", ""},
+	{bib.ErrMissingChangeReason, http.StatusBadRequest, "missing_reason", "reason"},
+	{bib.ErrConcurrentUpdate, http.StatusConflict, "concurrent_update", ""},
+
+	// timing
+	{timing.ErrNotConfigured, http.StatusNotFound, "timing_not_configured", ""},
+	{timing.ErrResultFromTiming, http.StatusConflict, "timed_race", "finish_time_ms"},
+	{timing.ErrEmptyRaceID, http.StatusBadRequest, "empty_race_id", "race_id"},
+	{timing.ErrMissingGunTime, http.StatusBadRequest, "missing_gun_time", "gun_time"},
+	{timing.ErrInvalidDebounce, http.StatusBadRequest, "invalid_debounce", "debounce_ms"},
+	{timing.ErrEmptyCheckpointID, http.StatusBadRequest, "empty_checkpoint_id", "checkpoint_id"},
+	{timing.ErrInvalidCheckpointKm, http.StatusBadRequest, "invalid_checkpoint_distance", "checkpoints"},
+	{timing.ErrNoCheckpoints, http.StatusBadRequest, "missing_checkpoints", "checkpoints"},
+	{timing.ErrDuplicateCheckpoint, http.StatusBadRequest, "duplicate_checkpoint", "checkpoints"},
+	{timing.ErrCheckpointBeyondRace, http.StatusBadRequest, "checkpoint_beyond_race", "checkpoints"},
+	{timing.ErrMissingFinish, http.StatusBadRequest, "missing_finish", "checkpoints"},
+	{timing.ErrCheckpointInUse, http.StatusConflict, "checkpoint_in_use", "checkpoints"},
+	{timing.ErrEmptyChipID, http.StatusBadRequest, "empty_chip_id", "chip_id"},
+	{timing.ErrMissingReadTime, http.StatusBadRequest, "missing_timestamp", "timestamp"},
+	{timing.ErrUnknownCheckpoint, http.StatusUnprocessableEntity, "unknown_checkpoint", "checkpoint_id"},
+	{timing.ErrConcurrentUpdate, http.StatusConflict, "concurrent_update", ""},
+	{appTiming.ErrUnsupportedReadFormat, http.StatusBadRequest, "unsupported_format", "format"},
+	{appTiming.ErrInvalidReadFile, http.StatusBadRequest, "invalid_read_file", "file"},
+
+	// analytics
+	{race.ErrNoPerformances, http.StatusUnprocessableEntity, "no_results", ""},
+	{analytics.ErrEmptyRunnerID, http.StatusBadRequest, "empty_runner_id", "runner_id"},
+}
+
+// Map returns the HTTP status and error detail of err.
+// Unknown errors are reported as internal errors without exposing their message.
+func Map(err error) (int, ErrorDetail) {
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return m.status, ErrorDetail{Code: m.code, Message: err.Error(), Field: m.field}
+		}
+	}
+	return http.StatusInternalServerError, ErrorDetail{Code: CodeInternal, Message: http.StatusText(http.StatusInternalServerError)}
+}
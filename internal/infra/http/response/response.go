@@ -0,0 +1,84 @@
+// Package response contains the helpers that write the JSON responses of the HTTP API
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ErrorResponse represents the envelope of every error returned by the HTTP API
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an error with a stable machine-readable code
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+// CreatedResponse represents the response model of a created resource
+type CreatedResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// JSON writes the provided value as a JSON body with the provided status code
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Created writes a 201 Created response with the id of the created resource.
+// The Location header is set when location is not empty.
+func Created(w http.ResponseWriter, location string, id uuid.UUID) {
+	if location != "" {
+		w.Header().Set("Location", location)
+	}
+	JSON(w, http.StatusCreated, CreatedResponse{ID: id})
+}
+
+// NoContent writes a 204 No Content response
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Error writes the error envelope for err, using the status and code registered in the error mapper
+func Error(w http.ResponseWriter, err error) {
+	status, detail := Map(err)
+	JSON(w, status, ErrorResponse{Error: detail})
+}
+
+// BadRequest writes a 400 Bad Request error envelope for request problems detected by the handlers
+func BadRequest(w http.ResponseWriter, code, field, message string) {
+	JSON(w, http.StatusBadRequest, ErrorResponse{Error: ErrorDetail{Code: code, Message: message, Field: field}})
+}
+
+// MalformedBody writes the error envelope for a request body that could not be decoded.
+// Bodies that exceed the size limit of the request are reported as too large.
+func MalformedBody(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		RequestTooLarge(w, tooLarge.Limit)
+		return
+	}
+	BadRequest(w, CodeMalformedBody, "", err.Error())
+}
+
+// RequestTooLarge writes a 413 Request Entity Too Large error envelope for a body exceeding the limit in bytes
+func RequestTooLarge(w http.ResponseWriter, limit int64) {
+	JSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{Error: ErrorDetail{
+		Code:    CodeRequestTooLarge,
+		Message: fmt.Sprintf("request body exceeds %d bytes", limit),
+	}})
+}
+
+// InvalidID writes the error envelope for an identifier that is not a valid UUID
+func InvalidID(w http.ResponseWriter, field string) {
+	BadRequest(w, CodeInvalidID, field, "invalid "+field+" format")
+}
@@ -0,0 +1,126 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail ErrorDetail
+	}{
+		{
+			name:       "should map validation error with field",
+			err:        runner.ErrInvalidEmail,
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Code: "invalid_email", Message: "invalid email address", Field: "email_address"},
+		},
+		{
+			name:       "should map domain race validation error",
+			err:        race.ErrEmptyName,
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Code: "empty_name", Message: "name cannot be empty", Field: "name"},
+		},
+		{
+			name:       "should map wrapped not found error",
+			err:        fmt.Errorf("loading race: %w", race.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+			wantDetail: ErrorDetail{Code: "race_not_found", Message: "loading race: race not found"},
+		},
+		{
+			name:       "should map registration conflict",
+			err:        registration.ErrRegistrationClosed,
+			wantStatus: http.StatusConflict,
+			wantDetail: ErrorDetail{Code: "registration_closed", Message: "registration is not open"},
+		},
+		{
+			name:       "should map exceeded request deadline",
+			err:        fmt.Errorf("querying runner: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusServiceUnavailable,
+			wantDetail: ErrorDetail{Code: CodeRequestTimeout, Message: "querying runner: context deadline exceeded"},
+		},
+		{
+			name:       "should map missing authorization",
+			err:        auth.ErrForbidden,
+			wantStatus: http.StatusForbidden,
+			wantDetail: ErrorDetail{Code: "forbidden", Message: "not allowed to perform this operation"},
+		},
+		{
+			name:       "should hide unknown errors",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: ErrorDetail{Code: CodeInternal, Message: "Internal Server Error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := Map(tt.err)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantDetail, detail)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	rsp := httptest.NewRecorder()
+	Error(rsp, runner.ErrNotFound)
+
+	assert.Equal(t, http.StatusNotFound, rsp.Code)
+	assert.Equal(t, "application/json", rsp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":{"code":"runner_not_found","message":"runner not found"}}`, rsp.Body.String())
+}
+
+func TestCreated(t *testing.T) {
+	id := uuid.New()
+	rsp := httptest.NewRecorder()
+	Created(rsp, "/runners/"+id.String(), id)
+
+	assert.Equal(t, http.StatusCreated, rsp.Code)
+	assert.Equal(t, "/runners/"+id.String(), rsp.Header().Get("Location"))
+	assert.JSONEq(t, `{"id":"`+id.String()+`"}`, rsp.Body.String())
+}
+
+func TestMalformedBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "should report a decoding error",
+			err:        errors.New("unexpected EOF"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"malformed_body","message":"unexpected EOF"}}`,
+		},
+		{
+			name:       "should report a body exceeding the limit as too large",
+			err:        &http.MaxBytesError{Limit: 1024},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   `{"error":{"code":"request_too_large","message":"request body exceeds 1024 bytes"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp := httptest.NewRecorder()
+			MalformedBody(rsp, tt.err)
+
+			assert.Equal(t, tt.wantStatus, rsp.Code)
+			assert.JSONEq(t, tt.wantBody, rsp.Body.String())
+		})
+	}
+}
@@ -2,16 +2,24 @@
 package runner
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/google/uuid"
-	domainRunner "github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/gorilla/mux"
+	appRunner "github.com/pkritiotis/go-clean-architecture-example/internal/app/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type runnerService interface {
-	CreateRunner(name, email string) (uuid.UUID, error)
+	CreateRunner(ctx context.Context, name, email string) (uuid.UUID, error)
+	GetRunner(ctx context.Context, id uuid.UUID) (appRunner.RunnerItem, error)
+	ListRunners(ctx context.Context, offset, limit int) (appRunner.RunnerPage, error)
+	RenameRunner(ctx context.Context, id uuid.UUID, name string) error
+	UpdateRunnerProfile(ctx context.Context, id uuid.UUID, gender string, dateOfBirth time.Time) error
+	DeleteRunner(ctx context.Context, id uuid.UUID) error
 }
 
 // Handler Runner http request service
@@ -35,19 +43,190 @@ func (c Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var runnerToAdd CreateRunnerRequestModel
 	decodeErr := json.NewDecoder(r.Body).Decode(&runnerToAdd)
 	if decodeErr != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, decodeErr.Error())
+		response.MalformedBody(w, decodeErr)
 		return
 	}
-	id, err := c.runnerService.CreateRunner(runnerToAdd.Name, runnerToAdd.EmailAddress)
+	id, err := c.runnerService.CreateRunner(r.Context(), runnerToAdd.Name, runnerToAdd.EmailAddress)
 	if err != nil {
-		if errors.Is(err, domainRunner.ErrInvalidEmail) || errors.Is(err, domainRunner.ErrRunnerNameCannotBeEmpty) {
-			w.WriteHeader(http.StatusBadRequest)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, err)
+		return
+	}
+	response.Created(w, "/runners/"+id.String(), id)
+}
+
+// RunnerResponseModel represents the response model of a runner
+type RunnerResponseModel struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	EmailAddress string    `json:"email_address"`
+	Gender       string    `json:"gender,omitempty"`
+	DateOfBirth  string    `json:"date_of_birth,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RunnerListResponseModel represents the response model of a page of runners
+type RunnerListResponseModel struct {
+	Runners []RunnerResponseModel `json:"runners"`
+	Total   int                   `json:"total"`
+	Offset  int                   `json:"offset"`
+	Limit   int                   `json:"limit"`
+}
+
+// Get Returns the runner with the id provided in the path
+func (c Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := runnerIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := c.runnerService.GetRunner(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRunnerResponseModel(item))
+}
+
+// List Returns a page of runners based on the offset and limit query parameters
+func (c Handler) List(w http.ResponseWriter, r *http.Request) {
+	offset, err := intQueryParam(r, "offset")
+	if err != nil {
+		response.BadRequest(w, response.CodeInvalidParameter, "offset", "offset query parameter must be an integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit")
+	if err != nil {
+		response.BadRequest(w, response.CodeInvalidParameter, "limit", "limit query parameter must be an integer")
+		return
+	}
+
+	page, err := c.runnerService.ListRunners(r.Context(), offset, limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	list := RunnerListResponseModel{
+		Runners: make([]RunnerResponseModel, len(page.Runners)),
+		Total:   page.Total,
+		Offset:  page.Offset,
+		Limit:   page.Limit,
+	}
+	for i, item := range page.Runners {
+		list.Runners[i] = toRunnerResponseModel(item)
+	}
+
+	response.JSON(w, http.StatusOK, list)
+}
+
+// RenameRunnerRequestModel represents the request model expected for Rename request
+type RenameRunnerRequestModel struct {
+	Name string `json:"name"`
+}
+
+// Rename Renames the runner with the id provided in the path
+func (c Handler) Rename(w http.ResponseWriter, r *http.Request) {
+	id, ok := runnerIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var renameRequest RenameRunnerRequestModel
+	decodeErr := json.NewDecoder(r.Body).Decode(&renameRequest)
+	if decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	err := c.runnerService.RenameRunner(r.Context(), id, renameRequest.Name)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// UpdateProfileRequestModel represents the request model expected for UpdateProfile request
+type UpdateProfileRequestModel struct {
+	Gender      string `json:"gender"`
+	DateOfBirth string `json:"date_of_birth"`
+}
+
+// UpdateProfile Sets the gender and date of birth of the runner with the id provided in the path
+func (c Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	id, ok := runnerIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var profileRequest UpdateProfileRequestModel
+	decodeErr := json.NewDecoder(r.Body).Decode(&profileRequest)
+	if decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	var dateOfBirth time.Time
+	if profileRequest.DateOfBirth != "" {
+		var err error
+		dateOfBirth, err = time.Parse(time.DateOnly, profileRequest.DateOfBirth)
+		if err != nil {
+			response.BadRequest(w, response.CodeInvalidParameter, "date_of_birth", "date_of_birth must be formatted as YYYY-MM-DD")
+			return
 		}
-		fmt.Fprint(w, err.Error())
+	}
+
+	err := c.runnerService.UpdateRunnerProfile(r.Context(), id, profileRequest.Gender, dateOfBirth)
+	if err != nil {
+		response.Error(w, err)
 		return
 	}
-	w.Write([]byte(id.String()))
+	response.NoContent(w)
+}
+
+// Delete Deletes the runner with the id provided in the path
+func (c Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := runnerIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := c.runnerService.DeleteRunner(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+func runnerIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.InvalidID(w, "id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func intQueryParam(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func toRunnerResponseModel(item appRunner.RunnerItem) RunnerResponseModel {
+	model := RunnerResponseModel{
+		ID:           item.ID,
+		Name:         item.Name,
+		EmailAddress: item.EmailAddress,
+		Gender:       item.Gender,
+		CreatedAt:    item.CreatedAt,
+	}
+	if !item.DateOfBirth.IsZero() {
+		model.DateOfBirth = item.DateOfBirth.Format(time.DateOnly)
+	}
+	return model
 }
@@ -2,24 +2,55 @@ package runner
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	appRunner "github.com/pkritiotis/go-clean-architecture-example/internal/app/runner"
+	domainRunner "github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type MockRunningService struct {
-	Handler func(name, email string) (uuid.UUID, error)
+	Handler        func(name, email string) (uuid.UUID, error)
+	GetHandler     func(id uuid.UUID) (appRunner.RunnerItem, error)
+	ListHandler    func(offset, limit int) (appRunner.RunnerPage, error)
+	RenameHandler  func(id uuid.UUID, name string) error
+	ProfileHandler func(id uuid.UUID, gender string, dateOfBirth time.Time) error
+	DeleteHandler  func(id uuid.UUID) error
 }
 
-func (m MockRunningService) CreateRunner(name string, email string) (uuid.UUID, error) {
+func (m MockRunningService) CreateRunner(_ context.Context, name string, email string) (uuid.UUID, error) {
 	return m.Handler(name, email)
 }
 
+func (m MockRunningService) GetRunner(_ context.Context, id uuid.UUID) (appRunner.RunnerItem, error) {
+	return m.GetHandler(id)
+}
+
+func (m MockRunningService) ListRunners(_ context.Context, offset, limit int) (appRunner.RunnerPage, error) {
+	return m.ListHandler(offset, limit)
+}
+
+func (m MockRunningService) RenameRunner(_ context.Context, id uuid.UUID, name string) error {
+	return m.RenameHandler(id, name)
+}
+
+func (m MockRunningService) UpdateRunnerProfile(_ context.Context, id uuid.UUID, gender string, dateOfBirth time.Time) error {
+	return m.ProfileHandler(id, gender, dateOfBirth)
+}
+
+func (m MockRunningService) DeleteRunner(_ context.Context, id uuid.UUID) error {
+	return m.DeleteHandler(id)
+}
+
 func TestRunnerHandler_AddRunner(t *testing.T) {
 	testUUID := uuid.New()
 	tests := []struct {
@@ -43,8 +74,8 @@ func TestRunnerHandler_AddRunner(t *testing.T) {
 				Name:         "test",
 				EmailAddress: "name@example.com",
 			},
-			ResultBodyContains: testUUID.String(),
-			ResultStatus:       http.StatusOK,
+			ResultBodyContains: `{"id":"` + testUUID.String() + `"}`,
+			ResultStatus:       http.StatusCreated,
 		},
 		{
 			name: "should return error",
@@ -59,9 +90,22 @@ func TestRunnerHandler_AddRunner(t *testing.T) {
 				Name:         "test",
 				EmailAddress: "name@example.com",
 			},
-			ResultBodyContains: errors.New("test error").Error(),
+			ResultBodyContains: `{"error":{"code":"internal_error","message":"Internal Server Error"}}`,
 			ResultStatus:       http.StatusInternalServerError,
 		},
+		{
+			name: "should return validation error",
+			service: MockRunningService{Handler: func(name, email string) (uuid.UUID, error) {
+				return uuid.UUID{}, domainRunner.ErrInvalidEmail
+			}},
+			reqVars: map[string]interface{}{},
+			Body: CreateRunnerRequestModel{
+				Name:         "test",
+				EmailAddress: "invalid",
+			},
+			ResultBodyContains: `{"error":{"code":"invalid_email","message":"invalid email address","field":"email_address"}}`,
+			ResultStatus:       http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -71,7 +115,216 @@ func TestRunnerHandler_AddRunner(t *testing.T) {
 			req, _ := http.NewRequest("POST", "", buf)
 			rsp := httptest.NewRecorder()
 			c.Create(rsp, req)
-			assert.Contains(t, tt.ResultBodyContains, rsp.Body.String())
+			assert.Contains(t, rsp.Body.String(), tt.ResultBodyContains)
+			assert.Equal(t, tt.ResultStatus, rsp.Code)
+			assert.Equal(t, "application/json", rsp.Header().Get("Content-Type"))
+			if rsp.Code == http.StatusCreated {
+				assert.Equal(t, "/runners/"+testUUID.String(), rsp.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestRunnerHandler_Get(t *testing.T) {
+	testUUID := uuid.New()
+	createdAt := time.Date(2025, 3, 14, 18, 0, 0, 0, time.UTC)
+	service := MockRunningService{GetHandler: func(id uuid.UUID) (appRunner.RunnerItem, error) {
+		if id != testUUID {
+			return appRunner.RunnerItem{}, domainRunner.ErrNotFound
+		}
+		return appRunner.RunnerItem{ID: testUUID, Name: "test", EmailAddress: "name@example.com", CreatedAt: createdAt}, nil
+	}}
+
+	tests := []struct {
+		name               string
+		id                 string
+		ResultBodyContains string
+		ResultStatus       int
+	}{
+		{
+			name:               "should return runner",
+			id:                 testUUID.String(),
+			ResultBodyContains: `"name":"test"`,
+			ResultStatus:       http.StatusOK,
+		},
+		{
+			name:               "should return not found",
+			id:                 uuid.New().String(),
+			ResultBodyContains: `"code":"runner_not_found"`,
+			ResultStatus:       http.StatusNotFound,
+		},
+		{
+			name:               "should reject invalid id",
+			id:                 "not-a-uuid",
+			ResultBodyContains: `"code":"invalid_id"`,
+			ResultStatus:       http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHandler(service)
+			req, _ := http.NewRequest("GET", "/runners/"+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rsp := httptest.NewRecorder()
+			c.Get(rsp, req)
+			assert.Contains(t, rsp.Body.String(), tt.ResultBodyContains)
+			assert.Equal(t, tt.ResultStatus, rsp.Code)
+		})
+	}
+}
+
+func TestRunnerHandler_List(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		service            MockRunningService
+		ResultBodyContains string
+		ResultStatus       int
+	}{
+		{
+			name:  "should return page of runners",
+			query: "?offset=1&limit=1",
+			service: MockRunningService{ListHandler: func(offset, limit int) (appRunner.RunnerPage, error) {
+				if offset != 1 || limit != 1 {
+					return appRunner.RunnerPage{}, errors.New("objects not matching")
+				}
+				return appRunner.RunnerPage{
+					Runners: []appRunner.RunnerItem{{ID: uuid.New(), Name: "test", EmailAddress: "name@example.com"}},
+					Total:   2,
+					Offset:  1,
+					Limit:   1,
+				}, nil
+			}},
+			ResultBodyContains: `"total":2`,
+			ResultStatus:       http.StatusOK,
+		},
+		{
+			name:               "should reject non numeric limit",
+			query:              "?limit=ten",
+			service:            MockRunningService{},
+			ResultBodyContains: "limit query parameter must be an integer",
+			ResultStatus:       http.StatusBadRequest,
+		},
+		{
+			name:  "should return validation error",
+			query: "?offset=-1",
+			service: MockRunningService{ListHandler: func(offset, limit int) (appRunner.RunnerPage, error) {
+				return appRunner.RunnerPage{}, appRunner.ErrInvalidOffset
+			}},
+			ResultBodyContains: appRunner.ErrInvalidOffset.Error(),
+			ResultStatus:       http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHandler(tt.service)
+			req, _ := http.NewRequest("GET", "/runners"+tt.query, nil)
+			rsp := httptest.NewRecorder()
+			c.List(rsp, req)
+			assert.Contains(t, rsp.Body.String(), tt.ResultBodyContains)
+			assert.Equal(t, tt.ResultStatus, rsp.Code)
+		})
+	}
+}
+
+func TestRunnerHandler_Rename(t *testing.T) {
+	testUUID := uuid.New()
+	service := MockRunningService{RenameHandler: func(id uuid.UUID, name string) error {
+		if id != testUUID {
+			return domainRunner.ErrNotFound
+		}
+		if name == "" {
+			return domainRunner.ErrRunnerNameCannotBeEmpty
+		}
+		return nil
+	}}
+
+	tests := []struct {
+		name         string
+		id           string
+		body         string
+		ResultStatus int
+	}{
+		{name: "should rename runner", id: testUUID.String(), body: `{"name":"new name"}`, ResultStatus: http.StatusNoContent},
+		{name: "should reject empty name", id: testUUID.String(), body: `{"name":""}`, ResultStatus: http.StatusBadRequest},
+		{name: "should reject malformed body", id: testUUID.String(), body: `{`, ResultStatus: http.StatusBadRequest},
+		{name: "should return not found", id: uuid.New().String(), body: `{"name":"new name"}`, ResultStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHandler(service)
+			req, _ := http.NewRequest("PATCH", "/runners/"+tt.id, strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rsp := httptest.NewRecorder()
+			c.Rename(rsp, req)
+			assert.Equal(t, tt.ResultStatus, rsp.Code)
+		})
+	}
+}
+
+func TestRunnerHandler_UpdateProfile(t *testing.T) {
+	testUUID := uuid.New()
+	service := MockRunningService{ProfileHandler: func(id uuid.UUID, gender string, dateOfBirth time.Time) error {
+		if id != testUUID {
+			return domainRunner.ErrNotFound
+		}
+		if gender != "female" && gender != "" {
+			return domainRunner.ErrInvalidGender
+		}
+		if !dateOfBirth.IsZero() && !dateOfBirth.Equal(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)) {
+			return errors.New("unexpected date of birth")
+		}
+		return nil
+	}}
+
+	tests := []struct {
+		name         string
+		id           string
+		body         string
+		ResultStatus int
+	}{
+		{name: "should update profile", id: testUUID.String(), body: `{"gender":"female","date_of_birth":"1990-05-17"}`, ResultStatus: http.StatusNoContent},
+		{name: "should clear profile", id: testUUID.String(), body: `{}`, ResultStatus: http.StatusNoContent},
+		{name: "should reject invalid gender", id: testUUID.String(), body: `{"gender":"x"}`, ResultStatus: http.StatusBadRequest},
+		{name: "should reject invalid date of birth", id: testUUID.String(), body: `{"date_of_birth":"17/05/1990"}`, ResultStatus: http.StatusBadRequest},
+		{name: "should return not found", id: uuid.New().String(), body: `{}`, ResultStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHandler(service)
+			req, _ := http.NewRequest("PUT", "/runners/"+tt.id+"/profile", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rsp := httptest.NewRecorder()
+			c.UpdateProfile(rsp, req)
+			assert.Equal(t, tt.ResultStatus, rsp.Code)
+		})
+	}
+}
+
+func TestRunnerHandler_Delete(t *testing.T) {
+	testUUID := uuid.New()
+	service := MockRunningService{DeleteHandler: func(id uuid.UUID) error {
+		if id != testUUID {
+			return domainRunner.ErrNotFound
+		}
+		return nil
+	}}
+
+	tests := []struct {
+		name         string
+		id           string
+		ResultStatus int
+	}{
+		{name: "should delete runner", id: testUUID.String(), ResultStatus: http.StatusNoContent},
+		{name: "should return not found", id: uuid.New().String(), ResultStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHandler(service)
+			req, _ := http.NewRequest("DELETE", "/runners/"+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rsp := httptest.NewRecorder()
+			c.Delete(rsp, req)
 			assert.Equal(t, tt.ResultStatus, rsp.Code)
 		})
 	}
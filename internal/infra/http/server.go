@@ -2,12 +2,31 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/activity"
+	appAnalytics "github.com/pkritiotis/go-clean-architecture-example/internal/app/analytics"
+	appAuth "github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	appBib "github.com/pkritiotis/go-clean-architecture-example/internal/app/bib"
+	appCourse "github.com/pkritiotis/go-clean-architecture-example/internal/app/course"
+	appLogging "github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
 	appRace "github.com/pkritiotis/go-clean-architecture-example/internal/app/race"
+	appRegistration "github.com/pkritiotis/go-clean-architecture-example/internal/app/registration"
+	appRunner "github.com/pkritiotis/go-clean-architecture-example/internal/app/runner"
+	appTiming "github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/analytics"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/course"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/logging"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/race"
-	"log"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/timing"
+	"io"
+	"net"
 	"net/http"
 	"time"
 
@@ -16,37 +35,195 @@ import (
 )
 
 type runnerService interface {
-	CreateRunner(name, email string) (uuid.UUID, error)
+	CreateRunner(ctx context.Context, name, email string) (uuid.UUID, error)
+	GetRunner(ctx context.Context, id uuid.UUID) (appRunner.RunnerItem, error)
+	ListRunners(ctx context.Context, offset, limit int) (appRunner.RunnerPage, error)
+	RenameRunner(ctx context.Context, id uuid.UUID, name string) error
+	UpdateRunnerProfile(ctx context.Context, id uuid.UUID, gender string, dateOfBirth time.Time) error
+	DeleteRunner(ctx context.Context, id uuid.UUID) error
 }
 
 type raceService interface {
-	CreateRace(name, location string, date time.Time, distanceKm, elevationGain float64) (uuid.UUID, error)
-	AddResult(runnerID, raceID uuid.UUID, finishTime time.Duration, heartRateAvg int, notes string) (uuid.UUID, error)
-	GetResults(runnerID uuid.UUID) ([]appRace.ResultItem, error)
+	CreateRace(ctx context.Context, name, location string, date time.Time, distanceKm, elevationGain float64, waves []appRace.WaveItem, rankingPolicy string) (uuid.UUID, error)
+	GetRace(ctx context.Context, raceID uuid.UUID) (appRace.RaceItem, error)
+	ListRaces(ctx context.Context, filter appRace.RaceFilterItem) (appRace.RacePage, error)
+	UpdateRace(ctx context.Context, raceID uuid.UUID, changes appRace.RaceChangesItem) (appRace.RaceItem, error)
+	CancelRace(ctx context.Context, raceID uuid.UUID, reason string) (appRace.RaceItem, error)
+	AddResult(ctx context.Context, runnerID, raceID uuid.UUID, status, statusReason string, finishTime, gunTime time.Duration, heartRateAvg int, notes string, splits []appRace.SplitItem) (appRace.AddedResultItem, error)
+	GetResults(ctx context.Context, runnerID uuid.UUID, status string) ([]appRace.ResultItem, error)
+	PublishResults(ctx context.Context, raceID uuid.UUID, reason string) (int, error)
+	AmendResult(ctx context.Context, raceID, resultID uuid.UUID, status, statusReason string, finishTime time.Duration, reason string) error
+	GetPersonalRecords(ctx context.Context, runnerID uuid.UUID) ([]appRace.PersonalRecordItem, error)
+	GetLeaderboard(ctx context.Context, raceID uuid.UUID) ([]appRace.StandingItem, error)
+	ImportResult(ctx context.Context, runnerID, raceID uuid.UUID, format activity.Format, file io.Reader, notes string) (appRace.ImportedResultItem, error)
+}
+
+type analyticsService interface {
+	PredictFinishTimes(ctx context.Context, runnerID uuid.UUID, distancesKm []float64, elevationGain float64) ([]appAnalytics.PredictionItem, error)
+}
+
+type registrationService interface {
+	ConfigureRegistration(ctx context.Context, raceID uuid.UUID, capacity int, opensAt, closesAt time.Time, requiredForResults bool) error
+	Register(ctx context.Context, raceID, runnerID uuid.UUID) (appRegistration.RegistrationItem, error)
+	CancelRegistration(ctx context.Context, raceID, runnerID uuid.UUID) error
+	GetEntryList(ctx context.Context, raceID uuid.UUID) (appRegistration.EntryListItem, error)
+}
+
+type bibService interface {
+	ConfigureRanges(ctx context.Context, raceID uuid.UUID, ranges []appBib.RangeItem) error
+	GetRanges(ctx context.Context, raceID uuid.UUID) ([]appBib.RangeItem, error)
+	AssignBib(ctx context.Context, raceID, runnerID uuid.UUID, rangeName, chipID string) (appBib.BibItem, error)
+	ReassignBib(ctx context.Context, raceID, runnerID uuid.UUID, number int, rangeName, chipID, reason string) (appBib.BibItem, error)
+	ExportBibs(ctx context.Context, raceID uuid.UUID) ([]appBib.BibItem, error)
+	GetBibChanges(ctx context.Context, raceID uuid.UUID) ([]appBib.BibChangeItem, error)
+}
+
+type timingService interface {
+	ConfigureTiming(ctx context.Context, raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []appTiming.CheckpointItem) error
+	GetTiming(ctx context.Context, raceID uuid.UUID) (appTiming.SessionItem, error)
+	RecordReads(ctx context.Context, raceID uuid.UUID, reads []appTiming.ReadItem) (appTiming.RecordedReadsItem, error)
+	ImportReads(ctx context.Context, raceID uuid.UUID, format appTiming.ReadFormat, file io.Reader) (appTiming.RecordedReadsItem, error)
+	GetTimes(ctx context.Context, raceID uuid.UUID) ([]appTiming.TimeItem, error)
+	GenerateResults(ctx context.Context, raceID uuid.UUID) (appTiming.GeneratedResultsItem, error)
+}
+
+type courseService interface {
+	SetCourse(ctx context.Context, raceID uuid.UUID, format appCourse.RouteFormat, file io.Reader, validateDistance bool) (appCourse.CourseItem, error)
+	GetCourse(ctx context.Context, raceID uuid.UUID, profileStepKm float64) (appCourse.CourseItem, error)
+	ExportCourse(ctx context.Context, raceID uuid.UUID, format appCourse.RouteFormat, w io.Writer) error
+}
+
+type authService interface {
+	Authenticate(ctx context.Context, credentials appAuth.Credentials) (appAuth.Principal, error)
+}
+
+type loggingService interface {
+	GetLevel(ctx context.Context) (appLogging.Level, error)
+	SetLevel(ctx context.Context, level string) (appLogging.Level, error)
+}
+
+// RequestMetrics records the requests served by the server and exposes the metrics to the Prometheus scrapers
+type RequestMetrics interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+	Handler() http.Handler
+}
+
+// Config contains the settings of the http server
+type Config struct {
+	// RequestTimeout bounds the context passed to the application services. Zero disables it.
+	RequestTimeout time.Duration
+	// AuthEnabled authenticates the callers of every request. When disabled every request is anonymous.
+	AuthEnabled bool
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound the connections as in http.Server.
+	// Zero disables them.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds the time in-flight requests are given to complete on shutdown. Zero waits for them.
+	ShutdownTimeout time.Duration
+	// MaxBodyBytes bounds the size of the request bodies other than multipart uploads. Zero disables the limit.
+	MaxBodyBytes int64
+	// Logger receives the access logs, the recovered panics and the lifecycle events of the server.
+	// Nil discards them.
+	Logger appLogging.Logger
+	// Metrics records the requests and is served on /metrics. Nil disables both.
+	Metrics RequestMetrics
 }
 
 // Server Represents the http server running for this service
 type Server struct {
-	runnerService runnerService
-	raceService   raceService
-	router        *mux.Router
+	runnerService       runnerService
+	raceService         raceService
+	analyticsService    analyticsService
+	registrationService registrationService
+	bibService          bibService
+	timingService       timingService
+	courseService       courseService
+	authService         authService
+	loggingService      loggingService
+	logger              appLogging.Logger
+	router              *mux.Router
+	middlewares         []Middleware
+	server              *http.Server
+	shutdownTimeout     time.Duration
 }
 
 // NewServer HTTP Server constructor
-func NewServer(appServices app.Services) *Server {
-	httpServer := &Server{runnerService: appServices.RunnerService, raceService: appServices.RaceService}
+func NewServer(appServices app.Services, cfg Config) *Server {
+	httpServer := &Server{
+		runnerService:       appServices.RunnerService,
+		raceService:         appServices.RaceService,
+		analyticsService:    appServices.AnalyticsService,
+		registrationService: appServices.RegistrationService,
+		bibService:          appServices.BibService,
+		timingService:       appServices.TimingService,
+		courseService:       appServices.CourseService,
+		authService:         appServices.AuthService,
+		loggingService:      appServices.LoggingService,
+		logger:              cfg.Logger,
+	}
+	if httpServer.logger == nil {
+		httpServer.logger = appLogging.Discard
+	}
 	httpServer.router = mux.NewRouter()
+	httpServer.router.NotFoundHandler = http.HandlerFunc(notFound)
+	httpServer.router.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowed)
 	httpServer.AddRunnerHTTPRoutes()
 	httpServer.AddRaceHTTPRoutes()
-	http.Handle("/", httpServer.router)
+	httpServer.AddAnalyticsHTTPRoutes()
+	httpServer.AddRegistrationHTTPRoutes()
+	httpServer.AddBibHTTPRoutes()
+	httpServer.AddTimingHTTPRoutes()
+	httpServer.AddCourseHTTPRoutes()
+	httpServer.AddLoggingHTTPRoutes()
+
+	httpServer.Use(
+		requestIDMiddleware(),
+		accessLogMiddleware(httpServer.logger, httpServer.router),
+	)
+	if cfg.Metrics != nil {
+		httpServer.AddMetricsHTTPRoutes(cfg.Metrics)
+		httpServer.Use(metricsMiddleware(cfg.Metrics, httpServer.router))
+	}
+	httpServer.Use(
+		recoveryMiddleware(httpServer.logger),
+		bodyLimitMiddleware(cfg.MaxBodyBytes),
+		timeoutMiddleware(cfg.RequestTimeout),
+		authMiddleware(httpServer.authService, cfg.AuthEnabled),
+	)
+	httpServer.server = &http.Server{
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	httpServer.shutdownTimeout = cfg.ShutdownTimeout
 
 	return httpServer
 }
 
+// Use appends middlewares to the stack wrapping every request, including the requests that match no route.
+// Middlewares run in the order they are added, before the route specific middlewares of the router.
+func (httpServer *Server) Use(middlewares ...Middleware) {
+	httpServer.middlewares = append(httpServer.middlewares, middlewares...)
+}
+
+// Handler returns the handler serving the routes of the server through the middleware stack
+func (httpServer *Server) Handler() http.Handler {
+	return chain(httpServer.router, httpServer.middlewares...)
+}
+
 // AddRunnerHTTPRoutes registers runner route handlers
 func (httpServer *Server) AddRunnerHTTPRoutes() {
 	const runnersHTTPRoutePath = "/runners"
-	httpServer.router.HandleFunc(runnersHTTPRoutePath, runner.NewHandler(httpServer.runnerService).Create).Methods("POST")
+	handler := runner.NewHandler(httpServer.runnerService)
+	httpServer.router.HandleFunc(runnersHTTPRoutePath, handler.Create).Methods("POST")
+	httpServer.router.HandleFunc(runnersHTTPRoutePath, handler.List).Methods("GET")
+	httpServer.router.HandleFunc(runnersHTTPRoutePath+"/{id}", handler.Get).Methods("GET")
+	httpServer.router.HandleFunc(runnersHTTPRoutePath+"/{id}", handler.Rename).Methods("PATCH")
+	httpServer.router.HandleFunc(runnersHTTPRoutePath+"/{id}", handler.Delete).Methods("DELETE")
+	httpServer.router.HandleFunc(runnersHTTPRoutePath+"/{id}/profile", handler.UpdateProfile).Methods("PUT")
 }
 
 // AddRaceHTTPRoutes registers race route handlers
@@ -54,12 +231,147 @@ func (httpServer *Server) AddRaceHTTPRoutes() {
 	const racesHTTPRoutePath = "/races"
 	handler := race.NewHandler(httpServer.raceService)
 	httpServer.router.HandleFunc(racesHTTPRoutePath, handler.CreateRace).Methods("POST")
-	httpServer.router.HandleFunc(racesHTTPRoutePath, handler.GetRaceResults).Methods("GET")
+	httpServer.router.HandleFunc(racesHTTPRoutePath, handler.ListRaces).Methods("GET")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}", handler.GetRace).Methods("GET")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}", handler.ReplaceRace).Methods("PUT")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}", handler.UpdateRace).Methods("PATCH")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/cancellation", handler.CancelRace).Methods("POST")
 	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/results", handler.AddResult).Methods("POST")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/results", handler.GetLeaderboard).Methods("GET")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/results/import", handler.ImportResult).Methods("POST")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/results/publish", handler.PublishResults).Methods("POST")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/results/{resultID}/amendments", handler.AmendResult).Methods("POST")
+	httpServer.router.HandleFunc("/runners/{id}/results", handler.GetRaceResults).Methods("GET")
+	httpServer.router.HandleFunc("/runners/{id}/records", handler.GetPersonalRecords).Methods("GET")
+}
+
+// AddAnalyticsHTTPRoutes registers analytics route handlers
+func (httpServer *Server) AddAnalyticsHTTPRoutes() {
+	handler := analytics.NewHandler(httpServer.analyticsService)
+	httpServer.router.HandleFunc("/runners/{id}/predictions", handler.GetPredictions).Methods("GET")
+}
+
+// AddRegistrationHTTPRoutes registers registration route handlers
+func (httpServer *Server) AddRegistrationHTTPRoutes() {
+	const racesHTTPRoutePath = "/races"
+	handler := registration.NewHandler(httpServer.registrationService)
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/registration", handler.ConfigureRegistration).Methods("PUT")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/registration", handler.GetEntryList).Methods("GET")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/registrations", handler.Register).Methods("POST")
+	httpServer.router.HandleFunc(racesHTTPRoutePath+"/{raceID}/registrations/{runnerID}", handler.CancelRegistration).Methods("DELETE")
+}
+
+// AddBibHTTPRoutes registers bib route handlers
+func (httpServer *Server) AddBibHTTPRoutes() {
+	const bibsHTTPRoutePath = "/races/{raceID}/bibs"
+	handler := bib.NewHandler(httpServer.bibService)
+	httpServer.router.HandleFunc(bibsHTTPRoutePath, handler.AssignBib).Methods("POST")
+	httpServer.router.HandleFunc(bibsHTTPRoutePath, handler.ExportBibs).Methods("GET")
+	httpServer.router.HandleFunc(bibsHTTPRoutePath+"/ranges", handler.ConfigureRanges).Methods("PUT")
+	httpServer.router.HandleFunc(bibsHTTPRoutePath+"/ranges", handler.GetRanges).Methods("GET")
+	httpServer.router.HandleFunc(bibsHTTPRoutePath+"/changes", handler.GetBibChanges).Methods("GET")
+	httpServer.router.HandleFunc(bibsHTTPRoutePath+"/{runnerID}", handler.ReassignBib).Methods("PUT")
+}
+
+// AddTimingHTTPRoutes registers timing route handlers
+func (httpServer *Server) AddTimingHTTPRoutes() {
+	const timingHTTPRoutePath = "/races/{raceID}/timing"
+	handler := timing.NewHandler(httpServer.timingService)
+	httpServer.router.HandleFunc(timingHTTPRoutePath, handler.ConfigureTiming).Methods("PUT")
+	httpServer.router.HandleFunc(timingHTTPRoutePath, handler.GetTiming).Methods("GET")
+	httpServer.router.HandleFunc(timingHTTPRoutePath+"/reads", handler.RecordReads).Methods("POST")
+	httpServer.router.HandleFunc(timingHTTPRoutePath+"/reads/import", handler.ImportReads).Methods("POST")
+	httpServer.router.HandleFunc(timingHTTPRoutePath+"/times", handler.GetTimes).Methods("GET")
+	httpServer.router.HandleFunc(timingHTTPRoutePath+"/results", handler.GenerateResults).Methods("POST")
+}
+
+// AddCourseHTTPRoutes registers course route handlers
+func (httpServer *Server) AddCourseHTTPRoutes() {
+	const courseHTTPRoutePath = "/races/{raceID}/course"
+	handler := course.NewHandler(httpServer.courseService)
+	httpServer.router.HandleFunc(courseHTTPRoutePath, handler.SetCourse).Methods("PUT")
+	httpServer.router.HandleFunc(courseHTTPRoutePath, handler.GetCourse).Methods("GET")
+	httpServer.router.HandleFunc(courseHTTPRoutePath+"/export", handler.ExportCourse).Methods("GET")
+}
+
+// AddLoggingHTTPRoutes registers the route handlers administering the logging
+func (httpServer *Server) AddLoggingHTTPRoutes() {
+	const logLevelHTTPRoutePath = "/admin/log-level"
+	handler := logging.NewHandler(httpServer.loggingService)
+	httpServer.router.HandleFunc(logLevelHTTPRoutePath, handler.GetLevel).Methods("GET")
+	httpServer.router.HandleFunc(logLevelHTTPRoutePath, handler.SetLevel).Methods("PUT")
 }
 
-// ListenAndServe Starts listening for requests
-func (httpServer *Server) ListenAndServe(port string) {
-	fmt.Println("Listening on port " + port)
-	log.Fatal(http.ListenAndServe(port, nil))
+// AddMetricsHTTPRoutes registers the route exposing the metrics to the Prometheus scrapers, which authenticate
+// as admins
+func (httpServer *Server) AddMetricsHTTPRoutes(metrics RequestMetrics) {
+	httpServer.router.Handle("/metrics", adminOnly(metrics.Handler())).Methods("GET")
+}
+
+// adminOnly only lets admins through to the handler, for the routes that are not served by an application
+// service checking the authorization itself
+func adminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := appAuth.RequireRole(r.Context(), appAuth.RoleAdmin); err != nil {
+			response.Error(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func notFound(w http.ResponseWriter, _ *http.Request) {
+	response.JSON(w, http.StatusNotFound, response.ErrorResponse{Error: response.ErrorDetail{
+		Code:    response.CodeNotFound,
+		Message: http.StatusText(http.StatusNotFound),
+	}})
+}
+
+func methodNotAllowed(w http.ResponseWriter, _ *http.Request) {
+	response.JSON(w, http.StatusMethodNotAllowed, response.ErrorResponse{Error: response.ErrorDetail{
+		Code:    response.CodeMethodNotAllowed,
+		Message: http.StatusText(http.StatusMethodNotAllowed),
+	}})
+}
+
+// ListenAndServe listens on the address and serves requests until the context is done, see Serve
+func (httpServer *Server) ListenAndServe(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	httpServer.logger.Info(ctx, "listening", appLogging.String("address", listener.Addr().String()))
+	return httpServer.Serve(ctx, listener)
+}
+
+// Serve serves the requests of the listener until the context is done, then stops accepting connections and
+// waits up to the shutdown timeout for the in-flight requests to complete.
+// It returns nil after a graceful shutdown, and the error that stopped the server otherwise.
+func (httpServer *Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer.server.Handler = httpServer.Handler()
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx := context.WithoutCancel(ctx)
+	if httpServer.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, httpServer.shutdownTimeout)
+		defer cancel()
+	}
+	httpServer.logger.Info(ctx, "shutting down, draining in-flight requests")
+	if err := httpServer.server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down http server: %w", err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServer_OwnsItsHandler(t *testing.T) {
+	first := NewServer(app.Services{}, Config{})
+	second := NewServer(app.Services{}, Config{ReadTimeout: time.Second})
+
+	assert.NotSame(t, first.router, second.router)
+	assert.Equal(t, time.Second, second.server.ReadTimeout)
+
+	rr := httptest.NewRecorder()
+	first.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestNewServer_ServesMetrics(t *testing.T) {
+	metrics := new(recordingMetrics)
+	server := NewServer(app.Services{}, Config{Metrics: metrics})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	server.Handler().ServeHTTP(rr, req.WithContext(auth.WithPrincipal(req.Context(), auth.System)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "# metrics", rr.Body.String())
+	assert.Equal(t, []observedRequest{{method: http.MethodGet, route: "/metrics", status: http.StatusOK}}, metrics.observed)
+}
+
+func TestNewServer_MetricsRequireAdmin(t *testing.T) {
+	server := NewServer(app.Services{}, Config{Metrics: new(recordingMetrics)})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	server.Handler().ServeHTTP(rr, req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: "athens-events", Role: auth.RoleOrganiser})))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestNewServer_AuthenticatesInTheMiddlewareStack(t *testing.T) {
+	server := NewServer(app.Services{}, Config{AuthEnabled: true})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	req.Header.Set("Authorization", "Basic YW5uYTpzZWNyZXQ=")
+	server.Handler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get(requestIDHeader))
+}
+
+func TestNewServer_WithoutMetrics(t *testing.T) {
+	server := NewServer(app.Services{}, Config{})
+
+	rr := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestServer_Serve_DrainsInFlightRequests(t *testing.T) {
+	server := NewServer(app.Services{}, Config{ShutdownTimeout: 5 * time.Second})
+	started := make(chan struct{})
+	server.router.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(ctx, listener) }()
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+
+	<-started
+	cancel()
+	assert.Equal(t, "done", <-responses, "the in-flight request completes")
+	assert.NoError(t, <-served)
+
+	_, err = http.Get("http://" + listener.Addr().String() + "/slow")
+	assert.Error(t, err, "no connections are accepted after the shutdown")
+}
+
+func TestServer_Serve_ReturnsListenerErrors(t *testing.T) {
+	server := NewServer(app.Services{}, Config{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	assert.Error(t, server.Serve(context.Background(), listener))
+}
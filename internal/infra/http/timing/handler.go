@@ -0,0 +1,288 @@
+// Package timing contains the http handlers of the chip timing of races
+package timing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/http/response"
+)
+
+type timingService interface {
+	ConfigureTiming(ctx context.Context, raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []timing.CheckpointItem) error
+	GetTiming(ctx context.Context, raceID uuid.UUID) (timing.SessionItem, error)
+	RecordReads(ctx context.Context, raceID uuid.UUID, reads []timing.ReadItem) (timing.RecordedReadsItem, error)
+	ImportReads(ctx context.Context, raceID uuid.UUID, format timing.ReadFormat, file io.Reader) (timing.RecordedReadsItem, error)
+	GetTimes(ctx context.Context, raceID uuid.UUID) ([]timing.TimeItem, error)
+	GenerateResults(ctx context.Context, raceID uuid.UUID) (timing.GeneratedResultsItem, error)
+}
+
+// maxUploadSize bounds the size of the read files accepted by ImportReads
+const maxUploadSize = 32 << 20
+
+// Handler timing http request service
+type Handler struct {
+	timingService timingService
+}
+
+// NewHandler Constructor
+func NewHandler(service timingService) Handler {
+	return Handler{timingService: service}
+}
+
+// CheckpointModel represents a timing mat at a distance of the course
+type CheckpointModel struct {
+	ID         string  `json:"id"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// ConfigureTimingRequestModel represents the request model for configuring the timing of a race.
+// The checkpoint at 0 km is the start line and the furthest one is the finish line.
+type ConfigureTimingRequestModel struct {
+	GunTime     time.Time         `json:"gun_time"`
+	DebounceMs  int64             `json:"debounce_ms"`
+	Checkpoints []CheckpointModel `json:"checkpoints"`
+}
+
+// ConfigureTiming handles requests to set the gun time, the debounce and the checkpoints of a race
+func (h Handler) ConfigureTiming(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	var timingRequest ConfigureTimingRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&timingRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	checkpoints := make([]timing.CheckpointItem, len(timingRequest.Checkpoints))
+	for i, model := range timingRequest.Checkpoints {
+		checkpoints[i] = timing.CheckpointItem{ID: model.ID, DistanceKm: model.DistanceKm}
+	}
+	debounce := time.Duration(timingRequest.DebounceMs) * time.Millisecond
+	if err := h.timingService.ConfigureTiming(r.Context(), raceID, timingRequest.GunTime, debounce, checkpoints); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// TimingResponse represents the response model of the timing settings of a race
+type TimingResponse struct {
+	GunTime     time.Time         `json:"gun_time"`
+	DebounceMs  int64             `json:"debounce_ms"`
+	Checkpoints []CheckpointModel `json:"checkpoints"`
+	Reads       int               `json:"reads"`
+}
+
+// GetTiming handles requests to get the timing settings of a race
+func (h Handler) GetTiming(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	session, err := h.timingService.GetTiming(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	model := TimingResponse{
+		GunTime:     session.GunTime,
+		DebounceMs:  session.Debounce.Milliseconds(),
+		Checkpoints: make([]CheckpointModel, len(session.Checkpoints)),
+		Reads:       session.Reads,
+	}
+	for i, c := range session.Checkpoints {
+		model.Checkpoints[i] = CheckpointModel{ID: c.ID, DistanceKm: c.DistanceKm}
+	}
+	response.JSON(w, http.StatusOK, model)
+}
+
+// ReadModel represents a detection of a timing chip by the mat of a checkpoint
+type ReadModel struct {
+	ChipID       string    `json:"chip_id"`
+	CheckpointID string    `json:"checkpoint_id"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// RecordReadsRequestModel represents the request model for recording a batch of reads
+type RecordReadsRequestModel struct {
+	Reads []ReadModel `json:"reads"`
+}
+
+// RecordedReadsResponse represents the response model of a recorded batch of reads
+type RecordedReadsResponse struct {
+	Received   int `json:"received"`
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+}
+
+func toRecordedReadsResponse(item timing.RecordedReadsItem) RecordedReadsResponse {
+	return RecordedReadsResponse{Received: item.Received, Accepted: item.Accepted, Duplicates: item.Duplicates}
+}
+
+// RecordReads handles requests to record a batch of reads. Duplicates are dropped, so a batch can be sent again.
+func (h Handler) RecordReads(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	var readsRequest RecordReadsRequestModel
+	if decodeErr := json.NewDecoder(r.Body).Decode(&readsRequest); decodeErr != nil {
+		response.MalformedBody(w, decodeErr)
+		return
+	}
+
+	reads := make([]timing.ReadItem, len(readsRequest.Reads))
+	for i, model := range readsRequest.Reads {
+		reads[i] = timing.ReadItem{ChipID: model.ChipID, CheckpointID: model.CheckpointID, At: model.Timestamp}
+	}
+	recorded, err := h.timingService.RecordReads(r.Context(), raceID, reads)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRecordedReadsResponse(recorded))
+}
+
+// ImportReads handles multipart requests that record the reads of a CSV or line protocol file.
+// The form contains the file and an optional format that defaults to the file extension.
+func (h Handler) ImportReads(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, response.CodeMissingParameter, "file", "a multipart file field is required")
+		return
+	}
+	defer file.Close()
+
+	var format timing.ReadFormat
+	if value := r.FormValue("format"); value != "" {
+		format, err = timing.ParseReadFormat(value)
+	} else {
+		format, err = timing.ReadFormatFromFilename(header.Filename)
+	}
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	recorded, err := h.timingService.ImportReads(r.Context(), raceID, format, file)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toRecordedReadsResponse(recorded))
+}
+
+// SplitModel represents the net time at an intermediate checkpoint
+type SplitModel struct {
+	CheckpointID string  `json:"checkpoint_id"`
+	DistanceKm   float64 `json:"distance_km"`
+	ElapsedMs    int64   `json:"elapsed_ms"`
+}
+
+// TimeResponse represents the response model of the timing of a chip that crossed the finish line
+type TimeResponse struct {
+	ChipID     string       `json:"chip_id"`
+	Bib        int          `json:"bib,omitempty"`
+	RunnerID   *uuid.UUID   `json:"runner_id,omitempty"`
+	Wave       string       `json:"wave,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	GunTimeMs  int64        `json:"gun_time_ms"`
+	NetTimeMs  int64        `json:"net_time_ms"`
+	Splits     []SplitModel `json:"splits,omitempty"`
+}
+
+// GetTimes handles requests to get the gun and net times of the chips that crossed the finish line
+func (h Handler) GetTimes(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	times, err := h.timingService.GetTimes(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	models := make([]TimeResponse, len(times))
+	for i, item := range times {
+		models[i] = TimeResponse{
+			ChipID:     item.ChipID,
+			Bib:        item.Bib,
+			Wave:       item.Wave,
+			StartedAt:  item.StartedAt,
+			FinishedAt: item.FinishedAt,
+			GunTimeMs:  item.GunTime.Milliseconds(),
+			NetTimeMs:  item.NetTime.Milliseconds(),
+		}
+		if item.RunnerID != uuid.Nil {
+			runnerID := item.RunnerID
+			models[i].RunnerID = &runnerID
+		}
+		for _, split := range item.Splits {
+			models[i].Splits = append(models[i].Splits, SplitModel{
+				CheckpointID: split.CheckpointID,
+				DistanceKm:   split.DistanceKm,
+				ElapsedMs:    split.Elapsed.Milliseconds(),
+			})
+		}
+	}
+	response.JSON(w, http.StatusOK, models)
+}
+
+// GeneratedResultsResponse represents the response model of the results generated from the timing reads
+type GeneratedResultsResponse struct {
+	Created        int      `json:"created"`
+	Skipped        int      `json:"skipped"`
+	UnmatchedChips []string `json:"unmatched_chips"`
+}
+
+// GenerateResults handles requests to create the results of a race from the timing reads
+func (h Handler) GenerateResults(w http.ResponseWriter, r *http.Request) {
+	raceID, err := uuid.Parse(mux.Vars(r)["raceID"])
+	if err != nil {
+		response.InvalidID(w, "race_id")
+		return
+	}
+
+	generated, err := h.timingService.GenerateResults(r.Context(), raceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	unmatched := generated.UnmatchedChips
+	if unmatched == nil {
+		unmatched = []string{}
+	}
+	response.JSON(w, http.StatusOK, GeneratedResultsResponse{Created: generated.Created, Skipped: generated.Skipped, UnmatchedChips: unmatched})
+}
@@ -0,0 +1,338 @@
+package timing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	domainTiming "github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTimingService struct {
+	mock.Mock
+}
+
+func (m *mockTimingService) ConfigureTiming(_ context.Context, raceID uuid.UUID, gunTime time.Time, debounce time.Duration, checkpoints []timing.CheckpointItem) error {
+	return m.Called(raceID, gunTime, debounce, checkpoints).Error(0)
+}
+
+func (m *mockTimingService) GetTiming(_ context.Context, raceID uuid.UUID) (timing.SessionItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(timing.SessionItem), args.Error(1)
+}
+
+func (m *mockTimingService) RecordReads(_ context.Context, raceID uuid.UUID, reads []timing.ReadItem) (timing.RecordedReadsItem, error) {
+	args := m.Called(raceID, reads)
+	return args.Get(0).(timing.RecordedReadsItem), args.Error(1)
+}
+
+func (m *mockTimingService) ImportReads(_ context.Context, raceID uuid.UUID, format timing.ReadFormat, _ io.Reader) (timing.RecordedReadsItem, error) {
+	args := m.Called(raceID, format)
+	return args.Get(0).(timing.RecordedReadsItem), args.Error(1)
+}
+
+func (m *mockTimingService) GetTimes(_ context.Context, raceID uuid.UUID) ([]timing.TimeItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).([]timing.TimeItem), args.Error(1)
+}
+
+func (m *mockTimingService) GenerateResults(_ context.Context, raceID uuid.UUID) (timing.GeneratedResultsItem, error) {
+	args := m.Called(raceID)
+	return args.Get(0).(timing.GeneratedResultsItem), args.Error(1)
+}
+
+var gun = time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+
+func TestHandler_ConfigureTiming(t *testing.T) {
+	raceID := uuid.New()
+	checkpoints := []timing.CheckpointItem{{ID: "start", DistanceKm: 0}, {ID: "finish", DistanceKm: 10}}
+	body := `{"gun_time":"2025-10-05T08:00:00Z","debounce_ms":5000,"checkpoints":[{"id":"start","distance_km":0},{"id":"finish","distance_km":10}]}`
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mockTimingService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "configures the timing",
+			body: body,
+			mockSetup: func(m *mockTimingService) {
+				m.On("ConfigureTiming", raceID, gun, 5*time.Second, checkpoints).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "finish short of the race",
+			body: body,
+			mockSetup: func(m *mockTimingService) {
+				m.On("ConfigureTiming", raceID, gun, 5*time.Second, checkpoints).Return(domainTiming.ErrMissingFinish)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"code":"missing_finish","message":"the furthest checkpoint must be the finish at the race distance","field":"checkpoints"}}`,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"gun_time":"08:00"}`,
+			mockSetup:      func(*mockTimingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockTimingService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/races/"+raceID.String()+"/timing", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.ConfigureTiming(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetTiming(t *testing.T) {
+	raceID := uuid.New()
+	mockService := new(mockTimingService)
+	mockService.On("GetTiming", raceID).Return(timing.SessionItem{
+		GunTime:     gun,
+		Debounce:    5 * time.Second,
+		Checkpoints: []timing.CheckpointItem{{ID: "finish", DistanceKm: 10}},
+		Reads:       42,
+	}, nil)
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/timing", nil)
+	req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+	w := httptest.NewRecorder()
+	handler.GetTiming(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"gun_time":"2025-10-05T08:00:00Z","debounce_ms":5000,"checkpoints":[{"id":"finish","distance_km":10}],"reads":42}`, w.Body.String())
+}
+
+func TestHandler_RecordReads(t *testing.T) {
+	raceID := uuid.New()
+	reads := []timing.ReadItem{{ChipID: "E2001", CheckpointID: "finish", At: gun.Add(40 * time.Minute)}}
+	body := `{"reads":[{"chip_id":"E2001","checkpoint_id":"finish","timestamp":"2025-10-05T08:40:00Z"}]}`
+
+	tests := []struct {
+		name           string
+		mockSetup      func(m *mockTimingService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "records the reads",
+			mockSetup: func(m *mockTimingService) {
+				m.On("RecordReads", raceID, reads).Return(timing.RecordedReadsItem{Received: 1, Accepted: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"received":1,"accepted":1,"duplicates":0}`,
+		},
+		{
+			name: "unknown checkpoint",
+			mockSetup: func(m *mockTimingService) {
+				m.On("RecordReads", raceID, reads).Return(timing.RecordedReadsItem{}, domainTiming.ErrUnknownCheckpoint)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":{"code":"unknown_checkpoint","message":"read is from a checkpoint that is not configured","field":"checkpoint_id"}}`,
+		},
+		{
+			name: "timing not configured",
+			mockSetup: func(m *mockTimingService) {
+				m.On("RecordReads", raceID, reads).Return(timing.RecordedReadsItem{}, domainTiming.ErrNotConfigured)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"code":"timing_not_configured","message":"timing is not configured for the race"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockTimingService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/timing/reads", bytes.NewBufferString(body))
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.RecordReads(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ImportReads(t *testing.T) {
+	raceID := uuid.New().String()
+	newRequest := func(filename string, fields map[string]string) *http.Request {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range fields {
+			_ = writer.WriteField(k, v)
+		}
+		if filename != "" {
+			part, _ := writer.CreateFormFile("file", filename)
+			_, _ = part.Write([]byte("reads"))
+		}
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/races/"+raceID+"/timing/reads/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return mux.SetURLVars(req, map[string]string{"raceID": raceID})
+	}
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		mockSetup      func(m *mockTimingService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "should import reads using the file extension",
+			req:  newRequest("mat2.csv", nil),
+			mockSetup: func(m *mockTimingService) {
+				m.On("ImportReads", uuid.MustParse(raceID), timing.ReadFormatCSV).Return(timing.RecordedReadsItem{Received: 3, Accepted: 2, Duplicates: 1}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"received":3,"accepted":2,"duplicates":1}`,
+		},
+		{
+			name: "should prefer the format field",
+			req:  newRequest("reads.txt", map[string]string{"format": "lp"}),
+			mockSetup: func(m *mockTimingService) {
+				m.On("ImportReads", uuid.MustParse(raceID), timing.ReadFormatLineProtocol).Return(timing.RecordedReadsItem{Received: 1, Accepted: 1}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"received":1,"accepted":1,"duplicates":0}`,
+		},
+		{
+			name:           "should reject unsupported formats",
+			req:            newRequest("reads.xlsx", nil),
+			mockSetup:      func(*mockTimingService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"unsupported_format","message":"read format must be one of csv or lp","field":"format"}}`,
+		},
+		{
+			name:           "should require a file",
+			req:            newRequest("", nil),
+			mockSetup:      func(*mockTimingService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":{"code":"missing_parameter","message":"a multipart file field is required","field":"file"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockTimingService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.ImportReads(w, tt.req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetTimes(t *testing.T) {
+	raceID, runnerID := uuid.New(), uuid.New()
+	mockService := new(mockTimingService)
+	mockService.On("GetTimes", raceID).Return([]timing.TimeItem{
+		{
+			ChipID:     "E2001",
+			Bib:        7,
+			RunnerID:   runnerID,
+			Wave:       "A",
+			StartedAt:  gun.Add(20 * time.Second),
+			FinishedAt: gun.Add(40*time.Minute + 20*time.Second),
+			GunTime:    40*time.Minute + 20*time.Second,
+			NetTime:    40 * time.Minute,
+			Splits:     []timing.SplitItem{{CheckpointID: "5k", DistanceKm: 5, Elapsed: 20 * time.Minute}},
+		},
+		{ChipID: "E2009", StartedAt: gun, FinishedAt: gun.Add(50 * time.Minute), GunTime: 50 * time.Minute, NetTime: 50 * time.Minute},
+	}, nil)
+	handler := NewHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/races/"+raceID.String()+"/timing/times", nil)
+	req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+	w := httptest.NewRecorder()
+	handler.GetTimes(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"chip_id":"E2001","bib":7,"runner_id":"`+runnerID.String()+`","wave":"A","started_at":"2025-10-05T08:00:20Z","finished_at":"2025-10-05T08:40:20Z",
+		 "gun_time_ms":2420000,"net_time_ms":2400000,"splits":[{"checkpoint_id":"5k","distance_km":5,"elapsed_ms":1200000}]},
+		{"chip_id":"E2009","started_at":"2025-10-05T08:00:00Z","finished_at":"2025-10-05T08:50:00Z","gun_time_ms":3000000,"net_time_ms":3000000}
+	]`, w.Body.String())
+}
+
+func TestHandler_GenerateResults(t *testing.T) {
+	raceID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(m *mockTimingService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "generates the results",
+			mockSetup: func(m *mockTimingService) {
+				m.On("GenerateResults", raceID).Return(timing.GeneratedResultsItem{Created: 2, Skipped: 1, UnmatchedChips: []string{"E2009"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"created":2,"skipped":1,"unmatched_chips":["E2009"]}`,
+		},
+		{
+			name: "every chip matched",
+			mockSetup: func(m *mockTimingService) {
+				m.On("GenerateResults", raceID).Return(timing.GeneratedResultsItem{Created: 2}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"created":2,"skipped":0,"unmatched_chips":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockTimingService)
+			tt.mockSetup(mockService)
+			handler := NewHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/races/"+raceID.String()+"/timing/results", nil)
+			req = mux.SetURLVars(req, map[string]string{"raceID": raceID.String()})
+			w := httptest.NewRecorder()
+			handler.GenerateResults(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package logging contains the log/slog implementation of the logger of the application
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/request"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Logger provides a log/slog implementation of the Logger and the Levels ports.
+// Every record carries the identifier of the request its context belongs to, if any.
+type Logger struct {
+	logger *slog.Logger
+	level  *slog.LevelVar
+}
+
+// NewLogger creates a Logger writing records of at least the provided level to w in the provided format
+func NewLogger(w io.Writer, format string, level logging.Level) (*Logger, error) {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slogLevel(level))
+	options := &slog.HandlerOptions{Level: levelVar}
+
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, expected %q or %q", format, FormatJSON, FormatText)
+	}
+	return &Logger{logger: slog.New(handler), level: levelVar}, nil
+}
+
+// Debug logs a debug record
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, slog.LevelDebug, msg, fields)
+}
+
+// Info logs an info record
+func (l *Logger) Info(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, slog.LevelInfo, msg, fields)
+}
+
+// Warn logs a warning record
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, slog.LevelWarn, msg, fields)
+}
+
+// Error logs an error record
+func (l *Logger) Error(ctx context.Context, msg string, fields ...logging.Field) {
+	l.log(ctx, slog.LevelError, msg, fields)
+}
+
+// Level returns the minimum level of the logged records
+func (l *Logger) Level() logging.Level {
+	switch level := l.level.Level(); {
+	case level <= slog.LevelDebug:
+		return logging.LevelDebug
+	case level <= slog.LevelInfo:
+		return logging.LevelInfo
+	case level <= slog.LevelWarn:
+		return logging.LevelWarn
+	default:
+		return logging.LevelError
+	}
+}
+
+// SetLevel changes the minimum level of the logged records
+func (l *Logger) SetLevel(level logging.Level) {
+	l.level.Set(slogLevel(level))
+}
+
+func (l *Logger) log(ctx context.Context, level slog.Level, msg string, fields []logging.Field) {
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	if id := request.IDFrom(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func slogLevel(level logging.Level) slog.Level {
+	switch level {
+	case logging.LevelDebug:
+		return slog.LevelDebug
+	case logging.LevelWarn:
+		return slog.LevelWarn
+	case logging.LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_WritesJSONRecordsWithContextualFields(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(&out, FormatJSON, logging.LevelInfo)
+	require.NoError(t, err)
+	runnerID := uuid.New()
+	raceID := uuid.New()
+
+	ctx := request.WithID(context.Background(), "request-1")
+	logger.Warn(ctx, "failed to send a race notification",
+		logging.RaceID(raceID), logging.RunnerID(runnerID), logging.Err(errors.New("smtp unavailable")))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "failed to send a race notification", record["msg"])
+	assert.Equal(t, "request-1", record["request_id"])
+	assert.Equal(t, raceID.String(), record["race_id"])
+	assert.Equal(t, runnerID.String(), record["runner_id"])
+	assert.Equal(t, "smtp unavailable", record["error"])
+}
+
+func TestLogger_WritesTextRecords(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(&out, FormatText, logging.LevelInfo)
+	require.NoError(t, err)
+
+	logger.Info(context.Background(), "listening", logging.String("address", ":8080"))
+
+	assert.Contains(t, out.String(), `level=INFO msg=listening address=:8080`)
+	assert.NotContains(t, out.String(), "request_id")
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(&out, FormatJSON, logging.LevelWarn)
+	require.NoError(t, err)
+	assert.Equal(t, logging.LevelWarn, logger.Level())
+
+	logger.Info(context.Background(), "dropped")
+	assert.Empty(t, out.String())
+
+	logger.SetLevel(logging.LevelDebug)
+	logger.Debug(context.Background(), "kept")
+
+	assert.Equal(t, logging.LevelDebug, logger.Level())
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+	assert.Contains(t, out.String(), `"msg":"kept"`)
+}
+
+func TestNewLogger_RejectsUnknownFormat(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "xml", logging.LevelInfo)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics are the metrics of the service. It implements the Recorder port of the application, and its decorators
+// instrument the repositories and the notification service.
+type Metrics struct {
+	registry            *Registry
+	httpRequests        *Counter
+	httpDuration        *Histogram
+	repositoryDuration  *Histogram
+	repositoryErrors    *Counter
+	runnersCreated      *Counter
+	resultsLogged       *Counter
+	notificationsFailed *Counter
+}
+
+// New creates the metrics of the service in a new registry
+func New() *Metrics {
+	registry := NewRegistry()
+	return &Metrics{
+		registry: registry,
+		httpRequests: registry.NewCounter("racetracker_http_requests_total",
+			"HTTP requests served, by method, route template and status code.", "method", "route", "status"),
+		httpDuration: registry.NewHistogram("racetracker_http_request_duration_seconds",
+			"Latency of the HTTP requests, by method and route template.", DefaultBuckets, "method", "route"),
+		repositoryDuration: registry.NewHistogram("racetracker_repository_call_duration_seconds",
+			"Latency of the repository calls, by repository and method.", DefaultBuckets, "repository", "method"),
+		repositoryErrors: registry.NewCounter("racetracker_repository_call_errors_total",
+			"Repository calls that returned an error, by repository and method.", "repository", "method"),
+		runnersCreated: registry.NewCounter("racetracker_runners_created_total",
+			"Runners created."),
+		resultsLogged: registry.NewCounter("racetracker_results_logged_total",
+			"Race results logged, by status.", "status"),
+		notificationsFailed: registry.NewCounter("racetracker_notifications_failed_total",
+			"Notifications that could not be queued or delivered."),
+	}
+}
+
+// Handler serves the metrics in the Prometheus text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return m.registry.Handler()
+}
+
+// ObserveRequest records a served HTTP request
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.Inc(method, route, strconv.Itoa(status))
+	m.httpDuration.ObserveDuration(duration, method, route)
+}
+
+// RunnerCreated counts a runner that signed up
+func (m *Metrics) RunnerCreated(_ context.Context) {
+	m.runnersCreated.Inc()
+}
+
+// ResultLogged counts a result stored for a race, by its status
+func (m *Metrics) ResultLogged(_ context.Context, status string) {
+	m.resultsLogged.Inc(status)
+}
+
+// observeCall records the latency of a repository call and whether it failed
+func (m *Metrics) observeCall(repository, method string, start time.Time, err error) {
+	m.repositoryDuration.ObserveDuration(time.Since(start), repository, method)
+	if err != nil {
+		m.repositoryErrors.Inc(repository, method)
+	}
+}
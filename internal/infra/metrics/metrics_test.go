@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_RecordsBusinessEvents(t *testing.T) {
+	m := New()
+
+	m.RunnerCreated(context.Background())
+	m.RunnerCreated(context.Background())
+	m.ResultLogged(context.Background(), "finished")
+
+	assert.Equal(t, float64(2), m.runnersCreated.Value())
+	assert.Equal(t, float64(1), m.resultsLogged.Value("finished"))
+	assert.Equal(t, float64(0), m.resultsLogged.Value("dnf"))
+}
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest("GET", "/runners/{id}", 404, 20*time.Millisecond)
+
+	assert.Equal(t, float64(1), m.httpRequests.Value("GET", "/runners/{id}", "404"))
+	assert.Equal(t, uint64(1), m.httpDuration.Count("GET", "/runners/{id}"))
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+)
+
+// NotificationService decorates a notification.Service with the count of the notifications it fails to send
+type NotificationService struct {
+	next    notification.Service
+	metrics *Metrics
+}
+
+// NewNotificationService constructor for NotificationService
+func NewNotificationService(next notification.Service, metrics *Metrics) NotificationService {
+	return NotificationService{next: next, metrics: metrics}
+}
+
+// Notify sends the notification through the decorated service, counting the failures
+func (s NotificationService) Notify(ctx context.Context, n notification.Notification) error {
+	err := s.next.Notify(ctx, n)
+	if err != nil {
+		s.metrics.notificationsFailed.Inc()
+	}
+	return err
+}
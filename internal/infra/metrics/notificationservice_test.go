@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	errSMTP := errors.New("smtp unavailable")
+	tests := []struct {
+		name       string
+		err        error
+		wantFailed float64
+	}{
+		{name: "should not count delivered notifications", wantFailed: 0},
+		{name: "should count and return failures", err: errSMTP, wantFailed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			next := new(notification.MockNotificationService)
+			n := notification.Notification{Subject: "Welcome"}
+			next.On("Notify", n).Return(tt.err)
+
+			err := NewNotificationService(next, m).Notify(context.Background(), n)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.wantFailed, m.notificationsFailed.Value())
+			next.AssertExpectations(t)
+		})
+	}
+}
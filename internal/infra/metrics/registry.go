@@ -0,0 +1,298 @@
+// Package metrics contains the Prometheus instrumentation of the service: a registry of counters and histograms
+// exposed in the Prometheus text format, and the decorators that feed it
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms, as in the Prometheus client libraries
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelSeparator joins the label values of a series into its key, it cannot appear in valid UTF-8
+const labelSeparator = "\xff"
+
+// metric is a family of series that writes itself in the text exposition format
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics of the service and writes them in the Prometheus text exposition format
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewCounter registers a counter partitioned by the provided labels
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: newFamily(name, help, labels), series: make(map[string]*counterSeries)}
+	r.register(c)
+	return c
+}
+
+// NewHistogram registers a histogram with the provided bucket upper bounds, partitioned by the provided labels
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &Histogram{family: newFamily(name, help, labels), buckets: sorted, series: make(map[string]*histogramSeries)}
+	r.register(h)
+	return h
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format, in the order they were registered
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	counter := &countingWriter{w: w}
+	buffered := bufio.NewWriter(counter)
+	for _, m := range metrics {
+		m.write(buffered)
+	}
+	err := buffered.Flush()
+	return counter.n, err
+}
+
+// Handler serves the metrics to the Prometheus scrapers
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// family holds the description shared by the series of a metric
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func newFamily(name, help string, labels []string) family {
+	return family{name: name, help: help, labels: labels}
+}
+
+// key returns the key of the series with the provided label values, which must match the labels of the family
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s has labels %v, got %d values", f.name, f.labels, len(values)))
+	}
+	return strings.Join(values, labelSeparator)
+}
+
+func (f family) writeHeader(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, kind)
+}
+
+// writeSample writes a sample line of the series with the provided label values and an optional extra label
+func (f family) writeSample(w *bufio.Writer, name string, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(values) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range f.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabelValue(values[i]))
+		}
+		if extraLabel != "" {
+			if len(values) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraLabel, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// Counter is a monotonically increasing value per combination of label values
+type Counter struct {
+	family
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// Inc increments the series with the provided label values by one
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increments the series with the provided label values by delta, which must not be negative
+func (c *Counter) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+// Value returns the value of the series with the provided label values
+func (c *Counter) Value(values ...string) float64 {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.series[key]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeHeader(w, "counter")
+	if len(c.labels) == 0 && len(c.series) == 0 {
+		c.writeSample(w, c.name, nil, "", "", 0)
+		return
+	}
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		c.writeSample(w, c.name, s.values, "", "", s.value)
+	}
+}
+
+// Histogram counts observations in cumulative buckets per combination of label values
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe records a value in the series with the provided label values
+func (h *Histogram) Observe(value float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// ObserveDuration records a duration in seconds in the series with the provided label values
+func (h *Histogram) ObserveDuration(d time.Duration, values ...string) {
+	h.Observe(d.Seconds(), values...)
+}
+
+// Count returns the number of observations of the series with the provided label values
+func (h *Histogram) Count(values ...string) uint64 {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[key]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, upper := range h.buckets {
+			h.writeSample(w, h.name+"_bucket", s.values, "le", formatFloat(upper), float64(s.counts[i]))
+		}
+		h.writeSample(w, h.name+"_bucket", s.values, "le", "+Inf", float64(s.count))
+		h.writeSample(w, h.name+"_sum", s.values, "", "", s.sum)
+		h.writeSample(w, h.name+"_count", s.values, "", "", float64(s.count))
+	}
+}
+
+func sortedKeys[V any](series map[string]V) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("http_requests_total", "Requests served.\nBy route.", "method", "route")
+	registry.NewCounter("events_total", "Events.")
+	latency := registry.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+
+	requests.Add(2, "POST", `/b"\`)
+	requests.Inc("GET", "/a")
+	latency.Observe(0.25, "/a")
+	latency.Observe(0.5, "/a")
+	latency.ObserveDuration(2*time.Second, "/a")
+
+	var out strings.Builder
+	n, err := registry.WriteTo(&out)
+
+	require.NoError(t, err)
+	want := `# HELP http_requests_total Requests served.\nBy route.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/a"} 1
+http_requests_total{method="POST",route="/b\"\\"} 2
+# HELP events_total Events.
+# TYPE events_total counter
+events_total 0
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 0
+latency_seconds_bucket{route="/a",le="1"} 2
+latency_seconds_bucket{route="/a",le="+Inf"} 3
+latency_seconds_sum{route="/a"} 2.75
+latency_seconds_count{route="/a"} 3
+`
+	assert.Equal(t, want, out.String())
+	assert.Equal(t, int64(len(want)), n)
+	assert.Equal(t, float64(2), requests.Value("POST", `/b"\`))
+	assert.Equal(t, float64(0), requests.Value("DELETE", "/a"))
+	assert.Equal(t, uint64(3), latency.Count("/a"))
+}
+
+func TestCounter_PanicsOnMisuse(t *testing.T) {
+	counter := NewRegistry().NewCounter("events_total", "Events.", "kind")
+
+	assert.Panics(t, func() { counter.Inc() }, "missing label value")
+	assert.Panics(t, func() { counter.Add(-1, "kind") }, "negative delta")
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("events_total", "Events.").Inc()
+
+	rr := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "# HELP events_total Events.\n# TYPE events_total counter\nevents_total 1\n", rr.Body.String())
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// RunnerRepository decorates a runner.Repository with the latency and the errors of its calls
+type RunnerRepository struct {
+	next    runner.Repository
+	metrics *Metrics
+}
+
+// NewRunnerRepository constructor for RunnerRepository
+func NewRunnerRepository(next runner.Repository, metrics *Metrics) RunnerRepository {
+	return RunnerRepository{next: next, metrics: metrics}
+}
+
+// GetByID instruments runner.Repository.GetByID
+func (r RunnerRepository) GetByID(ctx context.Context, id uuid.UUID) (_ *runner.Runner, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+// GetByIDs instruments runner.Repository.GetByIDs
+func (r RunnerRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (_ []*runner.Runner, err error) {
+	defer r.observe("GetByIDs", time.Now(), &err)
+	return r.next.GetByIDs(ctx, ids)
+}
+
+// GetAll instruments runner.Repository.GetAll
+func (r RunnerRepository) GetAll(ctx context.Context) (_ []*runner.Runner, err error) {
+	defer r.observe("GetAll", time.Now(), &err)
+	return r.next.GetAll(ctx)
+}
+
+// Add instruments runner.Repository.Add
+func (r RunnerRepository) Add(ctx context.Context, rn *runner.Runner) (err error) {
+	defer r.observe("Add", time.Now(), &err)
+	return r.next.Add(ctx, rn)
+}
+
+// Update instruments runner.Repository.Update
+func (r RunnerRepository) Update(ctx context.Context, rn *runner.Runner) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, rn)
+}
+
+// Delete instruments runner.Repository.Delete
+func (r RunnerRepository) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r RunnerRepository) observe(method string, start time.Time, err *error) {
+	r.metrics.observeCall("runner", method, start, *err)
+}
+
+// RaceRepository decorates a race.Repository with the latency and the errors of its calls
+type RaceRepository struct {
+	next    race.Repository
+	metrics *Metrics
+}
+
+// NewRaceRepository constructor for RaceRepository
+func NewRaceRepository(next race.Repository, metrics *Metrics) RaceRepository {
+	return RaceRepository{next: next, metrics: metrics}
+}
+
+// SaveRace instruments race.Repository.SaveRace
+func (r RaceRepository) SaveRace(ctx context.Context, rc race.Race) (err error) {
+	defer r.observe("SaveRace", time.Now(), &err)
+	return r.next.SaveRace(ctx, rc)
+}
+
+// GetRace instruments race.Repository.GetRace
+func (r RaceRepository) GetRace(ctx context.Context, raceID uuid.UUID) (_ race.Race, err error) {
+	defer r.observe("GetRace", time.Now(), &err)
+	return r.next.GetRace(ctx, raceID)
+}
+
+// SearchRaces instruments race.Repository.SearchRaces
+func (r RaceRepository) SearchRaces(ctx context.Context, search race.Search) (_ []race.Race, err error) {
+	defer r.observe("SearchRaces", time.Now(), &err)
+	return r.next.SearchRaces(ctx, search)
+}
+
+// SaveRaceResult instruments race.Repository.SaveRaceResult
+func (r RaceRepository) SaveRaceResult(ctx context.Context, result race.Result) (err error) {
+	defer r.observe("SaveRaceResult", time.Now(), &err)
+	return r.next.SaveRaceResult(ctx, result)
+}
+
+// GetResult instruments race.Repository.GetResult
+func (r RaceRepository) GetResult(ctx context.Context, resultID uuid.UUID) (_ race.Result, err error) {
+	defer r.observe("GetResult", time.Now(), &err)
+	return r.next.GetResult(ctx, resultID)
+}
+
+// UpdateRaceResult instruments race.Repository.UpdateRaceResult
+func (r RaceRepository) UpdateRaceResult(ctx context.Context, result race.Result) (err error) {
+	defer r.observe("UpdateRaceResult", time.Now(), &err)
+	return r.next.UpdateRaceResult(ctx, result)
+}
+
+// SaveRaceWithResults instruments race.Repository.SaveRaceWithResults
+func (r RaceRepository) SaveRaceWithResults(ctx context.Context, rc race.Race, results []race.Result) (err error) {
+	defer r.observe("SaveRaceWithResults", time.Now(), &err)
+	return r.next.SaveRaceWithResults(ctx, rc, results)
+}
+
+// GetRaceResults instruments race.Repository.GetRaceResults
+func (r RaceRepository) GetRaceResults(ctx context.Context, runnerID uuid.UUID) (_ []race.Result, err error) {
+	defer r.observe("GetRaceResults", time.Now(), &err)
+	return r.next.GetRaceResults(ctx, runnerID)
+}
+
+// GetResultsByRace instruments race.Repository.GetResultsByRace
+func (r RaceRepository) GetResultsByRace(ctx context.Context, raceID uuid.UUID) (_ []race.Result, err error) {
+	defer r.observe("GetResultsByRace", time.Now(), &err)
+	return r.next.GetResultsByRace(ctx, raceID)
+}
+
+func (r RaceRepository) observe(method string, start time.Time, err *error) {
+	r.metrics.observeCall("race", method, start, *err)
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	racememrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/race"
+	runnermemrepo "github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/memory/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerRepository(t *testing.T) {
+	m := New()
+	repo := NewRunnerRepository(runnermemrepo.NewRepository(), m)
+	r, err := runner.NewRunner("Jane Doe", "jane@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Add(context.Background(), r))
+	got, err := repo.GetByID(context.Background(), r.ID())
+	require.NoError(t, err)
+	_, err = repo.GetByID(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, runner.ErrNotFound, "errors pass through")
+	assert.Equal(t, r.ID(), got.ID())
+	assert.Equal(t, uint64(1), m.repositoryDuration.Count("runner", "Add"))
+	assert.Equal(t, uint64(2), m.repositoryDuration.Count("runner", "GetByID"))
+	assert.Equal(t, float64(0), m.repositoryErrors.Value("runner", "Add"))
+	assert.Equal(t, float64(1), m.repositoryErrors.Value("runner", "GetByID"))
+}
+
+func TestRaceRepository(t *testing.T) {
+	m := New()
+	repo := NewRaceRepository(racememrepo.NewRepository(), m)
+
+	_, err := repo.GetRace(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, race.ErrNotFound, "errors pass through")
+	_, err = repo.GetResultsByRace(context.Background(), uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), m.repositoryDuration.Count("race", "GetRace"))
+	assert.Equal(t, float64(1), m.repositoryErrors.Value("race", "GetRace"))
+	assert.Equal(t, uint64(1), m.repositoryDuration.Count("race", "GetResultsByRace"))
+	assert.Equal(t, float64(0), m.repositoryErrors.Value("race", "GetResultsByRace"))
+}
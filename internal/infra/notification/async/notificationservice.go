@@ -0,0 +1,86 @@
+// Package async contains a notification service that sends the notifications of another service in the background
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+)
+
+var (
+	// ErrQueueFull Error when a notification is sent while the queue of pending notifications is full
+	ErrQueueFull = errors.New("notification queue is full")
+	// ErrClosed Error when a notification is sent after the service is closed
+	ErrClosed = errors.New("notification service is closed")
+)
+
+// NotificationService provides an implementation of the Service that queues notifications and sends them
+// through the wrapped service in the background, so that callers do not wait for slow deliveries
+type NotificationService struct {
+	next    notification.Service
+	logger  logging.Logger
+	queue   chan pending
+	done    chan struct{}
+	closing sync.RWMutex
+	closed  bool
+}
+
+type pending struct {
+	ctx          context.Context
+	notification notification.Notification
+}
+
+// NewNotificationService constructor for NotificationService, holding up to queueSize pending notifications.
+// Failed deliveries are logged, as the callers no longer wait for them.
+func NewNotificationService(next notification.Service, queueSize int, logger logging.Logger) *NotificationService {
+	s := &NotificationService{next: next, logger: logger, queue: make(chan pending, queueSize), done: make(chan struct{})}
+	go s.run()
+	return s
+}
+
+// Notify queues the notification. The notification outlives the cancellation of the context, which usually
+// belongs to a request that completes before the notification is sent.
+func (s *NotificationService) Notify(ctx context.Context, n notification.Notification) error {
+	s.closing.RLock()
+	defer s.closing.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case s.queue <- pending{ctx: context.WithoutCancel(ctx), notification: n}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (s *NotificationService) run() {
+	defer close(s.done)
+	for p := range s.queue {
+		if err := s.next.Notify(p.ctx, p.notification); err != nil {
+			s.logger.Warn(p.ctx, "failed to send a queued notification",
+				logging.String("email_address", p.notification.EmailAddress), logging.Err(err))
+		}
+	}
+}
+
+// Close stops accepting notifications and waits until the pending notifications are sent or the context is done
+func (s *NotificationService) Close(ctx context.Context) error {
+	s.closing.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.closing.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("flushing pending notifications: %w", ctx.Err())
+	}
+}
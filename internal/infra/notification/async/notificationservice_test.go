@@ -0,0 +1,91 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingService records the notifications it sends after waiting for release to be closed, and fails with err
+type recordingService struct {
+	release chan struct{}
+	err     error
+	mu      sync.Mutex
+	sent    []notification.Notification
+	ctxErrs []error
+}
+
+func (r *recordingService) Notify(ctx context.Context, n notification.Notification) error {
+	<-r.release
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+	r.ctxErrs = append(r.ctxErrs, ctx.Err())
+	return r.err
+}
+
+func TestNotificationService_Close_FlushesPendingNotifications(t *testing.T) {
+	next := &recordingService{release: make(chan struct{})}
+	s := NewNotificationService(next, 10, logging.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for _, subject := range []string{"first", "second"} {
+		require.NoError(t, s.Notify(ctx, notification.Notification{Subject: subject}))
+	}
+	cancel()
+	close(next.release)
+
+	require.NoError(t, s.Close(context.Background()))
+	assert.Equal(t, []notification.Notification{{Subject: "first"}, {Subject: "second"}}, next.sent)
+	assert.Equal(t, []error{nil, nil}, next.ctxErrs, "notifications outlive the context of the caller")
+	assert.ErrorIs(t, s.Notify(context.Background(), notification.Notification{Subject: "late"}), ErrClosed)
+}
+
+func TestNotificationService_Notify_RejectsWhenQueueIsFull(t *testing.T) {
+	next := &recordingService{release: make(chan struct{})}
+	s := NewNotificationService(next, 1, logging.Discard)
+
+	//the first notification is taken by the sender, the second fills the queue
+	require.NoError(t, s.Notify(context.Background(), notification.Notification{Subject: "first"}))
+	assert.Eventually(t, func() bool { return len(s.queue) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, s.Notify(context.Background(), notification.Notification{Subject: "second"}))
+	assert.ErrorIs(t, s.Notify(context.Background(), notification.Notification{Subject: "third"}), ErrQueueFull)
+
+	close(next.release)
+	require.NoError(t, s.Close(context.Background()))
+	assert.Len(t, next.sent, 2)
+}
+
+func TestNotificationService_Close_StopsWaitingWhenContextIsDone(t *testing.T) {
+	next := &recordingService{release: make(chan struct{})}
+	s := NewNotificationService(next, 1, logging.Discard)
+	require.NoError(t, s.Notify(context.Background(), notification.Notification{Subject: "stuck"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Close(ctx), context.DeadlineExceeded)
+	close(next.release)
+}
+
+func TestNotificationService_LogsFailedDeliveries(t *testing.T) {
+	sendErr := errors.New("smtp unavailable")
+	next := &recordingService{release: make(chan struct{}), err: sendErr}
+	close(next.release)
+	logger := new(logging.RecordingLogger)
+	s := NewNotificationService(next, 1, logger)
+
+	require.NoError(t, s.Notify(context.Background(), notification.Notification{EmailAddress: "anna@example.com"}))
+	require.NoError(t, s.Close(context.Background()))
+
+	records := logger.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, logging.LevelWarn, records[0].Level)
+	assert.Equal(t, []logging.Field{logging.String("email_address", "anna@example.com"), logging.Err(sendErr)}, records[0].Fields)
+}
@@ -2,26 +2,29 @@
 package console
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 )
 
-// NotificationService provides a console implementation of the Service
-type NotificationService struct{}
+// NotificationService provides a console implementation of the Service that logs the notifications instead of
+// sending them
+type NotificationService struct {
+	logger logging.Logger
+}
 
 // NewNotificationService constructor for NotificationService
-func NewNotificationService() *NotificationService {
-	return &NotificationService{}
+func NewNotificationService(logger logging.Logger) *NotificationService {
+	return &NotificationService{logger: logger}
 }
 
-// Notify prints out the notifications in console
-func (NotificationService) Notify(notification notification.Notification) error {
-	jsonNotification, err := json.Marshal(notification)
-	if err != nil {
-		return err
-	}
-	fmt.Printf("Notification Received: %v\n", string(jsonNotification))
+// Notify logs the notification
+func (s NotificationService) Notify(ctx context.Context, n notification.Notification) error {
+	s.logger.Info(ctx, "notification received",
+		logging.String("email_address", n.EmailAddress),
+		logging.String("subject", n.Subject),
+		logging.String("message", n.Message),
+	)
 	return nil
 }
@@ -1,10 +1,13 @@
 package console
 
 import (
+	"context"
 	"testing"
 
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConsoleNotificationService_Notify(t *testing.T) {
@@ -17,11 +20,12 @@ func TestConsoleNotificationService_Notify(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "Should not return error",
+			name: "Should log the notification",
 			args: args{
 				notification: notification.Notification{
-					Subject: "Test Subject",
-					Message: "Test Message",
+					EmailAddress: "anna@example.com",
+					Subject:      "Test Subject",
+					Message:      "Test Message",
 				},
 			},
 			wantErr: false,
@@ -29,9 +33,19 @@ func TestConsoleNotificationService_Notify(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			co := NotificationService{}
-			err := co.Notify(tt.args.notification)
+			logger := new(logging.RecordingLogger)
+			co := NewNotificationService(logger)
+			err := co.Notify(context.Background(), tt.args.notification)
 			assert.Equal(t, tt.wantErr, err != nil)
+
+			records := logger.Records()
+			require.Len(t, records, 1)
+			assert.Equal(t, logging.LevelInfo, records[0].Level)
+			assert.Equal(t, []logging.Field{
+				logging.String("email_address", tt.args.notification.EmailAddress),
+				logging.String("subject", tt.args.notification.Subject),
+				logging.String("message", tt.args.notification.Message),
+			}, records[0].Fields)
 		})
 	}
 }
@@ -0,0 +1,21 @@
+// Package noop contains a notification service that discards all notifications
+package noop
+
+import (
+	"context"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/notification"
+)
+
+// NotificationService provides an implementation of the Service that discards notifications
+type NotificationService struct{}
+
+// NewNotificationService constructor for NotificationService
+func NewNotificationService() *NotificationService {
+	return &NotificationService{}
+}
+
+// Notify discards the notification
+func (NotificationService) Notify(context.Context, notification.Notification) error {
+	return nil
+}
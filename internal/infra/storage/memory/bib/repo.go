@@ -0,0 +1,59 @@
+// Package bib contains the in-memory implementation of the bib repository
+package bib
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+)
+
+// Repo is an in-memory implementation of the bib repository
+type Repo struct {
+	allocations map[uuid.UUID]*bib.Allocation
+	mu          sync.RWMutex
+}
+
+// NewRepository creates a new in-memory bib repository
+func NewRepository() *Repo {
+	return &Repo{
+		allocations: make(map[uuid.UUID]*bib.Allocation),
+	}
+}
+
+// GetAllocation returns a copy of the bib allocation of the race
+func (r *Repo) GetAllocation(_ context.Context, raceID uuid.UUID) (*bib.Allocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, exists := r.allocations[raceID]
+	if !exists {
+		return nil, bib.ErrNotConfigured
+	}
+
+	return clone(found, found.Version())
+}
+
+// SaveAllocation stores a copy of the allocation when its version matches the stored version
+func (r *Repo) SaveAllocation(_ context.Context, allocation *bib.Allocation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.allocations[allocation.RaceID()]
+	if exists && stored.Version() != allocation.Version() || !exists && allocation.Version() != 0 {
+		return bib.ErrConcurrentUpdate
+	}
+
+	saved, err := clone(allocation, allocation.Version()+1)
+	if err != nil {
+		return err
+	}
+	r.allocations[allocation.RaceID()] = saved
+	return nil
+}
+
+// clone copies the allocation so callers cannot change the stored one
+func clone(a *bib.Allocation, version int) (*bib.Allocation, error) {
+	return bib.LoadAllocation(a.RaceID(), a.Ranges(), a.Assignments(), a.Changes(), version)
+}
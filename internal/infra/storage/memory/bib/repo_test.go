@@ -0,0 +1,14 @@
+package bib
+
+import (
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+)
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.BibRepositoryContract(t, func(*testing.T) bib.Repository {
+		return NewRepository()
+	})
+}
@@ -2,7 +2,8 @@
 package race
 
 import (
-	"fmt"
+	"context"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -14,6 +15,8 @@ type Repo struct {
 	races           map[uuid.UUID]race.Race
 	raceResults     map[uuid.UUID]race.Result
 	resultsByRunner map[uuid.UUID][]uuid.UUID
+	resultsByRace   map[uuid.UUID][]uuid.UUID
+	courses         map[uuid.UUID]race.Course
 	mu              sync.RWMutex
 }
 
@@ -23,24 +26,46 @@ func NewRepository() *Repo {
 		races:           make(map[uuid.UUID]race.Race),
 		raceResults:     make(map[uuid.UUID]race.Result),
 		resultsByRunner: make(map[uuid.UUID][]uuid.UUID),
+		resultsByRace:   make(map[uuid.UUID][]uuid.UUID),
+		courses:         make(map[uuid.UUID]race.Course),
 	}
 }
 
 // GetRace retrieves a race by ID
-func (r *Repo) GetRace(raceID uuid.UUID) (race.Race, error) {
+func (r *Repo) GetRace(_ context.Context, raceID uuid.UUID) (race.Race, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	race, exists := r.races[raceID]
+	found, exists := r.races[raceID]
 	if !exists {
-		return race, fmt.Errorf("race with ID %s not found", raceID)
+		return race.Race{}, race.ErrNotFound
 	}
 
-	return race, nil
+	return found, nil
+}
+
+// SearchRaces returns the races that match the search after its cursor, in the order of the search
+func (r *Repo) SearchRaces(_ context.Context, search race.Search) ([]race.Race, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found []race.Race
+	for _, candidate := range r.races {
+		if search.Matches(candidate) && search.IsAfterCursor(candidate) {
+			found = append(found, candidate)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return search.Less(race.CursorOf(found[i]), race.CursorOf(found[j]))
+	})
+	if search.Limit > 0 && len(found) > search.Limit {
+		found = found[:search.Limit]
+	}
+	return found, nil
 }
 
 // SaveRace saves a race to the repository
-func (r *Repo) SaveRace(race race.Race) error {
+func (r *Repo) SaveRace(_ context.Context, race race.Race) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -49,7 +74,7 @@ func (r *Repo) SaveRace(race race.Race) error {
 }
 
 // SaveRaceResult saves a race result to the repository
-func (r *Repo) SaveRaceResult(result race.Result) error {
+func (r *Repo) SaveRaceResult(_ context.Context, result race.Result) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -60,30 +85,102 @@ func (r *Repo) SaveRaceResult(result race.Result) error {
 	runnerID := result.RunnerID()
 	r.resultsByRunner[runnerID] = append(r.resultsByRunner[runnerID], result.ID())
 
+	// Add to race's results
+	raceID := result.RaceID()
+	r.resultsByRace[raceID] = append(r.resultsByRace[raceID], result.ID())
+
 	return nil
 }
 
-// GetRaceResults gets all race results for a runner
-func (r *Repo) GetRaceResults(runnerID uuid.UUID) ([]race.Result, error) {
+// GetResult gets a race result by ID
+func (r *Repo) GetResult(_ context.Context, resultID uuid.UUID) (race.Result, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	resultIDs, exists := r.resultsByRunner[runnerID]
+	found, exists := r.raceResults[resultID]
 	if !exists {
-		return []race.Result{}, nil
+		return race.Result{}, race.ErrResultNotFound
+	}
+
+	return found, nil
+}
+
+// UpdateRaceResult replaces a stored race result
+func (r *Repo) UpdateRaceResult(_ context.Context, result race.Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.raceResults[result.ID()]; !exists {
+		return race.ErrResultNotFound
+	}
+	r.raceResults[result.ID()] = result
+	return nil
+}
+
+// SaveRaceWithResults saves a race and replaces the provided stored results, changing nothing when a result
+// is missing
+func (r *Repo) SaveRaceWithResults(_ context.Context, saved race.Race, results []race.Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, result := range results {
+		if _, exists := r.raceResults[result.ID()]; !exists {
+			return race.ErrResultNotFound
+		}
+	}
+	r.races[saved.ID()] = saved
+	for _, result := range results {
+		r.raceResults[result.ID()] = result
 	}
+	return nil
+}
+
+// GetRaceResults gets all race results for a runner
+func (r *Repo) GetRaceResults(_ context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.resultsOf(r.resultsByRunner[runnerID]), nil
+}
+
+// GetResultsByRace gets all results of a race
+func (r *Repo) GetResultsByRace(_ context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
+	return r.resultsOf(r.resultsByRace[raceID]), nil
+}
+
+// resultsOf returns the stored results with the provided ids; the caller must hold the lock
+func (r *Repo) resultsOf(resultIDs []uuid.UUID) []race.Result {
 	results := make([]race.Result, 0, len(resultIDs))
 	for _, resultID := range resultIDs {
 		result, exists := r.raceResults[resultID]
 		if exists {
 			results = append(results, result)
-			// 😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈
-			// 😈😈 Oops I did it again 😈😈
-			// 😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈😈
-			results = append(results, result)
 		}
 	}
+	return results
+}
+
+// SaveCourse stores the course of a race, replacing any existing course
+func (r *Repo) SaveCourse(_ context.Context, raceID uuid.UUID, course race.Course) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.courses[raceID] = course
+	return nil
+}
+
+// GetCourse gets the course of a race
+func (r *Repo) GetCourse(_ context.Context, raceID uuid.UUID) (race.Course, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, exists := r.courses[raceID]
+	if !exists {
+		return race.Course{}, race.ErrCourseNotFound
+	}
 
-	return results, nil
+	return found, nil
 }
@@ -0,0 +1,59 @@
+// Package registration contains the in-memory implementation of the registration repository
+package registration
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+)
+
+// Repo is an in-memory implementation of the registration repository
+type Repo struct {
+	entryLists map[uuid.UUID]*registration.EntryList
+	mu         sync.RWMutex
+}
+
+// NewRepository creates a new in-memory registration repository
+func NewRepository() *Repo {
+	return &Repo{
+		entryLists: make(map[uuid.UUID]*registration.EntryList),
+	}
+}
+
+// GetEntryList returns a copy of the entry list of the race
+func (r *Repo) GetEntryList(_ context.Context, raceID uuid.UUID) (*registration.EntryList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, exists := r.entryLists[raceID]
+	if !exists {
+		return nil, registration.ErrNotConfigured
+	}
+
+	return clone(found, found.Version())
+}
+
+// SaveEntryList stores a copy of the entry list when its version matches the stored version
+func (r *Repo) SaveEntryList(_ context.Context, entryList *registration.EntryList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.entryLists[entryList.RaceID()]
+	if exists && stored.Version() != entryList.Version() || !exists && entryList.Version() != 0 {
+		return registration.ErrConcurrentUpdate
+	}
+
+	saved, err := clone(entryList, entryList.Version()+1)
+	if err != nil {
+		return err
+	}
+	r.entryLists[entryList.RaceID()] = saved
+	return nil
+}
+
+// clone copies the entry list so callers cannot change the stored one
+func clone(l *registration.EntryList, version int) (*registration.EntryList, error) {
+	return registration.LoadEntryList(l.RaceID(), l.Capacity(), l.OpensAt(), l.ClosesAt(), l.RequiredForResults(), l.Registrations(), version)
+}
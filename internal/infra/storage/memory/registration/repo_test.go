@@ -0,0 +1,14 @@
+package registration
+
+import (
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+)
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.RegistrationRepositoryContract(t, func(*testing.T) registration.Repository {
+		return NewRepository()
+	})
+}
@@ -2,64 +2,98 @@
 package runner
 
 import (
-	"fmt"
+	"context"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
 )
 
-// Repo Implements the Repository Interface to provide an in-memory storage provider
+// Repo Implements the Repository Interface to provide an in-memory storage provider.
+// Runners are stored and returned as copies, so callers never share the stored runners.
 type Repo struct {
-	runners map[uuid.UUID]*runner.Runner
+	runners map[uuid.UUID]runner.Runner
+	mu      sync.RWMutex
 }
 
 // NewRepository Constructor
-func NewRepository() Repo {
-	runners := make(map[uuid.UUID]*runner.Runner)
-	return Repo{runners}
+func NewRepository() *Repo {
+	return &Repo{runners: make(map[uuid.UUID]runner.Runner)}
 }
 
 // GetByID Returns the runner with the provided id
-func (m Repo) GetByID(id uuid.UUID) (*runner.Runner, error) {
+func (m *Repo) GetByID(_ context.Context, id uuid.UUID) (*runner.Runner, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	r, ok := m.runners[id]
 	if !ok {
-		return nil, nil
+		return nil, runner.ErrNotFound
 	}
-	return r, nil
+	return &r, nil
 }
 
-// GetAll Returns all stored runners
-func (m Repo) GetAll() ([]*runner.Runner, error) {
-	keys := make([]uuid.UUID, 0)
+// GetByIDs Returns the runners with the provided ids, skipping unknown ids
+func (m *Repo) GetByIDs(_ context.Context, ids []uuid.UUID) ([]*runner.Runner, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	for key := range m.runners {
-		keys = append(keys, key)
+	values := make([]*runner.Runner, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := m.runners[id]; ok {
+			values = append(values, &r)
+		}
 	}
+	return values, nil
+}
 
-	var values []*runner.Runner
+// GetAll Returns all stored runners ordered by creation date
+func (m *Repo) GetAll(context.Context) ([]*runner.Runner, error) {
+	m.mu.RLock()
+	values := make([]*runner.Runner, 0, len(m.runners))
 	for _, value := range m.runners {
-		values = append(values, value)
+		values = append(values, &value)
 	}
+	m.mu.RUnlock()
+
+	sort.Slice(values, func(i, j int) bool {
+		if !values[i].CreatedAt().Equal(values[j].CreatedAt()) {
+			return values[i].CreatedAt().Before(values[j].CreatedAt())
+		}
+		return values[i].ID().String() < values[j].ID().String()
+	})
 	return values, nil
 }
 
 // Add the provided runner
-func (m Repo) Add(runner *runner.Runner) error {
-	m.runners[runner.ID()] = runner
+func (m *Repo) Add(_ context.Context, runner *runner.Runner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runners[runner.ID()] = *runner
 	return nil
 }
 
 // Update the provided runner
-func (m Repo) Update(runner *runner.Runner) error {
-	m.runners[runner.ID()] = runner
+func (m *Repo) Update(_ context.Context, r *runner.Runner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.runners[r.ID()]; !exists {
+		return runner.ErrNotFound
+	}
+	m.runners[r.ID()] = *r
 	return nil
 }
 
 // Delete the runner with the provided id
-func (m Repo) Delete(id uuid.UUID) error {
-	_, exists := m.runners[id]
-	if !exists {
-		return fmt.Errorf("id %v not found", id.String())
+func (m *Repo) Delete(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.runners[id]; !exists {
+		return runner.ErrNotFound
 	}
 	delete(m.runners, id)
 	return nil
@@ -1,11 +1,15 @@
 package runner
 
 import (
+	"context"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRepo(t *testing.T) {
@@ -15,8 +19,8 @@ func TestNewRepo(t *testing.T) {
 	}{
 		{
 			name: "Should create an inmemory memory",
-			want: Repo{
-				runners: make(map[uuid.UUID]*runner.Runner),
+			want: &Repo{
+				runners: make(map[uuid.UUID]runner.Runner),
 			},
 		},
 	}
@@ -30,7 +34,7 @@ func TestNewRepo(t *testing.T) {
 
 func Test_inMemoryRepo_AddRunner(t *testing.T) {
 	type fields struct {
-		runners map[uuid.UUID]*runner.Runner
+		runners map[uuid.UUID]runner.Runner
 	}
 	type args struct {
 		runner *runner.Runner
@@ -44,7 +48,7 @@ func Test_inMemoryRepo_AddRunner(t *testing.T) {
 		{
 			name: "should add runner",
 			fields: fields{
-				runners: make(map[uuid.UUID]*runner.Runner),
+				runners: make(map[uuid.UUID]runner.Runner),
 			},
 			args: args{
 				runner: func() *runner.Runner {
@@ -57,12 +61,84 @@ func Test_inMemoryRepo_AddRunner(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := Repo{
+			m := &Repo{
 				runners: tt.fields.runners,
 			}
-			err := m.Add(tt.args.runner)
+			err := m.Add(context.Background(), tt.args.runner)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Contains(t, m.runners, tt.args.runner.ID())
 		})
 	}
 }
+
+func TestRepo_ConcurrentReadsAndWrites(t *testing.T) {
+	repo := NewRepository()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		r, err := runner.NewRunner("John Doe", "johndoe@email.com")
+		require.NoError(t, err)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_ = repo.Add(context.Background(), r)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = repo.GetAll(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			_ = repo.Delete(context.Background(), r.ID())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRepo_ConcurrentRenames(t *testing.T) {
+	repo := NewRepository()
+	r, err := runner.NewRunner("John Doe", "johndoe@email.com")
+	require.NoError(t, err)
+	require.NoError(t, repo.Add(context.Background(), r))
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"John Smith", "Jon Doe", "Johnny Doe", "J. Doe"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded, err := repo.GetByID(context.Background(), r.ID())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, loaded.Rename(name))
+			assert.NoError(t, repo.Update(context.Background(), loaded))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRepo_ReturnsCopies(t *testing.T) {
+	repo := NewRepository()
+	r, err := runner.NewRunner("John Doe", "johndoe@email.com")
+	require.NoError(t, err)
+	require.NoError(t, repo.Add(context.Background(), r))
+
+	//changes to the added runner and to the loaded runners are not stored until Update
+	require.NoError(t, r.Rename("John Smith"))
+	loaded, err := repo.GetByID(context.Background(), r.ID())
+	require.NoError(t, err)
+	require.NoError(t, loaded.Rename("Jon Doe"))
+	all, err := repo.GetAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.NoError(t, all[0].Rename("Johnny Doe"))
+
+	got, err := repo.GetByID(context.Background(), r.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", got.Name())
+}
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.RunnerRepositoryContract(t, func(*testing.T) runner.Repository {
+		return NewRepository()
+	})
+}
@@ -0,0 +1,59 @@
+// Package timing contains the in-memory implementation of the timing repository
+package timing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+)
+
+// Repo is an in-memory implementation of the timing repository
+type Repo struct {
+	sessions map[uuid.UUID]*timing.Session
+	mu       sync.RWMutex
+}
+
+// NewRepository creates a new in-memory timing repository
+func NewRepository() *Repo {
+	return &Repo{
+		sessions: make(map[uuid.UUID]*timing.Session),
+	}
+}
+
+// GetSession returns a copy of the timing session of the race
+func (r *Repo) GetSession(_ context.Context, raceID uuid.UUID) (*timing.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, exists := r.sessions[raceID]
+	if !exists {
+		return nil, timing.ErrNotConfigured
+	}
+
+	return clone(found, found.Version())
+}
+
+// SaveSession stores a copy of the session when its version matches the stored version
+func (r *Repo) SaveSession(_ context.Context, session *timing.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.sessions[session.RaceID()]
+	if exists && stored.Version() != session.Version() || !exists && session.Version() != 0 {
+		return timing.ErrConcurrentUpdate
+	}
+
+	saved, err := clone(session, session.Version()+1)
+	if err != nil {
+		return err
+	}
+	r.sessions[session.RaceID()] = saved
+	return nil
+}
+
+// clone copies the session so callers cannot change the stored one
+func clone(s *timing.Session, version int) (*timing.Session, error) {
+	return timing.LoadSession(s.RaceID(), s.GunTime(), s.Debounce(), s.Checkpoints(), s.Reads(), version)
+}
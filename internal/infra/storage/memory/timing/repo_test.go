@@ -0,0 +1,14 @@
+package timing
+
+import (
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+)
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.TimingRepositoryContract(t, func(*testing.T) timing.Repository {
+		return NewRepository()
+	})
+}
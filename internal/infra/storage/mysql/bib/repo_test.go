@@ -0,0 +1,28 @@
+//go:build integration
+
+package bib
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/migrations"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dsn = "user:password@tcp(localhost:3306)/dbname?parseTime=true"
+)
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.BibRepositoryContract(t, func(t *testing.T) bib.Repository {
+		db, err := sql.Open("mysql", dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		require.NoError(t, migrations.Migrate(context.Background(), db))
+		return NewRepository(db)
+	})
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS runners (
+    id            CHAR(36)     NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    email_address VARCHAR(255) NOT NULL,
+    created_at    DATETIME(6)  NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS races (
+    id             CHAR(36)     NOT NULL,
+    name           VARCHAR(255) NOT NULL,
+    location       VARCHAR(255) NOT NULL,
+    date           DATETIME(6)  NOT NULL,
+    distance_km    DOUBLE       NOT NULL,
+    elevation_gain DOUBLE       NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS results (
+    id              CHAR(36)    NOT NULL,
+    runner_id       CHAR(36)    NOT NULL,
+    race_id         CHAR(36)    NOT NULL,
+    finish_time_ms  BIGINT      NOT NULL,
+    pace_min_per_km DOUBLE      NOT NULL,
+    heart_rate_avg  INT         NOT NULL,
+    notes           TEXT        NOT NULL,
+    logged_at       DATETIME(6) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_results_runner_id (runner_id),
+    INDEX idx_results_race_id (race_id)
+);
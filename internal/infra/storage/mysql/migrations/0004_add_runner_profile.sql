@@ -0,0 +1,3 @@
+ALTER TABLE runners
+    ADD COLUMN gender        VARCHAR(16) NOT NULL DEFAULT '' AFTER email_address,
+    ADD COLUMN date_of_birth DATE        NULL AFTER gender;
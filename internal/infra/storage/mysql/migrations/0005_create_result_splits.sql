@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS result_splits (
+    result_id   CHAR(36) NOT NULL,
+    seq         INT      NOT NULL,
+    distance_km DOUBLE   NOT NULL,
+    elapsed_ms  BIGINT   NOT NULL,
+    PRIMARY KEY (result_id, seq)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE results
+    ADD COLUMN status             VARCHAR(16)  NOT NULL DEFAULT 'finished' AFTER race_id,
+    ADD COLUMN status_reason      VARCHAR(255) NOT NULL DEFAULT '' AFTER status,
+    ADD COLUMN publication        VARCHAR(16)  NOT NULL DEFAULT 'provisional' AFTER notes,
+    ADD COLUMN publication_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER publication;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS entry_lists (
+    race_id              CHAR(36)    NOT NULL,
+    capacity             INT         NOT NULL,
+    opens_at             DATETIME(6) NOT NULL,
+    closes_at            DATETIME(6) NOT NULL,
+    required_for_results BOOLEAN     NOT NULL,
+    version              INT         NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS registrations (
+    id            CHAR(36)    NOT NULL,
+    race_id       CHAR(36)    NOT NULL,
+    runner_id     CHAR(36)    NOT NULL,
+    status        VARCHAR(16) NOT NULL,
+    registered_at DATETIME(6) NOT NULL,
+    updated_at    DATETIME(6) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_registrations_race_id (race_id)
+);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS bib_allocations (
+    race_id CHAR(36) NOT NULL,
+    version INT      NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS bib_ranges (
+    race_id      CHAR(36)    NOT NULL,
+    name         VARCHAR(64) NOT NULL,
+    first_number INT         NOT NULL,
+    last_number  INT         NOT NULL,
+    PRIMARY KEY (race_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bib_assignments (
+    race_id     CHAR(36)     NOT NULL,
+    runner_id   CHAR(36)     NOT NULL,
+    number      INT          NOT NULL,
+    range_name  VARCHAR(64)  NOT NULL,
+    chip_id     VARCHAR(64)  NOT NULL,
+    assigned_at DATETIME(6)  NOT NULL,
+    PRIMARY KEY (race_id, runner_id)
+);
+
+CREATE TABLE IF NOT EXISTS bib_changes (
+    race_id          CHAR(36)     NOT NULL,
+    seq              INT          NOT NULL,
+    runner_id        CHAR(36)     NOT NULL,
+    previous_number  INT          NOT NULL,
+    number           INT          NOT NULL,
+    previous_chip_id VARCHAR(64)  NOT NULL,
+    chip_id          VARCHAR(64)  NOT NULL,
+    reason           VARCHAR(255) NOT NULL,
+    changed_at       DATETIME(6)  NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
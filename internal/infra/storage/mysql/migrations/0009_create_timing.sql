@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS timing_sessions (
+    race_id     CHAR(36)    NOT NULL,
+    gun_time    DATETIME(6) NOT NULL,
+    debounce_ms BIGINT      NOT NULL,
+    version     INT         NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS timing_checkpoints (
+    race_id       CHAR(36)    NOT NULL,
+    checkpoint_id VARCHAR(64) NOT NULL,
+    distance_km   DOUBLE      NOT NULL,
+    PRIMARY KEY (race_id, checkpoint_id)
+);
+
+CREATE TABLE IF NOT EXISTS timing_reads (
+    race_id       CHAR(36)    NOT NULL,
+    chip_id       VARCHAR(64) NOT NULL,
+    checkpoint_id VARCHAR(64) NOT NULL,
+    read_at       DATETIME(6) NOT NULL,
+    PRIMARY KEY (race_id, chip_id, checkpoint_id, read_at)
+);
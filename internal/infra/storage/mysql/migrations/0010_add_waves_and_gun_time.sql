@@ -0,0 +1,14 @@
+ALTER TABLE races
+    ADD COLUMN ranking_policy VARCHAR(16) NOT NULL DEFAULT 'gun' AFTER elevation_gain;
+
+CREATE TABLE IF NOT EXISTS race_waves (
+    race_id    CHAR(36)    NOT NULL,
+    name       VARCHAR(64) NOT NULL,
+    start_time DATETIME(6) NOT NULL,
+    PRIMARY KEY (race_id, name)
+);
+
+ALTER TABLE results
+    ADD COLUMN gun_time_ms BIGINT NOT NULL DEFAULT 0 AFTER finish_time_ms;
+
+UPDATE results SET gun_time_ms = finish_time_ms;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS race_course_points (
+    race_id     CHAR(36) NOT NULL,
+    seq         INT      NOT NULL,
+    lat         DOUBLE   NOT NULL,
+    lon         DOUBLE   NOT NULL,
+    elevation_m DOUBLE   NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
+
+CREATE TABLE IF NOT EXISTS race_course_checkpoints (
+    race_id CHAR(36)     NOT NULL,
+    seq     INT          NOT NULL,
+    name    VARCHAR(128) NOT NULL,
+    kind    VARCHAR(16)  NOT NULL,
+    lat     DOUBLE       NOT NULL,
+    lon     DOUBLE       NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE races
+    ADD COLUMN cancelled_at        DATETIME(6)  NULL AFTER ranking_policy,
+    ADD COLUMN cancellation_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER cancelled_at;
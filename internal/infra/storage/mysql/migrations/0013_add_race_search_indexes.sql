@@ -0,0 +1,4 @@
+ALTER TABLE races
+    ADD INDEX idx_races_date (date, id),
+    ADD INDEX idx_races_location (location),
+    ADD INDEX idx_races_distance_km (distance_km, id);
@@ -0,0 +1,2 @@
+ALTER TABLE races
+    ADD COLUMN organiser VARCHAR(255) NOT NULL DEFAULT '' AFTER ranking_policy;
@@ -0,0 +1,252 @@
+// Package migrations contains the versioned schema migrations of the MySQL storage provider
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration represents a single versioned schema change
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+// Load returns the embedded migrations ordered by version
+func Load() ([]Migration, error) {
+	return load(files)
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	seen := make(map[int]string)
+	for _, name := range names {
+		versionPart, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s: file name must be <version>_<name>.sql", name)
+		}
+		version, err := strconv.Atoi(versionPart)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", name, versionPart)
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", name, version, other)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		statements := splitStatements(string(content))
+		if len(statements) == 0 {
+			return nil, fmt.Errorf("migration %s: no statements found", name)
+		}
+
+		migrations = append(migrations, Migration{
+			Version:    version,
+			Name:       strings.TrimSuffix(name, ".sql"),
+			Statements: statements,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// splitStatements splits a migration file on statement terminators, since the driver
+// does not execute multiple statements in a single call by default. Terminators inside
+// quoted strings, quoted identifiers and comments are ignored, and comments are dropped.
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == ';':
+			flush()
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(content, i)
+			current.WriteString(content[i:end])
+			i = end - 1
+		case c == '#' || strings.HasPrefix(content[i:], "-- ") || strings.HasPrefix(content[i:], "--\n"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				i = len(content)
+				continue
+			}
+			i += end
+			current.WriteByte('\n')
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				i = len(content)
+				continue
+			}
+			i += end + 3
+			current.WriteByte(' ')
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// closingQuote returns the offset just past the quote that closes the one at start,
+// honouring backslash escapes and doubled quotes, or the length of content if it is unterminated
+func closingQuote(content string, start int) int {
+	quote := content[start]
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(content) && content[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(content)
+}
+
+// Migrate applies the embedded migrations that have not been applied yet.
+//
+// Migrations are not atomic: MySQL commits DDL statements implicitly, so a migration that
+// fails part way leaves its earlier statements applied. Each statement is therefore recorded
+// in schema_migration_steps once it succeeds, and a rerun resumes a partially applied
+// migration at the statement that failed. A crash between a statement and its record
+// makes the rerun execute that statement again.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT       NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    applied_at DATETIME(6)  NOT NULL,
+    PRIMARY KEY (version)
+)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migration_steps (
+    version    BIGINT      NOT NULL,
+    step       INT         NOT NULL,
+    applied_at DATETIME(6) NOT NULL,
+    PRIMARY KEY (version, step)
+)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migration_steps table: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func appliedSteps(ctx context.Context, db *sql.DB, version int) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT step FROM schema_migration_steps WHERE version = ?", version)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var step int
+		if err := rows.Scan(&step); err != nil {
+			return nil, err
+		}
+		applied[step] = true
+	}
+	return applied, rows.Err()
+}
+
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	steps, err := appliedSteps(ctx, db, m.Version)
+	if err != nil {
+		return err
+	}
+
+	for i, statement := range m.Statements {
+		step := i + 1
+		if steps[step] {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("statement %d: %w", step, err)
+		}
+		_, err := db.ExecContext(ctx, "INSERT INTO schema_migration_steps (version, step, applied_at) VALUES (?, ?, ?)", m.Version, step, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("recording statement %d: %w", step, err)
+		}
+	}
+
+	_, err = db.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now().UTC())
+	return err
+}
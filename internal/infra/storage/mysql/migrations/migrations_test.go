@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Statements)
+	}
+	assert.Equal(t, "0001_create_runners", migrations[0].Name)
+}
+
+func Test_load(t *testing.T) {
+	tests := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantErr      bool
+		wantVersions []int
+	}{
+		{
+			name: "should order migrations by version",
+			fsys: fstest.MapFS{
+				"0010_second.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+				"0002_first.sql":  {Data: []byte("CREATE TABLE a (id INT);\nCREATE TABLE c (id INT);")},
+			},
+			wantVersions: []int{2, 10},
+		},
+		{
+			name: "should reject file names without version",
+			fsys: fstest.MapFS{
+				"create.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should reject duplicate versions",
+			fsys: fstest.MapFS{
+				"0001_a.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_b.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should reject empty migrations",
+			fsys: fstest.MapFS{
+				"0001_a.sql": {Data: []byte("  \n")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			versions := make([]int, len(migrations))
+			for i, m := range migrations {
+				versions[i] = m.Version
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func Test_splitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "should split on statement terminators",
+			content: "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "should keep terminators inside quoted strings",
+			content: "UPDATE a SET note = 'one; two', other = \"it\\'s; fine\";\nUPDATE b SET note = 'it''s; fine';",
+			want:    []string{"UPDATE a SET note = 'one; two', other = \"it\\'s; fine\"", "UPDATE b SET note = 'it''s; fine'"},
+		},
+		{
+			name:    "should keep terminators inside quoted identifiers",
+			content: "CREATE TABLE `a;b` (id INT);",
+			want:    []string{"CREATE TABLE `a;b` (id INT)"},
+		},
+		{
+			name:    "should drop comments containing terminators",
+			content: "-- first; table\nCREATE TABLE a (id INT);\n# second; table\nCREATE TABLE b (id INT /* key; */);\n/* trailing; */",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT  )"},
+		},
+		{
+			name:    "should ignore empty statements",
+			content: ";\n  ;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.content))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package race
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/migrations"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dsn = "user:password@tcp(localhost:3306)/dbname?parseTime=true"
+)
+
+func newTestRepo(t *testing.T) (Repo, *sql.DB) {
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, migrations.Migrate(context.Background(), db))
+
+	return NewRepository(db), db
+}
+
+func TestRepo_SaveRace(t *testing.T) {
+	repo, db := newTestRepo(t)
+	r, err := race.NewRace("Athens Marathon", "Athens", time.Now().UTC(), 42.195, 250)
+	require.NoError(t, err)
+
+	err = repo.SaveRace(context.Background(), r)
+	require.NoError(t, err)
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM races WHERE id = ?", r.ID()).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	err = repo.SaveRace(context.Background(), r)
+	require.NoError(t, err)
+}
+
+func TestRepo_GetRace(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	date := time.Now().UTC().Truncate(time.Microsecond)
+	r, err := race.NewRace("Athens Marathon", "Athens", date, 42.195, 250)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveRace(context.Background(), r))
+
+	got, err := repo.GetRace(context.Background(), r.ID())
+	require.NoError(t, err)
+	assert.Equal(t, r.ID(), got.ID())
+	assert.Equal(t, "Athens Marathon", got.Name())
+	assert.Equal(t, "Athens", got.Location())
+	assert.True(t, date.Equal(got.Date()))
+	assert.Equal(t, 42.195, got.DistanceKm())
+	assert.Equal(t, 250.0, got.ElevationGain())
+
+	_, err = repo.GetRace(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, race.ErrNotFound)
+}
+
+func TestRepo_SaveRaceResult(t *testing.T) {
+	repo, db := newTestRepo(t)
+	result, err := race.NewResult(uuid.New(), uuid.New(), 30*time.Minute, 5.0, 150, "Good race")
+	require.NoError(t, err)
+
+	err = repo.SaveRaceResult(context.Background(), result)
+	require.NoError(t, err)
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM results WHERE id = ?", result.ID()).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRepo_GetRaceResults(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	runnerID := uuid.New()
+	result1, err := race.NewResult(runnerID, uuid.New(), 30*time.Minute, 5.0, 150, "First race")
+	require.NoError(t, err)
+	result2, err := race.NewResult(runnerID, uuid.New(), 45*time.Minute, 4.5, 160, "Second race")
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveRaceResult(context.Background(), result1))
+	require.NoError(t, repo.SaveRaceResult(context.Background(), result2))
+
+	results, err := repo.GetRaceResults(context.Background(), runnerID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, result1.ID(), results[0].ID())
+	assert.Equal(t, result1.FinishTime(), results[0].FinishTime())
+	assert.Equal(t, result1.Pace(), results[0].Pace())
+	assert.Equal(t, result1.HeartRateAvg(), results[0].HeartRateAvg())
+	assert.Equal(t, result1.Notes(), results[0].Notes())
+	assert.Equal(t, result2.ID(), results[1].ID())
+
+	results, err = repo.GetRaceResults(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.RaceRepositoryContract(t, func(t *testing.T) race.Repository {
+		repo, _ := newTestRepo(t)
+		return repo
+	})
+}
+
+func TestRepo_CourseContract(t *testing.T) {
+	storagetest.CourseRepositoryContract(t, func(t *testing.T) race.CourseRepository {
+		repo, _ := newTestRepo(t)
+		return repo
+	})
+}
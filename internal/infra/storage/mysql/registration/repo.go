@@ -0,0 +1,121 @@
+// Package registration implements the registration Repository Interface to provide a MySQL storage provider
+package registration
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+)
+
+// Repo Implements the registration Repository Interface to provide a MySQL storage provider
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepository Constructor
+func NewRepository(db *sql.DB) Repo {
+	return Repo{db}
+}
+
+// GetEntryList Returns the entry list of the race with the provided id together with its registrations
+func (m Repo) GetEntryList(ctx context.Context, raceID uuid.UUID) (*registration.EntryList, error) {
+	var l struct {
+		capacity           int
+		opensAt            time.Time
+		closesAt           time.Time
+		requiredForResults bool
+		version            int
+	}
+	query := "SELECT capacity, opens_at, closes_at, required_for_results, version FROM entry_lists WHERE race_id = ?"
+	row := m.db.QueryRowContext(ctx, query, raceID)
+	err := row.Scan(&l.capacity, &l.opensAt, &l.closesAt, &l.requiredForResults, &l.version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, registration.ErrNotConfigured
+		}
+		return nil, err
+	}
+
+	registrations, err := m.queryRegistrations(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	return registration.LoadEntryList(raceID, l.capacity, l.opensAt, l.closesAt, l.requiredForResults, registrations, l.version)
+}
+
+func (m Repo) queryRegistrations(ctx context.Context, raceID uuid.UUID) ([]registration.Registration, error) {
+	query := `SELECT id, race_id, runner_id, status, registered_at, updated_at FROM registrations
+WHERE race_id = ? ORDER BY registered_at`
+	rows, err := m.db.QueryContext(ctx, query, raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var registrations []registration.Registration
+	for rows.Next() {
+		var r struct {
+			id           uuid.UUID
+			raceID       uuid.UUID
+			runnerID     uuid.UUID
+			status       string
+			registeredAt time.Time
+			updatedAt    time.Time
+		}
+		if err := rows.Scan(&r.id, &r.raceID, &r.runnerID, &r.status, &r.registeredAt, &r.updatedAt); err != nil {
+			return nil, err
+		}
+		loaded, err := registration.LoadRegistration(r.id, r.raceID, r.runnerID, registration.Status(r.status), r.registeredAt, r.updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, loaded)
+	}
+	return registrations, rows.Err()
+}
+
+// SaveEntryList stores the entry list and its registrations when the stored version matches the version
+// the entry list was loaded from
+func (m Repo) SaveEntryList(ctx context.Context, l *registration.EntryList) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var res sql.Result
+	if l.Version() == 0 {
+		query := `INSERT IGNORE INTO entry_lists (race_id, capacity, opens_at, closes_at, required_for_results, version)
+VALUES (?, ?, ?, ?, ?, 1)`
+		res, err = tx.ExecContext(ctx, query, l.RaceID(), l.Capacity(), l.OpensAt(), l.ClosesAt(), l.RequiredForResults())
+	} else {
+		query := `UPDATE entry_lists SET capacity = ?, opens_at = ?, closes_at = ?, required_for_results = ?,
+version = version + 1 WHERE race_id = ? AND version = ?`
+		res, err = tx.ExecContext(ctx, query, l.Capacity(), l.OpensAt(), l.ClosesAt(), l.RequiredForResults(), l.RaceID(), l.Version())
+	}
+	if err != nil {
+		return err
+	}
+	//the version always changes, so no affected rows means another request saved the entry list first
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return registration.ErrConcurrentUpdate
+	}
+
+	for _, r := range l.Registrations() {
+		query := `INSERT INTO registrations (id, race_id, runner_id, status, registered_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE status = VALUES(status), updated_at = VALUES(updated_at)`
+		_, err := tx.ExecContext(ctx, query, r.ID(), r.RaceID(), r.RunnerID(), r.Status(), r.RegisteredAt(), r.UpdatedAt())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
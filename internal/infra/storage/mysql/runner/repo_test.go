@@ -3,12 +3,14 @@
 package runner
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -29,13 +31,16 @@ func TestRepo_GetByID(t *testing.T) {
 	_, err = db.Exec("INSERT INTO runners (id, name, email_address, created_at) VALUES (?, ?, ?, ?)", id, "John Doe", "john.doe@example.com", createdAt)
 	require.NoError(t, err)
 
-	r, err := repo.GetByID(id)
+	r, err := repo.GetByID(context.Background(), id)
 	require.NoError(t, err)
 	assert.NotNil(t, r)
 	assert.Equal(t, id, r.ID())
 	assert.Equal(t, "John Doe", r.Name())
 	assert.Equal(t, "john.doe@example.com", r.EmailAddress())
 	assert.Equal(t, createdAt, r.CreatedAt())
+
+	_, err = repo.GetByID(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, runner.ErrNotFound)
 }
 
 func TestRepo_GetAll(t *testing.T) {
@@ -53,7 +58,7 @@ func TestRepo_GetAll(t *testing.T) {
 	_, err = db.Exec("INSERT INTO runners (id, name, email_address, created_at) VALUES (?, ?, ?, ?)", id2, "Jane Doe", "jane.doe@example.com", createdAt)
 	require.NoError(t, err)
 
-	runners, err := repo.GetAll()
+	runners, err := repo.GetAll(context.Background())
 	require.NoError(t, err)
 	assert.Len(t, runners, 2)
 }
@@ -67,7 +72,7 @@ func TestRepo_Add(t *testing.T) {
 	r, err := runner.NewRunner("John Doe", "john.doe@example.com")
 	require.NoError(t, err)
 
-	err = repo.Add(r)
+	err = repo.Add(context.Background(), r)
 	require.NoError(t, err)
 
 	var count int
@@ -75,7 +80,7 @@ func TestRepo_Add(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, count)
 
-	err = repo.Add(r)
+	err = repo.Add(context.Background(), r)
 	require.NoError(t, err)
 }
 
@@ -87,11 +92,11 @@ func TestRepo_Update(t *testing.T) {
 	repo := NewRepository(db)
 	r, err := runner.NewRunner("John Doe", "john.doe@example.com")
 	require.NoError(t, err)
-	err = repo.Add(r)
+	err = repo.Add(context.Background(), r)
 	require.NoError(t, err)
 
 	r.Rename("John Smith")
-	err = repo.Update(r)
+	err = repo.Update(context.Background(), r)
 	require.NoError(t, err)
 
 	var name string
@@ -99,7 +104,7 @@ func TestRepo_Update(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "John Smith", name)
 
-	err = repo.Update(r)
+	err = repo.Update(context.Background(), r)
 	require.NoError(t, err)
 }
 
@@ -111,9 +116,9 @@ func TestRepo_Delete(t *testing.T) {
 	repo := NewRepository(db)
 	r, err := runner.NewRunner("John Doe", "john.doe@example.com")
 	require.NoError(t, err)
-	err = repo.Add(r)
+	err = repo.Add(context.Background(), r)
 	require.NoError(t, err)
-	err = repo.Delete(r.ID())
+	err = repo.Delete(context.Background(), r.ID())
 	require.NoError(t, err)
 
 	var count int
@@ -121,6 +126,15 @@ func TestRepo_Delete(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 0, count)
 
-	err = repo.Delete(id)
-	require.NoError(t, err)
+	err = repo.Delete(context.Background(), r.ID())
+	assert.ErrorIs(t, err, runner.ErrNotFound)
+}
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.RunnerRepositoryContract(t, func(t *testing.T) runner.Repository {
+		db, err := sql.Open("mysql", dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return NewRepository(db)
+	})
 }
@@ -0,0 +1,28 @@
+//go:build integration
+
+package timing
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/mysql/migrations"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dsn = "user:password@tcp(localhost:3306)/dbname?parseTime=true"
+)
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.TimingRepositoryContract(t, func(t *testing.T) timing.Repository {
+		db, err := sql.Open("mysql", dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		require.NoError(t, migrations.Migrate(context.Background(), db))
+		return NewRepository(db)
+	})
+}
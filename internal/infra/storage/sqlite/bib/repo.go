@@ -0,0 +1,189 @@
+// Package bib implements the bib Repository Interface to provide a SQLite storage provider
+package bib
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+)
+
+// Repo Implements the bib Repository Interface to provide a SQLite storage provider
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepository Constructor
+func NewRepository(db *sql.DB) Repo {
+	return Repo{db}
+}
+
+// GetAllocation Returns the bib allocation of the race with the provided id
+func (m Repo) GetAllocation(ctx context.Context, raceID uuid.UUID) (*bib.Allocation, error) {
+	var version int
+	row := m.db.QueryRowContext(ctx, "SELECT version FROM bib_allocations WHERE race_id = ?", raceID)
+	if err := row.Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, bib.ErrNotConfigured
+		}
+		return nil, err
+	}
+
+	ranges, err := m.queryRanges(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := m.queryAssignments(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := m.queryChanges(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	return bib.LoadAllocation(raceID, ranges, assignments, changes, version)
+}
+
+func (m Repo) queryRanges(ctx context.Context, raceID uuid.UUID) ([]bib.Range, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT name, first_number, last_number FROM bib_ranges WHERE race_id = ?", raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []bib.Range
+	for rows.Next() {
+		var name string
+		var first, last int
+		if err := rows.Scan(&name, &first, &last); err != nil {
+			return nil, err
+		}
+		r, err := bib.NewRange(name, first, last)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, rows.Err()
+}
+
+func (m Repo) queryAssignments(ctx context.Context, raceID uuid.UUID) ([]bib.Assignment, error) {
+	query := "SELECT runner_id, number, range_name, chip_id, assigned_at FROM bib_assignments WHERE race_id = ?"
+	rows, err := m.db.QueryContext(ctx, query, raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []bib.Assignment
+	for rows.Next() {
+		var a struct {
+			runnerID   uuid.UUID
+			number     int
+			rangeName  string
+			chipID     string
+			assignedAt time.Time
+		}
+		if err := rows.Scan(&a.runnerID, &a.number, &a.rangeName, &a.chipID, &a.assignedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, bib.LoadAssignment(a.runnerID, a.number, a.rangeName, a.chipID, a.assignedAt))
+	}
+	return assignments, rows.Err()
+}
+
+func (m Repo) queryChanges(ctx context.Context, raceID uuid.UUID) ([]bib.Change, error) {
+	query := `SELECT runner_id, previous_number, number, previous_chip_id, chip_id, reason, changed_at FROM bib_changes
+WHERE race_id = ? ORDER BY seq`
+	rows, err := m.db.QueryContext(ctx, query, raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []bib.Change
+	for rows.Next() {
+		var c struct {
+			runnerID       uuid.UUID
+			previousNumber int
+			number         int
+			previousChipID string
+			chipID         string
+			reason         string
+			changedAt      time.Time
+		}
+		err := rows.Scan(&c.runnerID, &c.previousNumber, &c.number, &c.previousChipID, &c.chipID, &c.reason, &c.changedAt)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, bib.LoadChange(c.runnerID, c.previousNumber, c.number, c.previousChipID, c.chipID, c.reason, c.changedAt))
+	}
+	return changes, rows.Err()
+}
+
+// SaveAllocation stores the ranges, the assignments and the new changes of the allocation when the stored version
+// matches the version the allocation was loaded from
+func (m Repo) SaveAllocation(ctx context.Context, a *bib.Allocation) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var res sql.Result
+	if a.Version() == 0 {
+		res, err = tx.ExecContext(ctx, "INSERT OR IGNORE INTO bib_allocations (race_id, version) VALUES (?, 1)", a.RaceID())
+	} else {
+		res, err = tx.ExecContext(ctx, "UPDATE bib_allocations SET version = version + 1 WHERE race_id = ? AND version = ?",
+			a.RaceID(), a.Version())
+	}
+	if err != nil {
+		return err
+	}
+	//the version always changes, so no affected rows means another request saved the allocation first
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return bib.ErrConcurrentUpdate
+	}
+
+	//ranges and assignments are replaced, so that numbers can move between runners without unique key clashes
+	if _, err := tx.ExecContext(ctx, "DELETE FROM bib_ranges WHERE race_id = ?", a.RaceID()); err != nil {
+		return err
+	}
+	for _, r := range a.Ranges() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO bib_ranges (race_id, name, first_number, last_number) VALUES (?, ?, ?, ?)",
+			a.RaceID(), r.Name(), r.First(), r.Last())
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM bib_assignments WHERE race_id = ?", a.RaceID()); err != nil {
+		return err
+	}
+	for _, assignment := range a.Assignments() {
+		query := `INSERT INTO bib_assignments (race_id, runner_id, number, range_name, chip_id, assigned_at)
+VALUES (?, ?, ?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, a.RaceID(), assignment.RunnerID(), assignment.Number(), assignment.RangeName(),
+			assignment.ChipID(), assignment.AssignedAt())
+		if err != nil {
+			return err
+		}
+	}
+
+	//changes are append only, so the ones that are already stored are skipped
+	for seq, c := range a.Changes() {
+		query := `INSERT OR IGNORE INTO bib_changes (race_id, seq, runner_id, previous_number, number, previous_chip_id, chip_id,
+reason, changed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, a.RaceID(), seq, c.RunnerID(), c.PreviousNumber(), c.Number(), c.PreviousChipID(),
+			c.ChipID(), c.Reason(), c.ChangedAt())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
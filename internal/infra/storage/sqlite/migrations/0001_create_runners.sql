@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS runners (
+    id            TEXT     NOT NULL,
+    name          TEXT     NOT NULL,
+    email_address TEXT     NOT NULL,
+    gender        TEXT     NOT NULL DEFAULT '',
+    date_of_birth DATE     NULL,
+    created_at    DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS races (
+    id                  TEXT     NOT NULL,
+    name                TEXT     NOT NULL COLLATE NOCASE,
+    location            TEXT     NOT NULL COLLATE NOCASE,
+    date                DATETIME NOT NULL,
+    distance_km         REAL     NOT NULL,
+    elevation_gain      REAL     NOT NULL,
+    ranking_policy      TEXT     NOT NULL DEFAULT 'gun',
+    organiser           TEXT     NOT NULL DEFAULT '',
+    cancelled_at        DATETIME NULL,
+    cancellation_reason TEXT     NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_races_date ON races (date, id);
+CREATE INDEX IF NOT EXISTS idx_races_location ON races (location);
+CREATE INDEX IF NOT EXISTS idx_races_distance_km ON races (distance_km, id);
+
+CREATE TABLE IF NOT EXISTS race_waves (
+    race_id    TEXT     NOT NULL,
+    name       TEXT     NOT NULL,
+    start_time DATETIME NOT NULL,
+    PRIMARY KEY (race_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS race_course_points (
+    race_id     TEXT    NOT NULL,
+    seq         INTEGER NOT NULL,
+    lat         REAL    NOT NULL,
+    lon         REAL    NOT NULL,
+    elevation_m REAL    NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
+
+CREATE TABLE IF NOT EXISTS race_course_checkpoints (
+    race_id TEXT    NOT NULL,
+    seq     INTEGER NOT NULL,
+    name    TEXT    NOT NULL,
+    kind    TEXT    NOT NULL,
+    lat     REAL    NOT NULL,
+    lon     REAL    NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
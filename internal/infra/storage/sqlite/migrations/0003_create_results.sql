@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS results (
+    id                 TEXT     NOT NULL,
+    runner_id          TEXT     NOT NULL,
+    race_id            TEXT     NOT NULL,
+    status             TEXT     NOT NULL DEFAULT 'finished',
+    status_reason      TEXT     NOT NULL DEFAULT '',
+    finish_time_ms     INTEGER  NOT NULL,
+    gun_time_ms        INTEGER  NOT NULL DEFAULT 0,
+    pace_min_per_km    REAL     NOT NULL,
+    heart_rate_avg     INTEGER  NOT NULL,
+    notes              TEXT     NOT NULL,
+    publication        TEXT     NOT NULL DEFAULT 'provisional',
+    publication_reason TEXT     NOT NULL DEFAULT '',
+    logged_at          DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_results_runner_id ON results (runner_id);
+CREATE INDEX IF NOT EXISTS idx_results_race_id ON results (race_id);
+
+CREATE TABLE IF NOT EXISTS result_splits (
+    result_id   TEXT    NOT NULL,
+    seq         INTEGER NOT NULL,
+    distance_km REAL    NOT NULL,
+    elapsed_ms  INTEGER NOT NULL,
+    PRIMARY KEY (result_id, seq)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS entry_lists (
+    race_id              TEXT     NOT NULL,
+    capacity             INTEGER  NOT NULL,
+    opens_at             DATETIME NOT NULL,
+    closes_at            DATETIME NOT NULL,
+    required_for_results BOOLEAN  NOT NULL,
+    version              INTEGER  NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS registrations (
+    id            TEXT     NOT NULL,
+    race_id       TEXT     NOT NULL,
+    runner_id     TEXT     NOT NULL,
+    status        TEXT     NOT NULL,
+    registered_at DATETIME NOT NULL,
+    updated_at    DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_registrations_race_id ON registrations (race_id);
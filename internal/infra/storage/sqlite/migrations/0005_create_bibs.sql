@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS bib_allocations (
+    race_id TEXT    NOT NULL,
+    version INTEGER NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS bib_ranges (
+    race_id      TEXT    NOT NULL,
+    name         TEXT    NOT NULL,
+    first_number INTEGER NOT NULL,
+    last_number  INTEGER NOT NULL,
+    PRIMARY KEY (race_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bib_assignments (
+    race_id     TEXT     NOT NULL,
+    runner_id   TEXT     NOT NULL,
+    number      INTEGER  NOT NULL,
+    range_name  TEXT     NOT NULL,
+    chip_id     TEXT     NOT NULL,
+    assigned_at DATETIME NOT NULL,
+    PRIMARY KEY (race_id, runner_id)
+);
+
+CREATE TABLE IF NOT EXISTS bib_changes (
+    race_id          TEXT     NOT NULL,
+    seq              INTEGER  NOT NULL,
+    runner_id        TEXT     NOT NULL,
+    previous_number  INTEGER  NOT NULL,
+    number           INTEGER  NOT NULL,
+    previous_chip_id TEXT     NOT NULL,
+    chip_id          TEXT     NOT NULL,
+    reason           TEXT     NOT NULL,
+    changed_at       DATETIME NOT NULL,
+    PRIMARY KEY (race_id, seq)
+);
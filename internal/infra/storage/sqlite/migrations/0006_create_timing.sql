@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS timing_sessions (
+    race_id     TEXT     NOT NULL,
+    gun_time    DATETIME NOT NULL,
+    debounce_ms INTEGER  NOT NULL,
+    version     INTEGER  NOT NULL,
+    PRIMARY KEY (race_id)
+);
+
+CREATE TABLE IF NOT EXISTS timing_checkpoints (
+    race_id       TEXT    NOT NULL,
+    checkpoint_id TEXT    NOT NULL,
+    distance_km   REAL    NOT NULL,
+    PRIMARY KEY (race_id, checkpoint_id)
+);
+
+CREATE TABLE IF NOT EXISTS timing_reads (
+    race_id       TEXT     NOT NULL,
+    chip_id       TEXT     NOT NULL,
+    checkpoint_id TEXT     NOT NULL,
+    read_at       DATETIME NOT NULL,
+    PRIMARY KEY (race_id, chip_id, checkpoint_id, read_at)
+);
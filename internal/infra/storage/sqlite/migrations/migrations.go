@@ -0,0 +1,147 @@
+// Package migrations contains the versioned schema migrations of the SQLite storage provider
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration represents a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	// Script holds the statements of the migration, executed together since SQLite runs multiple statements in one call
+	Script string
+}
+
+// Load returns the embedded migrations ordered by version
+func Load() ([]Migration, error) {
+	return load(files)
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	seen := make(map[int]string)
+	for _, name := range names {
+		versionPart, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s: file name must be <version>_<name>.sql", name)
+		}
+		version, err := strconv.Atoi(versionPart)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", name, versionPart)
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", name, version, other)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		script := strings.TrimSpace(string(content))
+		if script == "" {
+			return nil, fmt.Errorf("migration %s: no statements found", name)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    strings.TrimSuffix(name, ".sql"),
+			Script:  script,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrate applies the embedded migrations that have not been applied yet.
+// SQLite schema changes are transactional, so each migration is applied together with its record or not at all.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER  NOT NULL,
+    name       TEXT     NOT NULL,
+    applied_at DATETIME NOT NULL,
+    PRIMARY KEY (version)
+)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.Script); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
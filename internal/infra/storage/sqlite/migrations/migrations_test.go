@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Script)
+	}
+	assert.Equal(t, "0001_create_runners", migrations[0].Name)
+}
+
+func Test_load(t *testing.T) {
+	tests := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantErr      bool
+		wantVersions []int
+	}{
+		{
+			name: "should order migrations by version",
+			fsys: fstest.MapFS{
+				"0010_second.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+				"0002_first.sql":  {Data: []byte("CREATE TABLE a (id INT);\nCREATE TABLE c (id INT);")},
+			},
+			wantVersions: []int{2, 10},
+		},
+		{
+			name: "should reject file names without version",
+			fsys: fstest.MapFS{
+				"create.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should reject duplicate versions",
+			fsys: fstest.MapFS{
+				"0001_a.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_b.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should reject empty migrations",
+			fsys: fstest.MapFS{
+				"0001_a.sql": {Data: []byte("  \n")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			versions := make([]int, len(migrations))
+			for i, m := range migrations {
+				versions[i] = m.Version
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "racetracker.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, Migrate(context.Background(), db))
+	//applying the migrations again is a no-op
+	require.NoError(t, Migrate(context.Background(), db))
+
+	migrations, err := Load()
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count))
+	assert.Equal(t, len(migrations), count)
+}
@@ -0,0 +1,601 @@
+// Package race implements the race Repository Interface to provide a SQLite storage provider
+package race
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+)
+
+// Repo Implements the race Repository Interface to provide a SQLite storage provider
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepository Constructor
+func NewRepository(db *sql.DB) Repo {
+	return Repo{db}
+}
+
+// SaveRace stores the provided race together with its waves, replacing any existing race with the same id
+func (m Repo) SaveRace(ctx context.Context, r race.Race) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveRace(ctx, tx, r); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveRace stores the race and replaces its waves within the transaction
+func saveRace(ctx context.Context, tx *sql.Tx, r race.Race) error {
+	var cancelledAt sql.NullTime
+	if r.IsCancelled() {
+		cancelledAt = sql.NullTime{Time: r.CancelledAt(), Valid: true}
+	}
+	query := `INSERT INTO races (id, name, location, date, distance_km, elevation_gain, ranking_policy, organiser, cancelled_at, cancellation_reason)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET name = excluded.name, location = excluded.location, date = excluded.date,
+distance_km = excluded.distance_km, elevation_gain = excluded.elevation_gain, ranking_policy = excluded.ranking_policy,
+organiser = excluded.organiser, cancelled_at = excluded.cancelled_at, cancellation_reason = excluded.cancellation_reason`
+	_, err := tx.ExecContext(ctx, query, r.ID(), r.Name(), r.Location(), r.Date(), r.DistanceKm(), r.ElevationGain(), r.RankingPolicy(),
+		r.Organiser(), cancelledAt, r.CancellationReason())
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM race_waves WHERE race_id = ?", r.ID()); err != nil {
+		return err
+	}
+	for _, w := range r.Waves() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO race_waves (race_id, name, start_time) VALUES (?, ?, ?)", r.ID(), w.Name(), w.StartTime())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// raceColumns are the columns of the races table scanned by scanRace
+const raceColumns = "id, name, location, date, distance_km, elevation_gain, ranking_policy, organiser, cancelled_at, cancellation_reason"
+
+// raceRow is a row of the races table, without the waves of the race
+type raceRow struct {
+	id            uuid.UUID
+	name          string
+	location      string
+	date          time.Time
+	distanceKm    float64
+	elevationGain float64
+	rankingPolicy string
+	organiser     string
+	cancelledAt   sql.NullTime
+	cancelReason  string
+}
+
+func scanRace(row interface{ Scan(dest ...any) error }) (raceRow, error) {
+	var r raceRow
+	err := row.Scan(&r.id, &r.name, &r.location, &r.date, &r.distanceKm, &r.elevationGain, &r.rankingPolicy, &r.organiser, &r.cancelledAt, &r.cancelReason)
+	return r, err
+}
+
+// loadRace loads the race of the row together with its waves
+func (m Repo) loadRace(ctx context.Context, r raceRow) (race.Race, error) {
+	waves, err := m.queryWaves(ctx, r.id)
+	if err != nil {
+		return race.Race{}, err
+	}
+	loaded, err := race.LoadRace(r.id, r.name, r.location, r.date, r.distanceKm, r.elevationGain, waves, race.RankingPolicy(r.rankingPolicy),
+		r.cancelledAt.Time, r.cancelReason)
+	if err != nil {
+		return race.Race{}, err
+	}
+	return loaded.WithOrganiser(r.organiser), nil
+}
+
+// GetRace Returns the race with the provided id
+func (m Repo) GetRace(ctx context.Context, raceID uuid.UUID) (race.Race, error) {
+	row := m.db.QueryRowContext(ctx, "SELECT "+raceColumns+" FROM races WHERE id = ?", raceID)
+	r, err := scanRace(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return race.Race{}, race.ErrNotFound
+		}
+		return race.Race{}, err
+	}
+	return m.loadRace(ctx, r)
+}
+
+// sortColumns maps the sort fields of a search to the columns of the races table
+var sortColumns = map[race.SortField]string{
+	race.SortByDate:          "date",
+	race.SortByName:          "name",
+	race.SortByDistance:      "distance_km",
+	race.SortByElevationGain: "elevation_gain",
+}
+
+// SearchRaces returns the races that match the search after its cursor, in the order of the search.
+// The cursor is applied as a keyset condition on the sort column and the id, so pages stay stable while
+// races are added.
+func (m Repo) SearchRaces(ctx context.Context, search race.Search) ([]race.Race, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(condition string, values ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+	if !search.From.IsZero() {
+		where("date >= ?", search.From)
+	}
+	if !search.To.IsZero() {
+		where("date < ?", search.To)
+	}
+	// location and name compare case-insensitively under the NOCASE collation of their columns
+	if search.Location != "" {
+		where("location = ?", search.Location)
+	}
+	if search.MinDistanceKm != nil {
+		where("distance_km >= ?", *search.MinDistanceKm)
+	}
+	if search.MaxDistanceKm != nil {
+		where("distance_km <= ?", *search.MaxDistanceKm)
+	}
+	if search.MinElevationGain != nil {
+		where("elevation_gain >= ?", *search.MinElevationGain)
+	}
+	if search.MaxElevationGain != nil {
+		where("elevation_gain <= ?", *search.MaxElevationGain)
+	}
+	if search.Text != "" {
+		where(`name LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(search.Text)+"%")
+	}
+
+	column, direction, comparison := sortColumns[search.Sort()], "ASC", ">"
+	if search.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if search.After != nil {
+		value := cursorValue(search.Sort(), *search.After)
+		where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparison), value, value, search.After.ID)
+	}
+
+	query := "SELECT " + raceColumns + " FROM races"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %[1]s %[2]s, id %[2]s", column, direction)
+	if search.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, search.Limit)
+	}
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var found []raceRow
+	for rows.Next() {
+		r, err := scanRace(rows)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// The waves are queried once the rows are read, so that the connection of the rows is released
+	rows.Close()
+
+	races := make([]race.Race, len(found))
+	for i, r := range found {
+		if races[i], err = m.loadRace(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return races, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// cursorValue returns the value of the sort column at the cursor
+func cursorValue(sortBy race.SortField, cursor race.Cursor) any {
+	switch sortBy {
+	case race.SortByName:
+		return cursor.Name
+	case race.SortByDistance:
+		return cursor.DistanceKm
+	case race.SortByElevationGain:
+		return cursor.ElevationGain
+	default:
+		return cursor.Date
+	}
+}
+
+// queryWaves returns the start waves of the race with the provided id
+func (m Repo) queryWaves(ctx context.Context, raceID uuid.UUID) ([]race.Wave, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT name, start_time FROM race_waves WHERE race_id = ? ORDER BY start_time", raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var waves []race.Wave
+	for rows.Next() {
+		var (
+			name      string
+			startTime time.Time
+		)
+		if err := rows.Scan(&name, &startTime); err != nil {
+			return nil, err
+		}
+		w, err := race.NewWave(name, startTime)
+		if err != nil {
+			return nil, err
+		}
+		waves = append(waves, w)
+	}
+	return waves, rows.Err()
+}
+
+// SaveRaceResult stores the provided race result together with its splits
+func (m Repo) SaveRaceResult(ctx context.Context, result race.Result) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO results (id, runner_id, race_id, status, status_reason, finish_time_ms, gun_time_ms,
+pace_min_per_km, heart_rate_avg, notes, publication, publication_reason, logged_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, query,
+		result.ID(),
+		result.RunnerID(),
+		result.RaceID(),
+		result.Status(),
+		result.StatusReason(),
+		result.FinishTime().Milliseconds(),
+		result.GunTime().Milliseconds(),
+		result.Pace(),
+		result.HeartRateAvg(),
+		result.Notes(),
+		result.Publication(),
+		result.PublicationReason(),
+		result.LoggedAt(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertSplits(ctx, tx, result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetResult Returns the result with the provided id
+func (m Repo) GetResult(ctx context.Context, resultID uuid.UUID) (race.Result, error) {
+	query := `SELECT ` + resultColumns + ` FROM results WHERE id = ?`
+	results, err := m.queryResults(ctx, query, resultID)
+	if err != nil {
+		return race.Result{}, err
+	}
+	if len(results) == 0 {
+		return race.Result{}, race.ErrResultNotFound
+	}
+	return results[0], nil
+}
+
+// UpdateRaceResult updates the status, times and publication of the provided result and replaces its splits
+func (m Repo) UpdateRaceResult(ctx context.Context, result race.Result) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateResult(ctx, tx, result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveRaceWithResults stores the race and updates the provided results in one transaction
+func (m Repo) SaveRaceWithResults(ctx context.Context, r race.Race, results []race.Result) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveRace(ctx, tx, r); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := updateResult(ctx, tx, result); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// updateResult updates the result and replaces its splits within the transaction
+func updateResult(ctx context.Context, tx *sql.Tx, result race.Result) error {
+	query := `UPDATE results SET status = ?, status_reason = ?, finish_time_ms = ?, gun_time_ms = ?, pace_min_per_km = ?,
+heart_rate_avg = ?, notes = ?, publication = ?, publication_reason = ? WHERE id = ?`
+	res, err := tx.ExecContext(ctx, query,
+		result.Status(),
+		result.StatusReason(),
+		result.FinishTime().Milliseconds(),
+		result.GunTime().Milliseconds(),
+		result.Pace(),
+		result.HeartRateAvg(),
+		result.Notes(),
+		result.Publication(),
+		result.PublicationReason(),
+		result.ID(),
+	)
+	if err != nil {
+		return err
+	}
+	//SQLite counts the matched rows, so no affected rows means the result does not exist
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return race.ErrResultNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM result_splits WHERE result_id = ?", result.ID()); err != nil {
+		return err
+	}
+	if err := insertSplits(ctx, tx, result); err != nil {
+		return err
+	}
+	return nil
+}
+
+func insertSplits(ctx context.Context, tx *sql.Tx, result race.Result) error {
+	for i, split := range result.Splits() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO result_splits (result_id, seq, distance_km, elapsed_ms) VALUES (?, ?, ?, ?)",
+			result.ID(), i, split.DistanceKm(), split.Elapsed().Milliseconds())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRaceResults Returns all race results of the runner with the provided id
+func (m Repo) GetRaceResults(ctx context.Context, runnerID uuid.UUID) ([]race.Result, error) {
+	query := `SELECT ` + resultColumns + ` FROM results WHERE runner_id = ? ORDER BY logged_at`
+	return m.queryResults(ctx, query, runnerID)
+}
+
+// GetResultsByRace Returns all results of the race with the provided id
+func (m Repo) GetResultsByRace(ctx context.Context, raceID uuid.UUID) ([]race.Result, error) {
+	query := `SELECT ` + resultColumns + ` FROM results WHERE race_id = ? ORDER BY finish_time_ms, logged_at`
+	return m.queryResults(ctx, query, raceID)
+}
+
+// resultColumns are the columns of a result in the order scanned by queryResults
+const resultColumns = `id, runner_id, race_id, status, status_reason, finish_time_ms, gun_time_ms, pace_min_per_km,
+heart_rate_avg, notes, publication, publication_reason, logged_at`
+
+type resultRow struct {
+	id                uuid.UUID
+	runnerID          uuid.UUID
+	raceID            uuid.UUID
+	status            string
+	statusReason      string
+	finishTimeMs      int64
+	gunTimeMs         int64
+	paceMinPerKm      float64
+	heartRateAvg      int
+	notes             string
+	publication       string
+	publicationReason string
+	loggedAt          time.Time
+}
+
+func (m Repo) queryResults(ctx context.Context, query string, args ...any) ([]race.Result, error) {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resultRows []resultRow
+	for rows.Next() {
+		var r resultRow
+		err := rows.Scan(&r.id, &r.runnerID, &r.raceID, &r.status, &r.statusReason, &r.finishTimeMs, &r.gunTimeMs, &r.paceMinPerKm,
+			&r.heartRateAvg, &r.notes, &r.publication, &r.publicationReason, &r.loggedAt)
+		if err != nil {
+			return nil, err
+		}
+		resultRows = append(resultRows, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	splits, err := m.querySplits(ctx, resultRows)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]race.Result, 0, len(resultRows))
+	for _, r := range resultRows {
+		result, err := race.LoadResult(
+			r.id,
+			r.runnerID,
+			r.raceID,
+			race.Status(r.status),
+			r.statusReason,
+			time.Duration(r.finishTimeMs)*time.Millisecond,
+			time.Duration(r.gunTimeMs)*time.Millisecond,
+			r.paceMinPerKm,
+			r.heartRateAvg,
+			r.notes,
+			splits[r.id],
+			race.Publication(r.publication),
+			r.publicationReason,
+			r.loggedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// querySplits returns the splits of the provided results grouped by result id
+func (m Repo) querySplits(ctx context.Context, resultRows []resultRow) (map[uuid.UUID][]race.Split, error) {
+	splits := make(map[uuid.UUID][]race.Split)
+	if len(resultRows) == 0 {
+		return splits, nil
+	}
+
+	placeholders := make([]string, len(resultRows))
+	args := make([]any, len(resultRows))
+	for i, r := range resultRows {
+		placeholders[i] = "?"
+		args[i] = r.id
+	}
+	query := "SELECT result_id, distance_km, elapsed_ms FROM result_splits WHERE result_id IN (" +
+		strings.Join(placeholders, ", ") + ") ORDER BY result_id, seq"
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			resultID   uuid.UUID
+			distanceKm float64
+			elapsedMs  int64
+		)
+		if err := rows.Scan(&resultID, &distanceKm, &elapsedMs); err != nil {
+			return nil, err
+		}
+		split, err := race.NewSplit(distanceKm, time.Duration(elapsedMs)*time.Millisecond)
+		if err != nil {
+			return nil, err
+		}
+		splits[resultID] = append(splits[resultID], split)
+	}
+	return splits, rows.Err()
+}
+
+// SaveCourse stores the track and the checkpoints of the course of the race, replacing any existing course
+func (m Repo) SaveCourse(ctx context.Context, raceID uuid.UUID, course race.Course) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM race_course_points WHERE race_id = ?", raceID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM race_course_checkpoints WHERE race_id = ?", raceID); err != nil {
+		return err
+	}
+	for i, p := range course.Points() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO race_course_points (race_id, seq, lat, lon, elevation_m) VALUES (?, ?, ?, ?, ?)",
+			raceID, i, p.Lat(), p.Lon(), p.ElevationM())
+		if err != nil {
+			return err
+		}
+	}
+	for i, c := range course.Checkpoints() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO race_course_checkpoints (race_id, seq, name, kind, lat, lon) VALUES (?, ?, ?, ?, ?, ?)",
+			raceID, i, c.Name(), c.Kind(), c.Position().Lat(), c.Position().Lon())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCourse Returns the course of the race with the provided id
+func (m Repo) GetCourse(ctx context.Context, raceID uuid.UUID) (race.Course, error) {
+	points, err := m.queryCoursePoints(ctx, raceID)
+	if err != nil {
+		return race.Course{}, err
+	}
+	if len(points) == 0 {
+		return race.Course{}, race.ErrCourseNotFound
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT name, kind, lat, lon FROM race_course_checkpoints WHERE race_id = ? ORDER BY seq", raceID)
+	if err != nil {
+		return race.Course{}, err
+	}
+	defer rows.Close()
+
+	var checkpoints []race.CourseCheckpoint
+	for rows.Next() {
+		var (
+			name, kind string
+			lat, lon   float64
+		)
+		if err := rows.Scan(&name, &kind, &lat, &lon); err != nil {
+			return race.Course{}, err
+		}
+		position, err := race.NewCoursePoint(lat, lon, 0)
+		if err != nil {
+			return race.Course{}, err
+		}
+		checkpoint, err := race.NewCourseCheckpoint(name, race.CheckpointKind(kind), position)
+		if err != nil {
+			return race.Course{}, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return race.Course{}, err
+	}
+	return race.NewCourse(points, checkpoints)
+}
+
+// queryCoursePoints returns the track points of the course of the race in running order
+func (m Repo) queryCoursePoints(ctx context.Context, raceID uuid.UUID) ([]race.CoursePoint, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT lat, lon, elevation_m FROM race_course_points WHERE race_id = ? ORDER BY seq", raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []race.CoursePoint
+	for rows.Next() {
+		var lat, lon, elevationM float64
+		if err := rows.Scan(&lat, &lon, &elevationM); err != nil {
+			return nil, err
+		}
+		p, err := race.NewCoursePoint(lat, lon, elevationM)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
@@ -0,0 +1,32 @@
+package race
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "racetracker.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.RaceRepositoryContract(t, func(t *testing.T) race.Repository {
+		return NewRepository(newTestDB(t))
+	})
+}
+
+func TestRepo_CourseContract(t *testing.T) {
+	storagetest.CourseRepositoryContract(t, func(t *testing.T) race.CourseRepository {
+		return NewRepository(newTestDB(t))
+	})
+}
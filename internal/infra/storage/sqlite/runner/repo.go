@@ -0,0 +1,153 @@
+// Package runner implements the runner Repository Interface to provide a SQLite storage provider
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+)
+
+// Repo Implements the Repository Interface to provide a SQLite storage provider
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepository Constructor
+func NewRepository(db *sql.DB) Repo {
+	return Repo{db}
+}
+
+// GetByID Returns the runner with the provided id
+func (m Repo) GetByID(ctx context.Context, id uuid.UUID) (*runner.Runner, error) {
+	query := "SELECT id, name, email_address, gender, date_of_birth, created_at FROM runners WHERE id = ?"
+	row := m.db.QueryRowContext(ctx, query, id)
+	domainRunner, err := scanRunner(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, runner.ErrNotFound
+		}
+		return nil, err
+	}
+	return domainRunner, nil
+}
+
+// maxIDsPerQuery bounds the placeholders of the IN clause of GetByIDs, far below the limit of SQLite
+const maxIDsPerQuery = 1000
+
+// GetByIDs Returns the runners with the provided ids, skipping unknown ids, with one query per 1000 ids
+func (m Repo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*runner.Runner, error) {
+	runners := make([]*runner.Runner, 0, len(ids))
+	for start := 0; start < len(ids); start += maxIDsPerQuery {
+		batch := ids[start:min(start+maxIDsPerQuery, len(ids))]
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT id, name, email_address, gender, date_of_birth, created_at FROM runners WHERE id IN (?" + strings.Repeat(", ?", len(batch)-1) + ")"
+		found, err := m.query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		runners = append(runners, found...)
+	}
+	return runners, nil
+}
+
+// GetAll Returns all stored runners ordered by creation date
+func (m Repo) GetAll(ctx context.Context) ([]*runner.Runner, error) {
+	query := "SELECT id, name, email_address, gender, date_of_birth, created_at FROM runners ORDER BY created_at, id"
+	return m.query(ctx, query)
+}
+
+// query returns the runners of the rows selected by the query
+func (m Repo) query(ctx context.Context, query string, args ...any) ([]*runner.Runner, error) {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runners []*runner.Runner
+	for rows.Next() {
+		domainRunner, err := scanRunner(rows)
+		if err != nil {
+			return nil, err
+		}
+		runners = append(runners, domainRunner)
+	}
+	return runners, rows.Err()
+}
+
+// Add the provided runner
+func (m Repo) Add(ctx context.Context, runner *runner.Runner) error {
+	query := "INSERT INTO runners (id, name, email_address, gender, date_of_birth, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := m.db.ExecContext(ctx, query, runner.ID(), runner.Name(), runner.EmailAddress(), string(runner.Gender()), nullableDate(runner.DateOfBirth()), runner.CreatedAt())
+	return err
+}
+
+// Update the provided runner
+func (m Repo) Update(ctx context.Context, r *runner.Runner) error {
+	query := "UPDATE runners SET name = ?, email_address = ?, gender = ?, date_of_birth = ?, created_at = ? WHERE id = ?"
+	result, err := m.db.ExecContext(ctx, query, r.Name(), r.EmailAddress(), string(r.Gender()), nullableDate(r.DateOfBirth()), r.CreatedAt(), r.ID())
+	if err != nil {
+		return err
+	}
+	//SQLite counts the matched rows, so no affected rows means the runner does not exist
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return runner.ErrNotFound
+	}
+	return nil
+}
+
+// Delete the runner with the provided id
+func (m Repo) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM runners WHERE id = ?"
+	result, err := m.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return runner.ErrNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRunner(row scanner) (*runner.Runner, error) {
+	var r struct {
+		id           uuid.UUID
+		name         string
+		emailAddress string
+		gender       string
+		dateOfBirth  sql.NullTime
+		createdAt    time.Time
+	}
+	err := row.Scan(&r.id, &r.name, &r.emailAddress, &r.gender, &r.dateOfBirth, &r.createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return runner.LoadRunner(r.id, r.name, r.emailAddress, runner.Gender(r.gender), r.dateOfBirth.Time, r.createdAt)
+}
+
+// nullableDate stores unknown dates as NULL
+func nullableDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,40 @@
+// Package sqlite opens the database of the SQLite storage provider
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite/migrations"
+)
+
+// Open opens the SQLite database of the data source name, a file name or a file: URI, and applies the pending
+// schema migrations
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	name, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("parsing sqlite dsn: %w", err)
+	}
+	//The repositories compare and order the DATETIME columns, so times are stored as microseconds since the epoch
+	params.Set("_timefmt", "unixepoch_micro")
+	//Writers take the lock when their transaction begins, so that concurrent writers wait instead of deadlocking
+	if !params.Has("_txlock") {
+		params.Set("_txlock", "immediate")
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+name+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+	if err := migrations.Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating sqlite schema: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,146 @@
+// Package timing implements the timing Repository Interface to provide a SQLite storage provider
+package timing
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+)
+
+// Repo Implements the timing Repository Interface to provide a SQLite storage provider
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepository Constructor
+func NewRepository(db *sql.DB) Repo {
+	return Repo{db}
+}
+
+// GetSession Returns the timing session of the race with the provided id
+func (m Repo) GetSession(ctx context.Context, raceID uuid.UUID) (*timing.Session, error) {
+	var s struct {
+		gunTime    time.Time
+		debounceMs int64
+		version    int
+	}
+	row := m.db.QueryRowContext(ctx, "SELECT gun_time, debounce_ms, version FROM timing_sessions WHERE race_id = ?", raceID)
+	if err := row.Scan(&s.gunTime, &s.debounceMs, &s.version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, timing.ErrNotConfigured
+		}
+		return nil, err
+	}
+
+	checkpoints, err := m.queryCheckpoints(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	reads, err := m.queryReads(ctx, raceID)
+	if err != nil {
+		return nil, err
+	}
+	return timing.LoadSession(raceID, s.gunTime, time.Duration(s.debounceMs)*time.Millisecond, checkpoints, reads, s.version)
+}
+
+func (m Repo) queryCheckpoints(ctx context.Context, raceID uuid.UUID) ([]timing.Checkpoint, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT checkpoint_id, distance_km FROM timing_checkpoints WHERE race_id = ?", raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checkpoints []timing.Checkpoint
+	for rows.Next() {
+		var id string
+		var distanceKm float64
+		if err := rows.Scan(&id, &distanceKm); err != nil {
+			return nil, err
+		}
+		c, err := timing.NewCheckpoint(id, distanceKm)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, c)
+	}
+	return checkpoints, rows.Err()
+}
+
+func (m Repo) queryReads(ctx context.Context, raceID uuid.UUID) ([]timing.Read, error) {
+	query := "SELECT chip_id, checkpoint_id, read_at FROM timing_reads WHERE race_id = ? ORDER BY read_at"
+	rows, err := m.db.QueryContext(ctx, query, raceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reads []timing.Read
+	for rows.Next() {
+		var chipID, checkpointID string
+		var at time.Time
+		if err := rows.Scan(&chipID, &checkpointID, &at); err != nil {
+			return nil, err
+		}
+		r, err := timing.NewRead(chipID, checkpointID, at)
+		if err != nil {
+			return nil, err
+		}
+		reads = append(reads, r)
+	}
+	return reads, rows.Err()
+}
+
+// SaveSession stores the settings, the checkpoints and the new reads of the session when the stored version
+// matches the version the session was loaded from
+func (m Repo) SaveSession(ctx context.Context, s *timing.Session) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	debounceMs := s.Debounce().Milliseconds()
+	var res sql.Result
+	if s.Version() == 0 {
+		res, err = tx.ExecContext(ctx, "INSERT OR IGNORE INTO timing_sessions (race_id, gun_time, debounce_ms, version) VALUES (?, ?, ?, 1)",
+			s.RaceID(), s.GunTime(), debounceMs)
+	} else {
+		query := "UPDATE timing_sessions SET gun_time = ?, debounce_ms = ?, version = version + 1 WHERE race_id = ? AND version = ?"
+		res, err = tx.ExecContext(ctx, query, s.GunTime(), debounceMs, s.RaceID(), s.Version())
+	}
+	if err != nil {
+		return err
+	}
+	//the version always changes, so no affected rows means another request saved the session first
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return timing.ErrConcurrentUpdate
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM timing_checkpoints WHERE race_id = ?", s.RaceID()); err != nil {
+		return err
+	}
+	for _, c := range s.Checkpoints() {
+		_, err := tx.ExecContext(ctx, "INSERT INTO timing_checkpoints (race_id, checkpoint_id, distance_km) VALUES (?, ?, ?)",
+			s.RaceID(), c.ID(), c.DistanceKm())
+		if err != nil {
+			return err
+		}
+	}
+
+	//reads are never removed and exact repeats are deduplicated, so the ones that are already stored are skipped
+	for _, r := range s.Reads() {
+		_, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO timing_reads (race_id, chip_id, checkpoint_id, read_at) VALUES (?, ?, ?, ?)",
+			s.RaceID(), r.ChipID(), r.CheckpointID(), r.At())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,26 @@
+package timing
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/sqlite"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/infra/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "racetracker.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRepo_Contract(t *testing.T) {
+	storagetest.TimingRepositoryContract(t, func(t *testing.T) timing.Repository {
+		return NewRepository(newTestDB(t))
+	})
+}
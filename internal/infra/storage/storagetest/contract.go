@@ -0,0 +1,762 @@
+// Package storagetest contains the contract tests that every repository implementation must pass
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/bib"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/race"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/registration"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/runner"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/domain/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunnerRepositoryContract verifies that the repository returned by newRepo honours the runner.Repository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func RunnerRepositoryContract(t *testing.T, newRepo func(t *testing.T) runner.Repository) {
+	ctx := context.Background()
+	newRunner := func(t *testing.T) *runner.Runner {
+		r, err := runner.NewRunner("John Doe", "john.doe@example.com")
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("GetByID returns ErrNotFound for unknown id", func(t *testing.T) {
+		repo := newRepo(t)
+		r, err := repo.GetByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, runner.ErrNotFound)
+		assert.Nil(t, r)
+	})
+
+	t.Run("GetByID returns added runner", func(t *testing.T) {
+		repo := newRepo(t)
+		r := newRunner(t)
+		require.NoError(t, repo.Add(ctx, r))
+
+		got, err := repo.GetByID(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, r.ID(), got.ID())
+		assert.Equal(t, r.Name(), got.Name())
+		assert.Equal(t, r.EmailAddress(), got.EmailAddress())
+	})
+
+	t.Run("GetByID returns the runner profile", func(t *testing.T) {
+		repo := newRepo(t)
+		r := newRunner(t)
+		dateOfBirth := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, r.UpdateProfile(runner.GenderFemale, dateOfBirth))
+		require.NoError(t, repo.Add(ctx, r))
+
+		got, err := repo.GetByID(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, runner.GenderFemale, got.Gender())
+		assert.True(t, dateOfBirth.Equal(got.DateOfBirth()))
+	})
+
+	t.Run("GetByIDs returns the known runners", func(t *testing.T) {
+		repo := newRepo(t)
+		r1 := newRunner(t)
+		r2 := newRunner(t)
+		require.NoError(t, repo.Add(ctx, r1))
+		require.NoError(t, repo.Add(ctx, r2))
+
+		runners, err := repo.GetByIDs(ctx, []uuid.UUID{r2.ID(), uuid.New(), r1.ID()})
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(runners))
+		for i, r := range runners {
+			ids[i] = r.ID()
+		}
+		assert.ElementsMatch(t, []uuid.UUID{r1.ID(), r2.ID()}, ids)
+	})
+
+	t.Run("GetByIDs returns nothing for no ids", func(t *testing.T) {
+		repo := newRepo(t)
+		runners, err := repo.GetByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, runners)
+	})
+
+	t.Run("GetAll returns added runners", func(t *testing.T) {
+		repo := newRepo(t)
+		r1 := newRunner(t)
+		r2 := newRunner(t)
+		require.NoError(t, repo.Add(ctx, r1))
+		require.NoError(t, repo.Add(ctx, r2))
+
+		runners, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(runners))
+		for i, r := range runners {
+			ids[i] = r.ID()
+		}
+		assert.Contains(t, ids, r1.ID())
+		assert.Contains(t, ids, r2.ID())
+	})
+
+	t.Run("Update returns ErrNotFound for unknown runner", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.Update(ctx, newRunner(t))
+		assert.ErrorIs(t, err, runner.ErrNotFound)
+	})
+
+	t.Run("Update stores the changes", func(t *testing.T) {
+		repo := newRepo(t)
+		r := newRunner(t)
+		require.NoError(t, repo.Add(ctx, r))
+		require.NoError(t, r.Rename("John Smith"))
+		require.NoError(t, repo.Update(ctx, r))
+
+		got, err := repo.GetByID(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "John Smith", got.Name())
+	})
+
+	t.Run("Delete returns ErrNotFound for unknown id", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.Delete(ctx, uuid.New())
+		assert.ErrorIs(t, err, runner.ErrNotFound)
+	})
+
+	t.Run("Delete removes the runner", func(t *testing.T) {
+		repo := newRepo(t)
+		r := newRunner(t)
+		require.NoError(t, repo.Add(ctx, r))
+		require.NoError(t, repo.Delete(ctx, r.ID()))
+
+		_, err := repo.GetByID(ctx, r.ID())
+		assert.ErrorIs(t, err, runner.ErrNotFound)
+	})
+}
+
+// RaceRepositoryContract verifies that the repository returned by newRepo honours the race.Repository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func RaceRepositoryContract(t *testing.T, newRepo func(t *testing.T) race.Repository) {
+	ctx := context.Background()
+	t.Run("GetRace returns ErrNotFound for unknown id", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetRace(ctx, uuid.New())
+		assert.ErrorIs(t, err, race.ErrNotFound)
+	})
+
+	t.Run("GetRace returns saved race", func(t *testing.T) {
+		repo := newRepo(t)
+		r, err := race.NewRace("Athens Marathon", "Athens", time.Now().UTC().Truncate(time.Second), 42.195, 250)
+		require.NoError(t, err)
+		r = r.WithOrganiser("athens-events")
+		require.NoError(t, repo.SaveRace(ctx, r))
+
+		got, err := repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, r.ID(), got.ID())
+		assert.Equal(t, r.Name(), got.Name())
+		assert.True(t, r.Date().Equal(got.Date()))
+		assert.Equal(t, r.DistanceKm(), got.DistanceKm())
+		assert.Equal(t, "athens-events", got.Organiser())
+	})
+
+	t.Run("GetRace returns the waves and the ranking policy of the race", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().UTC().Truncate(time.Second)
+		r, err := race.NewRace("Athens Marathon", "Athens", start, 42.195, 250)
+		require.NoError(t, err)
+		first, err := race.NewWave("A", start)
+		require.NoError(t, err)
+		second, err := race.NewWave("B", start.Add(15*time.Minute))
+		require.NoError(t, err)
+		r, err = r.WithWaves([]race.Wave{second, first})
+		require.NoError(t, err)
+		r, err = r.WithRankingPolicy(race.RankingNetTime)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+
+		got, err := repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, race.RankingNetTime, got.RankingPolicy())
+		require.Len(t, got.Waves(), 2)
+		assert.Equal(t, "A", got.Waves()[0].Name())
+		assert.True(t, second.StartTime().Equal(got.Waves()[1].StartTime()))
+
+		//saving the race again replaces its waves
+		r, err = r.WithWaves([]race.Wave{first})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+		got, err = repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Len(t, got.Waves(), 1)
+	})
+
+	t.Run("SaveRace stores the changes and the cancellation of the race", func(t *testing.T) {
+		repo := newRepo(t)
+		date := time.Now().UTC().Truncate(time.Second)
+		r, err := race.NewRace("Athens Marathon", "Athens", date, 42.195, 250)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+
+		r, err = r.Relocate("Marathon")
+		require.NoError(t, err)
+		r, err = r.Reschedule(date.Add(24 * time.Hour))
+		require.NoError(t, err)
+		r, err = r.Cancel("storm warning", date)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+
+		got, err := repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Marathon", got.Location())
+		assert.True(t, date.Add(24*time.Hour).Equal(got.Date()))
+		assert.True(t, got.IsCancelled())
+		assert.True(t, date.Equal(got.CancelledAt()))
+		assert.Equal(t, "storm warning", got.CancellationReason())
+	})
+
+	t.Run("SearchRaces filters, sorts and pages the races", func(t *testing.T) {
+		repo := newRepo(t)
+		// a location unique to the subtest isolates its races from the races of other tests
+		location := "Location " + uuid.NewString()
+		date := time.Now().UTC().Truncate(time.Second)
+		newRace := func(name string, offset time.Duration, distanceKm, elevationGain float64) race.Race {
+			r, err := race.NewRace(name, location, date.Add(offset), distanceKm, elevationGain)
+			require.NoError(t, err)
+			require.NoError(t, repo.SaveRace(ctx, r))
+			return r
+		}
+		marathon := newRace("Classic Marathon", 0, 42.195, 250)
+		tenK := newRace("City 10K", time.Hour, 10, 50)
+		nightTenK := newRace("Night 10K", 2*time.Hour, 10, 20)
+		trail := newRace("Mountain Trail", 3*time.Hour, 25, 1200)
+		ids := func(races []race.Race) []uuid.UUID {
+			found := make([]uuid.UUID, len(races))
+			for i, r := range races {
+				found[i] = r.ID()
+			}
+			return found
+		}
+		lower, upper := 10.0, 30.0
+
+		got, err := repo.SearchRaces(ctx, race.Search{Location: location})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{marathon.ID(), tenK.ID(), nightTenK.ID(), trail.ID()}, ids(got))
+
+		got, err = repo.SearchRaces(ctx, race.Search{Location: location, From: date.Add(time.Hour), To: date.Add(3 * time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tenK.ID(), nightTenK.ID()}, ids(got))
+
+		got, err = repo.SearchRaces(ctx, race.Search{Location: location, MinDistanceKm: &lower, MaxDistanceKm: &upper, MinElevationGain: &upper,
+			SortBy: race.SortByElevationGain, Descending: true})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{trail.ID(), tenK.ID()}, ids(got))
+
+		got, err = repo.SearchRaces(ctx, race.Search{Location: location, Text: "10k", SortBy: race.SortByName})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tenK.ID(), nightTenK.ID()}, ids(got))
+
+		first, err := repo.SearchRaces(ctx, race.Search{Location: location, SortBy: race.SortByDistance, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		cursor := race.CursorOf(first[1])
+		second, err := repo.SearchRaces(ctx, race.Search{Location: location, SortBy: race.SortByDistance, Limit: 2, After: &cursor})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{tenK.ID(), nightTenK.ID()}, ids(first), "races of the same distance are ordered by id")
+		assert.Equal(t, []uuid.UUID{trail.ID(), marathon.ID()}, ids(second))
+	})
+
+	t.Run("GetRaceResults returns saved results once", func(t *testing.T) {
+		repo := newRepo(t)
+		runnerID := uuid.New()
+		result, err := race.NewResult(runnerID, uuid.New(), 30*time.Minute, 5.0, 150, "Good race")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		results, err := repo.GetRaceResults(ctx, runnerID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, result.ID(), results[0].ID())
+	})
+
+	t.Run("GetResult returns the gun and the net time of the result", func(t *testing.T) {
+		repo := newRepo(t)
+		result, err := race.NewResult(uuid.New(), uuid.New(), 30*time.Minute, 5.0, 150, "")
+		require.NoError(t, err)
+		result, err = result.WithGunTime(31 * time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		got, err := repo.GetResult(ctx, result.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Minute, got.FinishTime())
+		assert.Equal(t, 31*time.Minute, got.GunTime())
+	})
+
+	t.Run("GetRaceResults returns the splits of the result in order", func(t *testing.T) {
+		repo := newRepo(t)
+		runnerID := uuid.New()
+		result, err := race.NewResult(runnerID, uuid.New(), 15*time.Minute, 5.0, 150, "")
+		require.NoError(t, err)
+		first, err := race.NewSplit(1, 5*time.Minute)
+		require.NoError(t, err)
+		second, err := race.NewSplit(2, 10*time.Minute)
+		require.NoError(t, err)
+		result, err = result.WithSplits([]race.Split{first, second}, 3)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		results, err := repo.GetRaceResults(ctx, runnerID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, []race.Split{first, second}, results[0].Splits())
+	})
+
+	t.Run("GetResultsByRace returns the results of the race only", func(t *testing.T) {
+		repo := newRepo(t)
+		raceID := uuid.New()
+		first, err := race.NewResult(uuid.New(), raceID, 30*time.Minute, 5.0, 150, "")
+		require.NoError(t, err)
+		second, err := race.NewResult(uuid.New(), raceID, 31*time.Minute, 5.1, 150, "")
+		require.NoError(t, err)
+		other, err := race.NewResult(uuid.New(), uuid.New(), 29*time.Minute, 4.9, 150, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, first))
+		require.NoError(t, repo.SaveRaceResult(ctx, second))
+		require.NoError(t, repo.SaveRaceResult(ctx, other))
+
+		results, err := repo.GetResultsByRace(ctx, raceID)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(results))
+		for i, r := range results {
+			ids[i] = r.ID()
+		}
+		assert.ElementsMatch(t, []uuid.UUID{first.ID(), second.ID()}, ids)
+	})
+
+	t.Run("GetResultsByRace returns empty list for unknown race", func(t *testing.T) {
+		repo := newRepo(t)
+		results, err := repo.GetResultsByRace(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("GetRaceResults returns empty list for unknown runner", func(t *testing.T) {
+		repo := newRepo(t)
+		results, err := repo.GetRaceResults(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("GetResult returns ErrResultNotFound for unknown id", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetResult(ctx, uuid.New())
+		assert.ErrorIs(t, err, race.ErrResultNotFound)
+	})
+
+	t.Run("GetResult returns the status of the result", func(t *testing.T) {
+		repo := newRepo(t)
+		result, err := race.NewResultWithStatus(uuid.New(), uuid.New(), race.StatusDisqualified, "course cutting", 0, 0, 0, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		got, err := repo.GetResult(ctx, result.ID())
+		require.NoError(t, err)
+		assert.Equal(t, race.StatusDisqualified, got.Status())
+		assert.Equal(t, "course cutting", got.StatusReason())
+		assert.Equal(t, race.PublicationProvisional, got.Publication())
+	})
+
+	t.Run("UpdateRaceResult returns ErrResultNotFound for unknown result", func(t *testing.T) {
+		repo := newRepo(t)
+		result, err := race.NewResult(uuid.New(), uuid.New(), 30*time.Minute, 5.0, 150, "")
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.UpdateRaceResult(ctx, result), race.ErrResultNotFound)
+	})
+
+	t.Run("UpdateRaceResult stores the amendment", func(t *testing.T) {
+		repo := newRepo(t)
+		runnerID := uuid.New()
+		result, err := race.NewResult(runnerID, uuid.New(), 15*time.Minute, 5.0, 150, "")
+		require.NoError(t, err)
+		split, err := race.NewSplit(1, 5*time.Minute)
+		require.NoError(t, err)
+		result, err = result.WithSplits([]race.Split{split}, 3)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		published, err := result.Publish("")
+		require.NoError(t, err)
+		amended, err := published.Amend(race.StatusDidNotStart, "", 0, 0, "wrong bib")
+		require.NoError(t, err)
+		require.NoError(t, repo.UpdateRaceResult(ctx, amended))
+
+		results, err := repo.GetRaceResults(ctx, runnerID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, race.StatusDidNotStart, results[0].Status())
+		assert.Equal(t, time.Duration(0), results[0].FinishTime())
+		assert.Empty(t, results[0].Splits())
+		assert.Equal(t, race.PublicationAmended, results[0].Publication())
+		assert.Equal(t, "wrong bib", results[0].PublicationReason())
+	})
+
+	t.Run("SaveRaceWithResults stores the race and the results", func(t *testing.T) {
+		repo := newRepo(t)
+		r, err := race.NewRace("Athens 10K", "Athens", time.Now().UTC().Truncate(time.Second), 10, 50)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+		result, err := race.NewResult(uuid.New(), r.ID(), 50*time.Minute, 10, 150, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, result))
+
+		corrected, err := r.CorrectDistance(9.8, 50, []race.Result{result})
+		require.NoError(t, err)
+		recalculated, err := result.ForDistance(9.8)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceWithResults(ctx, corrected, []race.Result{recalculated}))
+
+		got, err := repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 9.8, got.DistanceKm())
+		gotResult, err := repo.GetResult(ctx, result.ID())
+		require.NoError(t, err)
+		assert.Equal(t, recalculated.Pace(), gotResult.Pace())
+	})
+
+	t.Run("SaveRaceWithResults changes nothing when a result is missing", func(t *testing.T) {
+		repo := newRepo(t)
+		r, err := race.NewRace("Athens 10K", "Athens", time.Now().UTC().Truncate(time.Second), 10, 50)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRace(ctx, r))
+		stored, err := race.NewResult(uuid.New(), r.ID(), 50*time.Minute, 10, 150, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveRaceResult(ctx, stored))
+		missing, err := race.NewResult(uuid.New(), r.ID(), 45*time.Minute, 10, 150, "")
+		require.NoError(t, err)
+
+		corrected, err := r.CorrectDistance(9.8, 50, []race.Result{stored})
+		require.NoError(t, err)
+		recalculated, err := stored.ForDistance(9.8)
+		require.NoError(t, err)
+		err = repo.SaveRaceWithResults(ctx, corrected, []race.Result{recalculated, missing})
+		assert.ErrorIs(t, err, race.ErrResultNotFound)
+
+		got, err := repo.GetRace(ctx, r.ID())
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, got.DistanceKm())
+		gotResult, err := repo.GetResult(ctx, stored.ID())
+		require.NoError(t, err)
+		assert.Equal(t, stored.Pace(), gotResult.Pace())
+	})
+}
+
+// CourseRepositoryContract verifies that the repository returned by newRepo honours the race.CourseRepository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func CourseRepositoryContract(t *testing.T, newRepo func(t *testing.T) race.CourseRepository) {
+	ctx := context.Background()
+	t.Run("GetCourse returns ErrCourseNotFound for unknown race", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetCourse(ctx, uuid.New())
+		assert.ErrorIs(t, err, race.ErrCourseNotFound)
+	})
+
+	t.Run("GetCourse returns the saved course", func(t *testing.T) {
+		repo := newRepo(t)
+		raceID := uuid.New()
+		start, err := race.NewCoursePoint(37.9838, 23.7275, 70)
+		require.NoError(t, err)
+		middle, err := race.NewCoursePoint(37.9928, 23.7275, 95)
+		require.NoError(t, err)
+		finish, err := race.NewCoursePoint(38.0018, 23.7275, 80)
+		require.NoError(t, err)
+		aid, err := race.NewCourseCheckpoint("Aid 1", race.CheckpointAidStation, middle)
+		require.NoError(t, err)
+		course, err := race.NewCourse([]race.CoursePoint{start, middle, finish}, []race.CourseCheckpoint{aid})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveCourse(ctx, raceID, course))
+
+		got, err := repo.GetCourse(ctx, raceID)
+		require.NoError(t, err)
+		require.Len(t, got.Points(), 3)
+		assert.Equal(t, 95.0, got.Points()[1].ElevationM())
+		assert.InDelta(t, course.DistanceKm(), got.DistanceKm(), 0.001)
+		require.Len(t, got.Checkpoints(), 1)
+		assert.Equal(t, "Aid 1", got.Checkpoints()[0].Name())
+		assert.Equal(t, race.CheckpointAidStation, got.Checkpoints()[0].Kind())
+
+		//saving the course again replaces it
+		short, err := race.NewCourse([]race.CoursePoint{start, finish}, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveCourse(ctx, raceID, short))
+		got, err = repo.GetCourse(ctx, raceID)
+		require.NoError(t, err)
+		assert.Len(t, got.Points(), 2)
+		assert.Empty(t, got.Checkpoints())
+	})
+}
+
+// RegistrationRepositoryContract verifies that the repository returned by newRepo honours the registration.Repository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func RegistrationRepositoryContract(t *testing.T, newRepo func(t *testing.T) registration.Repository) {
+	ctx := context.Background()
+	opensAt := time.Now().UTC().Truncate(time.Second)
+	closesAt := opensAt.Add(24 * time.Hour)
+	newEntryList := func(t *testing.T) *registration.EntryList {
+		l, err := registration.NewEntryList(uuid.New(), 1, opensAt, closesAt, true)
+		require.NoError(t, err)
+		return l
+	}
+
+	t.Run("GetEntryList returns ErrNotConfigured for unknown race", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetEntryList(ctx, uuid.New())
+		assert.ErrorIs(t, err, registration.ErrNotConfigured)
+	})
+
+	t.Run("GetEntryList returns the saved entry list and its registrations", func(t *testing.T) {
+		repo := newRepo(t)
+		l := newEntryList(t)
+		confirmed, err := l.Register(uuid.New(), opensAt)
+		require.NoError(t, err)
+		waitlisted, err := l.Register(uuid.New(), opensAt.Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveEntryList(ctx, l))
+
+		got, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Capacity())
+		assert.True(t, got.OpensAt().Equal(opensAt))
+		assert.True(t, got.ClosesAt().Equal(closesAt))
+		assert.True(t, got.RequiredForResults())
+		assert.True(t, got.IsConfirmed(confirmed.RunnerID()))
+		assert.Equal(t, 1, got.WaitlistPosition(waitlisted.RunnerID()))
+		assert.Equal(t, confirmed.ID(), got.Confirmed()[0].ID())
+	})
+
+	t.Run("SaveEntryList stores changes to loaded entry lists", func(t *testing.T) {
+		repo := newRepo(t)
+		l := newEntryList(t)
+		first, err := l.Register(uuid.New(), opensAt)
+		require.NoError(t, err)
+		second, err := l.Register(uuid.New(), opensAt.Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveEntryList(ctx, l))
+
+		loaded, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		_, _, err = loaded.Cancel(first.RunnerID(), opensAt.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveEntryList(ctx, loaded))
+
+		got, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		assert.True(t, got.IsConfirmed(second.RunnerID()))
+		assert.Empty(t, got.Waitlist())
+		assert.Len(t, got.Registrations(), 2)
+	})
+
+	t.Run("SaveEntryList returns ErrConcurrentUpdate for stale entry lists", func(t *testing.T) {
+		repo := newRepo(t)
+		l := newEntryList(t)
+		require.NoError(t, repo.SaveEntryList(ctx, l))
+		assert.ErrorIs(t, repo.SaveEntryList(ctx, l), registration.ErrConcurrentUpdate)
+
+		first, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		second, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		_, err = first.Register(uuid.New(), opensAt)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveEntryList(ctx, first))
+		_, err = second.Register(uuid.New(), opensAt)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.SaveEntryList(ctx, second), registration.ErrConcurrentUpdate)
+
+		got, err := repo.GetEntryList(ctx, l.RaceID())
+		require.NoError(t, err)
+		assert.Len(t, got.Registrations(), 1)
+	})
+}
+
+// BibRepositoryContract verifies that the repository returned by newRepo honours the bib.Repository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func BibRepositoryContract(t *testing.T, newRepo func(t *testing.T) bib.Repository) {
+	ctx := context.Background()
+	at := time.Now().UTC().Truncate(time.Second)
+	newAllocation := func(t *testing.T) *bib.Allocation {
+		elite, err := bib.NewRange("elite", 1, 99)
+		require.NoError(t, err)
+		open, err := bib.NewRange("open", 100, 999)
+		require.NoError(t, err)
+		a, err := bib.NewAllocation(uuid.New(), []bib.Range{elite, open})
+		require.NoError(t, err)
+		return a
+	}
+
+	t.Run("GetAllocation returns ErrNotConfigured for unknown race", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetAllocation(ctx, uuid.New())
+		assert.ErrorIs(t, err, bib.ErrNotConfigured)
+	})
+
+	t.Run("GetAllocation returns the saved ranges, assignments and changes", func(t *testing.T) {
+		repo := newRepo(t)
+		a := newAllocation(t)
+		runnerID := uuid.New()
+		_, err := a.Assign(runnerID, "elite", "CHIP-1", at)
+		require.NoError(t, err)
+		_, err = a.Assign(uuid.New(), "open", "", at)
+		require.NoError(t, err)
+		_, err = a.Reassign(runnerID, 7, "", "CHIP-7", "lost bib", at.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAllocation(ctx, a))
+
+		got, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		assert.Equal(t, a.Ranges(), got.Ranges())
+		require.Len(t, got.Assignments(), 2)
+		assignment, found := got.Assignment(runnerID)
+		require.True(t, found)
+		assert.Equal(t, 7, assignment.Number())
+		assert.Equal(t, "elite", assignment.RangeName())
+		assert.Equal(t, "CHIP-7", assignment.ChipID())
+		require.Len(t, got.Changes(), 1)
+		assert.Equal(t, 1, got.Changes()[0].PreviousNumber())
+		assert.Equal(t, "lost bib", got.Changes()[0].Reason())
+		assert.True(t, got.Changes()[0].ChangedAt().Equal(at.Add(time.Hour)))
+	})
+
+	t.Run("SaveAllocation keeps the changes in order", func(t *testing.T) {
+		repo := newRepo(t)
+		a := newAllocation(t)
+		runnerID := uuid.New()
+		_, err := a.Assign(runnerID, "elite", "", at)
+		require.NoError(t, err)
+		_, err = a.Reassign(runnerID, 2, "", "", "lost bib", at)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAllocation(ctx, a))
+
+		loaded, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		_, err = loaded.Reassign(runnerID, 3, "", "", "lost bib again", at)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAllocation(ctx, loaded))
+
+		got, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		require.Len(t, got.Changes(), 2)
+		assert.Equal(t, "lost bib", got.Changes()[0].Reason())
+		assert.Equal(t, "lost bib again", got.Changes()[1].Reason())
+	})
+
+	t.Run("SaveAllocation returns ErrConcurrentUpdate for stale allocations", func(t *testing.T) {
+		repo := newRepo(t)
+		a := newAllocation(t)
+		require.NoError(t, repo.SaveAllocation(ctx, a))
+		assert.ErrorIs(t, repo.SaveAllocation(ctx, a), bib.ErrConcurrentUpdate)
+
+		first, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		second, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		_, err = first.Assign(uuid.New(), "open", "", at)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAllocation(ctx, first))
+		_, err = second.Assign(uuid.New(), "open", "", at)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.SaveAllocation(ctx, second), bib.ErrConcurrentUpdate)
+
+		got, err := repo.GetAllocation(ctx, a.RaceID())
+		require.NoError(t, err)
+		assert.Len(t, got.Assignments(), 1)
+	})
+}
+
+// TimingRepositoryContract verifies that the repository returned by newRepo honours the timing.Repository contract.
+// newRepo is called once per subtest; the contract does not rely on the repository being empty.
+func TimingRepositoryContract(t *testing.T, newRepo func(t *testing.T) timing.Repository) {
+	ctx := context.Background()
+	gun := time.Now().UTC().Truncate(time.Second)
+	newSession := func(t *testing.T) *timing.Session {
+		start, err := timing.NewCheckpoint("start", 0)
+		require.NoError(t, err)
+		finish, err := timing.NewCheckpoint("finish", 10)
+		require.NoError(t, err)
+		s, err := timing.NewSession(uuid.New(), gun, 5*time.Second, []timing.Checkpoint{start, finish}, 10)
+		require.NoError(t, err)
+		return s
+	}
+	newRead := func(t *testing.T, chipID, checkpointID string, after time.Duration) timing.Read {
+		r, err := timing.NewRead(chipID, checkpointID, gun.Add(after))
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("GetSession returns ErrNotConfigured for unknown race", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetSession(ctx, uuid.New())
+		assert.ErrorIs(t, err, timing.ErrNotConfigured)
+	})
+
+	t.Run("GetSession returns the saved settings, checkpoints and reads", func(t *testing.T) {
+		repo := newRepo(t)
+		s := newSession(t)
+		_, err := s.Record([]timing.Read{
+			newRead(t, "CHIP-1", "finish", 40*time.Minute+500*time.Millisecond),
+			newRead(t, "CHIP-1", "start", 10*time.Second),
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveSession(ctx, s))
+
+		got, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		assert.True(t, got.GunTime().Equal(gun))
+		assert.Equal(t, 5*time.Second, got.Debounce())
+		assert.Equal(t, s.Checkpoints(), got.Checkpoints())
+		require.Len(t, got.Reads(), 2)
+		assert.Equal(t, "start", got.Reads()[0].CheckpointID())
+		assert.True(t, got.Reads()[1].At().Equal(gun.Add(40*time.Minute+500*time.Millisecond)))
+	})
+
+	t.Run("SaveSession adds the reads recorded after loading", func(t *testing.T) {
+		repo := newRepo(t)
+		s := newSession(t)
+		_, err := s.Record([]timing.Read{newRead(t, "CHIP-1", "finish", 40*time.Minute)})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveSession(ctx, s))
+
+		loaded, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		_, err = loaded.Record([]timing.Read{newRead(t, "CHIP-2", "finish", 39*time.Minute)})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveSession(ctx, loaded))
+
+		got, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		require.Len(t, got.Reads(), 2)
+		assert.Equal(t, "CHIP-2", got.Reads()[0].ChipID())
+	})
+
+	t.Run("SaveSession returns ErrConcurrentUpdate for stale sessions", func(t *testing.T) {
+		repo := newRepo(t)
+		s := newSession(t)
+		require.NoError(t, repo.SaveSession(ctx, s))
+		assert.ErrorIs(t, repo.SaveSession(ctx, s), timing.ErrConcurrentUpdate)
+
+		first, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		second, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		_, err = first.Record([]timing.Read{newRead(t, "CHIP-1", "finish", 40*time.Minute)})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveSession(ctx, first))
+		_, err = second.Record([]timing.Read{newRead(t, "CHIP-2", "finish", 40*time.Minute)})
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.SaveSession(ctx, second), timing.ErrConcurrentUpdate)
+
+		got, err := repo.GetSession(ctx, s.RaceID())
+		require.NoError(t, err)
+		require.Len(t, got.Reads(), 1)
+		assert.Equal(t, "CHIP-1", got.Reads()[0].ChipID())
+	})
+}
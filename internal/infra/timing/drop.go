@@ -0,0 +1,127 @@
+package timing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+)
+
+// Sub folders the drop folder moves the files it picked up to
+const (
+	ProcessedDir = "processed"
+	FailedDir    = "failed"
+)
+
+type readImporter interface {
+	ImportReads(ctx context.Context, raceID uuid.UUID, format timing.ReadFormat, file io.Reader) (timing.RecordedReadsItem, error)
+}
+
+// DropFolder imports the read files the timing system drops into a folder.
+// Files are named after the race they belong to, such as <race id>.csv or <race id>-mat2.lp, and are moved to
+// the processed or the failed sub folder once they are picked up. Files whose extension is not a read format
+// are left alone, so the timing system should write to a temporary name and rename the file when it is complete.
+type DropFolder struct {
+	dir      string
+	interval time.Duration
+	importer readImporter
+	logger   logging.Logger
+}
+
+// NewDropFolder Constructor
+func NewDropFolder(dir string, interval time.Duration, importer readImporter, logger logging.Logger) DropFolder {
+	return DropFolder{dir: dir, interval: interval, importer: importer, logger: logger}
+}
+
+// Run polls the folder every interval until the context is done
+func (d DropFolder) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll imports the read files that are in the folder and returns the number of files imported and failed
+func (d DropFolder) Poll(ctx context.Context) (imported, failed int) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		d.logger.Warn(ctx, "failed to read the timing drop folder", logging.String("dir", d.dir), logging.Err(err))
+		return 0, 0
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		format, err := timing.ReadFormatFromFilename(entry.Name())
+		if err != nil {
+			continue
+		}
+		if err := d.importFile(ctx, entry.Name(), format); err != nil {
+			d.logger.Warn(ctx, "failed to import a timing read file", logging.String("file", entry.Name()), logging.Err(err))
+			d.move(ctx, entry.Name(), FailedDir)
+			failed++
+			continue
+		}
+		d.move(ctx, entry.Name(), ProcessedDir)
+		imported++
+	}
+	return imported, failed
+}
+
+func (d DropFolder) importFile(ctx context.Context, name string, format timing.ReadFormat) error {
+	raceID, err := raceIDOf(name)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(filepath.Join(d.dir, name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	recorded, err := d.importer.ImportReads(ctx, raceID, format, file)
+	if err != nil {
+		return err
+	}
+	d.logger.Info(ctx, "imported a timing read file", logging.RaceID(raceID), logging.String("file", name),
+		logging.Int("accepted", recorded.Accepted), logging.Int("received", recorded.Received))
+	return nil
+}
+
+// raceIDOf returns the race ID the name of a read file starts with
+func raceIDOf(name string) (uuid.UUID, error) {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if len(base) > 36 && base[36] == '-' {
+		base = base[:36]
+	}
+	id, err := uuid.Parse(base)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("file name must start with the race ID: %w", err)
+	}
+	return id, nil
+}
+
+// move moves the file into the sub folder, creating it when needed
+func (d DropFolder) move(ctx context.Context, name, subDir string) {
+	target := filepath.Join(d.dir, subDir)
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		d.logger.Warn(ctx, "failed to create the timing drop sub folder", logging.String("dir", target), logging.Err(err))
+		return
+	}
+	if err := os.Rename(filepath.Join(d.dir, name), filepath.Join(target, name)); err != nil {
+		d.logger.Warn(ctx, "failed to move a timing read file", logging.String("file", name), logging.Err(err))
+	}
+}
@@ -0,0 +1,78 @@
+package timing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/logging"
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeImporter parses the files it receives and records the race and the reads of each import
+type fakeImporter struct {
+	imports map[uuid.UUID][]timing.ReadItem
+	err     error
+}
+
+func (f *fakeImporter) ImportReads(ctx context.Context, raceID uuid.UUID, format timing.ReadFormat, file io.Reader) (timing.RecordedReadsItem, error) {
+	if f.err != nil {
+		return timing.RecordedReadsItem{}, f.err
+	}
+	reads, err := NewParser().Parse(ctx, format, file)
+	if err != nil {
+		return timing.RecordedReadsItem{}, err
+	}
+	f.imports[raceID] = append(f.imports[raceID], reads...)
+	return timing.RecordedReadsItem{Received: len(reads), Accepted: len(reads)}, nil
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestDropFolder_Poll(t *testing.T) {
+	dir := t.TempDir()
+	raceID := uuid.New()
+	writeFile(t, dir, raceID.String()+".csv", "E2001,finish,2025-10-05T08:40:00Z\n")
+	writeFile(t, dir, raceID.String()+"-mat2.lp", "read,chip_id=E2002,checkpoint_id=finish 1759653600000000000\n")
+	writeFile(t, dir, "not-a-race.csv", "E2003,finish,2025-10-05T08:40:00Z\n")
+	writeFile(t, dir, raceID.String()+".csv.part", "E2004,fin")
+	writeFile(t, dir, raceID.String()+"-broken.csv", "E2005,finish,soon\nE2005,finish,later\n")
+
+	importer := &fakeImporter{imports: make(map[uuid.UUID][]timing.ReadItem)}
+	imported, failed := NewDropFolder(dir, 0, importer, logging.Discard).Poll(context.Background())
+
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, 2, failed)
+	assert.Len(t, importer.imports[raceID], 2)
+	assert.FileExists(t, filepath.Join(dir, ProcessedDir, raceID.String()+".csv"))
+	assert.FileExists(t, filepath.Join(dir, ProcessedDir, raceID.String()+"-mat2.lp"))
+	assert.FileExists(t, filepath.Join(dir, FailedDir, "not-a-race.csv"))
+	assert.FileExists(t, filepath.Join(dir, FailedDir, raceID.String()+"-broken.csv"))
+	assert.FileExists(t, filepath.Join(dir, raceID.String()+".csv.part"), "files still being written are left alone")
+
+	//the next poll only finds the file that is still being written
+	imported, failed = NewDropFolder(dir, 0, importer, logging.Discard).Poll(context.Background())
+	assert.Zero(t, imported)
+	assert.Zero(t, failed)
+}
+
+func TestDropFolder_Poll_ImportFailure(t *testing.T) {
+	dir := t.TempDir()
+	name := uuid.New().String() + ".csv"
+	writeFile(t, dir, name, "E2001,finish,2025-10-05T08:40:00Z\n")
+
+	importer := &fakeImporter{err: errors.New("timing is not configured for the race")}
+	imported, failed := NewDropFolder(dir, 0, importer, logging.Discard).Poll(context.Background())
+
+	assert.Zero(t, imported)
+	assert.Equal(t, 1, failed)
+	assert.FileExists(t, filepath.Join(dir, FailedDir, name))
+}
@@ -0,0 +1,115 @@
+// Package timing implements the timing ReadParser port for CSV and line protocol files, and the folder
+// the timing system drops its read files into
+package timing
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+)
+
+// maxFileSize bounds the size of the read files that are read in memory
+const maxFileSize = 32 << 20
+
+// Parser Implements the timing ReadParser port
+type Parser struct{}
+
+// NewParser Constructor
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse reads the timing reads of the file in the provided format
+func (p Parser) Parse(_ context.Context, format timing.ReadFormat, file io.Reader) ([]timing.ReadItem, error) {
+	file = io.LimitReader(file, maxFileSize)
+	switch format {
+	case timing.ReadFormatCSV:
+		return parseCSV(file)
+	case timing.ReadFormatLineProtocol:
+		return parseLineProtocol(file)
+	default:
+		return nil, timing.ErrUnsupportedReadFormat
+	}
+}
+
+// parseCSV reads chip_id,checkpoint_id,timestamp rows with RFC 3339 timestamps.
+// A first row whose timestamp column is not a time is a header and is skipped.
+func parseCSV(file io.Reader) ([]timing.ReadItem, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var reads []timing.ReadItem
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return reads, nil
+		}
+		if err != nil {
+			return nil, errInvalid(err.Error())
+		}
+		at, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(record[2]))
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, errInvalid(fmt.Sprintf("line %d: invalid timestamp %q", line, record[2]))
+		}
+		reads = append(reads, timing.ReadItem{ChipID: record[0], CheckpointID: record[1], At: at})
+	}
+}
+
+// parseLineProtocol reads points such as "read,chip_id=E2001,checkpoint_id=finish rssi=-61i 1759651200000000000"
+// with tags for the chip and the checkpoint and a Unix timestamp in nanoseconds. Fields are ignored, and empty
+// lines and lines starting with # are skipped.
+func parseLineProtocol(file io.Reader) ([]timing.ReadItem, error) {
+	scanner := bufio.NewScanner(file)
+	var reads []timing.ReadItem
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.Fields(text)
+		if len(parts) < 2 {
+			return nil, errInvalid(fmt.Sprintf("line %d: a timestamp is required", line))
+		}
+		nanos, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+		if err != nil {
+			return nil, errInvalid(fmt.Sprintf("line %d: invalid timestamp %q", line, parts[len(parts)-1]))
+		}
+
+		read := timing.ReadItem{At: time.Unix(0, nanos).UTC()}
+		//the first part is the measurement followed by the tags
+		for _, tag := range strings.Split(parts[0], ",")[1:] {
+			key, value, found := strings.Cut(tag, "=")
+			if !found {
+				return nil, errInvalid(fmt.Sprintf("line %d: invalid tag %q", line, tag))
+			}
+			switch key {
+			case "chip_id":
+				read.ChipID = value
+			case "checkpoint_id":
+				read.CheckpointID = value
+			}
+		}
+		reads = append(reads, read)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errInvalid(err.Error())
+	}
+	return reads, nil
+}
+
+// errInvalid wraps timing.ErrInvalidReadFile with the reason the file cannot be decoded
+func errInvalid(reason string) error {
+	return fmt.Errorf("%w: %s", timing.ErrInvalidReadFile, reason)
+}
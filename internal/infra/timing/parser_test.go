@@ -0,0 +1,95 @@
+package timing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkritiotis/go-clean-architecture-example/internal/app/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gun = time.Date(2025, 10, 5, 8, 0, 0, 0, time.UTC)
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  timing.ReadFormat
+		file    string
+		want    []timing.ReadItem
+		wantErr error
+	}{
+		{
+			name:   "csv with header",
+			format: timing.ReadFormatCSV,
+			file:   "chip_id,checkpoint_id,timestamp\nE2001,finish,2025-10-05T08:40:00.25Z\nE2002, 5k ,2025-10-05T10:20:00+02:00\n",
+			want: []timing.ReadItem{
+				{ChipID: "E2001", CheckpointID: "finish", At: gun.Add(40*time.Minute + 250*time.Millisecond)},
+				{ChipID: "E2002", CheckpointID: "5k ", At: gun.Add(20 * time.Minute)},
+			},
+		},
+		{
+			name:   "csv without header",
+			format: timing.ReadFormatCSV,
+			file:   "E2001,finish,2025-10-05T08:40:00Z",
+			want:   []timing.ReadItem{{ChipID: "E2001", CheckpointID: "finish", At: gun.Add(40 * time.Minute)}},
+		},
+		{
+			name:    "csv with an invalid timestamp",
+			format:  timing.ReadFormatCSV,
+			file:    "E2001,finish,2025-10-05T08:40:00Z\nE2002,finish,08:41\n",
+			wantErr: timing.ErrInvalidReadFile,
+		},
+		{
+			name:    "csv with missing columns",
+			format:  timing.ReadFormatCSV,
+			file:    "E2001,2025-10-05T08:40:00Z\n",
+			wantErr: timing.ErrInvalidReadFile,
+		},
+		{
+			name:   "line protocol",
+			format: timing.ReadFormatLineProtocol,
+			file: "# mat 2\n\nread,chip_id=E2001,checkpoint_id=finish rssi=-61i 1759653600000000000\n" +
+				"read,checkpoint_id=start,chip_id=E2002 1759651210000000000\n",
+			want: []timing.ReadItem{
+				{ChipID: "E2001", CheckpointID: "finish", At: gun.Add(40 * time.Minute)},
+				{ChipID: "E2002", CheckpointID: "start", At: gun.Add(10 * time.Second)},
+			},
+		},
+		{
+			name:    "line protocol without timestamp",
+			format:  timing.ReadFormatLineProtocol,
+			file:    "read,chip_id=E2001,checkpoint_id=finish\n",
+			wantErr: timing.ErrInvalidReadFile,
+		},
+		{
+			name:    "line protocol with an invalid tag",
+			format:  timing.ReadFormatLineProtocol,
+			file:    "read,chip_id 1759653600000000000\n",
+			wantErr: timing.ErrInvalidReadFile,
+		},
+		{
+			name:    "unsupported format",
+			format:  "xlsx",
+			wantErr: timing.ErrUnsupportedReadFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParser().Parse(context.Background(), tt.format, strings.NewReader(tt.file))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.want))
+			for i := range tt.want {
+				assert.Equal(t, tt.want[i].ChipID, got[i].ChipID)
+				assert.Equal(t, tt.want[i].CheckpointID, got[i].CheckpointID)
+				assert.True(t, tt.want[i].At.Equal(got[i].At), "read %d at %s, want %s", i, got[i].At, tt.want[i].At)
+			}
+		})
+	}
+}